@@ -4,13 +4,12 @@ Copyright © 2024 Angel Vargas <angelvargas@outlook.es>
 package cmd
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
-	"os"
+	"errors"
 
 	"github.com/angelhvargas/redfishcli/pkg/client"
 	"github.com/angelhvargas/redfishcli/pkg/config"
-	"github.com/angelhvargas/redfishcli/pkg/logger"
 	"github.com/spf13/cobra"
 )
 
@@ -24,58 +23,58 @@ var bootCmd = &cobra.Command{
 var bootStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Get boot status and order",
-	Run: func(cmd *cobra.Command, args []string) {
-		runBootCommand(func(c client.ServerClient) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBootCommand(cmd, func(c client.BootManager) (string, error) {
 			info, err := c.GetBootInfo()
 			if err != nil {
-				return err
+				return "", err
 			}
 			data, _ := json.MarshalIndent(info, "", "  ")
-			fmt.Println(string(data))
-			return nil
+			return string(data), nil
 		})
 	},
 }
 
+// bootTargets are the common BootSourceOverrideTarget values, completed when
+// the BMC cannot be asked for its own list.
+var bootTargets = []string{"None", "Pxe", "Floppy", "Cd", "Usb", "Hdd", "BiosSetup", "Utilities", "Diags", "UefiShell", "UefiTarget"}
+
 var bootSetCmd = &cobra.Command{
 	Use:   "set",
 	Short: "Set next boot device",
 	Long:  `Set the next boot device. Common values: None, Pxe, Floppy, Cd, Usb, Hdd, BiosSetup, Utilities, Diags, UefiShell, UefiTarget`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		device, _ := cmd.Flags().GetString("device")
 		if device == "" {
-			logger.Log.Error("Device type is required")
-			return
+			return errors.New("--device is required")
 		}
-		runBootCommand(func(c client.ServerClient) error {
-			return c.SetBootOrder(device)
+		return runBootCommand(cmd, func(c client.BootManager) (string, error) {
+			return "next boot from " + device, c.SetBootOrder(device)
 		})
 	},
 }
 
-func runBootCommand(action func(client.ServerClient) error) {
-	cfg, err := config.LoadConfigOrEnv(cfgFile, bmcType, bmcUsername, bmcPassword, bmcHost)
+// runBootCommand runs action on every server and prints what it returns.
+func runBootCommand(cmd *cobra.Command, action func(client.BootManager) (string, error)) error {
+	cfg, err := loadServers(bmcHost)
 	if err != nil {
-		logger.Log.Error(err.Error())
-		os.Exit(1)
+		return err
 	}
+	cmd.SilenceUsage = true
 
-	for _, server := range cfg.Servers {
-		fmt.Printf("Processing server: %s\n", server.Hostname)
-		c, err := client.NewClient(server.Type, config.BMCConnConfig{
-			Hostname: server.Hostname,
-			Username: server.Username,
-			Password: server.Password,
-		})
+	summary := runFleet(cfg.Servers, func(ctx context.Context, server config.ServerConfig) (string, error) {
+		c, err := newServerClient(ctx, server)
 		if err != nil {
-			logger.Log.Errorf("Error creating client: %s", err)
-			continue
+			return "", err
 		}
-
-		if err := action(c); err != nil {
-			logger.Log.Errorf("Error performing action: %s", err)
+		bm, err := client.Boot(c)
+		if err != nil {
+			return "", err
 		}
-	}
+		out, err := action(bm)
+		return out, client.Explain(c, client.CapabilityBoot, err)
+	}, printActionResult)
+	return fleetError(summary)
 }
 
 func init() {
@@ -83,4 +82,5 @@ func init() {
 	bootCmd.AddCommand(bootStatusCmd)
 	bootCmd.AddCommand(bootSetCmd)
 	bootSetCmd.Flags().StringP("device", "d", "", "Next boot device (e.g., Pxe, Hdd, Cd)")
+	bootSetCmd.RegisterFlagCompletionFunc("device", completeAllowableValues(func(v *client.AllowableValues) []string { return v.BootTargets }, bootTargets))
 }
@@ -67,4 +67,14 @@ servers:
 		assert.NoError(t, err)
 		mockClient.AssertExpectations(t)
 	})
+
+	t.Run("boot set invalid target", func(t *testing.T) {
+		mockClient.On("SetBootOrder", "Pex").Return(client.Validate("BootSourceOverrideTarget", "Pex", []string{"Hdd", "Pxe"})).Once()
+
+		_, stderr, err := runCommand("boot", "set", "--device", "Pex", "--config", configFile)
+
+		assert.Equal(t, exitFailed, exitCode(err))
+		assert.Contains(t, stderr, "test-server: failed (invalid): invalid BootSourceOverrideTarget \"Pex\": did you mean \"Pxe\"?")
+		mockClient.AssertExpectations(t)
+	})
 }
@@ -0,0 +1,115 @@
+/*
+Copyright © 2024 Angel Vargas <angelvargas@outlook.es>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/angelhvargas/redfishcli/pkg/client"
+	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/angelhvargas/redfishcli/pkg/fleet"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var capabilitiesOutput string
+
+type capabilitiesReport struct {
+	Hostname     string               `json:"hostname" yaml:"hostname"`
+	Capabilities *client.Capabilities `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`
+	Error        *fleet.HostError     `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// capabilitiesCmd represents the capabilities command
+var capabilitiesCmd = &cobra.Command{
+	Use:   "capabilities",
+	Short: "Show which features each BMC supports",
+	Long: `Probe each BMC for power control (including the allowed reset types), boot
+overrides (including the allowed targets), storage inspection and event logs.
+
+A capability is reported only when both the BMC exposes it and the backend for
+its type implements it.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadServers(bmcHost)
+		if err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+
+		var reports []capabilitiesReport
+		summary := runFleet(cfg.Servers, func(ctx context.Context, server config.ServerConfig) (*client.Capabilities, error) {
+			c, err := newServerClient(ctx, server)
+			if err != nil {
+				return nil, err
+			}
+			return probeCapabilities(c)
+		}, func(r fleet.Result[*client.Capabilities]) {
+			reports = append(reports, capabilitiesReport{Hostname: r.Server.Hostname, Capabilities: r.Value, Error: fleet.Describe(r.Err)})
+		})
+
+		printCapabilities(reports)
+		return fleetError(summary)
+	},
+}
+
+// probeCapabilities asks the BMC what it exposes, when the backend can
+// probe, and masks out anything the backend does not implement.
+func probeCapabilities(c client.ServerClient) (*client.Capabilities, error) {
+	caps := &client.Capabilities{Power: true, Boot: true, Storage: true, Logs: true}
+	if p, ok := c.(client.CapabilityProber); ok {
+		var err error
+		if caps, err = p.Capabilities(); err != nil {
+			return nil, err
+		}
+	}
+	_, power := c.(client.PowerManager)
+	_, boot := c.(client.BootManager)
+	_, storage := c.(client.StorageInspector)
+	_, logs := c.(client.LogReader)
+	caps.Power = caps.Power && power
+	caps.Boot = caps.Boot && boot
+	caps.Storage = caps.Storage && storage
+	caps.Logs = caps.Logs && logs
+	return caps, nil
+}
+
+func printCapabilities(reports []capabilitiesReport) {
+	switch capabilitiesOutput {
+	case "json":
+		data, _ := json.MarshalIndent(reports, "", "  ")
+		fmt.Println(string(data))
+	case "yaml":
+		data, _ := yaml.Marshal(reports)
+		fmt.Println(string(data))
+	default:
+		for _, r := range reports {
+			if r.Capabilities == nil {
+				continue
+			}
+			fmt.Printf("%s\n", r.Hostname)
+			fmt.Printf("  power:   %s\n", supported(r.Capabilities.Power, r.Capabilities.ResetTypes))
+			fmt.Printf("  boot:    %s\n", supported(r.Capabilities.Boot, r.Capabilities.BootTargets))
+			fmt.Printf("  storage: %s\n", supported(r.Capabilities.Storage, nil))
+			fmt.Printf("  logs:    %s\n", supported(r.Capabilities.Logs, nil))
+		}
+	}
+}
+
+func supported(ok bool, values []string) string {
+	if !ok {
+		return "not supported"
+	}
+	if len(values) == 0 {
+		return "supported"
+	}
+	return fmt.Sprintf("supported (%s)", strings.Join(values, ", "))
+}
+
+func init() {
+	rootCmd.AddCommand(capabilitiesCmd)
+	capabilitiesCmd.PersistentFlags().StringVarP(&capabilitiesOutput, "output", "o", "text", "Output format (json, yaml, text)")
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/angelhvargas/redfishcli/pkg/client"
+	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+// captureStdout runs the root command with args and returns what it printed.
+func captureStdout(t *testing.T, args ...string) string {
+	stdout, _, err := runCommand(args...)
+	assert.NoError(t, err)
+	return stdout
+}
+
+// runCommand runs the root command with args and returns what it printed to
+// stdout and stderr and the error it returned.
+func runCommand(args ...string) (stdout, stderr string, err error) {
+	oldStdout, oldStderr := os.Stdout, os.Stderr
+	outR, outW, _ := os.Pipe()
+	errR, errW, _ := os.Pipe()
+	os.Stdout, os.Stderr = outW, errW
+
+	// Drain both pipes while the command runs so large outputs cannot block it.
+	var outBuf, errBuf bytes.Buffer
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); io.Copy(&outBuf, outR) }()
+	go func() { defer wg.Done(); io.Copy(&errBuf, errR) }()
+
+	rootCmd.SetArgs(args)
+	err = rootCmd.Execute()
+	resetFlags(rootCmd)
+
+	outW.Close()
+	errW.Close()
+	wg.Wait()
+	os.Stdout, os.Stderr = oldStdout, oldStderr
+	return outBuf.String(), errBuf.String(), err
+}
+
+// resetFlags puts back the defaults of the flags a run set, which would
+// otherwise carry over to the next run of the same command.
+func resetFlags(cmd *cobra.Command) {
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if !f.Changed {
+			return
+		}
+		if s, ok := f.Value.(pflag.SliceValue); ok {
+			s.Replace(nil)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	})
+	for _, c := range cmd.Commands() {
+		resetFlags(c)
+	}
+}
+
+func TestCapabilitiesCmd(t *testing.T) {
+	configFile := "config_test_capabilities.yaml"
+	configContent := `
+servers:
+  - type: idrac
+    hostname: test-server
+    username: user
+    password: password
+`
+	err := os.WriteFile(configFile, []byte(configContent), 0644)
+	assert.NoError(t, err)
+	defer os.Remove(configFile)
+
+	t.Run("Backend without power support", func(t *testing.T) {
+		mockClient := new(client.MockLogReader)
+		client.ResetRegistry()
+		client.Register("idrac", func(cfg config.BMCConnConfig) client.ServerClient {
+			return mockClient
+		})
+
+		output := captureStdout(t, "power", "status", "--config", configFile)
+		assert.Contains(t, output, "test-server: power management not supported on this BMC")
+	})
+
+	t.Run("BMC without power support", func(t *testing.T) {
+		mockClient := new(client.MockCapabilityProber)
+		client.ResetRegistry()
+		client.Register("idrac", func(cfg config.BMCConnConfig) client.ServerClient {
+			return mockClient
+		})
+		mockClient.On("SetPowerState", "On").Return(errors.New("HTTP 404: resource not found")).Once()
+		mockClient.On("Capabilities").Return(&client.Capabilities{Logs: true}, nil).Once()
+
+		output := captureStdout(t, "power", "on", "--config", configFile)
+		assert.Contains(t, output, "test-server: power management not supported on this BMC")
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("capabilities", func(t *testing.T) {
+		mockClient := new(client.MockCapabilityProber)
+		client.ResetRegistry()
+		client.Register("idrac", func(cfg config.BMCConnConfig) client.ServerClient {
+			return mockClient
+		})
+		mockClient.On("Capabilities").Return(&client.Capabilities{
+			Power:      true,
+			ResetTypes: []string{"ForceOff", "On"},
+			Boot:       true,
+			Storage:    true,
+		}, nil).Once()
+
+		output := captureStdout(t, "capabilities", "--config", configFile)
+		assert.Contains(t, output, "power:   supported (ForceOff, On)")
+		// The BMC offers boot overrides but the backend cannot drive them.
+		assert.Contains(t, output, "boot:    not supported")
+		assert.Contains(t, output, "storage: not supported")
+		assert.Contains(t, output, "logs:    not supported")
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestAllowableValues(t *testing.T) {
+	server := startHealthEmulator(t, nil)
+	configFile := "config_test_allowable.yaml"
+	configContent := fmt.Sprintf(`
+servers:
+  - type: idrac
+    hostname: %s
+    username: root
+    password: calvin
+`, server.Hostname)
+	err := os.WriteFile(configFile, []byte(configContent), 0644)
+	assert.NoError(t, err)
+	defer os.Remove(configFile)
+
+	t.Run("Invalid boot target", func(t *testing.T) {
+		output, _, err := runCommand("boot", "set", "--device", "pxe", "--config", configFile)
+		assert.Equal(t, exitFailed, exitCode(err))
+		assert.Contains(t, output, `invalid BootSourceOverrideTarget "pxe": did you mean "Pxe"?`)
+	})
+
+	t.Run("Boot device completion", func(t *testing.T) {
+		output := captureStdout(t, cobra.ShellCompRequestCmd, "boot", "set", "--config", configFile, "--device", "u")
+		// UefiHttp is only known from the BMC's AllowableValues.
+		assert.Contains(t, output, "UefiHttp\n")
+		assert.Contains(t, output, "UefiTarget\n")
+		assert.NotContains(t, output, "Pxe\n")
+	})
+
+	t.Run("Reset type completion", func(t *testing.T) {
+		output := captureStdout(t, cobra.ShellCompRequestCmd, "power", "restart", "--config", configFile, "--reset-type", "")
+		assert.Contains(t, output, "ForceRestart\n")
+		assert.Contains(t, output, "GracefulRestart\n")
+	})
+}
@@ -0,0 +1,73 @@
+/*
+Copyright © 2024 Angel Vargas <angelvargas@outlook.es>
+*/
+package cmd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/angelhvargas/redfishcli/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+// completeAllowableValues completes a flag with the values the first
+// configured BMC advertises, as picked from its AllowableValues. When the BMC
+// cannot be reached or advertises nothing, fallback is offered instead.
+// Completion never prompts: a password only --ask-pass or the credential
+// store's passphrase prompt could give makes it fall back too.
+func completeAllowableValues(pick func(*client.AllowableValues) []string, fallback []string) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		values := fallback
+		if advertised := fetchAllowableValues(cmd, pick); len(advertised) > 0 {
+			values = advertised
+		}
+
+		var matches []string
+		for _, v := range values {
+			if strings.HasPrefix(strings.ToLower(v), strings.ToLower(toComplete)) {
+				matches = append(matches, v)
+			}
+		}
+		return matches, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func fetchAllowableValues(cmd *cobra.Command, pick func(*client.AllowableValues) []string) []string {
+	noPrompt = true
+	defer func() { noPrompt = false }()
+
+	// Completion does not run the root pre-run hook, so apply the context
+	// and build the HTTP pipeline from the flags here. Completion traffic is
+	// never recorded.
+	if err := applyContext(cmd); err != nil {
+		return nil
+	}
+	cfg, err := loadConfig(bmcHost)
+	if err != nil || len(cfg.Servers) == 0 {
+		return nil
+	}
+	if recordDir == "" {
+		if err := setupHTTPConfig(); err != nil {
+			return nil
+		}
+	}
+	ctx := context.Background()
+	server, err := resolveServer(ctx, cfg.Servers[0])
+	if err != nil {
+		return nil
+	}
+	c, err := newServerClient(ctx, server)
+	if err != nil {
+		return nil
+	}
+	reader, ok := c.(client.AllowableValuesReader)
+	if !ok {
+		return nil
+	}
+	values, err := reader.AllowableValues()
+	if err != nil {
+		return nil
+	}
+	return pick(values)
+}
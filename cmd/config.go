@@ -0,0 +1,75 @@
+/*
+Copyright © 2024 Angel Vargas <angelvargas@outlook.es>
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	configOutput  string
+	showPasswords bool
+)
+
+// configCmd groups the commands that inspect the configuration file.
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the configuration file",
+}
+
+// configExpandCmd prints the servers commands would run against.
+var configExpandCmd = &cobra.Command{
+	Use:   "expand",
+	Short: "Print the fully expanded server list",
+	Long: `Load the configuration file with the files it includes, expand its host
+ranges, brace lists, CIDR blocks and templates, and print every resulting
+server. --selector, --group, --limit and --exclude apply, so this also shows
+which servers a selection picks. Passwords are masked unless
+--show-passwords is given; references such as env:BMC_PASSWORD are printed
+as they are, unresolved.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(bmcHost)
+		if err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+
+		if !showPasswords {
+			for i := range cfg.Servers {
+				if p := cfg.Servers[i].Password; p != "" && !config.IsSecretRef(p) {
+					cfg.Servers[i].Password = "********"
+				}
+			}
+		}
+		printExpandedConfig(cfg)
+		return nil
+	},
+}
+
+func printExpandedConfig(cfg *config.BMCConfig) {
+	switch configOutput {
+	case "json":
+		data, _ := json.MarshalIndent(cfg, "", "  ")
+		fmt.Println(string(data))
+	case "text":
+		for _, server := range cfg.Servers {
+			fmt.Println(server.Hostname)
+		}
+	default:
+		data, _ := yaml.Marshal(cfg)
+		fmt.Print(string(data))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configExpandCmd)
+	configExpandCmd.Flags().StringVarP(&configOutput, "output", "o", "yaml", "Output format (yaml, json, text)")
+	configExpandCmd.Flags().BoolVar(&showPasswords, "show-passwords", false, "print passwords instead of masking them")
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestConfigExpandCmd(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+include: [credentials.yaml]
+templates:
+  dell:
+    type: idrac
+    credentials: dell
+    labels: {vendor: dell}
+servers:
+  - hostname: r12-bmc[01-03].ams
+    template: dell
+    labels: {site: ams}
+  - {type: xclarity, hostname: "bmc.{a,b}.fra", username: USERID, password: PASSW0RD, labels: {site: fra}}
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "credentials.yaml"), []byte(`
+credentials:
+  dell: {username: root, password: calvin}
+`), 0644))
+	t.Cleanup(func() {
+		configOutput = "yaml"
+		showPasswords = false
+		serverSelector = ""
+	})
+
+	stdout, _, err := runCommand("config", "expand", "--config", configFile, "-o", "yaml")
+	require.NoError(t, err)
+	var cfg config.BMCConfig
+	require.NoError(t, yaml.Unmarshal([]byte(stdout), &cfg))
+	require.Len(t, cfg.Servers, 5)
+	assert.Equal(t, config.ServerConfig{
+		Type: "idrac", Hostname: "r12-bmc02.ams", Username: "root", Password: "********",
+		Labels: map[string]string{"vendor": "dell", "site": "ams"},
+	}, cfg.Servers[1])
+	assert.Equal(t, "bmc.b.fra", cfg.Servers[4].Hostname)
+	assert.NotContains(t, stdout, "calvin")
+
+	stdout, _, err = runCommand("config", "expand", "--config", configFile, "-o", "text", "--selector", "site=fra", "--show-passwords")
+	require.NoError(t, err)
+	assert.Equal(t, "bmc.a.fra\nbmc.b.fra\n", stdout)
+
+	serverSelector = ""
+	stdout, _, err = runCommand("config", "expand", "--config", configFile, "-o", "json", "--show-passwords")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, `"password": "calvin"`)
+}
@@ -0,0 +1,252 @@
+/*
+Copyright © 2024 Angel Vargas <angelvargas@outlook.es>
+*/
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	contextName string
+
+	// activeContext is the context the current command runs in, nil when
+	// there is none.
+	activeContext *config.Context
+
+	contextOutput    string
+	contextVault     string
+	contextTLSVerify bool
+	contextCA        string
+	contextProxy     string
+	contextUse       bool
+	contextForce     bool
+)
+
+// contextCmd groups the commands that manage the contexts.
+var contextCmd = &cobra.Command{
+	Use:   "context",
+	Short: "Switch between named fleets and their settings",
+	Long: `A context names the defaults of a fleet: its configuration file, a
+default selector, fallback credentials, the credential store, the output
+format, the TLS policy and a proxy. The contexts are kept in
+~/.redfishcli/contexts.yaml, or REDFISHCLI_CONTEXTS. Commands run in the
+context given with --context, else REDFISHCLI_CONTEXT, else the one chosen
+with 'redfishcli context use'. Flags given on the command line win over the
+context.
+
+The configuration file is --config, else the context's, else
+~/.redfishcli/config.yaml when it exists. A host given with --host is a
+server of its own and does not load the context's fleet.
+
+Example:
+  redfishcli context create prod --config ~/fleets/prod.yaml --selector role!=spare --tls-verify
+  redfishcli context create lab --config ~/fleets/lab.yaml -u root -p env:LAB_PASSWORD
+  redfishcli context use prod
+  redfishcli health --context lab`,
+}
+
+var contextListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the contexts",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		cs, err := config.LoadContexts(config.ContextsPath())
+		if err != nil {
+			return err
+		}
+		current := contextInUse(cs)
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CURRENT\tNAME\tCONFIG\tSELECTOR")
+		for _, name := range cs.Names() {
+			mark := ""
+			if name == current {
+				mark = "*"
+			}
+			c := cs.Contexts[name]
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", mark, name, c.Config, c.Selector)
+		}
+		return w.Flush()
+	},
+}
+
+var contextUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Make a context the current one",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		path := config.ContextsPath()
+		cs, err := config.LoadContexts(path)
+		if err != nil {
+			return err
+		}
+		if _, err := cs.Get(args[0]); err != nil {
+			return err
+		}
+		cs.Current = args[0]
+		if err := cs.Save(path); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Switched to context %q\n", args[0])
+		return nil
+	},
+}
+
+var contextShowCmd = &cobra.Command{
+	Use:   "show [name]",
+	Short: "Print a context, by default the one in use",
+	Long: `Print the settings of a context as YAML, by default those of the context
+in use. A password that is not a reference is masked.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		cs, err := config.LoadContexts(config.ContextsPath())
+		if err != nil {
+			return err
+		}
+		name := contextInUse(cs)
+		if len(args) == 1 {
+			name = args[0]
+		}
+		if name == "" {
+			return fmt.Errorf("no context in use; see 'redfishcli context list'")
+		}
+		c, err := cs.Get(name)
+		if err != nil {
+			return err
+		}
+		if c.Password != "" && !config.IsSecretRef(c.Password) {
+			c.Password = "********"
+		}
+		data, err := yaml.Marshal(map[string]*config.Context{name: c})
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(cmd.OutOrStdout(), string(data))
+		return nil
+	},
+}
+
+var contextCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a context from the given flags",
+	Long: `Create a context from --config, --selector, --username and --password and
+the flags below. Paths are stored absolute. The first context created
+becomes the current one, as does any created with --use.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		c := config.Context{
+			Selector: serverSelector,
+			Username: bmcUsername,
+			Password: bmcPassword,
+			Output:   contextOutput,
+			TLS:      config.ContextTLS{Verify: contextTLSVerify},
+			Proxy:    contextProxy,
+		}
+		if _, err := config.ParseSelector(c.Selector); err != nil {
+			return fmt.Errorf("--selector: %w", err)
+		}
+		if c.Proxy != "" {
+			if _, err := url.Parse(c.Proxy); err != nil {
+				return fmt.Errorf("--proxy: %w", err)
+			}
+		}
+		for dst, src := range map[*string]string{&c.Config: cfgFile, &c.Vault: contextVault, &c.TLS.CA: contextCA} {
+			if src == "" {
+				continue
+			}
+			abs, err := filepath.Abs(src)
+			if err != nil {
+				return err
+			}
+			*dst = abs
+		}
+		cmd.SilenceUsage = true
+
+		path := config.ContextsPath()
+		cs, err := config.LoadContexts(path)
+		if err != nil {
+			return err
+		}
+		if _, exists := cs.Contexts[name]; exists && !contextForce {
+			return fmt.Errorf("context %q already exists; use --force to replace it", name)
+		}
+		if cs.Contexts == nil {
+			cs.Contexts = make(map[string]config.Context)
+		}
+		cs.Contexts[name] = c
+		if cs.Current == "" || contextUse {
+			cs.Current = name
+		}
+		if err := cs.Save(path); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Created context %q\n", name)
+		return nil
+	},
+}
+
+// contextInUse returns the name of the context commands run in: --context,
+// else REDFISHCLI_CONTEXT, else the current context of cs.
+func contextInUse(cs *config.Contexts) string {
+	if contextName != "" {
+		return contextName
+	}
+	if name := os.Getenv("REDFISHCLI_CONTEXT"); name != "" {
+		return name
+	}
+	return cs.Current
+}
+
+// applyContext loads the context the command runs in, if any, and makes its
+// output format the default of the command's --output. The context commands
+// manage the contexts file themselves.
+func applyContext(cmd *cobra.Command) error {
+	activeContext = nil
+	if cmd.Parent() == contextCmd {
+		return nil
+	}
+	cs, err := config.LoadContexts(config.ContextsPath())
+	if err != nil {
+		return err
+	}
+	name := contextInUse(cs)
+	if name == "" {
+		return nil
+	}
+	if activeContext, err = cs.Get(name); err != nil {
+		return err
+	}
+	if activeContext.Output != "" {
+		if f := cmd.Flags().Lookup("output"); f != nil && !f.Changed {
+			if err := f.Value.Set(activeContext.Output); err != nil {
+				return fmt.Errorf("context %q: output: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&contextName, "context", "", "run in this context instead of the current one (see 'redfishcli context')")
+	rootCmd.AddCommand(contextCmd)
+	contextCmd.AddCommand(contextListCmd, contextUseCmd, contextShowCmd, contextCreateCmd)
+	contextCreateCmd.Flags().StringVarP(&contextOutput, "output", "o", "", "default output format of the commands, e.g. json")
+	contextCreateCmd.Flags().StringVar(&contextVault, "vault", "", "credential store file (see 'redfishcli vault')")
+	contextCreateCmd.Flags().BoolVar(&contextTLSVerify, "tls-verify", false, "check the BMC certificates")
+	contextCreateCmd.Flags().StringVar(&contextCA, "ca", "", "PEM file of the authorities that sign the BMC certificates")
+	contextCreateCmd.Flags().StringVar(&contextProxy, "proxy", "", "URL of the HTTP proxy the BMCs are reached through")
+	contextCreateCmd.Flags().BoolVar(&contextUse, "use", false, "make the new context the current one")
+	contextCreateCmd.Flags().BoolVar(&contextForce, "force", false, "replace a context of the same name")
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestContexts(t *testing.T) {
+	_, dellHost, lenovoHost := writeFleetConfig(t)
+	dir := t.TempDir()
+	t.Setenv("REDFISHCLI_CONTEXTS", filepath.Join(dir, "contexts.yaml"))
+	t.Setenv("REDFISHCLI_CONTEXT", "")
+	fleetFile := filepath.Join(dir, "fleet.yaml")
+	require.NoError(t, os.WriteFile(fleetFile, []byte(fmt.Sprintf(`
+servers:
+  - {type: idrac, hostname: %s, labels: {site: ams}}
+  - {type: xclarity, hostname: %s, labels: {site: fra}}
+`, dellHost, lenovoHost)), 0644))
+	// The flags keep their values between runs.
+	reset := func() {
+		cfgFile, contextName, serverSelector, bmcUsername, bmcPassword = "", "", "", "", ""
+		contextUse, contextForce = false, false
+	}
+	reset()
+	t.Cleanup(reset)
+
+	stdout, _, err := runCommand("context", "create", "ams", "--config", fleetFile, "--selector", "site=ams", "-u", "root", "-p", "calvin")
+	require.NoError(t, err)
+	assert.Equal(t, "Created context \"ams\"\n", stdout)
+	reset()
+	_, _, err = runCommand("context", "create", "all", "--config", fleetFile, "-u", "root", "-p", "env:REDFISHCLI_TEST_CONTEXT_PASSWORD")
+	require.NoError(t, err)
+	reset()
+	_, _, err = runCommand("context", "create", "all")
+	assert.EqualError(t, err, `context "all" already exists; use --force to replace it`)
+	reset()
+
+	stdout, _, err = runCommand("context", "list")
+	require.NoError(t, err)
+	assert.Regexp(t, `\*\s+ams\s+`+fleetFile+`\s+site=ams`, stdout, "the first context is the current one")
+	assert.Regexp(t, `\n\s+all\s+`+fleetFile, stdout)
+
+	stdout, _, err = runCommand("context", "show")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "ams:\n")
+	assert.Contains(t, stdout, `password: '********'`)
+
+	// The current context's configuration, selector and credentials apply.
+	stdout, _, err = runCommand("sysinfo")
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(stdout, "Manufacturer:"))
+	assert.Contains(t, stdout, "Manufacturer: Dell Inc.")
+
+	// So do those of --context, and the flags win.
+	t.Setenv("REDFISHCLI_TEST_CONTEXT_PASSWORD", "calvin")
+	stdout, _, err = runCommand("sysinfo", "--context", "all")
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(stdout, "Manufacturer:"))
+	reset()
+	stdout, _, err = runCommand("sysinfo", "--selector", "site=fra")
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(stdout, "Manufacturer:"))
+	assert.Contains(t, stdout, "Manufacturer: Lenovo")
+	reset()
+
+	stdout, _, err = runCommand("context", "use", "all")
+	require.NoError(t, err)
+	assert.Equal(t, "Switched to context \"all\"\n", stdout)
+	stdout, _, err = runCommand("context", "show")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "all:\n")
+	assert.Contains(t, stdout, "password: env:REDFISHCLI_TEST_CONTEXT_PASSWORD")
+
+	_, _, err = runCommand("context", "use", "lab")
+	assert.EqualError(t, err, `no context "lab"`)
+	_, _, err = runCommand("sysinfo", "--context", "lab")
+	assert.EqualError(t, err, `no context "lab"`)
+}
+
+func TestContextOutput(t *testing.T) {
+	configFile, dellHost, lenovoHost := writeFleetConfig(t)
+	dir := t.TempDir()
+	t.Setenv("REDFISHCLI_CONTEXTS", filepath.Join(dir, "contexts.yaml"))
+	t.Setenv("REDFISHCLI_CONTEXT", "")
+	oldSysinfo, oldControllers := sysinfoOutput, controllersOutput
+	reset := func() {
+		cfgFile, contextName, serverSelector, bmcUsername, bmcPassword, contextOutput = "", "", "", "", "", ""
+		contextUse, contextForce = false, false
+		sysinfoOutput, controllersOutput = oldSysinfo, oldControllers
+	}
+	reset()
+	t.Cleanup(reset)
+
+	_, _, err := runCommand("context", "create", "yaml", "--config", configFile, "-o", "yaml")
+	require.NoError(t, err)
+	reset()
+
+	// The context's format sets each command's own output flag.
+	stdout, _, err := runCommand("sysinfo")
+	require.NoError(t, err)
+	var systems []map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(stdout), &systems), stdout)
+	require.Len(t, systems, 2)
+	assert.Equal(t, dellHost, systems[0]["hostname"])
+	assert.Equal(t, lenovoHost, systems[1]["hostname"])
+	assert.Equal(t, "yaml", sysinfoOutput)
+
+	stdout, _, err = runCommand("storage", "controllers")
+	require.NoError(t, err)
+	var reports []controllersReport
+	require.NoError(t, yaml.Unmarshal([]byte(stdout), &reports), stdout)
+	require.Len(t, reports, 2)
+	assert.Equal(t, "yaml", controllersOutput)
+	reset()
+
+	// -o wins.
+	stdout, _, err = runCommand("sysinfo", "-o", "text")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Manufacturer: Dell Inc.")
+}
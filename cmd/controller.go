@@ -1,18 +1,23 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"sync"
 
 	"github.com/angelhvargas/redfishcli/pkg/client"
 	"github.com/angelhvargas/redfishcli/pkg/config"
-	"github.com/angelhvargas/redfishcli/pkg/logger"
-	"github.com/angelhvargas/redfishcli/pkg/model"
+	"github.com/angelhvargas/redfishcli/pkg/fleet"
+	"github.com/angelhvargas/redfishcli/pkg/inventory"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
 
+var (
+	controllersOutput string
+	controllersRaw    bool
+)
+
 // controllersCmd represents the controllers command
 var controllersCmd = &cobra.Command{
 	Use:   "controllers",
@@ -35,111 +40,96 @@ Example:
 
   List storage controllers of a Lenovo server with XClarity:
     redfishcli storage controllers -t xclarity -u admin -p "your_password" -n 192.168.1.101 | jq`,
-	Run: func(cmd *cobra.Command, args []string) {
-		cfg, err := config.LoadConfigOrEnv(cfgFile, bmcType, bmcUsername, bmcPassword, bmcHost)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadServers(bmcHost)
 		if err != nil {
-			logger.Log.Error(err.Error())
-			return
-		}
-		servers := cfg.Servers
-
-		var wg sync.WaitGroup
-		controllersReportsCh := make(chan *model.ControllersReport, len(servers))
-		errorsCh := make(chan error, len(servers))
-
-		for _, server := range servers {
-			wg.Add(1)
-			go processControllers(&wg, server, controllersReportsCh, errorsCh)
+			return err
 		}
-
-		wg.Wait()
-		close(controllersReportsCh)
-		close(errorsCh)
-
-		var controllersReports []*model.ControllersReport
-		for report := range controllersReportsCh {
+		cmd.SilenceUsage = true
+		var controllersReports []*controllersReport
+		summary := runFleet(cfg.Servers, serverControllersReport, func(r fleet.Result[*controllersReport]) {
+			report := r.Value
+			if report == nil {
+				report = &controllersReport{Hostname: r.Server.Hostname, Controllers: []inventory.Controller{}}
+			}
+			report.Error = fleet.Describe(r.Err)
 			controllersReports = append(controllersReports, report)
-		}
-
-		switch output {
-		case "json":
+		})
+
+		switch {
+		case controllersRaw:
+			var payloads []json.RawMessage
+			for _, report := range controllersReports {
+				for _, controller := range report.Controllers {
+					payloads = append(payloads, controller.Raw)
+				}
+			}
+			printRaw(payloads)
+		case controllersOutput == "json":
 			jsonData, err := json.Marshal(controllersReports)
 			if err != nil {
-				logger.Log.Error(err.Error())
-				return
+				return err
 			}
 			fmt.Println(string(jsonData))
-		case "yaml":
+		case controllersOutput == "yaml":
 			yamlData, err := yaml.Marshal(controllersReports)
 			if err != nil {
-				logger.Log.Error(err.Error())
-				return
+				return err
 			}
 			fmt.Println(string(yamlData))
-		case "table":
+		case controllersOutput == "table":
 			printTable(controllersReports)
 		default:
-			logger.Log.Errorf("Unsupported output format: %s", output)
-		}
-
-		for err := range errorsCh {
-			logger.Log.Error(err.Error())
+			return fmt.Errorf("unsupported output format: %s", controllersOutput)
 		}
+		return fleetError(summary)
 	},
 }
 
-func processControllers(wg *sync.WaitGroup, server config.ServerConfig, controllersReportsCh chan<- *model.ControllersReport, errorsCh chan<- error) {
-	defer wg.Done()
-
+// serverControllersReport lists the storage controllers of one server.
+func serverControllersReport(ctx context.Context, server config.ServerConfig) (*controllersReport, error) {
 	// Create client using the registry
-	bmcClient, err := client.NewClient(server.Type, config.BMCConnConfig{
-		Hostname: server.Hostname,
-		Username: server.Username,
-		Password: server.Password,
-	})
+	bmcClient, err := newServerClient(ctx, server)
 	if err != nil {
-		logger.Log.Errorf("Error creating client for server %s: %s", server.Hostname, err)
-		errorsCh <- err
-		return
+		return nil, err
 	}
+	return gatherControllersReport(bmcClient, server.Hostname)
+}
 
-	report, err := gatherControllersReport(bmcClient, server.Hostname)
-	if err != nil {
-		logger.Log.Error(err.Error())
-		errorsCh <- err
-		return
-	}
-	controllersReportsCh <- report
+// controllersReport lists the storage controllers of one server.
+type controllersReport struct {
+	Controllers []inventory.Controller `json:"controllers" yaml:"controllers"`
+	Hostname    string                 `json:"hostname" yaml:"hostname"`
+	// Error is set when the controllers could not all be read.
+	Error *fleet.HostError `json:"error,omitempty" yaml:"error,omitempty"`
 }
 
-func gatherControllersReport(bmcClient client.ServerClient, hostname string) (*model.ControllersReport, error) {
-	controllerConfig := &model.StorageControllerConfig{
-		Type: "RAID",
-	}
-	controllers, err := bmcClient.GetStorageControllers(controllerConfig)
-	if err != nil {
+func gatherControllersReport(bmcClient client.ServerClient, hostname string) (*controllersReport, error) {
+	inv, err := inventory.Collect(bmcClient, hostname, inventory.PartStorage)
+	if inv.Controllers == nil {
 		return nil, err
 	}
 
-	report := &model.ControllersReport{
+	report := &controllersReport{
 		Hostname:    hostname,
-		Controllers: controllers,
+		Controllers: inv.Controllers,
 	}
 
-	return report, nil
+	return report, err
 }
 
-func printTable(reports []*model.ControllersReport) {
+func printTable(reports []*controllersReport) {
 	fmt.Printf("%-20s %-20s %-20s %-20s\n", "Hostname", "ID", "Name", "Status")
 	for _, report := range reports {
 		fmt.Printf("%-20s\n", report.Hostname)
 		for _, controller := range report.Controllers {
-			fmt.Printf("%-20s %-20s %-20s %-20s\n", "", controller.ID, controller.Name, controller.Status)
+			fmt.Printf("%-20s %-20s %-20s %-20s\n", "", controller.ID, controller.Name, controller.Status.Health)
 		}
 	}
 }
 
 func init() {
 	storageCmd.AddCommand(controllersCmd)
-	controllersCmd.PersistentFlags().StringVarP(&output, "output", "o", "json", "Output format (json, yaml, table)")
+	controllersCmd.PersistentFlags().StringVarP(&controllersOutput, "output", "o", "json", "Output format (json, yaml, table)")
+	controllersCmd.Flags().BoolVar(&controllersRaw, "raw", false, "print the original BMC JSON instead of formatted output")
 }
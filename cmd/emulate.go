@@ -0,0 +1,106 @@
+/*
+Copyright © 2024 Angel Vargas <angelvargas@outlook.es>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/angelhvargas/redfishcli/pkg/emulator"
+	"github.com/angelhvargas/redfishcli/pkg/fault"
+	"github.com/spf13/cobra"
+)
+
+var (
+	emulateProfile     string
+	emulateMockup      string
+	emulateListen      string
+	emulateSELInterval time.Duration
+	emulateFaults      []string
+)
+
+// emulateCmd represents the emulate command
+var emulateCmd = &cobra.Command{
+	Use:   "emulate",
+	Short: "Run a local Redfish BMC emulator",
+	Long: `Serve a stateful Redfish service over HTTPS from a built-in profile or a
+mockup directory in the DMTF Redfish-Mockup-Creator layout.
+
+The emulator answers power resets, boot overrides, PATCH requests, sessions and
+log services, so every redfishcli command can be tried without real hardware.
+
+Built-in profiles:
+  idrac   Dell PowerEdge R740 with iDRAC 9
+  xcc     Lenovo ThinkSystem SR650 with XClarity Controller
+
+Faults can be injected per path with --fault <path-regex>:<options>, where
+options are latency=<duration>, status=<code>, truncate, tls, drop,
+auth-expiry, method=<verb>, after=<n> and times=<n>.
+
+Example:
+  redfishcli emulate --profile idrac --listen 127.0.0.1:8443
+  redfishcli sysinfo -t idrac -n 127.0.0.1:8443 -u root -p calvin
+  redfishcli emulate --fault 'Drives/Disk.Bay.1$:status=500' --fault '/Storage:latency=3s'`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile, err := emulator.LookupProfile(emulateProfile)
+		if err != nil {
+			return err
+		}
+		if emulateMockup != "" {
+			profile = emulator.ProfileFromDir(emulateMockup)
+		}
+
+		username, password := bmcUsername, bmcPassword
+		if username == "" {
+			username = "root"
+		}
+		if password == "" {
+			password = "calvin"
+		}
+
+		emu, err := emulator.New(profile, emulator.Options{Username: username, Password: password})
+		if err != nil {
+			return err
+		}
+		opts := emulator.StartOptions{SELInterval: emulateSELInterval}
+		if len(emulateFaults) > 0 {
+			rules := make([]fault.Rule, 0, len(emulateFaults))
+			for _, spec := range emulateFaults {
+				rule, err := fault.Parse(spec)
+				if err != nil {
+					return err
+				}
+				rules = append(rules, rule)
+			}
+			opts.Faults = fault.New(rules...)
+		}
+		srv, err := emu.Start(emulateListen, opts)
+		if err != nil {
+			return err
+		}
+		defer srv.Close()
+
+		fmt.Printf("Emulating %s at %s (username %q)\n", profile.Name, srv.URL, username)
+		if profile.BMCType != "" {
+			fmt.Printf("Try: redfishcli sysinfo -t %s -n %s -u %s -p <password>\n", profile.BMCType, srv.Host, username)
+		}
+
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+		<-stop
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(emulateCmd)
+	emulateCmd.Flags().StringVar(&emulateProfile, "profile", "idrac", fmt.Sprintf("built-in profile to serve %v", emulator.ProfileNames()))
+	emulateCmd.Flags().StringVar(&emulateMockup, "mockup", "", "serve this mockup directory instead of a built-in profile")
+	emulateCmd.Flags().StringVar(&emulateListen, "listen", "127.0.0.1:8443", "address to listen on")
+	emulateCmd.Flags().DurationVar(&emulateSELInterval, "sel-interval", 0, "append a system event log entry at this interval (0 disables)")
+	emulateCmd.Flags().StringArrayVar(&emulateFaults, "fault", nil, "inject a fault into matching requests (repeatable), e.g. 'Disk.Bay.1$:status=500'")
+}
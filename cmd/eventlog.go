@@ -4,70 +4,108 @@ Copyright © 2024 Angel Vargas <angelvargas@outlook.es>
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"os"
 
 	"github.com/angelhvargas/redfishcli/pkg/client"
 	"github.com/angelhvargas/redfishcli/pkg/config"
-	"github.com/angelhvargas/redfishcli/pkg/logger"
+	"github.com/angelhvargas/redfishcli/pkg/fleet"
 	"github.com/angelhvargas/redfishcli/pkg/model"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
 
+var (
+	eventlogOutput string
+	eventlogRaw    bool
+)
+
+// eventlogReport is the event log of one server, or why it could not be
+// read.
+type eventlogReport struct {
+	Hostname string           `json:"hostname" yaml:"hostname"`
+	Entries  []model.LogEntry `json:"entries" yaml:"entries"`
+	Error    *fleet.HostError `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
 // eventlogCmd represents the eventlog command
 var eventlogCmd = &cobra.Command{
 	Use:   "eventlog",
 	Short: "Get system event logs",
 	Long:  `Retrieve the System Event Log (SEL).`,
-	Run: func(cmd *cobra.Command, args []string) {
-		cfg, err := config.LoadConfigOrEnv(cfgFile, bmcType, bmcUsername, bmcPassword, bmcHost)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadServers(bmcHost)
 		if err != nil {
-			logger.Log.Error(err.Error())
-			os.Exit(1)
+			return err
 		}
+		cmd.SilenceUsage = true
 
-		for _, server := range cfg.Servers {
-			fmt.Printf("--- Event Logs for %s ---\n", server.Hostname)
-			c, err := client.NewClient(server.Type, config.BMCConnConfig{
-				Hostname: server.Hostname,
-				Username: server.Username,
-				Password: server.Password,
-			})
+		reports := make([]eventlogReport, 0)
+		summary := runFleet(cfg.Servers, func(ctx context.Context, server config.ServerConfig) ([]model.LogEntry, error) {
+			c, err := newServerClient(ctx, server)
 			if err != nil {
-				logger.Log.Errorf("Error creating client: %s", err)
-				continue
+				return nil, err
 			}
-
-			logs, err := c.GetSystemEventLog()
+			lr, err := client.Logs(c)
 			if err != nil {
-				logger.Log.Errorf("Error getting event logs: %s", err)
-				continue
+				return nil, err
 			}
-
-			printEventLogs(logs)
-		}
+			logs, err := lr.GetSystemEventLog()
+			return logs, client.Explain(c, client.CapabilityLogs, err)
+		}, func(r fleet.Result[[]model.LogEntry]) {
+			if !eventlogRaw && eventlogOutput != "json" && eventlogOutput != "yaml" {
+				// The text output streams; the summary on stderr says why
+				// a log could not be read.
+				if r.Err == nil {
+					fmt.Printf("--- Event Logs for %s ---\n", r.Server.Hostname)
+					printEventLogText(r.Value)
+				}
+				return
+			}
+			entries := r.Value
+			if entries == nil {
+				entries = []model.LogEntry{}
+			}
+			reports = append(reports, eventlogReport{Hostname: r.Server.Hostname, Entries: entries, Error: fleet.Describe(r.Err)})
+		})
+		printEventLogs(reports)
+		return fleetError(summary)
 	},
 }
 
-func printEventLogs(logs []model.EventLogEntry) {
-	switch output {
-	case "json":
-		data, _ := json.MarshalIndent(logs, "", "  ")
+// printEventLogs prints the logs of every server as one document; the text
+// output is printed as the logs arrive.
+func printEventLogs(reports []eventlogReport) {
+	switch {
+	case eventlogRaw:
+		payloads := make([]json.RawMessage, 0)
+		for _, r := range reports {
+			for _, entry := range r.Entries {
+				payloads = append(payloads, entry.Raw)
+			}
+		}
+		printRaw(payloads)
+	case eventlogOutput == "json":
+		data, _ := json.MarshalIndent(reports, "", "  ")
 		fmt.Println(string(data))
-	case "yaml":
-		data, _ := yaml.Marshal(logs)
+	case eventlogOutput == "yaml":
+		data, _ := yaml.Marshal(reports)
 		fmt.Println(string(data))
-	default:
-		// Simple text output
-		for _, entry := range logs {
-			fmt.Printf("[%s] %s: %s (Severity: %s)\n", entry.Created, entry.EntryType, entry.Message, entry.Severity)
+	}
+}
+
+func printEventLogText(logs []model.LogEntry) {
+	for _, entry := range logs {
+		fmt.Printf("[%s] %s: %s (Severity: %s)\n", entry.Created, entry.EntryType, entry.Message, entry.Severity)
+		if entry.Resolution != "" {
+			fmt.Printf("    Resolution: %s\n", entry.Resolution)
 		}
 	}
 }
 
 func init() {
 	rootCmd.AddCommand(eventlogCmd)
-	eventlogCmd.PersistentFlags().StringVarP(&output, "output", "o", "text", "Output format (json, yaml, text)")
+	eventlogCmd.PersistentFlags().StringVarP(&eventlogOutput, "output", "o", "text", "Output format (json, yaml, text)")
+	eventlogCmd.Flags().BoolVar(&eventlogRaw, "raw", false, "print the original BMC JSON instead of formatted output")
 }
@@ -2,14 +2,19 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"io"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/angelhvargas/redfishcli/pkg/client"
 	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/angelhvargas/redfishcli/pkg/fleet"
 	"github.com/angelhvargas/redfishcli/pkg/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func TestEventLogCmd(t *testing.T) {
@@ -64,3 +69,65 @@ servers:
 	assert.Contains(t, output, "Critical")
 	mockClient.AssertExpectations(t)
 }
+
+func TestEventLogCmdResolvesMessages(t *testing.T) {
+	server := startHealthEmulator(t, nil)
+	oldOutput, oldConfig := eventlogOutput, cfgFile
+	oldHost, oldUser, oldPass := bmcHost, bmcUsername, bmcPassword
+	cfgFile = ""
+	t.Cleanup(func() {
+		eventlogOutput, cfgFile = oldOutput, oldConfig
+		bmcHost, bmcUsername, bmcPassword = oldHost, oldUser, oldPass
+	})
+
+	stdout, _, err := runCommand("eventlog", "-n", server.Hostname, "-u", "root", "-p", "calvin", "-o", "json")
+	require.NoError(t, err)
+	var reports []eventlogReport
+	require.NoError(t, json.Unmarshal([]byte(stdout), &reports), "one JSON document per run")
+	require.Len(t, reports, 1)
+	assert.Equal(t, server.Hostname, reports[0].Hostname)
+	assert.Contains(t, stdout, `"hostname": "`+server.Hostname+`"`)
+	assert.Nil(t, reports[0].Error)
+	logs := reports[0].Entries
+	require.Len(t, logs, 3)
+
+	// The BMC sent only the MessageId and MessageArgs of the second entry;
+	// the rest comes from the registry the BMC hosts.
+	assert.Equal(t, "The System Board Inlet Temp temperature is greater than the upper warning threshold of 42 degrees Celsius.", logs[1].Message)
+	assert.Equal(t, model.EventSeverity("Warning"), logs[1].Severity)
+	assert.Contains(t, stdout, `"Resolution": "Review the system operating environment`)
+	assert.Equal(t, "No response action is required.", logs[0].Resolution)
+
+	stdout, _, err = runCommand("eventlog", "-n", server.Hostname, "-u", "root", "-p", "calvin", "-o", "yaml")
+	require.NoError(t, err)
+	reports = nil
+	require.NoError(t, yaml.Unmarshal([]byte(stdout), &reports), "one YAML document per run")
+	require.Len(t, reports, 1)
+	assert.Len(t, reports[0].Entries, 3)
+
+	stdout, _, err = runCommand("eventlog", "-n", server.Hostname, "-u", "root", "-p", "calvin", "-o", "text")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(stdout, "--- Event Logs for "+server.Hostname+" ---\n"))
+	assert.Contains(t, stdout, "    Resolution: No response action is required.\n")
+}
+
+func TestEventLogCmdErrorInDocument(t *testing.T) {
+	oldOutput, oldConfig := eventlogOutput, cfgFile
+	oldHost, oldUser, oldPass := bmcHost, bmcUsername, bmcPassword
+	cfgFile = ""
+	t.Cleanup(func() {
+		eventlogOutput, cfgFile = oldOutput, oldConfig
+		bmcHost, bmcUsername, bmcPassword = oldHost, oldUser, oldPass
+	})
+	server := startHealthEmulator(t, nil)
+
+	stdout, _, err := runCommand("eventlog", "-n", server.Hostname, "-u", "root", "-p", "wrong", "-o", "json")
+	assert.Equal(t, exitFailed, exitCode(err))
+	var reports []eventlogReport
+	require.NoError(t, json.Unmarshal([]byte(stdout), &reports))
+	require.Len(t, reports, 1)
+	assert.Equal(t, server.Hostname, reports[0].Hostname)
+	assert.Empty(t, reports[0].Entries)
+	require.NotNil(t, reports[0].Error)
+	assert.Equal(t, fleet.ClassAuth, reports[0].Error.Class)
+}
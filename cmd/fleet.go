@@ -0,0 +1,235 @@
+/*
+Copyright © 2024 Angel Vargas <angelvargas@outlook.es>
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/angelhvargas/redfishcli/pkg/client"
+	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/angelhvargas/redfishcli/pkg/fleet"
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+)
+
+var (
+	fleetParallel    int
+	fleetTimeout     time.Duration
+	fleetAsCompleted bool
+
+	serverSelector string
+	serverGroups   []string
+	serverLimit    []string
+	serverExclude  []string
+
+	inventorySources []string
+)
+
+// loadConfig loads the servers of --config, or of the context's or the
+// default configuration file when no host is given, or else the one given by
+// the flags and environment, adds those of the --inventory sources and keeps
+// those --selector, --group, --limit and --exclude select.
+func loadConfig(host string) (*config.BMCConfig, error) {
+	path, username, password, selectorFlag := cfgFile, bmcUsername, bmcPassword, serverSelector
+	if host == "" {
+		// A host given on the command line is a server of its own, not one
+		// of the context's or the default configuration.
+		path = config.ConfigPath(cfgFile, activeContext)
+	}
+	if activeContext != nil {
+		if username == "" {
+			username = activeContext.Username
+		}
+		if password == "" {
+			password = activeContext.Password
+		}
+		if selectorFlag == "" {
+			selectorFlag = activeContext.Selector
+		}
+	}
+	if askPass {
+		var err error
+		if password, err = promptPassword(); err != nil {
+			return nil, err
+		}
+	}
+	secrets = &config.Secrets{}
+
+	cfg, err := config.LoadConfigOrEnv(path, bmcType, username, password, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(inventorySources) > 0 {
+		specs := make([]config.SourceSpec, len(inventorySources))
+		for i, s := range inventorySources {
+			if specs[i], err = config.ParseSourceSpec(s); err != nil {
+				return nil, err
+			}
+		}
+		servers, err := config.LoadSources(context.Background(), specs...)
+		if err != nil {
+			return nil, err
+		}
+		for i := range servers {
+			if servers[i].Type == "" {
+				servers[i].Type = bmcType
+			}
+		}
+		config.DefaultCredentials(servers, username, password)
+		cfg.Servers = append(cfg.Servers, servers...)
+	}
+	selector, err := config.ParseSelector(selectorFlag)
+	if err != nil {
+		return nil, fmt.Errorf("--selector: %w", err)
+	}
+	cfg.Servers, err = cfg.Select(config.Filter{Selector: selector, Groups: serverGroups, Limit: serverLimit, Exclude: serverExclude})
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// loadServers is loadConfig for the commands that work on servers: a run
+// that selects none is a configuration error rather than a silent success.
+func loadServers(host string) (*config.BMCConfig, error) {
+	cfg, err := loadConfig(host)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Servers) == 0 {
+		return nil, fmt.Errorf("no servers selected; give a host, use --config or widen --selector, --group, --limit and --exclude")
+	}
+	return cfg, nil
+}
+
+func fleetOptions() fleet.Options {
+	return fleet.Options{Parallel: fleetParallel, Timeout: fleetTimeout, AsCompleted: fleetAsCompleted}
+}
+
+// runFleet runs task on every server as --parallel, --timeout and
+// --as-completed ask, calls emit with each result and prints the summary on
+// stderr. Each server's password is resolved right before its task runs,
+// and ctx is cancelled once the server's time is up. A server whose BMC or
+// backend lacks the capability the task needs is skipped rather than
+// failed.
+func runFleet[T any](servers []config.ServerConfig, task func(context.Context, config.ServerConfig) (T, error), emit func(fleet.Result[T])) fleet.Summary {
+	summary := fleet.Run(context.Background(), servers, fleetOptions(), func(ctx context.Context, server config.ServerConfig) (T, error) {
+		server, err := resolveServer(ctx, server)
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+		value, err := task(ctx, server)
+		if errors.Is(err, client.ErrNotSupported) {
+			err = fleet.Skip(err)
+		}
+		return value, err
+	}, emit)
+	printFleetSummary(os.Stderr, summary)
+	return summary
+}
+
+// Exit codes of the commands, as documented in the README.
+const (
+	exitOK        = 0
+	exitPartial   = 1
+	exitFailed    = 2
+	exitUnhealthy = 3
+)
+
+// exitError ends the command with code.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// exitCode maps the error a command returned to the process exit code. An
+// error that is not an exitError means the command could not run at all.
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	var exit *exitError
+	if errors.As(err, &exit) {
+		return exit.code
+	}
+	return exitFailed
+}
+
+// fleetError is the error of a run on the fleet: a partial failure when
+// some servers failed, a total failure when none succeeded. Skipped servers
+// are not failures.
+func fleetError(s fleet.Summary) error {
+	switch {
+	case s.Failed == 0:
+		return nil
+	case s.Succeeded == 0:
+		return &exitError{code: exitFailed, err: fmt.Errorf("%d of %d servers failed", s.Failed, s.Total())}
+	default:
+		return &exitError{code: exitPartial, err: fmt.Errorf("%d of %d servers failed", s.Failed, s.Total())}
+	}
+}
+
+// unhealthyError reports n problems found on servers that were read
+// successfully, once fleetError found no failure.
+func unhealthyError(n int, format string, args ...interface{}) error {
+	if n == 0 {
+		return nil
+	}
+	return &exitError{code: exitUnhealthy, err: fmt.Errorf(format, args...)}
+}
+
+// printFleetSummary prints how many servers succeeded, failed and were
+// skipped, and why for each that did not succeed. A single server that
+// succeeded needs no summary. The messages of a BMC's error payload are
+// listed with their resolutions, as wrappers format their text before the
+// messages are resolved.
+func printFleetSummary(w io.Writer, s fleet.Summary) {
+	problems := s.Problems()
+	if s.Total() <= 1 && len(problems) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%d servers: %d succeeded, %d failed, %d skipped in %s\n",
+		s.Total(), s.Succeeded, s.Failed, s.Skipped, s.Duration.Round(time.Millisecond))
+	for _, p := range problems {
+		e := fleet.Describe(p.Err)
+		line := fmt.Sprintf("  %s: %s (%s): %s", p.Hostname, p.Status, e.Class, strings.ReplaceAll(e.Message, "\n", "; "))
+		if e.URI != "" {
+			line += " [" + e.URI + "]"
+		}
+		fmt.Fprintln(w, line)
+		var httpErr *httpclient.HTTPError
+		if errors.As(p.Err, &httpErr) {
+			for _, info := range httpErr.ExtendedInfo {
+				if info.Resolution != "" {
+					fmt.Fprintf(w, "    %s: %s Resolution: %s\n", info.MessageID, info.Message, info.Resolution)
+				}
+			}
+		}
+	}
+}
+
+func init() {
+	rootCmd.PersistentFlags().IntVar(&fleetParallel, "parallel", 16, "number of servers worked on at once (0 for all)")
+	rootCmd.PersistentFlags().DurationVar(&fleetTimeout, "timeout", 0, "give up on a server after this long (0 means no limit)")
+	rootCmd.PersistentFlags().StringVarP(&serverSelector, "selector", "l", "", "only servers whose labels match, e.g. 'site=ams,role!=storage'")
+	rootCmd.PersistentFlags().StringSliceVar(&serverGroups, "group", nil, "only servers in any of these groups")
+	rootCmd.PersistentFlags().StringSliceVar(&serverLimit, "limit", nil, "only these servers (hostnames or wildcard patterns)")
+	rootCmd.PersistentFlags().StringSliceVar(&serverExclude, "exclude", nil, "leave out these servers (hostnames or wildcard patterns)")
+	rootCmd.PersistentFlags().StringArrayVar(&inventorySources, "inventory", nil, "also run against the servers of this inventory source, as kind:location (csv, ansible, netbox, dhcp), e.g. ansible:hosts.ini")
+	rootCmd.PersistentFlags().BoolVar(&fleetAsCompleted, "as-completed", false, "print each server's result as soon as it is ready instead of in configuration order")
+}
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/angelhvargas/redfishcli/pkg/client"
+	"github.com/angelhvargas/redfishcli/pkg/fleet"
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintFleetSummary(t *testing.T) {
+	var buf bytes.Buffer
+	printFleetSummary(&buf, fleet.Summary{Succeeded: 1})
+	assert.Empty(t, buf.String(), "a single server that succeeded needs no summary")
+
+	printFleetSummary(&buf, fleet.Summary{
+		Succeeded: 1, Failed: 1, Skipped: 1, Duration: 1500 * time.Millisecond,
+		Hosts: []fleet.HostStatus{
+			{Hostname: "bmc-1", Status: fleet.StatusOK},
+			{Hostname: "bmc-2", Status: fleet.StatusFailed, Err: &httpclient.HTTPError{
+				StatusCode: 400,
+				Message:    "unexpected error",
+				URI:        "/redfish/v1/Systems/1",
+				ExtendedInfo: []httpclient.MessageInfo{
+					{MessageID: "Base.1.8.PropertyValueNotInList", Message: "The value is not allowed.", Resolution: "Choose a listed value."},
+				},
+			}},
+			{Hostname: "bmc-3", Status: fleet.StatusSkipped, Err: fleet.Skip(fmt.Errorf("event logs %w", client.ErrNotSupported))},
+		},
+	})
+	out := buf.String()
+	assert.Contains(t, out, "3 servers: 1 succeeded, 1 failed, 1 skipped in 1.5s\n")
+	assert.Contains(t, out, "  bmc-2: failed (bmc-error): HTTP 400: unexpected error: The value is not allowed. [/redfish/v1/Systems/1]\n")
+	assert.Contains(t, out, "    Base.1.8.PropertyValueNotInList: The value is not allowed. Resolution: Choose a listed value.\n")
+	assert.Contains(t, out, "  bmc-3: skipped (unsupported): event logs not supported on this BMC\n")
+	assert.NotContains(t, out, "bmc-1")
+}
+
+func TestFleetFlags(t *testing.T) {
+	_, dellHost, lenovoHost := writeFleetConfig(t)
+
+	// A BMC that accepts connections but never answers.
+	hung, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { hung.Close() })
+	go func() {
+		for {
+			conn, err := hung.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { conn.Close() })
+		}
+	}()
+
+	configFile := "config_test_fleet.yaml"
+	content := fmt.Sprintf(`
+servers:
+  - type: idrac
+    hostname: %s
+    username: root
+    password: calvin
+  - type: idrac
+    hostname: %s
+    username: root
+    password: calvin
+  - type: xclarity
+    hostname: %s
+    username: root
+    password: calvin
+`, hung.Addr(), dellHost, lenovoHost)
+	require.NoError(t, os.WriteFile(configFile, []byte(content), 0644))
+	t.Cleanup(func() { os.Remove(configFile) })
+	t.Cleanup(func() {
+		fleetParallel, fleetTimeout, fleetAsCompleted = 16, 0, false
+	})
+
+	start := time.Now()
+	stdout, stderr, err := runCommand("sysinfo", "--config", configFile, "--parallel", "2", "--timeout", "300ms")
+	assert.EqualError(t, err, "1 of 3 servers failed")
+	assert.Equal(t, exitPartial, exitCode(err))
+	// The run waits for every task to return, so this only holds when the
+	// request to the hung server is cancelled with its context.
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	// The hung server does not hold back the others and is reported once
+	// it timed out.
+	assert.Contains(t, stdout, "Manufacturer: Dell Inc.")
+	assert.Contains(t, stdout, "Manufacturer: Lenovo")
+	assert.Contains(t, stderr, "3 servers: 2 succeeded, 1 failed, 0 skipped")
+	assert.Contains(t, stderr, fmt.Sprintf("  %s: failed (timeout): timed out after 300ms\n", hung.Addr()))
+}
+
+func TestServerSelection(t *testing.T) {
+	_, dellHost, lenovoHost := writeFleetConfig(t)
+	configFile := "config_test_selection.yaml"
+	content := fmt.Sprintf(`
+groups:
+  lenovo: [%q]
+servers:
+  - type: idrac
+    hostname: %s
+    username: root
+    password: calvin
+    labels: {site: ams, role: compute}
+    groups: [canary]
+  - type: xclarity
+    hostname: %s
+    username: root
+    password: calvin
+    labels: {site: ams, role: storage}
+`, lenovoHost, dellHost, lenovoHost)
+	require.NoError(t, os.WriteFile(configFile, []byte(content), 0644))
+	t.Cleanup(func() { os.Remove(configFile) })
+	// The flags keep their values between runs.
+	reset := func() {
+		serverSelector = ""
+		for _, name := range []string{"group", "limit", "exclude"} {
+			rootCmd.PersistentFlags().Lookup(name).Value.(pflag.SliceValue).Replace(nil)
+		}
+	}
+	t.Cleanup(reset)
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--selector", "site=ams,role!=storage"}, "Dell"},
+		{[]string{"--group", "canary"}, "Dell"},
+		{[]string{"--group", "lenovo"}, "Lenovo"},
+		{[]string{"--limit", "127.0.0.1:*", "--exclude", dellHost}, "Lenovo"},
+	}
+	for _, tt := range tests {
+		stdout, _, err := runCommand(append([]string{"sysinfo", "--config", configFile}, tt.args...)...)
+		require.NoError(t, err, tt.args)
+		assert.Equal(t, 1, strings.Count(stdout, "Manufacturer:"), tt.args)
+		assert.Contains(t, stdout, "Manufacturer: "+tt.want, tt.args)
+		reset()
+	}
+
+	_, _, err := runCommand("sysinfo", "--config", configFile, "--group", "web")
+	assert.EqualError(t, err, `unknown group "web"`)
+	assert.Equal(t, exitFailed, exitCode(err))
+}
+
+func TestNoServers(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("REDFISHCLI_CONTEXTS", "")
+	t.Setenv("REDFISHCLI_CONTEXT", "")
+	oldConfig, oldHost := cfgFile, bmcHost
+	cfgFile, bmcHost = "", ""
+	t.Cleanup(func() { cfgFile, bmcHost = oldConfig, oldHost })
+
+	// A run without servers fails instead of succeeding silently.
+	for _, args := range [][]string{{"sysinfo"}, {"storage", "raid", "health"}, {"power", "status"}} {
+		stdout, _, err := runCommand(args...)
+		assert.ErrorContains(t, err, "no servers selected", args)
+		assert.Equal(t, exitFailed, exitCode(err), args)
+		assert.Empty(t, stdout, args)
+	}
+}
+
+func TestInventoryFlag(t *testing.T) {
+	_, dellHost, lenovoHost := writeFleetConfig(t)
+	inventory := filepath.Join(t.TempDir(), "servers.csv")
+	require.NoError(t, os.WriteFile(inventory, []byte(fmt.Sprintf("hostname,type,site\n%s,,ams\n%s,xclarity,fra\n", dellHost, lenovoHost)), 0644))
+	// Run without a configuration file, which earlier tests leave set.
+	cfgFile = ""
+	// The flags keep their values between runs.
+	reset := func() {
+		bmcUsername, bmcPassword, serverSelector = "", "", ""
+		rootCmd.PersistentFlags().Lookup("inventory").Value.(pflag.SliceValue).Replace(nil)
+	}
+	t.Cleanup(reset)
+
+	stdout, _, err := runCommand("sysinfo", "--inventory", "csv:"+inventory, "-u", "root", "-p", "calvin")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Manufacturer: Dell Inc.", "the server without a type gets --bmc-type")
+	assert.Contains(t, stdout, "Manufacturer: Lenovo")
+	reset()
+
+	stdout, _, err = runCommand("sysinfo", "--inventory", "csv:"+inventory, "--selector", "site=fra", "-u", "root", "-p", "calvin")
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(stdout, "Manufacturer:"))
+	assert.Contains(t, stdout, "Manufacturer: Lenovo")
+	reset()
+
+	_, _, err = runCommand("sysinfo", "--inventory", "servers.csv")
+	assert.EqualError(t, err, `invalid inventory source "servers.csv", want kind:location`)
+}
@@ -1,23 +1,22 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"sync"
-	"time"
 
 	"github.com/angelhvargas/redfishcli/pkg/client"
 	"github.com/angelhvargas/redfishcli/pkg/config"
-	"github.com/angelhvargas/redfishcli/pkg/logger"
-	"github.com/angelhvargas/redfishcli/pkg/model"
+	"github.com/angelhvargas/redfishcli/pkg/fleet"
+	"github.com/angelhvargas/redfishcli/pkg/inventory"
 	"github.com/angelhvargas/redfishcli/pkg/tableprinter"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
 
 var (
-	drives  bool
-	timeout time.Duration
+	drives       bool
+	healthOutput string
 )
 
 // healthCmd represents the health command
@@ -63,45 +62,37 @@ servers:
 To use the configuration file, simply run:
   redfishcli storage raid health --drives
 redfishcli will automatically load the servers listed in the configuration file and scan their health.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		cfg, err := config.LoadConfigOrEnv(cfgFile, bmcType, bmcUsername, bmcPassword, bmcHost)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadServers(bmcHost)
 		if err != nil {
-			logger.Log.Error(err.Error())
-			return
+			return err
 		}
-		servers := cfg.Servers
-
-		var wg sync.WaitGroup
-		healthReportsCh := make(chan *model.RAIDHealthReport, len(servers))
-		errorsCh := make(chan error, len(servers))
-
-		for _, server := range servers {
-			wg.Add(1)
-			go processServer(&wg, server, healthReportsCh, errorsCh)
-		}
-
-		wg.Wait()
-		close(healthReportsCh)
-		close(errorsCh)
-
-		var healthReports []*model.RAIDHealthReport
-		for report := range healthReportsCh {
+		cmd.SilenceUsage = true
+		var healthReports []*raidHealthReport
+		unhealthy := 0
+		summary := runFleet(cfg.Servers, serverHealthReport, func(r fleet.Result[*raidHealthReport]) {
+			report := r.Value
+			if report == nil {
+				report = unknownHealthReport(r.Server.Hostname)
+			}
+			report.Error = fleet.Describe(r.Err)
+			if r.Err == nil && report.HealthStatus != "" && report.HealthStatus != string(inventory.HealthOK) {
+				unhealthy++
+			}
 			healthReports = append(healthReports, report)
-		}
+		})
 
-		switch output {
+		switch healthOutput {
 		case "json":
 			jsonData, err := json.Marshal(healthReports)
 			if err != nil {
-				logger.Log.Error(err.Error())
-				return
+				return err
 			}
 			fmt.Println(string(jsonData))
 		case "yaml":
 			yamlData, err := yaml.Marshal(healthReports)
 			if err != nil {
-				logger.Log.Error(err.Error())
-				return
+				return err
 			}
 			fmt.Println(string(yamlData))
 		case "table":
@@ -115,96 +106,107 @@ redfishcli will automatically load the servers listed in the configuration file
 			}
 			tableprinter.PrintTable(healthReports, headers, fields, nestedConfig, 0)
 		default:
-			logger.Log.Errorf("Unsupported output format: %s", output)
+			return fmt.Errorf("unsupported output format: %s", healthOutput)
 		}
-
-		for err := range errorsCh {
-			logger.Log.Error(err.Error())
+		if err := fleetError(summary); err != nil {
+			return err
 		}
+		return unhealthyError(unhealthy, "%d servers have RAID controllers that are not healthy", unhealthy)
 	},
 }
 
-func processServer(wg *sync.WaitGroup, server config.ServerConfig, healthReportsCh chan<- *model.RAIDHealthReport, errorsCh chan<- error) {
-	defer wg.Done()
-
+// serverHealthReport gathers the RAID health report of one server. A report
+// is returned even on failure, with the error recorded in it.
+func serverHealthReport(ctx context.Context, server config.ServerConfig) (*raidHealthReport, error) {
 	// Create client using the registry
-	bmcClient, err := client.NewClient(server.Type, config.BMCConnConfig{
-		Hostname: server.Hostname,
-		Username: server.Username,
-		Password: server.Password,
-	})
+	bmcClient, err := newServerClient(ctx, server)
 	if err != nil {
-		logger.Log.Errorf("Error creating client for server %s: %s", server.Hostname, err)
-		errorsCh <- err
-		return
+		report := unknownHealthReport(server.Hostname)
+		report.Error = fleet.Describe(err)
+		return report, err
 	}
 
 	report, err := gatherHealthReport(bmcClient, server.Hostname)
 	if err != nil {
-		logger.Log.Error(err.Error())
-		errorsCh <- err
-		// If there is an error, create a report with "unknown" state
-		report = &model.RAIDHealthReport{
-			Hostname:     server.Hostname,
-			State:        "unknown",
-			HealthStatus: "unknown",
+		if report == nil {
+			// Nothing could be gathered, create a report with "unknown" state
+			report = unknownHealthReport(server.Hostname)
 		}
+		report.Error = fleet.Describe(err)
 	}
-	healthReportsCh <- report
+	return report, err
 }
 
-func gatherHealthReport(bmcClient client.ServerClient, hostname string) (*model.RAIDHealthReport, error) {
-	serverStatus, err := bmcClient.GetServerInfo()
-	if err != nil {
+// unknownHealthReport is the report of a server nothing could be read from;
+// its Error says why.
+func unknownHealthReport(hostname string) *raidHealthReport {
+	return &raidHealthReport{Hostname: hostname, State: "unknown", HealthStatus: "unknown"}
+}
+
+// raidHealthReport is the RAID health of one server.
+type raidHealthReport struct {
+	ID           string            `json:"id" yaml:"id"`
+	Name         string            `json:"name" yaml:"name"`
+	HealthStatus string            `json:"health_status" yaml:"health_status"`
+	State        string            `json:"state" yaml:"state"`
+	Drives       []inventory.Drive `json:"drives" yaml:"drives"`
+	DrivesCount  int               `json:"drives_count" yaml:"drives_count"`
+	Hostname     string            `json:"hostname" yaml:"hostname"`
+	// Error is set when the report is partial or unknown because some
+	// requests failed, and says why.
+	Error *fleet.HostError `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// gatherHealthReport reads the server's storage inventory: the RAID
+// controllers and, with --drives, their member drives. Failures after the
+// controller list has been read do not discard what was already gathered:
+// the partial report is returned along with the joined errors.
+func gatherHealthReport(bmcClient client.ServerClient, hostname string) (*raidHealthReport, error) {
+	inv := &inventory.Inventory{Hostname: hostname}
+	if err := inv.Read(bmcClient, inventory.PartSystem); err != nil {
 		return nil, err
 	}
 
-	if serverStatus.PowerState != "On" {
-		return nil, fmt.Errorf("host %s: server is not powered on", hostname)
+	if inv.System.PowerState != inventory.PowerOn {
+		return nil, fleet.Skip(fmt.Errorf("host %s: server is not powered on", hostname))
 	}
-	config := &model.StorageControllerConfig{
-		Type: "RAID",
+	parts := inventory.PartStorage
+	if drives {
+		parts |= inventory.PartDrives
 	}
-	controllers, err := bmcClient.GetStorageControllers(config)
-	if err != nil {
+	err := inv.Read(bmcClient, parts)
+	if inv.Controllers == nil {
+		// The controllers could not even be listed.
 		return nil, err
 	}
 
-	healthReport := &model.RAIDHealthReport{
+	healthReport := &raidHealthReport{
 		Hostname: hostname,
-		Drives:   []model.Drive{},
+		Drives:   []inventory.Drive{},
 	}
-
-	for _, controller := range controllers {
-		raidCtrldetails, err := bmcClient.GetStorageControllerInfo(controller.ID)
-		if err != nil {
-			return nil, err
-		}
-
-		healthReport.ID = raidCtrldetails.ID
-		healthReport.Name = raidCtrldetails.Name
-		healthReport.HealthStatus = raidCtrldetails.Status.Health
-		healthReport.State = raidCtrldetails.Status.State
-
+	for _, ctrl := range inv.Controllers {
+		healthReport.ID = ctrl.ID
+		healthReport.Name = ctrl.Name
+		healthReport.HealthStatus = string(ctrl.Status.Health)
+		healthReport.State = string(ctrl.Status.State)
 		if drives {
-			for _, driveRef := range raidCtrldetails.Drives {
-				if len(driveRef.ID) > 0 {
-					driveDetails, err := bmcClient.GetStorageDriveDetails(driveRef.ID)
-					if err != nil {
-						return nil, err
-					}
-					healthReport.Drives = append(healthReport.Drives, *driveDetails)
-				}
-			}
-			healthReport.DrivesCount = int8(len(raidCtrldetails.Drives))
+			healthReport.Drives = append(healthReport.Drives, ctrl.Drives...)
+			healthReport.DrivesCount = ctrl.DriveCount
 		}
 	}
 
+	if err != nil {
+		if healthReport.HealthStatus == "" {
+			healthReport.HealthStatus = "unknown"
+			healthReport.State = "unknown"
+		}
+		return healthReport, fmt.Errorf("host %s: %w", hostname, err)
+	}
 	return healthReport, nil
 }
 
 func init() {
 	raidCmd.AddCommand(healthCmd)
 	healthCmd.PersistentFlags().BoolVarP(&drives, "drives", "", false, "return RAID controller member drives health")
-	healthCmd.PersistentFlags().DurationVarP(&timeout, "timeout", "", 60*time.Second, "Timeout duration for each server")
+	healthCmd.PersistentFlags().StringVarP(&healthOutput, "output", "o", "json", "Output format (json, yaml, table)")
 }
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/angelhvargas/redfishcli/pkg/client"
+	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/angelhvargas/redfishcli/pkg/emulator"
+	"github.com/angelhvargas/redfishcli/pkg/fault"
+	"github.com/angelhvargas/redfishcli/pkg/fleet"
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+	"github.com/angelhvargas/redfishcli/pkg/idrac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	diskBay0 = "/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1/Drives/Disk.Bay.0"
+	diskBay1 = "/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1/Drives/Disk.Bay.1"
+)
+
+func startHealthEmulator(t *testing.T, faults *fault.Injector) config.ServerConfig {
+	emu, err := emulator.New(emulator.IDRAC(), emulator.Options{Username: "root", Password: "calvin"})
+	require.NoError(t, err)
+	srv, err := emu.Start("127.0.0.1:0", emulator.StartOptions{Faults: faults})
+	require.NoError(t, err)
+	t.Cleanup(func() { srv.Close() })
+
+	client.ResetRegistry()
+	client.Register("idrac", func(cfg config.BMCConnConfig) client.ServerClient {
+		return idrac.NewClient(config.IDRACConfig{BMCConnConfig: cfg})
+	})
+	return config.ServerConfig{Type: "idrac", Hostname: srv.Host, Username: "root", Password: "calvin"}
+}
+
+// runHealthTask runs serverHealthReport for one server with --drives and
+// the given HTTP pipeline.
+func runHealthTask(t *testing.T, server config.ServerConfig, cfg httpclient.Config) (*raidHealthReport, error) {
+	oldDrives, oldHTTP := drives, httpConfig
+	drives, httpConfig = true, &cfg
+	t.Cleanup(func() { drives, httpConfig = oldDrives, oldHTTP })
+
+	return serverHealthReport(context.Background(), server)
+}
+
+func withFault(rule fault.Rule) httpclient.Config {
+	return httpclient.DefaultConfig().Use(fault.New(rule).Middleware())
+}
+
+func driveIDs(report *raidHealthReport) []string {
+	var ids []string
+	for _, d := range report.Drives {
+		ids = append(ids, d.ID)
+	}
+	return ids
+}
+
+func TestHealthTaskFaults(t *testing.T) {
+	server := startHealthEmulator(t, nil)
+
+	t.Run("No faults", func(t *testing.T) {
+		report, err := runHealthTask(t, server, httpclient.DefaultConfig())
+		require.NoError(t, err)
+		assert.Equal(t, "RAID.Integrated.1-1", report.ID)
+		assert.Equal(t, "OK", report.HealthStatus)
+		assert.Equal(t, []string{"Disk.Bay.0", "Disk.Bay.1"}, driveIDs(report))
+		assert.EqualValues(t, 2, report.DrivesCount)
+		assert.Nil(t, report.Error)
+	})
+
+	t.Run("Latency beyond the timeout on one drive", func(t *testing.T) {
+		cfg := withFault(fault.Rule{Path: regexp.MustCompile(`Disk\.Bay\.1$`), Latency: 2 * time.Second})
+		cfg.Timeout = 200 * time.Millisecond
+
+		report, err := runHealthTask(t, server, cfg)
+		require.Error(t, err)
+		assert.Equal(t, "OK", report.HealthStatus)
+		assert.Equal(t, []string{"Disk.Bay.0"}, driveIDs(report))
+		assert.EqualValues(t, 2, report.DrivesCount)
+		assert.Contains(t, report.Error.Message, "Disk.Bay.1")
+	})
+
+	t.Run("Server error half-way through the drive walk", func(t *testing.T) {
+		report, err := runHealthTask(t, server, withFault(fault.Rule{Path: regexp.MustCompile(`/Drives/`), After: 1, Status: 500}))
+		require.Error(t, err)
+		assert.Equal(t, []string{"Disk.Bay.0"}, driveIDs(report))
+		assert.Contains(t, report.Error.Message, "HTTP 500")
+		assert.Equal(t, fleet.ClassBMCError, report.Error.Class)
+		assert.Contains(t, report.Error.URI, "/Drives/")
+	})
+
+	t.Run("Truncated JSON for one drive", func(t *testing.T) {
+		report, err := runHealthTask(t, server, withFault(fault.Rule{Path: regexp.MustCompile(`Disk\.Bay\.0$`), Truncate: true}))
+		require.Error(t, err)
+		assert.Equal(t, []string{"Disk.Bay.1"}, driveIDs(report))
+		assert.Contains(t, report.Error.Message, "Disk.Bay.0")
+	})
+
+	t.Run("TLS failure on the controller", func(t *testing.T) {
+		report, err := runHealthTask(t, server, withFault(fault.Rule{Path: regexp.MustCompile(`RAID\.Integrated\.1-1$`), TLSFailure: true}))
+		require.Error(t, err)
+		assert.ErrorIs(t, err, fault.ErrTLS)
+		assert.Equal(t, server.Hostname, report.Hostname)
+		assert.Equal(t, "unknown", report.HealthStatus)
+		assert.Empty(t, report.Drives)
+	})
+
+	t.Run("Dropped connection on the system resource", func(t *testing.T) {
+		report, err := runHealthTask(t, server, withFault(fault.Rule{Path: regexp.MustCompile(`System\.Embedded\.1$`), Drop: true}))
+		require.Error(t, err)
+		assert.Equal(t, "unknown", report.State)
+		assert.Equal(t, "unknown", report.HealthStatus)
+		assert.Nil(t, report.Drives)
+		require.NotNil(t, report.Error)
+		assert.Equal(t, fleet.ClassUnreachable, report.Error.Class)
+	})
+
+	t.Run("Credentials expire during the drive walk", func(t *testing.T) {
+		report, err := runHealthTask(t, server, withFault(fault.Rule{Path: regexp.MustCompile(`/Drives/`), AuthExpiry: true}))
+		require.Error(t, err)
+		assert.ErrorIs(t, err, httpclient.ErrAuthentication)
+		assert.Equal(t, fleet.ClassAuth, report.Error.Class)
+		assert.Equal(t, "OK", report.HealthStatus)
+		assert.Empty(t, report.Drives)
+		assert.EqualValues(t, 2, report.DrivesCount)
+	})
+}
+
+func TestHealthTaskEmulatorFaults(t *testing.T) {
+	server := startHealthEmulator(t, fault.New(
+		fault.Rule{Path: regexp.MustCompile(`Disk\.Bay\.0$`), Drop: true},
+		fault.Rule{Path: regexp.MustCompile(`Disk\.Bay\.1$`), Truncate: true},
+	))
+
+	report, err := runHealthTask(t, server, httpclient.DefaultConfig())
+	require.Error(t, err)
+	assert.Equal(t, "RAID.Integrated.1-1", report.ID)
+	assert.Empty(t, report.Drives)
+	assert.Contains(t, report.Error.Message, "Disk.Bay.0")
+	assert.Contains(t, report.Error.Message, "Disk.Bay.1")
+}
+
+func TestHealthCmdErrors(t *testing.T) {
+	server := startHealthEmulator(t, nil)
+	oldOutput := healthOutput
+	t.Cleanup(func() { healthOutput = oldOutput })
+
+	configFile := "config_test_health.yaml"
+	content := fmt.Sprintf(`
+servers:
+  - type: idrac
+    hostname: %s
+    username: root
+    password: calvin
+  - type: idrac
+    hostname: %s
+    username: root
+    password: wrong
+`, server.Hostname, server.Hostname)
+	require.NoError(t, os.WriteFile(configFile, []byte(content), 0644))
+	t.Cleanup(func() { os.Remove(configFile) })
+
+	stdout, stderr, err := runCommand("storage", "raid", "health", "--config", configFile, "-o", "json")
+	assert.EqualError(t, err, "1 of 2 servers failed")
+	assert.Equal(t, exitPartial, exitCode(err))
+
+	// The server that failed is reported with why, not just as unknown.
+	var reports []raidHealthReport
+	require.NoError(t, json.Unmarshal([]byte(stdout), &reports))
+	require.Len(t, reports, 2)
+	assert.Nil(t, reports[0].Error)
+	assert.Equal(t, "OK", reports[0].HealthStatus)
+	assert.Equal(t, "unknown", reports[1].HealthStatus)
+	require.NotNil(t, reports[1].Error)
+	assert.Equal(t, fleet.ClassAuth, reports[1].Error.Class)
+	assert.Equal(t, "/redfish/v1/Systems/System.Embedded.1", reports[1].Error.URI)
+	assert.Contains(t, stderr, "2 servers: 1 succeeded, 1 failed, 0 skipped")
+}
+
+func TestHealthCmdDefaultOutput(t *testing.T) {
+	server := startHealthEmulator(t, nil)
+	// Start from the documented default, as a fresh process does, whatever
+	// the other commands' --output defaults are.
+	flag := healthCmd.PersistentFlags().Lookup("output")
+	require.Equal(t, "json", flag.DefValue)
+	oldOutput, oldConfig := healthOutput, cfgFile
+	healthOutput, cfgFile = flag.DefValue, ""
+	t.Cleanup(func() {
+		healthOutput, cfgFile = oldOutput, oldConfig
+		bmcHost, bmcUsername, bmcPassword = "", "", ""
+	})
+
+	stdout, _, err := runCommand("storage", "raid", "health", "-n", server.Hostname, "-u", "root", "-p", "calvin")
+	require.NoError(t, err)
+	var reports []raidHealthReport
+	require.NoError(t, json.Unmarshal([]byte(stdout), &reports))
+	require.Len(t, reports, 1)
+	assert.Equal(t, "OK", reports[0].HealthStatus)
+}
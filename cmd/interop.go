@@ -0,0 +1,260 @@
+/*
+Copyright © 2024 Angel Vargas <angelvargas@outlook.es>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/angelhvargas/redfishcli/pkg/emulator"
+	"github.com/angelhvargas/redfishcli/pkg/fleet"
+	"github.com/angelhvargas/redfishcli/pkg/interop"
+	"github.com/angelhvargas/redfishcli/pkg/mockup"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	interopProfile string
+	interopMockup  string
+	interopOutput  string
+)
+
+// interopReport is the profile check of one BMC or mockup.
+type interopReport struct {
+	Hostname        string `json:"hostname" yaml:"hostname"`
+	*interop.Report `yaml:",inline"`
+	// Error is set when the BMC could not be read.
+	Error *fleet.HostError `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// interopCmd represents the interop command
+var interopCmd = &cobra.Command{
+	Use:   "interop [host]",
+	Short: "Check a BMC against a Redfish interoperability profile",
+	Long: `Evaluate a BMC against a DMTF Redfish interoperability profile (DSP0272), such
+as the OCP baseline hardware management profile.
+
+Every resource of the BMC is read once, then each requirement of the profile is
+checked: ReadRequirement, MinVersion, MinCount, Values with their Comparison,
+MinSupportValues, ActionRequirements with their parameter values, and
+ConditionalRequirements. Each requirement passes, fails, warns (Recommended
+requirements that are not met) or is skipped (IfImplemented requirements the
+BMC does not implement, or values it does not advertise).
+
+--mockup checks a mockup directory captured with "redfishcli mockup capture"
+instead of a live BMC.
+
+Output formats: table (default), json, yaml and junit. The command fails when
+any requirement fails.
+
+Example:
+  redfishcli interop 192.168.1.100 -u root -p calvin --profile OCPBaselineHardwareManagement.v1_0_1.json
+  redfishcli interop --mockup ./r740 --profile ocp-baseline.json -o junit > interop.xml`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile, err := interop.Load(interopProfile)
+		if err != nil {
+			return err
+		}
+
+		var (
+			reports []interopReport
+			summary fleet.Summary
+		)
+		if interopMockup != "" {
+			resources, err := emulator.LoadMockup(os.DirFS(interopMockup))
+			if err != nil {
+				return err
+			}
+			reports = append(reports, interopReport{Hostname: interopMockup, Report: interop.Check(profile, resources)})
+		} else {
+			host := bmcHost
+			if len(args) == 1 {
+				host = args[0]
+			}
+			cfg, err := loadConfig(host)
+			if err != nil {
+				return err
+			}
+			if len(cfg.Servers) == 0 {
+				return fmt.Errorf("no servers configured; give a host, --mockup or --config")
+			}
+			cmd.SilenceUsage = true
+			summary = runFleet(cfg.Servers, func(ctx context.Context, server config.ServerConfig) (*interop.Report, error) {
+				svc, err := crawlService(ctx, server)
+				if err != nil {
+					return nil, err
+				}
+				return interop.Check(profile, svc), nil
+			}, func(r fleet.Result[*interop.Report]) {
+				reports = append(reports, interopReport{Hostname: r.Server.Hostname, Report: r.Value, Error: fleet.Describe(r.Err)})
+			})
+		}
+
+		if err := printInterop(reports); err != nil {
+			return err
+		}
+		if err := fleetError(summary); err != nil {
+			return err
+		}
+		failed := 0
+		for _, r := range reports {
+			if r.Report != nil {
+				failed += r.Failed
+			}
+		}
+		cmd.SilenceUsage = true
+		return unhealthyError(failed, "%d interoperability requirements failed", failed)
+	},
+}
+
+// crawlService reads every resource of a BMC.
+func crawlService(ctx context.Context, server config.ServerConfig) (interop.Service, error) {
+	svc := make(interop.Service)
+	_, err := mockup.Crawl(mockup.Options{
+		Host:       server.Hostname,
+		Username:   server.Username,
+		Password:   server.Password,
+		HTTP:       serverHTTPConfig(ctx),
+		UseSession: true,
+	}, func(uri string, doc interface{}, body []byte, header http.Header) error {
+		if m, ok := doc.(map[string]interface{}); ok {
+			svc[uri] = m
+		}
+		return nil
+	})
+	return svc, err
+}
+
+func printInterop(reports []interopReport) error {
+	switch interopOutput {
+	case "json":
+		data, _ := json.MarshalIndent(reports, "", "  ")
+		fmt.Println(string(data))
+	case "yaml":
+		data, _ := yaml.Marshal(reports)
+		fmt.Println(string(data))
+	case "junit":
+		data, err := xml.MarshalIndent(junitReport(reports), "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(xml.Header + string(data))
+	case "table", "text":
+		for _, r := range reports {
+			if r.Report == nil {
+				// The summary on stderr says why.
+				continue
+			}
+			fmt.Printf("%s: %s: %d passed, %d failed, %d warnings, %d skipped\n",
+				r.Hostname, r.Profile, r.Passed, r.Failed, r.Warnings, r.Skipped)
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "RESULT\tRESOURCE\tURI\tPROPERTY\tCHECK\tREQUIREMENT\tMESSAGE")
+			for _, f := range r.Findings {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", strings.ToUpper(string(f.Outcome)),
+					f.Resource, dash(f.URI), dash(f.Property), f.Check, f.Requirement, f.Message)
+			}
+			w.Flush()
+		}
+	default:
+		return fmt.Errorf("unknown output format %q (table, json, yaml, junit)", interopOutput)
+	}
+	return nil
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// JUnit XML, as read by CI systems: one test suite per BMC and one test
+// case per finding. Warnings pass and carry their message as system-out; a
+// BMC that could not be read is a suite with one test case in error.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr,omitempty"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr,omitempty"`
+	Skipped  int             `xml:"skipped,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+}
+
+func junitReport(reports []interopReport) junitTestSuites {
+	var suites junitTestSuites
+	for _, r := range reports {
+		if r.Report == nil {
+			// A BMC that could not be read is one test case in error.
+			suites.Tests++
+			suites.Errors++
+			suites.Suites = append(suites.Suites, junitTestSuite{Name: r.Hostname, Tests: 1, Errors: 1, Cases: []junitTestCase{{
+				ClassName: r.Hostname,
+				Name:      "read service",
+				Error:     &junitMessage{Message: r.Error.Message, Type: string(r.Error.Class)},
+			}}})
+			continue
+		}
+		suites.Name = r.Profile
+		suite := junitTestSuite{Name: r.Hostname, Tests: len(r.Findings), Failures: r.Failed, Skipped: r.Skipped}
+		for _, f := range r.Findings {
+			name := strings.Join(strings.Fields(strings.Join([]string{f.URI, f.Property, f.Check}, " ")), " ")
+			tc := junitTestCase{ClassName: r.Profile + "." + f.Resource, Name: name}
+			switch f.Outcome {
+			case interop.Fail:
+				tc.Failure = &junitMessage{Message: f.Message, Type: string(f.Requirement)}
+			case interop.Skip:
+				tc.Skipped = &junitMessage{Message: f.Message}
+			case interop.Warn:
+				tc.SystemOut = "warning: " + f.Message
+			}
+			suite.Cases = append(suite.Cases, tc)
+		}
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Skipped += suite.Skipped
+		suites.Suites = append(suites.Suites, suite)
+	}
+	return suites
+}
+
+func init() {
+	rootCmd.AddCommand(interopCmd)
+	interopCmd.PersistentFlags().StringVarP(&interopOutput, "output", "o", "table", "Output format (table, json, yaml, junit)")
+	interopCmd.Flags().StringVar(&interopProfile, "profile", "", "interoperability profile document (JSON)")
+	interopCmd.Flags().StringVar(&interopMockup, "mockup", "", "check this mockup directory instead of a live BMC")
+	interopCmd.MarkFlagRequired("profile")
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/angelhvargas/redfishcli/pkg/emulator"
+	"github.com/angelhvargas/redfishcli/pkg/interop"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const interopTestProfile = `{
+	"ProfileName": "ResetBaseline",
+	"ProfileVersion": "1.0.0",
+	"Protocol": {"MinVersion": "1.6"},
+	"Resources": {
+		"ComputerSystem": {
+			"PropertyRequirements": {"PowerState": {}, "Status": {"PropertyRequirements": {"Health": {}}}},
+			"ActionRequirements": {"Reset": {"Parameters": {"ResetType": {"ParameterValues": ["On", "ForceOff", "PowerCycle"]}}}}
+		}
+	}
+}`
+
+func writeInteropProfile(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "profile.json")
+	require.NoError(t, os.WriteFile(path, []byte(interopTestProfile), 0644))
+	return path
+}
+
+func TestInteropCmd(t *testing.T) {
+	server := startHealthEmulator(t, nil)
+	profile := writeInteropProfile(t)
+	oldOutput, oldConfig := interopOutput, cfgFile
+	cfgFile = ""
+	t.Cleanup(func() { interopOutput, cfgFile, interopProfile, interopMockup = oldOutput, oldConfig, "", "" })
+
+	// A table is the default.
+	stdout, _, err := runCommand("interop", server.Hostname, "-u", "root", "-p", "calvin", "--profile", profile)
+	require.NoError(t, err)
+	assert.Contains(t, stdout, server.Hostname+": ResetBaseline 1.0.0: 7 passed, 0 failed, 0 warnings, 0 skipped")
+	assert.Regexp(t, `PASS\s+ComputerSystem\s+/redfish/v1/Systems/System.Embedded.1\s+PowerState\s+ReadRequirement\s+Mandatory\s+present`, stdout)
+
+	stdout, _, err = runCommand("interop", server.Hostname, "-u", "root", "-p", "calvin", "--profile", profile, "-o", "json")
+	require.NoError(t, err)
+	var reports []interopReport
+	require.NoError(t, json.Unmarshal([]byte(stdout), &reports))
+	require.Len(t, reports, 1)
+	assert.Equal(t, server.Hostname, reports[0].Hostname)
+	assert.Equal(t, "ResetBaseline 1.0.0", reports[0].Profile)
+	assert.Zero(t, reports[0].Failed)
+	assert.Equal(t, 7, reports[0].Passed)
+
+}
+
+func TestInteropCmdMockup(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.CopyFS(dir, emulator.XCC().Mockup))
+	profile := writeInteropProfile(t)
+	oldOutput := interopOutput
+	t.Cleanup(func() { interopOutput, interopProfile, interopMockup = oldOutput, "", "" })
+
+	// The XCC does not offer PowerCycle.
+	stdout, _, err := runCommand("interop", "--mockup", dir, "--profile", profile, "-o", "junit")
+	require.EqualError(t, err, "1 interoperability requirements failed")
+	assert.Equal(t, exitUnhealthy, exitCode(err))
+
+	var suites junitTestSuites
+	require.NoError(t, xml.Unmarshal([]byte(stdout), &suites))
+	assert.Equal(t, "ResetBaseline 1.0.0", suites.Name)
+	assert.Equal(t, 7, suites.Tests)
+	assert.Equal(t, 1, suites.Failures)
+	require.Len(t, suites.Suites, 1)
+	assert.Equal(t, dir, suites.Suites[0].Name)
+
+	var failed []junitTestCase
+	for _, tc := range suites.Suites[0].Cases {
+		if tc.Failure != nil {
+			failed = append(failed, tc)
+		}
+	}
+	assert.Equal(t, []junitTestCase{{
+		ClassName: "ResetBaseline 1.0.0.ComputerSystem",
+		Name:      "/redfish/v1/Systems/1 Actions/#ComputerSystem.Reset/ResetType " + interop.CheckParameter,
+		Failure:   &junitMessage{Message: "PowerCycle not allowed", Type: "Mandatory"},
+	}}, failed)
+}
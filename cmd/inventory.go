@@ -0,0 +1,116 @@
+/*
+Copyright © 2024 Angel Vargas <angelvargas@outlook.es>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/angelhvargas/redfishcli/pkg/fleet"
+	"github.com/angelhvargas/redfishcli/pkg/inventory"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var inventoryOutput string
+
+// inventoryReport is the inventory of one server with its rollup health.
+type inventoryReport struct {
+	*inventory.Inventory `yaml:",inline"`
+	Health               inventory.Health  `json:"health" yaml:"health"`
+	Issues               []inventory.Issue `json:"issues,omitempty" yaml:"issues,omitempty"`
+	// Error is set when the inventory is partial or missing because some
+	// requests failed, and says why.
+	Error *fleet.HostError `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// inventoryCmd represents the inventory command
+var inventoryCmd = &cobra.Command{
+	Use:   "inventory",
+	Short: "Show a vendor-neutral inventory of each server",
+	Long: `Read the system, chassis, processors, memory, NICs, storage controllers with
+their drives and volumes, power supplies, fans, temperatures and firmware of
+each server into one normalized model.
+
+Health and state use the same values for every vendor, sizes are in bytes,
+speeds in MHz or Mbit/s, power in watts and temperatures in degrees Celsius, so
+reports for a mixed fleet can be compared directly. The text output summarizes
+each server and lists the components that are not healthy; use -o json or
+-o yaml for everything.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadServers(bmcHost)
+		if err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+
+		var reports []*inventoryReport
+		summary := runFleet(cfg.Servers, gatherInventory, func(r fleet.Result[*inventoryReport]) {
+			report := r.Value
+			if report == nil {
+				report = &inventoryReport{
+					Inventory: &inventory.Inventory{Hostname: r.Server.Hostname},
+					Health:    inventory.HealthUnknown,
+				}
+			}
+			report.Error = fleet.Describe(r.Err)
+			reports = append(reports, report)
+		})
+		printInventory(reports)
+		return fleetError(summary)
+	},
+}
+
+// gatherInventory reads the inventory of one server. When some requests
+// failed it returns the partial inventory with the error, and no inventory
+// when not even the system could be read.
+func gatherInventory(ctx context.Context, server config.ServerConfig) (*inventoryReport, error) {
+	c, err := newServerClient(ctx, server)
+	if err != nil {
+		return nil, err
+	}
+	inv, err := inventory.Collect(c, server.Hostname, inventory.PartAll)
+	if inv.System.ID == "" {
+		return nil, err
+	}
+	report := &inventoryReport{Inventory: inv, Health: inv.Health(), Issues: inv.Issues()}
+	return report, err
+}
+
+func printInventory(reports []*inventoryReport) {
+	switch inventoryOutput {
+	case "json":
+		data, _ := json.MarshalIndent(reports, "", "  ")
+		fmt.Println(string(data))
+	case "yaml":
+		data, _ := yaml.Marshal(reports)
+		fmt.Println(string(data))
+	default:
+		for _, r := range reports {
+			if r.System.ID == "" {
+				// Nothing was read; the summary on stderr says why.
+				continue
+			}
+			s := r.System
+			fmt.Printf("%s: %s %s (%s), health %s\n", r.Hostname, r.Vendor, s.Model, s.SerialNumber, r.Health)
+			fmt.Printf("  power %s, BIOS %s, %d CPUs, %.0f GiB memory\n", s.PowerState, s.BIOSVersion, s.ProcessorCount, float64(s.MemoryBytes)/(1<<30))
+			drives := 0
+			for _, c := range r.Controllers {
+				drives += c.DriveCount
+			}
+			fmt.Printf("  %d DIMMs, %d NICs, %d storage controllers, %d drives, %d PSUs, %d fans, %d firmware components\n",
+				len(r.Memory), len(r.NICs), len(r.Controllers), drives, len(r.PowerSupplies), len(r.Fans), len(r.Firmware))
+			for _, issue := range r.Issues {
+				fmt.Printf("  %-8s %s %s (%s)\n", issue.Status.Health, issue.Component, issue.ID, issue.Status.State)
+			}
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(inventoryCmd)
+	inventoryCmd.PersistentFlags().StringVarP(&inventoryOutput, "output", "o", "text", "Output format (json, yaml, text)")
+}
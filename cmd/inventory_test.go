@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/angelhvargas/redfishcli/pkg/client"
+	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/angelhvargas/redfishcli/pkg/emulator"
+	"github.com/angelhvargas/redfishcli/pkg/idrac"
+	"github.com/angelhvargas/redfishcli/pkg/inventory"
+	"github.com/angelhvargas/redfishcli/pkg/xclarity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// writeFleetConfig starts an iDRAC and an XCC emulator and writes a config
+// file listing both.
+func writeFleetConfig(t *testing.T) (configFile, dellHost, lenovoHost string) {
+	hosts := map[string]string{}
+	for name, profile := range map[string]emulator.Profile{"idrac": emulator.IDRAC(), "xclarity": emulator.XCC()} {
+		emu, err := emulator.New(profile, emulator.Options{Username: "root", Password: "calvin"})
+		require.NoError(t, err)
+		srv, err := emu.Start("127.0.0.1:0", emulator.StartOptions{})
+		require.NoError(t, err)
+		t.Cleanup(func() { srv.Close() })
+		hosts[name] = srv.Host
+	}
+
+	client.ResetRegistry()
+	client.Register("idrac", func(cfg config.BMCConnConfig) client.ServerClient {
+		return idrac.NewClient(config.IDRACConfig{BMCConnConfig: cfg})
+	})
+	client.Register("xclarity", func(cfg config.BMCConnConfig) client.ServerClient {
+		return xclarity.NewClient(config.XClarityConfig{BMCConnConfig: cfg})
+	})
+
+	configFile = "config_test_inventory.yaml"
+	content := fmt.Sprintf(`
+servers:
+  - type: idrac
+    hostname: %s
+    username: root
+    password: calvin
+  - type: xclarity
+    hostname: %s
+    username: root
+    password: calvin
+`, hosts["idrac"], hosts["xclarity"])
+	require.NoError(t, os.WriteFile(configFile, []byte(content), 0644))
+	t.Cleanup(func() { os.Remove(configFile) })
+	return configFile, hosts["idrac"], hosts["xclarity"]
+}
+
+func TestInventoryCmd(t *testing.T) {
+	configFile, dellHost, lenovoHost := writeFleetConfig(t)
+	oldOutput := inventoryOutput
+	t.Cleanup(func() { inventoryOutput = oldOutput })
+
+	t.Run("JSON is comparable across vendors", func(t *testing.T) {
+		out := captureStdout(t, "inventory", "-o", "json", "--config", configFile)
+
+		var reports []inventoryReport
+		require.NoError(t, json.Unmarshal([]byte(out), &reports))
+		require.Len(t, reports, 2)
+		dell, lenovo := reports[0], reports[1]
+		assert.Equal(t, dellHost, dell.Hostname)
+		assert.Equal(t, "Dell", dell.Vendor)
+		assert.Equal(t, lenovoHost, lenovo.Hostname)
+		assert.Equal(t, "Lenovo", lenovo.Vendor)
+
+		for _, r := range reports {
+			assert.Equal(t, inventory.PowerOn, r.System.PowerState, r.Hostname)
+			assert.NotEmpty(t, r.Processors, r.Hostname)
+			assert.NotEmpty(t, r.Controllers, r.Hostname)
+			assert.NotEmpty(t, r.Fans, r.Hostname)
+			assert.NotEmpty(t, r.Firmware, r.Hostname)
+			assert.Nil(t, r.Error, r.Hostname)
+		}
+
+		// The iDRAC's sensor IDs lose their vendor prefixes.
+		assert.Equal(t, "Fan.Embedded.1", dell.Fans[0].ID)
+		assert.Equal(t, "SystemBoardInletTemp", dell.Temperatures[0].ID)
+
+		// A DIMM in Warning degrades the Dell; the Lenovo's empty slot does not
+		// count against it.
+		assert.Equal(t, inventory.HealthWarning, dell.Health)
+		assert.Contains(t, dell.Issues, inventory.Issue{Component: "memory", ID: "DIMM.Socket.B1", Status: inventory.Status{Health: inventory.HealthWarning, State: inventory.StateEnabled}})
+		for _, issue := range lenovo.Issues {
+			assert.NotEqual(t, "memory", issue.Component)
+		}
+	})
+
+	t.Run("YAML", func(t *testing.T) {
+		out := captureStdout(t, "inventory", "-o", "yaml", "--config", configFile)
+
+		var reports []map[string]interface{}
+		require.NoError(t, yaml.Unmarshal([]byte(out), &reports))
+		require.Len(t, reports, 2)
+		assert.Equal(t, "Dell", reports[0]["vendor"])
+		assert.Contains(t, reports[0], "power_supplies")
+	})
+
+	t.Run("Text lists unhealthy components", func(t *testing.T) {
+		out := captureStdout(t, "inventory", "-o", "text", "--config", configFile)
+		assert.Contains(t, out, dellHost+": Dell")
+		assert.Contains(t, out, lenovoHost+": Lenovo")
+		assert.Regexp(t, `Warning\s+memory DIMM\.Socket\.B1 \(Enabled\)`, out)
+	})
+}
@@ -0,0 +1,118 @@
+/*
+Copyright © 2024 Angel Vargas <angelvargas@outlook.es>
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/angelhvargas/redfishcli/pkg/fleet"
+	"github.com/angelhvargas/redfishcli/pkg/mockup"
+	"github.com/angelhvargas/redfishcli/pkg/redact"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mockupOut     string
+	mockupHeaders bool
+	mockupRate    float64
+	mockupMax     int
+	mockupSession bool
+)
+
+// mockupCmd represents the mockup command
+var mockupCmd = &cobra.Command{
+	Use:   "mockup",
+	Short: "Work with Redfish mockups",
+	Long:  `Capture and manage Redfish mockups in the DMTF Redfish-Mockup-Creator layout.`,
+}
+
+// mockupCaptureCmd represents the mockup capture command
+var mockupCaptureCmd = &cobra.Command{
+	Use:   "capture",
+	Short: "Crawl a live BMC into a mockup directory",
+	Long: `Walk the whole Redfish tree of a BMC, following every @odata.id link, and
+save each resource as <out>/redfish/v1/.../index.json.
+
+The result can be attached to bug reports, diffed between firmware versions, or
+served locally with "redfishcli emulate --mockup <out>". Use --redact to scrub
+serial numbers, MAC addresses and IP addresses before sharing.
+
+When several servers are configured each one is written to <out>/<hostname>.
+
+Example:
+  redfishcli mockup capture -n 192.168.1.100 -u root -p calvin --out ./r740 --redact all`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		redaction, err := redact.Parse(redactList)
+		if err != nil {
+			return err
+		}
+		cfg, err := loadServers(bmcHost)
+		if err != nil {
+			return err
+		}
+
+		var interval time.Duration
+		if mockupRate > 0 {
+			interval = time.Duration(float64(time.Second) / mockupRate)
+		}
+
+		cmd.SilenceUsage = true
+		mockupOutDir := func(server config.ServerConfig) string {
+			if len(cfg.Servers) > 1 {
+				return filepath.Join(mockupOut, server.Hostname)
+			}
+			return mockupOut
+		}
+		summary := runFleet(cfg.Servers, func(ctx context.Context, server config.ServerConfig) (*mockup.Result, error) {
+			return mockup.Capture(mockup.Options{
+				Host:         server.Hostname,
+				Username:     server.Username,
+				Password:     server.Password,
+				HTTP:         serverHTTPConfig(ctx),
+				OutDir:       mockupOutDir(server),
+				Headers:      mockupHeaders,
+				Redact:       redaction.Rules(),
+				Interval:     interval,
+				MaxResources: mockupMax,
+				UseSession:   mockupSession,
+			})
+		}, func(r fleet.Result[*mockup.Result]) {
+			if r.Err == nil {
+				printCaptureResult(r.Server.Hostname, mockupOutDir(r.Server), r.Value)
+			}
+		})
+		return fleetError(summary)
+	},
+}
+
+func printCaptureResult(host, out string, result *mockup.Result) {
+	fmt.Printf("%s: captured %d resources into %s\n", host, result.Resources, out)
+	if result.Truncated {
+		fmt.Printf("%s: stopped after --max %d resources\n", host, mockupMax)
+	}
+	uris := make([]string, 0, len(result.Errors))
+	for uri := range result.Errors {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+	for _, uri := range uris {
+		fmt.Printf("%s: skipped %s: %s\n", host, uri, result.Errors[uri])
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(mockupCmd)
+	mockupCmd.AddCommand(mockupCaptureCmd)
+	mockupCaptureCmd.Flags().StringVar(&mockupOut, "out", "", "directory to write the mockup into")
+	mockupCaptureCmd.Flags().BoolVar(&mockupHeaders, "headers", false, "also save the response headers of each resource as headers.json")
+	mockupCaptureCmd.Flags().Float64Var(&mockupRate, "rate", 5, "maximum requests per second sent to the BMC (0 disables the limit)")
+	mockupCaptureCmd.Flags().IntVar(&mockupMax, "max", 0, "stop after capturing this many resources (0 means no limit)")
+	mockupCaptureCmd.Flags().BoolVar(&mockupSession, "session", true, "authenticate through a Redfish session instead of basic auth on every request")
+	mockupCaptureCmd.MarkFlagRequired("out")
+}
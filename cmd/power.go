@@ -4,12 +4,13 @@ Copyright © 2024 Angel Vargas <angelvargas@outlook.es>
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"os"
 
 	"github.com/angelhvargas/redfishcli/pkg/client"
 	"github.com/angelhvargas/redfishcli/pkg/config"
-	"github.com/angelhvargas/redfishcli/pkg/logger"
+	"github.com/angelhvargas/redfishcli/pkg/fleet"
 	"github.com/spf13/cobra"
 )
 
@@ -24,14 +25,13 @@ Available commands are: status, on, off, restart.`,
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Get the current power state",
-	Run: func(cmd *cobra.Command, args []string) {
-		runPowerCommand(func(c client.ServerClient) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPowerCommand(cmd, func(c client.PowerManager) (string, error) {
 			state, err := c.GetPowerState()
 			if err != nil {
-				return err
+				return "", err
 			}
-			fmt.Printf("Power State: %s\n", state)
-			return nil
+			return fmt.Sprintf("Power State: %s", state), nil
 		})
 	},
 }
@@ -39,55 +39,79 @@ var statusCmd = &cobra.Command{
 var onCmd = &cobra.Command{
 	Use:   "on",
 	Short: "Power on the server",
-	Run: func(cmd *cobra.Command, args []string) {
-		runPowerCommand(func(c client.ServerClient) error {
-			return c.SetPowerState("On")
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPowerCommand(cmd, func(c client.PowerManager) (string, error) {
+			return "On requested", c.SetPowerState("On")
 		})
 	},
 }
 
+var (
+	offType     string
+	restartType string
+)
+
+// resetTypes are the ResetType values defined by Redfish, completed when the
+// BMC cannot be asked for its own list.
+var resetTypes = []string{"On", "ForceOff", "GracefulShutdown", "GracefulRestart", "ForceRestart", "Nmi", "ForceOn", "PushPowerButton", "PowerCycle"}
+
 var offCmd = &cobra.Command{
 	Use:   "off",
 	Short: "Power off the server (ForceOff by default)",
-	Run: func(cmd *cobra.Command, args []string) {
-		runPowerCommand(func(c client.ServerClient) error {
-			return c.SetPowerState("ForceOff")
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPowerCommand(cmd, func(c client.PowerManager) (string, error) {
+			return offType + " requested", c.SetPowerState(offType)
 		})
 	},
 }
 
 var restartCmd = &cobra.Command{
 	Use:   "restart",
-	Short: "Restart the server (GracefulRestart)",
-	Run: func(cmd *cobra.Command, args []string) {
-		runPowerCommand(func(c client.ServerClient) error {
-			return c.Reboot()
+	Short: "Restart the server (GracefulRestart by default)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPowerCommand(cmd, func(c client.PowerManager) (string, error) {
+			if restartType == "GracefulRestart" {
+				return "GracefulRestart requested", c.Reboot()
+			}
+			return restartType + " requested", c.SetPowerState(restartType)
 		})
 	},
 }
 
-func runPowerCommand(action func(client.ServerClient) error) {
-	cfg, err := config.LoadConfigOrEnv(cfgFile, bmcType, bmcUsername, bmcPassword, bmcHost)
+// runPowerCommand runs action on every server and prints what it returns,
+// one line per server.
+func runPowerCommand(cmd *cobra.Command, action func(client.PowerManager) (string, error)) error {
+	cfg, err := loadServers(bmcHost)
 	if err != nil {
-		logger.Log.Error(err.Error())
-		os.Exit(1)
+		return err
 	}
+	cmd.SilenceUsage = true
 
-	for _, server := range cfg.Servers {
-		fmt.Printf("Processing server: %s\n", server.Hostname)
-		c, err := client.NewClient(server.Type, config.BMCConnConfig{
-			Hostname: server.Hostname,
-			Username: server.Username,
-			Password: server.Password,
-		})
+	summary := runFleet(cfg.Servers, func(ctx context.Context, server config.ServerConfig) (string, error) {
+		c, err := newServerClient(ctx, server)
 		if err != nil {
-			logger.Log.Errorf("Error creating client: %s", err)
-			continue
+			return "", err
 		}
-
-		if err := action(c); err != nil {
-			logger.Log.Errorf("Error performing action: %s", err)
+		pm, err := client.Power(c)
+		if err != nil {
+			return "", err
 		}
+		out, err := action(pm)
+		return out, client.Explain(c, client.CapabilityPower, err)
+	}, printActionResult)
+	return fleetError(summary)
+}
+
+// printActionResult prints what an action did on one server. Capabilities
+// the BMC does not offer are not failures and rejected values are the
+// user's to fix, so both are printed as a plain notice along with the
+// results; other failures are left to the summary.
+func printActionResult(r fleet.Result[string]) {
+	switch {
+	case r.Err == nil:
+		fmt.Printf("%s: %s\n", r.Server.Hostname, r.Value)
+	case errors.Is(r.Err, client.ErrNotSupported), errors.Is(r.Err, client.ErrInvalidValue):
+		fmt.Printf("%s: %s\n", r.Server.Hostname, r.Err)
 	}
 }
 
@@ -97,4 +121,10 @@ func init() {
 	powerCmd.AddCommand(onCmd)
 	powerCmd.AddCommand(offCmd)
 	powerCmd.AddCommand(restartCmd)
+
+	offCmd.Flags().StringVar(&offType, "reset-type", "ForceOff", "Reset type used to power off (e.g., ForceOff, GracefulShutdown)")
+	restartCmd.Flags().StringVar(&restartType, "reset-type", "GracefulRestart", "Reset type used to restart (e.g., GracefulRestart, ForceRestart)")
+	completeResetType := completeAllowableValues(func(v *client.AllowableValues) []string { return v.ResetTypes }, resetTypes)
+	offCmd.RegisterFlagCompletionFunc("reset-type", completeResetType)
+	restartCmd.RegisterFlagCompletionFunc("reset-type", completeResetType)
 }
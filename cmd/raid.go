@@ -44,8 +44,9 @@ Available Commands:
 Examples:
   redfishcli storage raid health --drives -t xclarity -u admin -p "your_password" -n 192.168.1.101
   redfishcli storage raid details -t idrac -u root -p "your_password" -n 192.168.1.100`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		fmt.Println("raid called")
+		return nil
 	},
 }
 
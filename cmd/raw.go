@@ -0,0 +1,184 @@
+/*
+Copyright © 2024 Angel Vargas <angelvargas@outlook.es>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/angelhvargas/redfishcli/pkg/fleet"
+	"github.com/angelhvargas/redfishcli/pkg/raw"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	rawData        string
+	rawWait        bool
+	rawTaskTimeout time.Duration
+	rawSession     bool
+	rawFormat      string
+)
+
+// rawResult is the answer of one BMC to a raw request.
+type rawResult struct {
+	Hostname      string `json:"hostname" yaml:"hostname"`
+	*raw.Response `yaml:",inline"`
+	Error         *fleet.HostError `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// rawCmd represents the raw command
+var rawCmd = &cobra.Command{
+	Use:   "raw",
+	Short: "Send raw Redfish requests",
+	Long: `Send any Redfish request to the configured BMCs, for everything redfishcli does
+not model yet. The requests use the same servers, credentials, sessions, TLS
+settings, --record/--replay cassettes and --validate-schema checks as every
+other command.
+
+A request the BMC accepts as a task (202 Accepted with a task monitor in the
+Location header) is followed until the task finishes, unless --wait=false.
+
+The responses are printed as a JSON (default) or YAML list with one entry per
+BMC; "text" prints the status line and the body of each. The command fails
+when any BMC answers with an error.
+
+Example:
+  redfishcli raw get /redfish/v1/Systems/System.Embedded.1 -n 192.168.1.100 -u root -p calvin
+  redfishcli raw patch /redfish/v1/Systems/System.Embedded.1 --data '{"HostName": "rack12-u07"}'
+  redfishcli raw post /redfish/v1/UpdateService/Actions/UpdateService.SimpleUpdate --data @update.json
+  redfishcli raw delete /redfish/v1/SessionService/Sessions/7 --config fleet.yaml`,
+}
+
+func newRawCmd(method string, withData bool) *cobra.Command {
+	c := &cobra.Command{
+		Use:   strings.ToLower(method) + " <uri>",
+		Short: fmt.Sprintf("Send a %s request", method),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var body []byte
+			if withData && rawData != "" {
+				data, err := readRawData(rawData)
+				if err != nil {
+					return err
+				}
+				body = data
+			} else if withData && method == http.MethodPatch {
+				return fmt.Errorf("%s needs --data", strings.ToLower(method))
+			}
+			return runRaw(cmd, method, args[0], body)
+		},
+	}
+	if withData {
+		c.Flags().StringVarP(&rawData, "data", "d", "", "JSON request body, @file to read it from a file or @- to read it from stdin")
+	}
+	return c
+}
+
+func runRaw(cmd *cobra.Command, method, uri string, body []byte) error {
+	cfg, err := loadServers(bmcHost)
+	if err != nil {
+		return err
+	}
+
+	cmd.SilenceUsage = true
+	var results []rawResult
+	summary := runFleet(cfg.Servers, func(ctx context.Context, server config.ServerConfig) (*raw.Response, error) {
+		c := raw.New(raw.Options{
+			Host:        server.Hostname,
+			Username:    server.Username,
+			Password:    server.Password,
+			HTTP:        serverHTTPConfig(ctx),
+			UseSession:  rawSession,
+			Wait:        rawWait,
+			TaskTimeout: rawTaskTimeout,
+		})
+		defer c.Close()
+		return c.Do(method, uri, body)
+	}, func(r fleet.Result[*raw.Response]) {
+		results = append(results, rawResult{Hostname: r.Server.Hostname, Response: r.Value, Error: fleet.Describe(r.Err)})
+	})
+
+	if err := printRawResults(results); err != nil {
+		return err
+	}
+	return fleetError(summary)
+}
+
+// readRawData reads a --data value: inline JSON, @file or @- for stdin.
+func readRawData(data string) ([]byte, error) {
+	var b []byte
+	switch {
+	case data == "@-":
+		read, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		b = read
+	case strings.HasPrefix(data, "@"):
+		read, err := os.ReadFile(data[1:])
+		if err != nil {
+			return nil, err
+		}
+		b = read
+	default:
+		b = []byte(data)
+	}
+	if !json.Valid(b) {
+		return nil, fmt.Errorf("--data is not valid JSON")
+	}
+	return b, nil
+}
+
+func printRawResults(results []rawResult) error {
+	switch rawFormat {
+	case "json":
+		data, _ := json.MarshalIndent(results, "", "  ")
+		fmt.Println(string(data))
+	case "yaml":
+		data, _ := yaml.Marshal(results)
+		fmt.Println(string(data))
+	case "text", "table":
+		for _, r := range results {
+			if r.Response == nil {
+				fmt.Printf("%s: %s\n", r.Hostname, r.Error.Message)
+				continue
+			}
+			fmt.Printf("%s: %s %s: %d %s\n", r.Hostname, r.Method, r.URI, r.Status, http.StatusText(r.Status))
+			if r.TaskMonitor != "" {
+				fmt.Printf("%s: task monitor %s\n", r.Hostname, r.TaskMonitor)
+			}
+			switch body := r.Body.(type) {
+			case nil:
+			case string:
+				fmt.Println(body)
+			default:
+				data, _ := json.MarshalIndent(body, "", "  ")
+				fmt.Println(string(data))
+			}
+		}
+	default:
+		return fmt.Errorf("unknown output format %q (json, yaml, text)", rawFormat)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(rawCmd)
+	rawCmd.AddCommand(newRawCmd(http.MethodGet, false))
+	rawCmd.AddCommand(newRawCmd(http.MethodPatch, true))
+	rawCmd.AddCommand(newRawCmd(http.MethodPost, true))
+	rawCmd.AddCommand(newRawCmd(http.MethodDelete, false))
+	rawCmd.PersistentFlags().StringVarP(&rawFormat, "output", "o", "json", "Output format (json, yaml, text)")
+	rawCmd.PersistentFlags().BoolVar(&rawWait, "wait", true, "follow the task monitor of a request the BMC runs as a task until the task finishes")
+	rawCmd.PersistentFlags().DurationVar(&rawTaskTimeout, "task-timeout", 30*time.Minute, "stop waiting for a task after this long (0 waits forever)")
+	rawCmd.PersistentFlags().BoolVar(&rawSession, "session", true, "authenticate through a Redfish session instead of basic auth on every request")
+}
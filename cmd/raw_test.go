@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/angelhvargas/redfishcli/pkg/fleet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRawCmd(t *testing.T) {
+	server := startHealthEmulator(t, nil)
+	oldOutput, oldConfig := rawFormat, cfgFile
+	oldHost, oldUser, oldPass := bmcHost, bmcUsername, bmcPassword
+	cfgFile = ""
+	t.Cleanup(func() {
+		rawFormat, cfgFile, rawData = oldOutput, oldConfig, ""
+		bmcHost, bmcUsername, bmcPassword = oldHost, oldUser, oldPass
+	})
+	login := []string{"-n", server.Hostname, "-u", "root", "-p", "calvin"}
+	system := "/redfish/v1/Systems/System.Embedded.1"
+
+	// JSON is the default.
+	stdout, _, err := runCommand(append([]string{"raw", "get", system}, login...)...)
+	require.NoError(t, err)
+	var results []rawResult
+	require.NoError(t, json.Unmarshal([]byte(stdout), &results))
+	require.Len(t, results, 1)
+	assert.Equal(t, server.Hostname, results[0].Hostname)
+	assert.Equal(t, "GET", results[0].Method)
+	assert.Equal(t, 200, results[0].Status)
+	assert.Equal(t, "On", results[0].Body.(map[string]interface{})["PowerState"])
+
+	data := filepath.Join(t.TempDir(), "patch.json")
+	require.NoError(t, os.WriteFile(data, []byte(`{"HostName": "rack12-u07"}`), 0644))
+	stdout, _, err = runCommand(append([]string{"raw", "patch", system, "--data", "@" + data, "-o", "yaml"}, login...)...)
+	require.NoError(t, err)
+	var patched []map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(stdout), &patched))
+	assert.Equal(t, "rack12-u07", patched[0]["body"].(map[string]interface{})["HostName"])
+
+	stdout, _, err = runCommand(append([]string{"raw", "post", system + "/Actions/ComputerSystem.Reset", "-d", `{"ResetType": "ForceOff"}`, "-o", "text"}, login...)...)
+	require.NoError(t, err)
+	assert.Contains(t, stdout, server.Hostname+": POST "+system+"/Actions/ComputerSystem.Reset: 204 No Content\n")
+
+	stdout, _, err = runCommand(append([]string{"raw", "get", system, "-o", "text"}, login...)...)
+	require.NoError(t, err)
+	assert.Contains(t, stdout, `"PowerState": "Off"`)
+
+	// Errors are printed with the BMC's payload and fail the command.
+	stdout, _, err = runCommand(append([]string{"raw", "delete", system, "-o", "json"}, login...)...)
+	require.EqualError(t, err, "1 of 1 servers failed")
+	assert.Equal(t, exitFailed, exitCode(err))
+	results = nil
+	require.NoError(t, json.Unmarshal([]byte(stdout), &results))
+	assert.Equal(t, 405, results[0].Status)
+	assert.Contains(t, results[0].Error.Message, "HTTP 405")
+	assert.Equal(t, fleet.ClassBMCError, results[0].Error.Class)
+	assert.Equal(t, system, results[0].Error.URI)
+	assert.Contains(t, results[0].Body, "error")
+
+	_, _, err = runCommand(append([]string{"raw", "post", system + "/Actions/ComputerSystem.Reset", "-d", `{"ResetType":`}, login...)...)
+	assert.EqualError(t, err, "--data is not valid JSON")
+}
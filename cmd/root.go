@@ -22,23 +22,41 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 
+	"github.com/angelhvargas/redfishcli/pkg/cassette"
+	"github.com/angelhvargas/redfishcli/pkg/client"
+	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
 	_ "github.com/angelhvargas/redfishcli/pkg/idrac"
+	"github.com/angelhvargas/redfishcli/pkg/logger"
+	"github.com/angelhvargas/redfishcli/pkg/schema"
 	_ "github.com/angelhvargas/redfishcli/pkg/xclarity"
 )
 
 var (
-	cfgFile     string
-	bmcUsername string
-	bmcPassword string
-	bmcHost     string
-	bmcType     string
-	output      string
+	cfgFile        string
+	bmcUsername    string
+	bmcPassword    string
+	bmcHost        string
+	bmcType        string
+	recordDir      string
+	replayDir      string
+	redactList     string
+	validateSchema bool
+
+	// httpConfig is the HTTP pipeline shared by every BMC client created for
+	// the current invocation. Nil means each backend uses its defaults.
+	httpConfig *httpclient.Config
+	recorder   *cassette.Recorder
+	// schemaResults collects what --validate-schema found.
+	schemaResults *schema.Collector
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -101,59 +119,148 @@ Usage:
   To use the configuration file, simply run:
   redfishcli storage raid health --drives
   redfishcli will automatically load the servers listed in the configuration file and scan their health.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := applyContext(cmd); err != nil {
+			return err
+		}
+		return setupHTTPConfig()
+	},
+	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+		if recorder != nil {
+			return recorder.Err()
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Command logic goes here
 		if cfgFile == "" && (bmcUsername == "" || bmcPassword == "" || bmcHost == "") {
-			cmd.Help() // Display help text
-			return
+			return cmd.Help() // Display help text
 		}
+		return nil
 	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	err := rootCmd.Execute()
-	if err != nil {
-		os.Exit(1)
-	}
+	os.Exit(exitCode(rootCmd.Execute()))
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
+	// Commands failing on some servers still report what --validate-schema
+	// found, which a post-run hook would skip.
+	cobra.OnFinalize(reportSchemaViolations)
 
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.redfishcli/config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is the context's, or else $HOME/.redfishcli/config.yaml if it exists)")
 	rootCmd.PersistentFlags().StringVarP(&bmcUsername, "username", "u", "", "username for server")
 	rootCmd.PersistentFlags().StringVarP(&bmcPassword, "password", "p", "", "password for server")
 	rootCmd.PersistentFlags().StringVarP(&bmcHost, "host", "n", "", "hostname of the server")
 	rootCmd.PersistentFlags().StringVarP(&bmcType, "bmc-type", "t", "idrac", "BMC type (iDRAC or xClarity)")
-	healthCmd.PersistentFlags().StringVarP(&output, "output", "o", "json", "Output format (json, yaml, table)")
+	rootCmd.PersistentFlags().StringVar(&recordDir, "record", "", "record every Redfish request/response into this cassette directory")
+	rootCmd.PersistentFlags().StringVar(&replayDir, "replay", "", "answer Redfish requests from this cassette directory instead of the network")
+	rootCmd.PersistentFlags().StringVar(&redactList, "redact", "credentials", "what to redact from recorded cassettes and captured mockups (credentials, serials, macs, ips, all, none)")
+	rootCmd.PersistentFlags().BoolVar(&validateSchema, "validate-schema", false, "check every Redfish response against its bundled DMTF schema and report violations as JSON on stderr")
+	rootCmd.MarkFlagsMutuallyExclusive("record", "replay")
 }
 
-// initConfig reads in config file and ENV variables if set.
-func initConfig() {
-	if cfgFile != "" {
-		// Use config file from the flag.
-		viper.SetConfigFile(cfgFile)
-	} else {
-		// Find home directory.
-		home, err := os.UserHomeDir()
-		cobra.CheckErr(err)
-
-		// Search config in home directory with name ".redfishcli" (without extension).
-		viper.AddConfigPath(home)
-		viper.SetConfigType("yaml")
-		viper.SetConfigName(".redfishcli")
+// setupHTTPConfig builds the HTTP pipeline from the global flags.
+func setupHTTPConfig() error {
+	httpConfig = nil
+	recorder = nil
+	schemaResults = nil
+	if recordDir == "" && replayDir == "" && !validateSchema && activeContext == nil {
+		return nil
 	}
 
-	viper.AutomaticEnv() // read in environment variables that match
+	cfg := httpclient.DefaultConfig()
+	if activeContext != nil {
+		var err error
+		if cfg, err = activeContext.HTTP(cfg); err != nil {
+			return fmt.Errorf("context: %w", err)
+		}
+	}
+	if replayDir != "" {
+		player, err := cassette.Load(replayDir)
+		if err != nil {
+			return fmt.Errorf("loading cassette: %w", err)
+		}
+		cfg.Transport = player
+	}
+	if recordDir != "" {
+		r, err := cassette.ParseRedaction(redactList)
+		if err != nil {
+			return err
+		}
+		recorder, err = cassette.NewRecorder(recordDir, r)
+		if err != nil {
+			return fmt.Errorf("creating cassette: %w", err)
+		}
+		cfg = cfg.Use(recorder.Middleware())
+	}
+	if validateSchema {
+		validator, err := schema.Bundled()
+		if err != nil {
+			return err
+		}
+		schemaResults = &schema.Collector{}
+		cfg = cfg.Use(schema.Middleware(validator, schemaResults.Add))
+	}
+	httpConfig = &cfg
+	return nil
+}
+
+// newServerClient creates the BMC client for a configured server.
+func newServerClient(ctx context.Context, server config.ServerConfig) (client.ServerClient, error) {
+	httpCfg := serverHTTPConfig(ctx)
+	return client.NewClient(server.Type, config.BMCConnConfig{
+		Hostname:   server.Hostname,
+		Username:   server.Username,
+		Password:   server.Password,
+		HTTPClient: &httpCfg,
+	})
+}
+
+// serverHTTPConfig returns the HTTP settings of the flags, with the requests
+// cancelled with ctx.
+func serverHTTPConfig(ctx context.Context) httpclient.Config {
+	base := httpclient.DefaultConfig()
+	if httpConfig != nil {
+		base = *httpConfig
+	}
+	return base.WithContext(ctx)
+}
 
-	// If a config file is found, read it in.
-	if err := viper.ReadInConfig(); err == nil {
-		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
+// reportSchemaViolations prints the resources --validate-schema found
+// invalid to stderr as a JSON array, keeping stdout for the command's output.
+func reportSchemaViolations() {
+	if schemaResults == nil {
+		return
+	}
+	invalid := schema.Invalid(schemaResults.Results())
+	if len(invalid) == 0 {
+		return
+	}
+	data, err := json.MarshalIndent(invalid, "", "  ")
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return
+	}
+	fmt.Fprintln(os.Stderr, string(data))
+}
+
+// printRaw prints the payloads resources were decoded from, untouched apart
+// from indentation, as one JSON array.
+func printRaw(payloads []json.RawMessage) {
+	data, err := json.Marshal(payloads)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return
 	}
+	var buf bytes.Buffer
+	json.Indent(&buf, data, "", "  ")
+	fmt.Println(buf.String())
 }
@@ -0,0 +1,83 @@
+/*
+Copyright © 2024 Angel Vargas <angelvargas@outlook.es>
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/angelhvargas/redfishcli/pkg/shell"
+)
+
+var (
+	askPass bool
+
+	// secrets resolves the password references of the servers loadConfig
+	// loaded, each once per command.
+	secrets *config.Secrets
+
+	// noPrompt is set while the shell completes a flag, where a prompt
+	// would hang the shell: secrets that need one are not read.
+	noPrompt bool
+)
+
+var errNoPrompt = errors.New("cannot prompt while completing")
+
+// readSecret asks for a secret on the terminal, without echoing it.
+var readSecret = func(prompt string) (string, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		// No controlling terminal, e.g. on Windows: try stdin.
+		tty = os.Stdin
+	} else {
+		defer tty.Close()
+	}
+	fmt.Fprint(tty, prompt)
+	secret, err := shell.ReadPassword(int(tty.Fd()))
+	fmt.Fprintln(tty)
+	return secret, err
+}
+
+// promptPassword asks for the password --ask-pass wants.
+func promptPassword() (string, error) {
+	if noPrompt {
+		return "", fmt.Errorf("--ask-pass: %w", errNoPrompt)
+	}
+	password, err := readSecret("BMC password: ")
+	if err != nil {
+		return "", fmt.Errorf("--ask-pass: %w", err)
+	}
+	return password, nil
+}
+
+// vaultPassphrase returns the passphrase of the credential store:
+// REDFISHCLI_VAULT_PASSPHRASE, which may be a secret reference, or else
+// what the user types.
+func vaultPassphrase() (string, error) {
+	if ref := os.Getenv("REDFISHCLI_VAULT_PASSPHRASE"); ref != "" {
+		return config.ResolveSecret(context.Background(), ref)
+	}
+	if noPrompt {
+		return "", fmt.Errorf("reading the vault passphrase: %w", errNoPrompt)
+	}
+	passphrase, err := readSecret("Vault passphrase: ")
+	if err != nil {
+		return "", fmt.Errorf("reading the vault passphrase: %w", err)
+	}
+	return passphrase, nil
+}
+
+// resolveServer returns the server with its password reference, if it has
+// one, replaced by the secret, right before a command connects to it.
+func resolveServer(ctx context.Context, server config.ServerConfig) (config.ServerConfig, error) {
+	return server.ResolveSecrets(ctx, secrets)
+}
+
+func init() {
+	config.VaultPassphrase = vaultPassphrase
+	rootCmd.PersistentFlags().BoolVar(&askPass, "ask-pass", false, "ask for the password instead of taking it from --password, e.g. to keep it out of the shell history")
+}
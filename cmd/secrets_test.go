@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordReferences(t *testing.T) {
+	server := startHealthEmulator(t, nil)
+	t.Setenv("REDFISHCLI_TEST_BMC_PASSWORD", "calvin")
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(fmt.Sprintf(`
+servers:
+  - {type: idrac, hostname: %q, username: root, password: "env:REDFISHCLI_TEST_BMC_PASSWORD"}
+  - {type: idrac, hostname: "127.0.0.1:1", username: root, password: "env:REDFISHCLI_TEST_UNSET"}
+`, server.Hostname)), 0644))
+
+	stdout, stderr, err := runCommand("sysinfo", "--config", configFile)
+	assert.Equal(t, exitPartial, exitCode(err))
+	assert.Contains(t, stdout, "Manufacturer: Dell Inc.")
+	assert.Contains(t, stderr, "127.0.0.1:1: failed (bmc-error): password of 127.0.0.1:1: env:REDFISHCLI_TEST_UNSET: REDFISHCLI_TEST_UNSET is not set")
+	assert.NotContains(t, stdout+stderr, "calvin")
+}
+
+func TestAskPass(t *testing.T) {
+	server := startHealthEmulator(t, nil)
+	oldRead := readSecret
+	prompts := 0
+	readSecret = func(prompt string) (string, error) {
+		prompts++
+		assert.Equal(t, "BMC password: ", prompt)
+		return "calvin", nil
+	}
+	cfgFile = ""
+	t.Cleanup(func() {
+		readSecret = oldRead
+		askPass, bmcHost, bmcUsername = false, "", ""
+	})
+
+	stdout, _, err := runCommand("sysinfo", "--host", server.Hostname, "-u", "root", "--ask-pass")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Manufacturer: Dell Inc.")
+	assert.Equal(t, 1, prompts)
+}
+
+func TestCompletionPasswordReference(t *testing.T) {
+	server := startHealthEmulator(t, nil)
+	t.Setenv("REDFISHCLI_TEST_BMC_PASSWORD", "calvin")
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(fmt.Sprintf(`
+servers:
+  - {type: idrac, hostname: %q, username: root, password: "env:REDFISHCLI_TEST_BMC_PASSWORD"}
+`, server.Hostname)), 0644))
+
+	stdout, _, err := runCommand("__complete", "boot", "set", "--config", configFile, "--device", "")
+	require.NoError(t, err)
+	// SDCard is advertised by the BMC only, not in the fallback targets.
+	assert.Contains(t, stdout, "SDCard")
+}
+
+func TestCompletionNeverPrompts(t *testing.T) {
+	server := startHealthEmulator(t, nil)
+	oldRead := readSecret
+	readSecret = func(prompt string) (string, error) {
+		t.Errorf("completion prompted %q", prompt)
+		return "calvin", nil
+	}
+	cfgFile = ""
+	t.Cleanup(func() {
+		readSecret = oldRead
+		askPass, bmcHost, bmcUsername = false, "", ""
+	})
+
+	stdout, _, err := runCommand("__complete", "boot", "set", "--host", server.Hostname, "-u", "root", "--ask-pass", "--device", "")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Floppy")
+	assert.NotContains(t, stdout, "SDCard")
+}
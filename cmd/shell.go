@@ -0,0 +1,128 @@
+/*
+Copyright © 2024 Angel Vargas <angelvargas@outlook.es>
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/angelhvargas/redfishcli/pkg/raw"
+	"github.com/angelhvargas/redfishcli/pkg/shell"
+	"github.com/spf13/cobra"
+)
+
+// shellCmd represents the shell command
+var shellCmd = &cobra.Command{
+	Use:   "shell [host]",
+	Short: "Browse the Redfish tree of a BMC interactively",
+	Long: `Open an interactive session over the Redfish resource tree of one BMC.
+
+The tree is browsed like a file system: cd into links such as Systems or a
+member Id, ls the links and properties of a resource, cat it as JSON, find
+resources and properties below the current one, list the actions a resource
+offers with their allowed values, and patch it. Tab completes commands, links
+and property names; the arrow keys walk the history. Every resource is read
+once over a single Redfish session.
+
+When stdin is not a terminal the commands are read from it one per line, so
+the shell can be scripted; the command then fails if any of them failed.
+
+Commands:
+  cd [path]             change to a resource (.. goes up, no path to the root)
+  pwd                   print the URI of the current resource
+  ls [path]             list links (ending in /) and properties
+  cat [path|property]   print a resource or a property (e.g. Status.Health)
+  find <text>           search URIs and property names below the current resource
+  actions [path]        list actions with their targets and allowed values
+  patch [path] <json>   PATCH a resource
+  history               list the commands run so far
+  help, exit
+
+Example:
+  redfishcli shell 192.168.1.100 -u root -p calvin
+  printf 'cd Systems/System.Embedded.1\ncat PowerState\n' | redfishcli shell 192.168.1.100`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		host := bmcHost
+		if len(args) == 1 {
+			host = args[0]
+		}
+		server, err := shellServer(host)
+		if err != nil {
+			return err
+		}
+
+		c := raw.New(raw.Options{
+			Host:       server.Hostname,
+			Username:   server.Username,
+			Password:   server.Password,
+			HTTP:       serverHTTPConfig(context.Background()),
+			UseSession: true,
+			Wait:       true,
+		})
+		defer c.Close()
+
+		sh := shell.New(c, cmd.OutOrStdout())
+		fd := int(os.Stdin.Fd())
+		if !shell.IsTerminal(fd) {
+			if failed := sh.Run(cmd.InOrStdin(), cmd.ErrOrStderr()); failed > 0 {
+				cmd.SilenceUsage = true
+				return &exitError{code: exitPartial, err: fmt.Errorf("%d shell commands failed", failed)}
+			}
+			return nil
+		}
+
+		editor := shell.NewEditor(os.Stdin, os.Stdout, fd)
+		editor.Complete = sh.Complete
+		fmt.Printf("Connected to %s. Type help for the commands, exit or Ctrl-D to leave.\n", server.Hostname)
+		for {
+			line, err := editor.ReadLine(fmt.Sprintf("%s:%s> ", server.Hostname, sh.Cwd()))
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			editor.AddHistory(line)
+			err = sh.Exec(line)
+			if errors.Is(err, shell.ErrExit) {
+				return nil
+			}
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		}
+	},
+}
+
+// shellServer picks the one BMC the shell connects to: the configured server
+// named by host, or the only configured server.
+func shellServer(host string) (config.ServerConfig, error) {
+	cfg, err := loadConfig(host)
+	if err != nil {
+		return config.ServerConfig{}, err
+	}
+	switch {
+	case len(cfg.Servers) == 0:
+		return config.ServerConfig{}, fmt.Errorf("no servers configured; give a host or use --config")
+	case host != "":
+		for _, server := range cfg.Servers {
+			if server.Hostname == host {
+				return resolveServer(context.Background(), server)
+			}
+		}
+		return config.ServerConfig{}, fmt.Errorf("%s is not in the configuration", host)
+	case len(cfg.Servers) > 1:
+		return config.ServerConfig{}, fmt.Errorf("%d servers configured; give the host to connect to", len(cfg.Servers))
+	}
+	return resolveServer(context.Background(), cfg.Servers[0])
+}
+
+func init() {
+	rootCmd.AddCommand(shellCmd)
+}
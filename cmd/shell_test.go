@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShellCmdScript(t *testing.T) {
+	server := startHealthEmulator(t, nil)
+	oldConfig := cfgFile
+	oldHost, oldUser, oldPass := bmcHost, bmcUsername, bmcPassword
+	cfgFile = ""
+	t.Cleanup(func() {
+		cfgFile = oldConfig
+		bmcHost, bmcUsername, bmcPassword = oldHost, oldUser, oldPass
+		rootCmd.SetIn(nil)
+	})
+
+	rootCmd.SetIn(strings.NewReader("cd Systems/System.Embedded.1\ncat PowerState\npwd\n"))
+	stdout, _, err := runCommand("shell", server.Hostname, "-u", "root", "-p", "calvin")
+	require.NoError(t, err)
+	assert.Equal(t, "\"On\"\n/redfish/v1/Systems/System.Embedded.1\n", stdout)
+
+	rootCmd.SetIn(strings.NewReader("cd Nowhere\nls Systems\n"))
+	stdout, stderr, err := runCommand("shell", server.Hostname, "-u", "root", "-p", "calvin")
+	require.EqualError(t, err, "1 shell commands failed")
+	assert.Contains(t, stdout, "System.Embedded.1/")
+	assert.Contains(t, stderr, "cd: /redfish/v1/Nowhere: HTTP 404")
+
+	configFile, dellHost, _ := writeFleetConfig(t)
+	_, _, err = runCommand("shell", "other-host", "--config", configFile)
+	assert.EqualError(t, err, "other-host is not in the configuration")
+	_, _, err = runCommand("shell", "--config", configFile)
+	assert.EqualError(t, err, "2 servers configured; give the host to connect to")
+
+	rootCmd.SetIn(strings.NewReader("cd Systems/System.Embedded.1\n"))
+	_, _, err = runCommand("shell", dellHost, "--config", configFile)
+	assert.NoError(t, err)
+}
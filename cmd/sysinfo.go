@@ -4,57 +4,74 @@ Copyright © 2024 Angel Vargas <angelvargas@outlook.es>
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"os"
 
-	"github.com/angelhvargas/redfishcli/pkg/client"
 	"github.com/angelhvargas/redfishcli/pkg/config"
-	"github.com/angelhvargas/redfishcli/pkg/logger"
-	"github.com/angelhvargas/redfishcli/pkg/model"
+	"github.com/angelhvargas/redfishcli/pkg/fleet"
+	"github.com/angelhvargas/redfishcli/pkg/inventory"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
 
+var (
+	sysinfoOutput string
+	sysinfoRaw    bool
+)
+
+// sysinfoReport is the system of one server, or why it could not be read.
+type sysinfoReport struct {
+	Hostname          string `json:"hostname" yaml:"hostname"`
+	*inventory.System `yaml:",inline"`
+	Error             *fleet.HostError `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
 // sysinfoCmd represents the sysinfo command
 var sysinfoCmd = &cobra.Command{
 	Use:   "sysinfo",
 	Short: "Get system information",
 	Long:  `Retrieve detailed system information including BIOS version, serial number, model, and SKU.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		cfg, err := config.LoadConfigOrEnv(cfgFile, bmcType, bmcUsername, bmcPassword, bmcHost)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadServers(bmcHost)
 		if err != nil {
-			logger.Log.Error(err.Error())
-			os.Exit(1)
+			return err
 		}
+		cmd.SilenceUsage = true
 
-		results := make([]*model.ServerInfo, 0)
-
-		for _, server := range cfg.Servers {
-			c, err := client.NewClient(server.Type, config.BMCConnConfig{
-				Hostname: server.Hostname,
-				Username: server.Username,
-				Password: server.Password,
-			})
+		results := make([]sysinfoReport, 0)
+		summary := runFleet(cfg.Servers, func(ctx context.Context, server config.ServerConfig) (*inventory.System, error) {
+			c, err := newServerClient(ctx, server)
 			if err != nil {
-				logger.Log.Errorf("Error creating client for server %s: %s", server.Hostname, err)
-				continue
+				return nil, err
 			}
-
-			info, err := c.GetServerInfo()
+			inv, err := inventory.Collect(c, server.Hostname, inventory.PartSystem)
 			if err != nil {
-				logger.Log.Errorf("Error getting sysinfo for server %s: %s", server.Hostname, err)
-				continue
+				return nil, err
 			}
-			results = append(results, info)
-		}
+			return &inv.System, nil
+		}, func(r fleet.Result[*inventory.System]) {
+			results = append(results, sysinfoReport{Hostname: r.Server.Hostname, System: r.Value, Error: fleet.Describe(r.Err)})
+		})
 
 		printSysInfo(results)
+		return fleetError(summary)
 	},
 }
 
-func printSysInfo(results []*model.ServerInfo) {
-	switch output {
+func printSysInfo(results []sysinfoReport) {
+	if sysinfoRaw {
+		payloads := make([]json.RawMessage, 0, len(results))
+		for _, info := range results {
+			if info.System != nil {
+				payloads = append(payloads, info.Raw)
+			}
+		}
+		printRaw(payloads)
+		return
+	}
+
+	switch sysinfoOutput {
 	case "json":
 		data, _ := json.MarshalIndent(results, "", "  ")
 		fmt.Println(string(data))
@@ -64,12 +81,15 @@ func printSysInfo(results []*model.ServerInfo) {
 	default:
 		// Simple text output
 		for _, info := range results {
+			if info.System == nil {
+				continue
+			}
 			fmt.Printf("ID: %s\n", info.ID)
 			fmt.Printf("Manufacturer: %s\n", info.Manufacturer)
 			fmt.Printf("Model: %s\n", info.Model)
 			fmt.Printf("Serial Number: %s\n", info.SerialNumber)
 			fmt.Printf("SKU: %s\n", info.SKU)
-			fmt.Printf("BIOS Version: %s\n", info.BiosVersion)
+			fmt.Printf("BIOS Version: %s\n", info.BIOSVersion)
 			fmt.Printf("Power State: %s\n", info.PowerState)
 			fmt.Printf("Health: %s\n", info.Status.Health)
 			fmt.Println("--------------------------------------------------")
@@ -79,5 +99,6 @@ func printSysInfo(results []*model.ServerInfo) {
 
 func init() {
 	rootCmd.AddCommand(sysinfoCmd)
-	sysinfoCmd.PersistentFlags().StringVarP(&output, "output", "o", "text", "Output format (json, yaml, text)")
+	sysinfoCmd.PersistentFlags().StringVarP(&sysinfoOutput, "output", "o", "text", "Output format (json, yaml, text)")
+	sysinfoCmd.Flags().BoolVar(&sysinfoRaw, "raw", false, "print the original BMC JSON instead of formatted output")
 }
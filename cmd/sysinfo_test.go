@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"io"
 	"os"
 	"testing"
@@ -10,6 +12,7 @@ import (
 	"github.com/angelhvargas/redfishcli/pkg/config"
 	"github.com/angelhvargas/redfishcli/pkg/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSysInfoCmd(t *testing.T) {
@@ -62,3 +65,35 @@ servers:
 	assert.Contains(t, output, "Model: R740")
 	mockClient.AssertExpectations(t)
 }
+
+func TestSysInfoRaw(t *testing.T) {
+	server := startHealthEmulator(t, nil)
+	configFile := "config_test_raw.yaml"
+	configContent := fmt.Sprintf(`
+servers:
+  - type: idrac
+    hostname: %s
+    username: root
+    password: calvin
+`, server.Hostname)
+	err := os.WriteFile(configFile, []byte(configContent), 0644)
+	assert.NoError(t, err)
+	defer os.Remove(configFile)
+
+	output := captureStdout(t, "sysinfo", "--raw", "--config", configFile)
+
+	var payloads []map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(output), &payloads))
+	require.Len(t, payloads, 1)
+	assert.Equal(t, "System.Embedded.1", payloads[0]["Id"])
+	// Properties without a struct field survive, OEM sections included.
+	assert.Contains(t, payloads[0], "ProcessorSummary")
+	assert.Contains(t, payloads[0]["Oem"], "Dell")
+}
+
+func TestRawFlagUnsupported(t *testing.T) {
+	// Only the commands that can print the BMC's payloads take --raw.
+	_, _, err := runCommand("inventory", "--raw", "-n", "127.0.0.1:1")
+	assert.EqualError(t, err, "unknown flag: --raw")
+	assert.Equal(t, exitFailed, exitCode(err))
+}
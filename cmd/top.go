@@ -0,0 +1,204 @@
+/*
+Copyright © 2024 Angel Vargas <angelvargas@outlook.es>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/angelhvargas/redfishcli/pkg/client"
+	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/angelhvargas/redfishcli/pkg/fleet"
+	"github.com/angelhvargas/redfishcli/pkg/raw"
+	"github.com/angelhvargas/redfishcli/pkg/shell"
+	"github.com/angelhvargas/redfishcli/pkg/top"
+	"github.com/spf13/cobra"
+)
+
+var (
+	topInterval time.Duration
+	topOnce     bool
+)
+
+// topCmd represents the top command
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Show a live dashboard of the configured servers",
+	Long: `Show a continuously refreshing dashboard of every configured server: its
+power state, overall health, RAID controller and drive status, power supply
+and fan alerts, and the system event log entries that appeared since top was
+started. All servers are read concurrently every --interval.
+
+Keys:
+  up/down, j/k    select a server
+  enter           show the selected server in detail
+  esc             back to the list
+  r               refresh now
+  q, Ctrl-C       quit
+
+In the detail view the identify LED and the power of the server can be
+changed; every action asks for confirmation with y:
+  i / I           blink / turn off the identify LED
+  o               power on
+  s / f           graceful shutdown / force off
+  b / B           graceful restart / force restart
+
+With --once, or when stdout is not a terminal, the fleet is read once and the
+table printed without colors.
+
+Example:
+  redfishcli top --interval 30s
+  redfishcli top --once`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadServers(bmcHost)
+		if err != nil {
+			return err
+		}
+		if topInterval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+		cmd.SilenceUsage = true
+
+		var hostnames []string
+		for _, server := range cfg.Servers {
+			hostnames = append(hostnames, server.Hostname)
+		}
+		dashboard := top.New(hostnames, topInterval)
+		refresh := func() []*top.Host { return topSnapshots(cfg.Servers) }
+
+		in, out := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+		if topOnce || !shell.IsTerminal(in) || !shell.IsTerminal(out) {
+			dashboard.Update(refresh(), time.Now())
+			dashboard.Render(cmd.OutOrStdout(), 0)
+			return nil
+		}
+
+		restore, err := shell.MakeRaw(in)
+		if err != nil {
+			return err
+		}
+		defer restore()
+		// Draw on the alternate screen with the cursor hidden, and give the
+		// screen back as it was on the way out.
+		fmt.Print("\x1b[?1049h\x1b[?25l")
+		defer fmt.Print("\x1b[?25h\x1b[?1049l")
+
+		dashboard.Color = true
+		return dashboard.Run(os.Stdin, os.Stdout, top.Options{
+			Interval: topInterval,
+			Refresh:  refresh,
+			Act: func(action top.Action) error {
+				return topAct(cfg.Servers, action)
+			},
+			Height: func() int {
+				_, height, _ := shell.Size(out)
+				return height
+			},
+		})
+	},
+}
+
+// topSnapshots reads every server as --parallel and --timeout ask. A server
+// that could not be read at all shows its error.
+func topSnapshots(servers []config.ServerConfig) []*top.Host {
+	results, _ := fleet.Collect(context.Background(), servers, fleetOptions(), func(ctx context.Context, server config.ServerConfig) (*top.Host, error) {
+		server, err := resolveServer(ctx, server)
+		if err != nil {
+			return nil, err
+		}
+		c, err := newServerClient(ctx, server)
+		if err != nil {
+			return nil, err
+		}
+		return top.Snapshot(c, server.Hostname), nil
+	})
+	hosts := make([]*top.Host, 0, len(results))
+	for _, result := range results {
+		host := result.Value
+		if host == nil {
+			host = &top.Host{Hostname: result.Server.Hostname, Err: result.Err}
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
+
+// topAct runs an action confirmed on the dashboard.
+func topAct(servers []config.ServerConfig, action top.Action) error {
+	var server *config.ServerConfig
+	for i := range servers {
+		if servers[i].Hostname == action.Hostname {
+			server = &servers[i]
+		}
+	}
+	if server == nil {
+		return fmt.Errorf("%s is not in the configuration", action.Hostname)
+	}
+	resolved, err := resolveServer(context.Background(), *server)
+	if err != nil {
+		return err
+	}
+	server = &resolved
+
+	if action.Kind == top.ActionIdentify {
+		return setIdentifyLED(*server, action.On)
+	}
+	c, err := newServerClient(context.Background(), *server)
+	if err != nil {
+		return err
+	}
+	pm, err := client.Power(c)
+	if err != nil {
+		return err
+	}
+	if action.ResetType == "GracefulRestart" {
+		err = pm.Reboot()
+	} else {
+		err = pm.SetPowerState(action.ResetType)
+	}
+	return client.Explain(c, client.CapabilityPower, err)
+}
+
+// setIdentifyLED turns the identify LED of the server's system on or off.
+// Systems implementing LocationIndicatorActive are patched with it, others
+// with the IndicatorLED it deprecates.
+func setIdentifyLED(server config.ServerConfig, on bool) error {
+	c := raw.New(raw.Options{Host: server.Hostname, Username: server.Username, Password: server.Password, HTTP: serverHTTPConfig(context.Background())})
+	defer c.Close()
+
+	systems, err := c.Get("/redfish/v1/Systems")
+	if err != nil {
+		return err
+	}
+	members, _ := systems.Body.(map[string]interface{})["Members"].([]interface{})
+	if len(members) == 0 {
+		return errors.New("the BMC lists no system")
+	}
+	uri, _ := members[0].(map[string]interface{})["@odata.id"].(string)
+	system, err := c.Get(uri)
+	if err != nil {
+		return err
+	}
+
+	patch := map[string]interface{}{"IndicatorLED": "Off"}
+	if on {
+		patch["IndicatorLED"] = "Blinking"
+	}
+	if _, ok := system.Body.(map[string]interface{})["LocationIndicatorActive"]; ok {
+		patch = map[string]interface{}{"LocationIndicatorActive": on}
+	}
+	body, _ := json.Marshal(patch)
+	_, err = c.Do("PATCH", uri, body)
+	return err
+}
+
+func init() {
+	rootCmd.AddCommand(topCmd)
+	topCmd.Flags().DurationVar(&topInterval, "interval", 10*time.Second, "time between two refreshes")
+	topCmd.Flags().BoolVar(&topOnce, "once", false, "read the fleet once, print the table and exit")
+}
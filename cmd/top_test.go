@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+	"github.com/angelhvargas/redfishcli/pkg/raw"
+	"github.com/angelhvargas/redfishcli/pkg/top"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopCmdOnce(t *testing.T) {
+	configFile, dellHost, lenovoHost := writeFleetConfig(t)
+	oldConfig, oldOnce := cfgFile, topOnce
+	t.Cleanup(func() { cfgFile, topOnce = oldConfig, oldOnce })
+
+	stdout, _, err := runCommand("top", "--once", "--config", configFile)
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "redfishcli top - 2 servers, refreshed ")
+	assert.Regexp(t, `\n> `+dellHost+` +On +OK +OK 2 drives +- +0\n`, stdout)
+	assert.Regexp(t, `\n  `+lenovoHost+` +On +OK +OK \d+ drives +- +0\n`, stdout)
+}
+
+func TestTopAct(t *testing.T) {
+	server := startHealthEmulator(t, nil)
+	servers := []config.ServerConfig{server}
+	const system = "/redfish/v1/Systems/System.Embedded.1"
+	property := func(name string) interface{} {
+		c := raw.New(raw.Options{Host: server.Hostname, Username: "root", Password: "calvin", HTTP: httpclient.DefaultConfig()})
+		resp, err := c.Get(system)
+		require.NoError(t, err)
+		return resp.Body.(map[string]interface{})[name]
+	}
+
+	require.NoError(t, topAct(servers, top.Action{Hostname: server.Hostname, Kind: top.ActionIdentify, On: true}))
+	assert.Equal(t, "Blinking", property("IndicatorLED"))
+	require.NoError(t, topAct(servers, top.Action{Hostname: server.Hostname, Kind: top.ActionIdentify}))
+	assert.Equal(t, "Off", property("IndicatorLED"))
+
+	require.NoError(t, topAct(servers, top.Action{Hostname: server.Hostname, Kind: top.ActionPower, ResetType: "ForceOff"}))
+	assert.Equal(t, "Off", property("PowerState"))
+
+	err := topAct(servers, top.Action{Hostname: "other", Kind: top.ActionPower, ResetType: "On"})
+	assert.EqualError(t, err, "other is not in the configuration")
+}
@@ -0,0 +1,176 @@
+/*
+Copyright © 2024 Angel Vargas <angelvargas@outlook.es>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/angelhvargas/redfishcli/pkg/fleet"
+	"github.com/angelhvargas/redfishcli/pkg/mockup"
+	"github.com/angelhvargas/redfishcli/pkg/schema"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	validateMax    int
+	validateAll    bool
+	validateOutput string
+)
+
+// validationReport is the schema validation of one BMC.
+type validationReport struct {
+	Hostname  string `json:"hostname" yaml:"hostname"`
+	Resources int    `json:"resources" yaml:"resources"`
+	Valid     int    `json:"valid" yaml:"valid"`
+	Invalid   int    `json:"invalid" yaml:"invalid"`
+	Skipped   int    `json:"skipped" yaml:"skipped"`
+	// Results lists the invalid and skipped resources, or every resource
+	// with --all.
+	Results []schema.Result `json:"results" yaml:"results"`
+	// FetchErrors maps the URIs that could not be read to the error.
+	FetchErrors map[string]string `json:"fetch_errors,omitempty" yaml:"fetch_errors,omitempty"`
+	// Error is set when the server could not be crawled.
+	Error *fleet.HostError `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate [host]",
+	Short: "Check every resource of a BMC against the Redfish schemas",
+	Long: `Crawl the Redfish tree of each BMC and check every resource against the DMTF
+JSON schema its @odata.type names, using the schemas bundled with redfishcli.
+
+Missing required properties, properties of the wrong JSON type and enum values
+the schema does not list are reported per URI with a JSON pointer to the
+property, ready to attach to a vendor bug. Resources whose schema is not
+bundled, such as OEM resources, are reported as skipped. The command fails when
+any resource is invalid.
+
+To check only the resources another command reads, pass --validate-schema to
+that command instead.
+
+Example:
+  redfishcli validate 192.168.1.100 -u root -p calvin -o json > r740-schema.json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		host := bmcHost
+		if len(args) == 1 {
+			host = args[0]
+		}
+		cfg, err := loadServers(host)
+		if err != nil {
+			return err
+		}
+		validator, err := schema.Bundled()
+		if err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+
+		var reports []validationReport
+		invalid := 0
+		summary := runFleet(cfg.Servers, func(ctx context.Context, server config.ServerConfig) (validationReport, error) {
+			report := validationReport{Hostname: server.Hostname, Results: []schema.Result{}}
+			result, err := mockup.Crawl(mockup.Options{
+				Host:         server.Hostname,
+				Username:     server.Username,
+				Password:     server.Password,
+				HTTP:         serverHTTPConfig(ctx),
+				MaxResources: validateMax,
+				UseSession:   true,
+			}, func(uri string, doc interface{}, body []byte, header http.Header) error {
+				res := validator.Validate(uri, doc)
+				report.Resources++
+				switch res.Status {
+				case schema.StatusValid:
+					report.Valid++
+				case schema.StatusInvalid:
+					report.Invalid++
+				case schema.StatusSkipped:
+					report.Skipped++
+				}
+				if validateAll || res.Status != schema.StatusValid {
+					report.Results = append(report.Results, res)
+				}
+				return nil
+			})
+			if err != nil {
+				return report, err
+			}
+			for uri, err := range result.Errors {
+				if report.FetchErrors == nil {
+					report.FetchErrors = make(map[string]string)
+				}
+				report.FetchErrors[uri] = err.Error()
+			}
+			sort.Slice(report.Results, func(i, j int) bool { return report.Results[i].URI < report.Results[j].URI })
+			return report, nil
+		}, func(r fleet.Result[validationReport]) {
+			report := r.Value
+			if report.Hostname == "" {
+				report = validationReport{Hostname: r.Server.Hostname, Results: []schema.Result{}}
+			}
+			report.Error = fleet.Describe(r.Err)
+			if r.Err == nil {
+				invalid += report.Invalid
+			}
+			reports = append(reports, report)
+		})
+
+		printValidation(reports)
+		if err := fleetError(summary); err != nil {
+			return err
+		}
+		return unhealthyError(invalid, "%d resources violate their schema", invalid)
+	},
+}
+
+func printValidation(reports []validationReport) {
+	switch validateOutput {
+	case "json":
+		data, _ := json.MarshalIndent(reports, "", "  ")
+		fmt.Println(string(data))
+	case "yaml":
+		data, _ := yaml.Marshal(reports)
+		fmt.Println(string(data))
+	default:
+		for _, r := range reports {
+			if r.Error != nil {
+				// The summary on stderr says why.
+				continue
+			}
+			fmt.Printf("%s: %d resources, %d valid, %d invalid, %d skipped\n", r.Hostname, r.Resources, r.Valid, r.Invalid, r.Skipped)
+			for _, res := range r.Results {
+				if res.Status != schema.StatusInvalid {
+					continue
+				}
+				fmt.Printf("  %s (%s)\n", res.URI, res.Schema)
+				for _, v := range res.Violations {
+					fmt.Printf("    %s: %s: %s\n", v.Path, v.Kind, v.Message)
+				}
+			}
+			uris := make([]string, 0, len(r.FetchErrors))
+			for uri := range r.FetchErrors {
+				uris = append(uris, uri)
+			}
+			sort.Strings(uris)
+			for _, uri := range uris {
+				fmt.Printf("  %s: not read: %s\n", uri, r.FetchErrors[uri])
+			}
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+	validateCmd.PersistentFlags().StringVarP(&validateOutput, "output", "o", "text", "Output format (json, yaml, text)")
+	validateCmd.Flags().IntVar(&validateMax, "max", 0, "stop after checking this many resources (0 means no limit)")
+	validateCmd.Flags().BoolVar(&validateAll, "all", false, "also list the resources that are valid")
+}
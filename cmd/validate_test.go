@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/angelhvargas/redfishcli/pkg/client"
+	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/angelhvargas/redfishcli/pkg/emulator"
+	"github.com/angelhvargas/redfishcli/pkg/idrac"
+	"github.com/angelhvargas/redfishcli/pkg/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validateSystem = "/redfish/v1/Systems/System.Embedded.1"
+
+// startBrokenIDRAC starts an iDRAC emulator whose system resource violates
+// its schema in the ways firmware regressions do.
+func startBrokenIDRAC(t *testing.T) string {
+	emu, err := emulator.New(emulator.IDRAC(), emulator.Options{Username: "root", Password: "calvin"})
+	require.NoError(t, err)
+	system, ok := emu.Resource(validateSystem)
+	require.True(t, ok)
+	delete(system, "Name")
+	system["PowerState"] = "Unknown"
+	system["MemorySummary"] = map[string]interface{}{"TotalSystemMemoryGiB": "64"}
+	emu.SetResource(validateSystem, system)
+
+	srv, err := emu.Start("127.0.0.1:0", emulator.StartOptions{})
+	require.NoError(t, err)
+	t.Cleanup(func() { srv.Close() })
+
+	client.ResetRegistry()
+	client.Register("idrac", func(cfg config.BMCConnConfig) client.ServerClient {
+		return idrac.NewClient(config.IDRACConfig{BMCConnConfig: cfg})
+	})
+	return srv.Host
+}
+
+func TestValidateCmd(t *testing.T) {
+	host := startBrokenIDRAC(t)
+	oldOutput, oldConfig := validateOutput, cfgFile
+	cfgFile = ""
+	t.Cleanup(func() { validateOutput, cfgFile, validateAll = oldOutput, oldConfig, false })
+
+	stdout, _, err := runCommand("validate", host, "-u", "root", "-p", "calvin", "-o", "json")
+	require.EqualError(t, err, "1 resources violate their schema")
+	assert.Equal(t, exitUnhealthy, exitCode(err))
+
+	var reports []validationReport
+	require.NoError(t, json.Unmarshal([]byte(stdout), &reports))
+	require.Len(t, reports, 1)
+	r := reports[0]
+	assert.Equal(t, host, r.Hostname)
+	assert.Equal(t, 1, r.Invalid)
+	assert.Positive(t, r.Valid)
+	assert.Equal(t, r.Resources, r.Valid+r.Invalid+r.Skipped)
+
+	var system *schema.Result
+	for i := range r.Results {
+		if r.Results[i].URI == validateSystem {
+			system = &r.Results[i]
+		}
+	}
+	require.NotNil(t, system)
+	assert.Equal(t, schema.StatusInvalid, system.Status)
+	assert.ElementsMatch(t, []schema.Violation{
+		{Path: "/Name", Kind: schema.KindMissingRequired, Message: "required property Name is missing"},
+		{Path: "/PowerState", Kind: schema.KindInvalidEnum, Message: `"Unknown" is not one of On, Off, PoweringOn, PoweringOff, Paused`},
+		{Path: "/MemorySummary/TotalSystemMemoryGiB", Kind: schema.KindWrongType, Message: "got string, want number or null"},
+	}, system.Violations)
+
+	stdout, _, err = runCommand("validate", host, "-u", "root", "-p", "calvin", "-o", "text")
+	require.Error(t, err)
+	assert.Contains(t, stdout, validateSystem+" (ComputerSystem.v1_20_0)")
+	assert.Contains(t, stdout, "/Name: missing_required: required property Name is missing")
+}
+
+func TestValidateSchemaFlag(t *testing.T) {
+	host := startBrokenIDRAC(t)
+	configFile := "config_test_validate.yaml"
+	content := fmt.Sprintf(`
+servers:
+  - type: idrac
+    hostname: %s
+    username: root
+    password: calvin
+`, host)
+	require.NoError(t, os.WriteFile(configFile, []byte(content), 0644))
+	t.Cleanup(func() { os.Remove(configFile); validateSchema = false })
+
+	stdout, stderr, err := runCommand("sysinfo", "--validate-schema", "--config", configFile)
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "ID: System.Embedded.1")
+
+	// The violations go to stderr as JSON; find the array among the log lines.
+	start := -1
+	for i := range stderr {
+		if stderr[i] == '[' {
+			start = i
+			break
+		}
+	}
+	require.GreaterOrEqual(t, start, 0, stderr)
+	var invalid []schema.Result
+	require.NoError(t, json.Unmarshal([]byte(stderr[start:]), &invalid))
+	require.Len(t, invalid, 1)
+	assert.Equal(t, validateSystem, invalid[0].URI)
+	assert.Len(t, invalid[0].Violations, 3)
+}
@@ -0,0 +1,271 @@
+/*
+Copyright © 2024 Angel Vargas <angelvargas@outlook.es>
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/angelhvargas/redfishcli/pkg/credstore"
+	"github.com/spf13/cobra"
+)
+
+var vaultAgentTimeout time.Duration
+
+// vaultCmd groups the commands that manage the local credential store.
+var vaultCmd = &cobra.Command{
+	Use:   "vault",
+	Short: "Manage the encrypted local credential store",
+	Long: `Keep BMC credentials in a local file encrypted with a key derived from a
+passphrase (argon2id, XChaCha20-Poly1305), for machines without a secrets
+manager. This is unrelated to vault: password references, which read
+HashiCorp Vault.
+
+Entries are keyed by host (a hostname or a pattern such as r12-*), group or
+selector:
+  host:10.0.0.5  host:r12-*  group:compute  selector:site=ams,role=gpu
+A key without a kind is a host. Servers without a username or password take
+them from the store: an exact host entry first, then a host pattern, a group
+and a selector, each in the order they were set.
+
+The store is REDFISHCLI_VAULT_FILE, else the context's vault, else
+~/.redfishcli/vault.json. The passphrase is asked for on the terminal, or
+taken from REDFISHCLI_VAULT_PASSPHRASE, which may be a reference such as
+cmd:pass show redfishcli. Run 'redfishcli vault agent' to keep the key in
+memory, so that commands run one after the other ask for it once.
+
+Example:
+  redfishcli vault init
+  redfishcli vault set group:compute -u root
+  redfishcli vault set host:10.0.0.5 -u admin -p secret
+  redfishcli vault list`,
+}
+
+var vaultInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create an empty credential store",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		path := vaultFile()
+		if credstore.Exists(path) {
+			return fmt.Errorf("%s already exists", path)
+		}
+		passphrase, err := newVaultPassphrase()
+		if err != nil {
+			return err
+		}
+		if _, err := credstore.Init(path, passphrase); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Created %s\n", path)
+		return nil
+	},
+}
+
+var vaultSetCmd = &cobra.Command{
+	Use:   "set <key>",
+	Short: "Store the credentials of a host, group or selector",
+	Long: `Store the username given with --username and the password given with
+--password, or typed when --password is not given, under the key.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := parseVaultKey(args[0])
+		if err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+		store, err := openVault()
+		if err != nil {
+			return err
+		}
+		password := bmcPassword
+		if password == "" {
+			if password, err = readSecret(fmt.Sprintf("Password for %s: ", key)); err != nil {
+				return err
+			}
+		}
+		store.Set(credstore.Entry{Key: key, Username: bmcUsername, Password: password})
+		return store.Save()
+	},
+}
+
+var vaultGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the credentials stored under a key",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := parseVaultKey(args[0])
+		if err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+		store, err := openVault()
+		if err != nil {
+			return err
+		}
+		entry, ok := store.Get(key)
+		if !ok {
+			return fmt.Errorf("no credentials stored for %s", key)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "username: %s\npassword: %s\n", entry.Username, entry.Password)
+		return nil
+	},
+}
+
+var vaultRmCmd = &cobra.Command{
+	Use:   "rm <key>",
+	Short: "Remove the credentials stored under a key",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := parseVaultKey(args[0])
+		if err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+		store, err := openVault()
+		if err != nil {
+			return err
+		}
+		if !store.Remove(key) {
+			return fmt.Errorf("no credentials stored for %s", key)
+		}
+		return store.Save()
+	},
+}
+
+var vaultListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the stored keys and usernames",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		store, err := openVault()
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "KEY\tUSERNAME")
+		for _, e := range store.Entries {
+			fmt.Fprintf(w, "%s\t%s\n", e.Key, e.Username)
+		}
+		return w.Flush()
+	},
+}
+
+var vaultAgentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Keep the store's key in memory for later commands",
+	Long: `Run in the foreground, keeping the key of the store in memory once a
+command has opened it, so that the commands that follow do not ask for the
+passphrase again. The key is forgotten after --timeout, on 'redfishcli vault
+lock' and when the agent exits. The socket is ~/.redfishcli/vault-agent.sock,
+or REDFISHCLI_VAULT_AGENT, and only the current user can use it.
+
+Example:
+  redfishcli vault agent --timeout 1h &
+  redfishcli vault unlock`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		fmt.Fprintf(cmd.ErrOrStderr(), "Vault agent listening on %s\n", credstore.AgentSocket())
+		return credstore.RunAgent(ctx, credstore.AgentSocket(), vaultAgentTimeout)
+	},
+}
+
+var vaultLockCmd = &cobra.Command{
+	Use:   "lock",
+	Short: "Make the agent forget the store's key",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return credstore.Lock()
+	},
+}
+
+var vaultUnlockCmd = &cobra.Command{
+	Use:   "unlock",
+	Short: "Ask for the passphrase and hand the key to the agent",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		if !credstore.AgentRunning() {
+			return fmt.Errorf("no agent on %s; start one with 'redfishcli vault agent'", credstore.AgentSocket())
+		}
+		_, err := openVault()
+		return err
+	},
+}
+
+// parseVaultKey parses a store key, checking that selectors parse.
+func parseVaultKey(s string) (credstore.Key, error) {
+	key, err := credstore.ParseKey(s)
+	if err != nil {
+		return credstore.Key{}, err
+	}
+	if key.Kind == credstore.KeySelector {
+		if _, err := config.ParseSelector(key.Value); err != nil {
+			return credstore.Key{}, err
+		}
+	}
+	return key, nil
+}
+
+// openVault opens the credential store, asking for the passphrase unless
+// the agent has the key.
+func openVault() (*credstore.Store, error) {
+	path := vaultFile()
+	if !credstore.Exists(path) {
+		return nil, fmt.Errorf("no credential store at %s; create one with 'redfishcli vault init'", path)
+	}
+	return credstore.Open(path, vaultPassphrase)
+}
+
+// vaultFile returns the path of the credential store: REDFISHCLI_VAULT_FILE,
+// else the context's store, else the default one.
+func vaultFile() string {
+	if os.Getenv("REDFISHCLI_VAULT_FILE") == "" && activeContext != nil && activeContext.Vault != "" {
+		return activeContext.Vault
+	}
+	return credstore.DefaultPath()
+}
+
+// newVaultPassphrase returns the passphrase of a new store, asking for it
+// twice when it is typed.
+func newVaultPassphrase() (string, error) {
+	if os.Getenv("REDFISHCLI_VAULT_PASSPHRASE") != "" {
+		return vaultPassphrase()
+	}
+	passphrase, err := readSecret("New vault passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", errors.New("empty passphrase")
+	}
+	again, err := readSecret("Repeat the passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if again != passphrase {
+		return "", errors.New("the passphrases do not match")
+	}
+	return passphrase, nil
+}
+
+func init() {
+	config.CredentialStorePath = vaultFile
+	rootCmd.AddCommand(vaultCmd)
+	vaultCmd.AddCommand(vaultInitCmd, vaultSetCmd, vaultGetCmd, vaultRmCmd, vaultListCmd, vaultAgentCmd, vaultLockCmd, vaultUnlockCmd)
+	vaultAgentCmd.Flags().DurationVar(&vaultAgentTimeout, "timeout", 15*time.Minute, "forget the key this long after it was handed over (0 keeps it until lock)")
+}
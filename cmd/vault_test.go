@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVault(t *testing.T) {
+	server := startHealthEmulator(t, nil)
+	dir := t.TempDir()
+	t.Setenv("REDFISHCLI_VAULT_FILE", filepath.Join(dir, "vault.json"))
+	t.Setenv("REDFISHCLI_VAULT_AGENT", filepath.Join(dir, "agent.sock"))
+	t.Setenv("REDFISHCLI_VAULT_PASSPHRASE", "correct horse")
+	t.Setenv("BMC_USERNAME", "")
+	t.Setenv("BMC_PASSWORD", "")
+	cfgFile = ""
+	t.Cleanup(func() { bmcHost, bmcUsername, bmcPassword = "", "", "" })
+
+	_, _, err := runCommand("vault", "list")
+	assert.ErrorContains(t, err, "no credential store")
+
+	stdout, _, err := runCommand("vault", "init")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Created "+filepath.Join(dir, "vault.json"))
+	_, _, err = runCommand("vault", "init")
+	assert.ErrorContains(t, err, "already exists")
+
+	_, _, err = runCommand("vault", "set", server.Hostname, "-u", "root", "-p", "calvin")
+	require.NoError(t, err)
+	_, _, err = runCommand("vault", "set", "group:compute", "-u", "admin", "-p", "secret")
+	require.NoError(t, err)
+	_, _, err = runCommand("vault", "set", "selector:=ams", "-u", "admin", "-p", "secret")
+	assert.Error(t, err)
+	bmcUsername, bmcPassword = "", ""
+
+	stdout, _, err = runCommand("vault", "list")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "host:"+server.Hostname)
+	assert.Contains(t, stdout, "group:compute")
+	assert.NotContains(t, stdout, "calvin")
+
+	stdout, _, err = runCommand("vault", "get", "host:"+server.Hostname)
+	require.NoError(t, err)
+	assert.Equal(t, "username: root\npassword: calvin\n", stdout)
+
+	// The credentials come from the store.
+	stdout, _, err = runCommand("sysinfo", "--host", server.Hostname)
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Manufacturer: Dell Inc.")
+
+	t.Setenv("REDFISHCLI_VAULT_PASSPHRASE", "wrong")
+	_, _, err = runCommand("vault", "list")
+	assert.ErrorContains(t, err, "wrong passphrase")
+	// The flags win, so the store is not opened.
+	fleetFile := filepath.Join(dir, "fleet.yaml")
+	require.NoError(t, os.WriteFile(fleetFile, []byte("servers:\n  - {type: idrac, hostname: "+server.Hostname+"}\n"), 0644))
+	stdout, _, err = runCommand("sysinfo", "--config", fleetFile, "-u", "root", "-p", "calvin")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Manufacturer: Dell Inc.")
+	cfgFile, bmcUsername, bmcPassword = "", "", ""
+	t.Setenv("REDFISHCLI_VAULT_PASSPHRASE", "correct horse")
+
+	_, _, err = runCommand("vault", "rm", "group:compute")
+	require.NoError(t, err)
+	_, _, err = runCommand("vault", "rm", "group:compute")
+	assert.ErrorContains(t, err, "no credentials stored for group:compute")
+
+	_, _, err = runCommand("vault", "unlock")
+	assert.ErrorContains(t, err, "no agent on")
+}
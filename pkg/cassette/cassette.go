@@ -0,0 +1,245 @@
+// Package cassette records Redfish HTTP exchanges to a directory and replays
+// them later without touching the network.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+	"github.com/angelhvargas/redfishcli/pkg/redact"
+)
+
+// Redacted replaces any value removed from a cassette.
+const Redacted = redact.Placeholder
+
+// Interaction is one recorded request/response pair as stored on disk.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Request struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Redaction selects what is scrubbed from interactions before they are saved.
+type Redaction = redact.Options
+
+var credentialHeaders = []string{"Authorization", "X-Auth-Token", "Cookie", "Set-Cookie"}
+
+// ParseRedaction turns a comma-separated list such as "credentials,serials"
+// into a Redaction. "none" disables redaction entirely.
+func ParseRedaction(s string) (Redaction, error) {
+	return redact.Parse(s)
+}
+
+// Recorder writes every exchange that passes through its middleware into a
+// cassette directory, one JSON file per interaction.
+type Recorder struct {
+	dir    string
+	redact Redaction
+
+	mu  sync.Mutex
+	seq int
+	err error
+}
+
+// NewRecorder creates dir if needed and returns a recorder writing into it.
+func NewRecorder(dir string, redaction Redaction) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Recorder{dir: dir, redact: redaction}, nil
+}
+
+// Middleware returns the httpclient middleware that feeds the recorder.
+func (r *Recorder) Middleware() httpclient.Middleware {
+	return httpclient.Record(func(ex *httpclient.Exchange) {
+		if ex.TransportError != nil {
+			return
+		}
+		r.save(r.interaction(ex))
+	})
+}
+
+// Err returns the first error hit while writing the cassette, if any.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+func (r *Recorder) interaction(ex *httpclient.Exchange) Interaction {
+	in := Interaction{
+		Request: Request{
+			Method:  ex.Method,
+			URL:     ex.URL,
+			Headers: ex.RequestHeader.Clone(),
+			Body:    string(ex.RequestBody),
+		},
+		Response: Response{
+			StatusCode: ex.StatusCode,
+			Headers:    ex.ResponseHeader.Clone(),
+			Body:       string(ex.ResponseBody),
+		},
+	}
+
+	if r.redact.Credentials {
+		for _, h := range credentialHeaders {
+			if in.Request.Headers.Get(h) != "" {
+				in.Request.Headers.Set(h, Redacted)
+			}
+			if in.Response.Headers.Get(h) != "" {
+				in.Response.Headers.Set(h, Redacted)
+			}
+		}
+	}
+	rules := r.redact.Rules()
+	in.Request.Body = string(redact.JSON([]byte(in.Request.Body), rules))
+	in.Response.Body = string(redact.JSON([]byte(in.Response.Body), rules))
+	return in
+}
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func (r *Recorder) save(in Interaction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq++
+
+	host := "unknown"
+	slug := "root"
+	if u, err := url.Parse(in.Request.URL); err == nil {
+		host = unsafeChars.ReplaceAllString(u.Host, "_")
+		if s := strings.Trim(unsafeChars.ReplaceAllString(u.Path, "_"), "_"); s != "" {
+			slug = s
+		}
+	}
+	dir := filepath.Join(r.dir, host)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		r.setErr(err)
+		return
+	}
+
+	data, err := json.MarshalIndent(in, "", "  ")
+	if err != nil {
+		r.setErr(err)
+		return
+	}
+	name := fmt.Sprintf("%05d-%s-%s.json", r.seq, in.Request.Method, slug)
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+		r.setErr(err)
+	}
+}
+
+func (r *Recorder) setErr(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// Player answers requests from a recorded cassette. It implements
+// http.RoundTripper so it can be used as httpclient.Config.Transport.
+type Player struct {
+	mu     sync.Mutex
+	tracks map[string][]Interaction
+	served map[string]int
+}
+
+// Load reads every interaction found under dir.
+func Load(dir string) (*Player, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(path, ".json") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	p := &Player{tracks: make(map[string][]Interaction), served: make(map[string]int)}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		var in Interaction
+		if err := json.Unmarshal(data, &in); err != nil {
+			return nil, fmt.Errorf("cassette %s: %w", f, err)
+		}
+		key := trackKey(in.Request.Method, in.Request.URL)
+		p.tracks[key] = append(p.tracks[key], in)
+	}
+	return p, nil
+}
+
+func trackKey(method, url string) string {
+	return strings.ToUpper(method) + " " + url
+}
+
+// RoundTrip serves the next recorded response for the request's method and
+// URL. Once a track is exhausted its last response keeps being served, so
+// repeated reads of the same resource still work.
+func (p *Player) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+
+	key := trackKey(req.Method, req.URL.String())
+	p.mu.Lock()
+	track := p.tracks[key]
+	if len(track) == 0 {
+		p.mu.Unlock()
+		return nil, fmt.Errorf("cassette: no recorded interaction for %s", key)
+	}
+	i := p.served[key]
+	if i >= len(track) {
+		i = len(track) - 1
+	}
+	p.served[key]++
+	in := track[i]
+	p.mu.Unlock()
+
+	header := in.Response.Headers.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+		StatusCode:    in.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewBufferString(in.Response.Body)),
+		ContentLength: int64(len(in.Response.Body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,111 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fakeBMC() http.RoundTripper {
+	return httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		var body string
+		switch req.URL.Path {
+		case "/redfish/v1/Systems/1":
+			body = `{"Id":"1","PowerState":"On","SerialNumber":"ABC123","Oem":{"ServiceTag":"XYZ"}}`
+		case "/redfish/v1/SessionService/Sessions":
+			body = `{"Id":"s1","UserName":"root"}`
+		default:
+			return &http.Response{StatusCode: http.StatusNotFound, Header: make(http.Header), Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}, "X-Auth-Token": []string{"secret-token"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	})
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+
+	recorder, err := NewRecorder(dir, Redaction{Credentials: true, Serials: true})
+	require.NoError(t, err)
+
+	cfg := httpclient.DefaultConfig().Use(recorder.Middleware())
+	cfg.Transport = fakeBMC()
+
+	live, err := httpclient.Get("https://bmc1/redfish/v1/Systems/1", "root", "calvin", cfg)
+	require.NoError(t, err)
+	_, err = httpclient.Request("POST", "https://bmc1/redfish/v1/SessionService/Sessions", "root", "calvin",
+		bytes.NewBufferString(`{"UserName":"root","Password":"calvin"}`), cfg)
+	require.NoError(t, err)
+	_, err = httpclient.Get("https://bmc1/redfish/v1/Missing", "root", "calvin", cfg)
+	require.ErrorIs(t, err, httpclient.ErrNotFound)
+	require.NoError(t, recorder.Err())
+
+	files, err := filepath.Glob(filepath.Join(dir, "bmc1", "*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+
+	t.Run("Redacts credentials and serials", func(t *testing.T) {
+		for _, f := range files {
+			data, err := os.ReadFile(f)
+			require.NoError(t, err)
+			assert.NotContains(t, string(data), "calvin")
+			assert.NotContains(t, string(data), "secret-token")
+			assert.NotContains(t, string(data), "ABC123")
+			assert.NotContains(t, string(data), "XYZ")
+		}
+
+		data, err := os.ReadFile(files[0])
+		require.NoError(t, err)
+		var in Interaction
+		require.NoError(t, json.Unmarshal(data, &in))
+		assert.Equal(t, Redacted, in.Request.Headers.Get("Authorization"))
+		assert.Contains(t, in.Response.Body, `"PowerState":"On"`)
+	})
+
+	t.Run("Replays without network", func(t *testing.T) {
+		player, err := Load(dir)
+		require.NoError(t, err)
+
+		replayCfg := httpclient.DefaultConfig()
+		replayCfg.Transport = player
+
+		for i := 0; i < 2; i++ {
+			body, err := httpclient.Get("https://bmc1/redfish/v1/Systems/1", "root", "calvin", replayCfg)
+			require.NoError(t, err)
+			assert.Contains(t, string(body), `"PowerState":"On"`)
+			assert.NotEqual(t, string(live), string(body), "serials should be redacted in the replay")
+		}
+
+		_, err = httpclient.Get("https://bmc1/redfish/v1/Missing", "root", "calvin", replayCfg)
+		assert.ErrorIs(t, err, httpclient.ErrNotFound)
+
+		_, err = httpclient.Get("https://bmc1/redfish/v1/NeverRecorded", "root", "calvin", replayCfg)
+		assert.ErrorContains(t, err, "no recorded interaction")
+	})
+}
+
+func TestParseRedaction(t *testing.T) {
+	r, err := ParseRedaction("credentials,serials")
+	require.NoError(t, err)
+	assert.Equal(t, Redaction{Credentials: true, Serials: true}, r)
+
+	r, err = ParseRedaction("none")
+	require.NoError(t, err)
+	assert.Equal(t, Redaction{}, r)
+
+	_, err = ParseRedaction("passwords")
+	assert.Error(t, err)
+}
@@ -0,0 +1,159 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// AllowableValues lists the values a BMC accepts for the parameters
+// redfishcli sends to it.
+type AllowableValues struct {
+	// ResetTypes are the accepted ComputerSystem.Reset ResetType values.
+	ResetTypes []string
+	// BootTargets are the accepted BootSourceOverrideTarget values.
+	BootTargets []string
+}
+
+// AllowableValuesReader is implemented by backends that can read
+// AllowableValues from the live BMC.
+type AllowableValuesReader interface {
+	AllowableValues() (*AllowableValues, error)
+}
+
+// ReadAllowableValues reads the ResetType and BootSourceOverrideTarget
+// values advertised by the ComputerSystem at systemURI, either inline with
+// @Redfish.AllowableValues or through the reset action's ActionInfo.
+func ReadAllowableValues(fetch Fetcher, systemURI string) (*AllowableValues, error) {
+	var system map[string]interface{}
+	if err := fetch(systemURI, &system); err != nil {
+		return nil, err
+	}
+	return allowableValues(fetch, system), nil
+}
+
+func allowableValues(fetch Fetcher, system map[string]interface{}) *AllowableValues {
+	values := &AllowableValues{}
+
+	actions, _ := system["Actions"].(map[string]interface{})
+	if reset, ok := actions["#ComputerSystem.Reset"].(map[string]interface{}); ok {
+		values.ResetTypes = stringList(reset["ResetType@Redfish.AllowableValues"])
+		if info, ok := reset["@Redfish.ActionInfo"].(string); ok && values.ResetTypes == nil {
+			var actionInfo struct {
+				Parameters []struct {
+					Name            string      `json:"Name"`
+					AllowableValues interface{} `json:"AllowableValues"`
+				} `json:"Parameters"`
+			}
+			if err := fetch(info, &actionInfo); err == nil {
+				for _, p := range actionInfo.Parameters {
+					if p.Name == "ResetType" {
+						values.ResetTypes = stringList(p.AllowableValues)
+					}
+				}
+			}
+		}
+	}
+
+	if boot, ok := system["Boot"].(map[string]interface{}); ok {
+		values.BootTargets = stringList(boot["BootSourceOverrideTarget@Redfish.AllowableValues"])
+	}
+	return values
+}
+
+// ErrInvalidValue matches every InvalidValueError with errors.Is.
+var ErrInvalidValue = errors.New("invalid value")
+
+// InvalidValueError reports a parameter value the BMC does not accept.
+type InvalidValueError struct {
+	Parameter string
+	Value     string
+	Allowed   []string
+	// Suggestion is the closest allowed value, if any is close enough.
+	Suggestion string
+}
+
+func (e *InvalidValueError) Error() string {
+	msg := fmt.Sprintf("invalid %s %q", e.Parameter, e.Value)
+	if e.Suggestion != "" {
+		msg += fmt.Sprintf(": did you mean %q?", e.Suggestion)
+	}
+	return fmt.Sprintf("%s (allowed: %s)", msg, strings.Join(e.Allowed, ", "))
+}
+
+func (e *InvalidValueError) Is(target error) bool {
+	return target == ErrInvalidValue
+}
+
+// Validate checks value against the allowed values of parameter. An empty
+// allowed list means the BMC did not advertise any, so everything passes.
+func Validate(parameter, value string, allowed []string) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+	for _, a := range allowed {
+		if a == value {
+			return nil
+		}
+	}
+	return &InvalidValueError{
+		Parameter:  parameter,
+		Value:      value,
+		Allowed:    allowed,
+		Suggestion: Suggest(value, allowed),
+	}
+}
+
+// Suggest returns the allowed value closest to value: a case-insensitive
+// match, then the nearest by edit distance, then the only value that starts
+// with it. It returns "" when nothing is close.
+func Suggest(value string, allowed []string) string {
+	lower := strings.ToLower(value)
+	for _, a := range allowed {
+		if strings.ToLower(a) == lower {
+			return a
+		}
+	}
+
+	best, bestDist := "", len(lower)/3+1
+	for _, a := range allowed {
+		if d := editDistance(lower, strings.ToLower(a)); d <= bestDist && (best == "" || d < bestDist) {
+			best, bestDist = a, d
+		}
+	}
+	if best != "" {
+		return best
+	}
+
+	var prefixed []string
+	for _, a := range allowed {
+		if lower != "" && strings.HasPrefix(strings.ToLower(a), lower) {
+			prefixed = append(prefixed, a)
+		}
+	}
+	if len(prefixed) == 1 {
+		return prefixed[0]
+	}
+	return ""
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggest(t *testing.T) {
+	allowed := []string{"On", "ForceOff", "GracefulShutdown", "GracefulRestart", "ForceRestart", "Nmi"}
+	tests := map[string]string{
+		"forceoff":       "ForceOff",
+		"ForceOf":        "ForceOff",
+		"GracefulRstart": "GracefulRestart",
+		"Graceful":       "",
+		"GracefulS":      "GracefulShutdown",
+		"Sideways":       "",
+	}
+	for value, want := range tests {
+		assert.Equal(t, want, Suggest(value, allowed), value)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate("ResetType", "On", []string{"On", "ForceOff"}))
+	assert.NoError(t, Validate("ResetType", "Anything", nil), "no advertised values means no validation")
+
+	err := Validate("BootSourceOverrideTarget", "pxe", []string{"Hdd", "Pxe"})
+	assert.ErrorIs(t, err, ErrInvalidValue)
+	assert.EqualError(t, err, `invalid BootSourceOverrideTarget "pxe": did you mean "Pxe"? (allowed: Hdd, Pxe)`)
+
+	err = Validate("BootSourceOverrideTarget", "Floppy9000", []string{"Hdd", "Pxe"})
+	assert.EqualError(t, err, `invalid BootSourceOverrideTarget "Floppy9000" (allowed: Hdd, Pxe)`)
+}
+
+func TestReadAllowableValues(t *testing.T) {
+	fetch := fakeFetcher(map[string]string{
+		"/Systems/1": `{
+			"Actions": {"#ComputerSystem.Reset": {"@Redfish.ActionInfo": "/Systems/1/ResetActionInfo"}},
+			"Boot": {"BootSourceOverrideTarget@Redfish.AllowableValues": ["Pxe", "Cd"]}
+		}`,
+		"/Systems/1/ResetActionInfo": `{"Parameters": [{"Name": "ResetType", "AllowableValues": ["On", "Nmi"]}]}`,
+	})
+	values, err := ReadAllowableValues(fetch, "/Systems/1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Nmi", "On"}, values.ResetTypes)
+	assert.Equal(t, []string{"Cd", "Pxe"}, values.BootTargets)
+}
@@ -0,0 +1,190 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+)
+
+// Capability names an optional feature of a BMC.
+type Capability string
+
+const (
+	CapabilityPower   Capability = "power"
+	CapabilityBoot    Capability = "boot"
+	CapabilityStorage Capability = "storage"
+	CapabilityLogs    Capability = "logs"
+)
+
+var capabilityNames = map[Capability]string{
+	CapabilityPower:   "power management",
+	CapabilityBoot:    "boot configuration",
+	CapabilityStorage: "storage inspection",
+	CapabilityLogs:    "event logs",
+}
+
+// ErrNotSupported matches every NotSupportedError with errors.Is.
+var ErrNotSupported = errors.New("not supported on this BMC")
+
+// NotSupportedError reports a capability that the backend does not
+// implement or that the BMC does not expose.
+type NotSupportedError struct {
+	Capability Capability
+}
+
+func (e *NotSupportedError) Error() string {
+	name, ok := capabilityNames[e.Capability]
+	if !ok {
+		name = string(e.Capability)
+	}
+	return fmt.Sprintf("%s not supported on this BMC", name)
+}
+
+func (e *NotSupportedError) Is(target error) bool {
+	return target == ErrNotSupported
+}
+
+// Capabilities is the result of probing a live BMC.
+type Capabilities struct {
+	Power bool `json:"power" yaml:"power"`
+	// ResetTypes lists the allowed ComputerSystem.Reset values, when the
+	// BMC advertises them.
+	ResetTypes []string `json:"reset_types,omitempty" yaml:"reset_types,omitempty"`
+	Boot       bool     `json:"boot" yaml:"boot"`
+	// BootTargets lists the allowed BootSourceOverrideTarget values.
+	BootTargets []string `json:"boot_targets,omitempty" yaml:"boot_targets,omitempty"`
+	Storage     bool     `json:"storage" yaml:"storage"`
+	Logs        bool     `json:"logs" yaml:"logs"`
+}
+
+// Has reports whether the capability was found.
+func (c *Capabilities) Has(capability Capability) bool {
+	switch capability {
+	case CapabilityPower:
+		return c.Power
+	case CapabilityBoot:
+		return c.Boot
+	case CapabilityStorage:
+		return c.Storage
+	case CapabilityLogs:
+		return c.Logs
+	}
+	return false
+}
+
+// CapabilityProber is implemented by backends that can inspect the live BMC
+// for the features it exposes.
+type CapabilityProber interface {
+	Capabilities() (*Capabilities, error)
+}
+
+// Fetcher GETs a Redfish URI and decodes the JSON response into target.
+type Fetcher func(uri string, target interface{}) error
+
+// ProbeCapabilities inspects the ComputerSystem at systemURI, its reset
+// ActionInfo and storage, and the log entries collection at logURI. Backends
+// implement CapabilityProber with it.
+func ProbeCapabilities(fetch Fetcher, systemURI, logURI string) (*Capabilities, error) {
+	var system map[string]interface{}
+	if err := fetch(systemURI, &system); err != nil {
+		return nil, err
+	}
+	values := allowableValues(fetch, system)
+	caps := &Capabilities{ResetTypes: values.ResetTypes, BootTargets: values.BootTargets}
+
+	actions, _ := system["Actions"].(map[string]interface{})
+	_, caps.Power = actions["#ComputerSystem.Reset"]
+	if boot, ok := system["Boot"].(map[string]interface{}); ok {
+		_, caps.Boot = boot["BootSourceOverrideTarget"]
+	}
+
+	if link, ok := system["Storage"].(map[string]interface{}); ok {
+		if uri, ok := link["@odata.id"].(string); ok {
+			var coll struct {
+				Members []interface{} `json:"Members"`
+			}
+			err := fetch(uri, &coll)
+			if err != nil && !errors.Is(err, httpclient.ErrNotFound) {
+				return nil, err
+			}
+			caps.Storage = err == nil && len(coll.Members) > 0
+		}
+	}
+
+	if logURI != "" {
+		var coll map[string]interface{}
+		err := fetch(logURI, &coll)
+		if err != nil && !errors.Is(err, httpclient.ErrNotFound) {
+			return nil, err
+		}
+		caps.Logs = err == nil
+	}
+	return caps, nil
+}
+
+func stringList(v interface{}) []string {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Explain re-examines a failed call: when c can probe the BMC and the BMC
+// does not expose the capability, it returns a NotSupportedError in place of
+// err. Probing only on failure keeps the successful path at one request.
+func Explain(c ServerClient, capability Capability, err error) error {
+	if err == nil || errors.Is(err, ErrNotSupported) || errors.Is(err, ErrInvalidValue) {
+		return err
+	}
+	p, ok := c.(CapabilityProber)
+	if !ok {
+		return err
+	}
+	if caps, perr := p.Capabilities(); perr == nil && !caps.Has(capability) {
+		return &NotSupportedError{Capability: capability}
+	}
+	return err
+}
+
+// Power returns the PowerManager of c, or a NotSupportedError when the
+// backend does not implement it.
+func Power(c ServerClient) (PowerManager, error) {
+	if pm, ok := c.(PowerManager); ok {
+		return pm, nil
+	}
+	return nil, &NotSupportedError{Capability: CapabilityPower}
+}
+
+// Boot returns the BootManager of c, or a NotSupportedError.
+func Boot(c ServerClient) (BootManager, error) {
+	if bm, ok := c.(BootManager); ok {
+		return bm, nil
+	}
+	return nil, &NotSupportedError{Capability: CapabilityBoot}
+}
+
+// Storage returns the StorageInspector of c, or a NotSupportedError.
+func Storage(c ServerClient) (StorageInspector, error) {
+	if si, ok := c.(StorageInspector); ok {
+		return si, nil
+	}
+	return nil, &NotSupportedError{Capability: CapabilityStorage}
+}
+
+// Logs returns the LogReader of c, or a NotSupportedError.
+func Logs(c ServerClient) (LogReader, error) {
+	if lr, ok := c.(LogReader); ok {
+		return lr, nil
+	}
+	return nil, &NotSupportedError{Capability: CapabilityLogs}
+}
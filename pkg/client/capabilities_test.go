@@ -0,0 +1,90 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFetcher serves JSON documents by URI and 404s everything else.
+func fakeFetcher(docs map[string]string) Fetcher {
+	return func(uri string, target interface{}) error {
+		doc, ok := docs[uri]
+		if !ok {
+			return httpclient.ErrNotFound
+		}
+		return json.Unmarshal([]byte(doc), target)
+	}
+}
+
+func TestProbeCapabilities(t *testing.T) {
+	t.Run("Everything exposed", func(t *testing.T) {
+		fetch := fakeFetcher(map[string]string{
+			"/Systems/1": `{
+				"Actions": {"#ComputerSystem.Reset": {"@Redfish.ActionInfo": "/Systems/1/ResetActionInfo"}},
+				"Boot": {"BootSourceOverrideTarget": "None", "BootSourceOverrideTarget@Redfish.AllowableValues": ["Pxe", "Hdd"]},
+				"Storage": {"@odata.id": "/Systems/1/Storage"}
+			}`,
+			"/Systems/1/ResetActionInfo": `{"Parameters": [{"Name": "ResetType", "AllowableValues": ["On", "ForceOff"]}]}`,
+			"/Systems/1/Storage":         `{"Members": [{"@odata.id": "/Systems/1/Storage/RAID"}]}`,
+			"/Logs/Entries":              `{"Members": []}`,
+		})
+
+		caps, err := ProbeCapabilities(fetch, "/Systems/1", "/Logs/Entries")
+		require.NoError(t, err)
+		assert.Equal(t, &Capabilities{
+			Power:       true,
+			ResetTypes:  []string{"ForceOff", "On"},
+			Boot:        true,
+			BootTargets: []string{"Hdd", "Pxe"},
+			Storage:     true,
+			Logs:        true,
+		}, caps)
+	})
+
+	t.Run("Nothing exposed", func(t *testing.T) {
+		fetch := fakeFetcher(map[string]string{"/Systems/1": `{"Id": "1"}`})
+		caps, err := ProbeCapabilities(fetch, "/Systems/1", "/Logs/Entries")
+		require.NoError(t, err)
+		assert.Equal(t, &Capabilities{}, caps)
+	})
+
+	t.Run("Probe failures are errors", func(t *testing.T) {
+		_, err := ProbeCapabilities(fakeFetcher(nil), "/Systems/1", "")
+		assert.ErrorIs(t, err, httpclient.ErrNotFound)
+	})
+}
+
+func TestCapabilityAccessors(t *testing.T) {
+	logsOnly := new(MockLogReader)
+
+	_, err := Power(logsOnly)
+	assert.ErrorIs(t, err, ErrNotSupported)
+	assert.EqualError(t, err, "power management not supported on this BMC")
+	_, err = Boot(logsOnly)
+	assert.ErrorIs(t, err, ErrNotSupported)
+	_, err = Storage(logsOnly)
+	assert.ErrorIs(t, err, ErrNotSupported)
+	lr, err := Logs(logsOnly)
+	require.NoError(t, err)
+	assert.Same(t, logsOnly, lr)
+}
+
+func TestExplain(t *testing.T) {
+	failure := errors.New("HTTP 400: unexpected error")
+
+	prober := new(MockCapabilityProber)
+	prober.On("Capabilities").Return(&Capabilities{Power: false}, nil).Once()
+	assert.ErrorIs(t, Explain(prober, CapabilityPower, failure), ErrNotSupported)
+
+	prober.On("Capabilities").Return(&Capabilities{Power: true}, nil).Once()
+	assert.Equal(t, failure, Explain(prober, CapabilityPower, failure))
+	prober.AssertExpectations(t)
+
+	assert.NoError(t, Explain(prober, CapabilityPower, nil))
+	assert.Equal(t, failure, Explain(new(MockPowerManager), CapabilityPower, failure))
+}
@@ -8,22 +8,52 @@ import (
 	"github.com/angelhvargas/redfishcli/pkg/model"
 )
 
-// ServerClient defines the interface for interacting with BMCs.
+// ServerClient is what every backend implements. Everything else a BMC can
+// do is an optional capability interface; use Power, Boot, Storage and Logs
+// to obtain one, so "unsupported" can be told apart from "failed".
 type ServerClient interface {
-	GetServerInfo() (*model.ServerInfo, error)
-	GetStorageInfo() (*model.StorageInfo, error)
+	SystemInspector
+}
+
+// SystemInspector reads the ComputerSystem resource.
+type SystemInspector interface {
+	GetServerInfo() (*model.ComputerSystem, error)
+}
+
+// StorageInspector walks storage controllers, volumes and drives.
+type StorageInspector interface {
+	GetStorageInfo() (*model.Collection[model.IDRef], error)
 	GetStorageControllers(config *model.StorageControllerConfig) ([]model.StorageController, error)
-	GetRAIDVolumeInfo(volumeEndpoint string) (*model.RAIDVolume, error)
-	GetStorageControllerInfo(endpoint string) (*model.StorageControllerDetails, error)
+	GetRAIDVolumeInfo(volumeEndpoint string) (*model.Volume, error)
+	GetStorageControllerInfo(endpoint string) (*model.Storage, error)
 	GetStorageDriveDetails(driveEndpoint string) (*model.Drive, error)
-	// Power Management
+}
+
+// PowerManager reads and changes the power state.
+type PowerManager interface {
 	GetPowerState() (string, error)
 	SetPowerState(state string) error
 	Reboot() error
-	// System Information
-	GetBootInfo() (*model.BootInfo, error)
+}
+
+// BootManager reads boot settings and sets one-time boot overrides.
+type BootManager interface {
+	GetBootInfo() (*model.Boot, error)
 	SetBootOrder(device string) error
-	GetSystemEventLog() ([]model.EventLogEntry, error)
+}
+
+// LogReader reads the system event log.
+type LogReader interface {
+	GetSystemEventLog() ([]model.LogEntry, error)
+}
+
+// FullClient is implemented by backends supporting every capability.
+type FullClient interface {
+	ServerClient
+	StorageInspector
+	PowerManager
+	BootManager
+	LogReader
 }
 
 // ClientFactory is a function that creates a new ServerClient.
@@ -0,0 +1,526 @@
+// Package clienttest is a conformance suite for client.ServerClient
+// implementations. A backend hands Run its factory and the emulator profile
+// (built-in or a captured mockup) it is meant to drive, and the suite checks
+// that it behaves like every other backend:
+//
+//   - capability probes match what the BMC exposes;
+//   - errors are returned rather than swallowed, and missing resources
+//     surface as httpclient.ErrNotFound;
+//   - power and boot changes round-trip;
+//   - values outside @Redfish.AllowableValues are rejected before they are
+//     sent;
+//   - paged logs are read completely;
+//   - the normalized inventory lists every component the BMC does.
+//
+// Checks for capability interfaces the backend does not implement are
+// skipped.
+//
+//	func TestConformance(t *testing.T) {
+//		clienttest.Run(t, clienttest.Backend{
+//			Factory: func(cfg config.BMCConnConfig) client.ServerClient { ... },
+//			Profile: emulator.XCC(),
+//		})
+//	}
+package clienttest
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/angelhvargas/redfishcli/pkg/client"
+	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/angelhvargas/redfishcli/pkg/emulator"
+	"github.com/angelhvargas/redfishcli/pkg/fault"
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+	"github.com/angelhvargas/redfishcli/pkg/inventory"
+	"github.com/angelhvargas/redfishcli/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Check names accepted by Backend.Skip.
+const (
+	CheckServerInfo       = "ServerInfo"
+	CheckCapabilities     = "Capabilities"
+	CheckStorageWalk      = "StorageWalk"
+	CheckNotFound         = "NotFound"
+	CheckErrorPropagation = "ErrorPropagation"
+	CheckPowerTransitions = "PowerTransitions"
+	CheckBootOverride     = "BootOverride"
+	CheckAllowableValues  = "AllowableValues"
+	CheckSELPaging        = "SELPaging"
+	CheckInventory        = "Inventory"
+)
+
+// Backend describes the implementation under test.
+type Backend struct {
+	// Factory creates the client, usually the same function given to
+	// client.Register. It must honour BMCConnConfig.HTTPClient.
+	Factory client.ClientFactory
+	// Profile is the service the backend targets.
+	Profile emulator.Profile
+	// Username and Password for the emulator. Default to root/calvin.
+	Username string
+	Password string
+	// Skip lists checks the backend does not support.
+	Skip []string
+}
+
+// Run executes every conformance check as a subtest of t. Each check gets a
+// fresh emulator so state changes do not leak between them.
+func Run(t *testing.T, b Backend) {
+	t.Helper()
+	if b.Username == "" {
+		b.Username = "root"
+	}
+	if b.Password == "" {
+		b.Password = "calvin"
+	}
+
+	checks := []struct {
+		name string
+		fn   func(*testing.T, Backend)
+	}{
+		{CheckServerInfo, checkServerInfo},
+		{CheckCapabilities, checkCapabilities},
+		{CheckStorageWalk, checkStorageWalk},
+		{CheckNotFound, checkNotFound},
+		{CheckErrorPropagation, checkErrorPropagation},
+		{CheckPowerTransitions, checkPowerTransitions},
+		{CheckBootOverride, checkBootOverride},
+		{CheckAllowableValues, checkAllowableValues},
+		{CheckSELPaging, checkSELPaging},
+		{CheckInventory, checkInventory},
+	}
+	for _, c := range checks {
+		t.Run(c.name, func(t *testing.T) {
+			if slices.Contains(b.Skip, c.name) {
+				t.Skipf("%s skipped by the backend", c.name)
+			}
+			c.fn(t, b)
+		})
+	}
+}
+
+// env is one running emulator with a client pointed at it.
+type env struct {
+	emu    *emulator.Emulator
+	host   string
+	client client.ServerClient
+	system string
+}
+
+func start(t *testing.T, b Backend, opts emulator.Options) *env {
+	t.Helper()
+	opts.Username, opts.Password = b.Username, b.Password
+	emu, err := emulator.New(b.Profile, opts)
+	require.NoError(t, err)
+	srv, err := emu.Start("127.0.0.1:0", emulator.StartOptions{})
+	require.NoError(t, err)
+	t.Cleanup(func() { srv.Close() })
+
+	e := &env{emu: emu, host: srv.Host}
+	e.client = e.connect(b, b.Password)
+
+	systems, ok := emu.Resource("/redfish/v1/Systems")
+	require.True(t, ok, "profile has no Systems collection")
+	members, _ := systems["Members"].([]interface{})
+	require.NotEmpty(t, members, "profile has no ComputerSystem")
+	e.system = odataID(members[0])
+	return e
+}
+
+func (e *env) connect(b Backend, password string) client.ServerClient {
+	cfg := httpclient.DefaultConfig()
+	return b.Factory(config.BMCConnConfig{
+		Hostname:   e.host,
+		Username:   b.Username,
+		Password:   password,
+		HTTPClient: &cfg,
+	})
+}
+
+func (e *env) resource(t *testing.T, uri string) map[string]interface{} {
+	t.Helper()
+	res, ok := e.emu.Resource(uri)
+	require.True(t, ok, "emulator has no resource %s", uri)
+	return res
+}
+
+func odataID(v interface{}) string {
+	if m, ok := v.(map[string]interface{}); ok {
+		id, _ := m["@odata.id"].(string)
+		return id
+	}
+	return ""
+}
+
+// need returns the capability T of c, skipping the test when the backend
+// does not implement it.
+func need[T any](t *testing.T, c client.ServerClient) T {
+	t.Helper()
+	v, ok := c.(T)
+	if !ok {
+		var zero T
+		t.Skipf("backend does not implement %T", &zero)
+	}
+	return v
+}
+
+// firstDrive walks the client's storage view down to one controller and
+// drive. ok is false when the backend does not inspect storage.
+func firstDrive(t *testing.T, c client.ServerClient) (controllerID, driveID string, ok bool) {
+	t.Helper()
+	storage, ok := c.(client.StorageInspector)
+	if !ok {
+		return "", "", false
+	}
+	controllers, err := storage.GetStorageControllers(&model.StorageControllerConfig{Type: "RAID"})
+	require.NoError(t, err)
+	for _, ctrl := range controllers {
+		details, err := storage.GetStorageControllerInfo(ctrl.ODataID)
+		require.NoError(t, err)
+		for _, d := range details.Drives {
+			if d.ODataID != "" {
+				return ctrl.ODataID, d.ODataID, true
+			}
+		}
+	}
+	t.Fatal("profile has no drive behind any storage controller")
+	return "", "", false
+}
+
+func checkServerInfo(t *testing.T, b Backend) {
+	e := start(t, b, emulator.Options{})
+	system := e.resource(t, e.system)
+
+	info, err := e.client.GetServerInfo()
+	require.NoError(t, err)
+	require.NotNil(t, info)
+	assert.Equal(t, system["Id"], info.ID)
+	assert.Equal(t, system["Manufacturer"], info.Manufacturer)
+	assert.Equal(t, system["Model"], info.Model)
+	assert.EqualValues(t, system["PowerState"], info.PowerState)
+
+	if power, ok := e.client.(client.PowerManager); ok {
+		state, err := power.GetPowerState()
+		require.NoError(t, err)
+		assert.Equal(t, system["PowerState"], state)
+	}
+}
+
+func checkCapabilities(t *testing.T, b Backend) {
+	e := start(t, b, emulator.Options{})
+	prober := need[client.CapabilityProber](t, e.client)
+
+	caps, err := prober.Capabilities()
+	require.NoError(t, err)
+	implemented := map[client.Capability]bool{}
+	_, implemented[client.CapabilityPower] = e.client.(client.PowerManager)
+	_, implemented[client.CapabilityBoot] = e.client.(client.BootManager)
+	_, implemented[client.CapabilityStorage] = e.client.(client.StorageInspector)
+	_, implemented[client.CapabilityLogs] = e.client.(client.LogReader)
+	for capability, ok := range implemented {
+		if ok {
+			assert.True(t, caps.Has(capability), "the profile exposes %s but the probe missed it", capability)
+		}
+	}
+	if caps.Power {
+		assert.Contains(t, caps.ResetTypes, "ForceOff")
+	}
+
+	// Remove the reset action: the probe must notice, and a failed reset
+	// must be explained as unsupported rather than as a plain failure.
+	system := e.resource(t, e.system)
+	delete(system, "Actions")
+	e.emu.SetResource(e.system, system)
+	caps, err = prober.Capabilities()
+	require.NoError(t, err)
+	assert.False(t, caps.Power)
+	if power, ok := e.client.(client.PowerManager); ok {
+		err := client.Explain(e.client, client.CapabilityPower, power.SetPowerState("ForceOff"))
+		assert.ErrorIs(t, err, client.ErrNotSupported)
+	}
+}
+
+func checkStorageWalk(t *testing.T, b Backend) {
+	e := start(t, b, emulator.Options{})
+	storage := need[client.StorageInspector](t, e.client)
+
+	controllers, err := storage.GetStorageControllers(&model.StorageControllerConfig{Type: "RAID"})
+	require.NoError(t, err)
+	require.NotEmpty(t, controllers)
+
+	walked := 0
+	for _, ctrl := range controllers {
+		details, err := storage.GetStorageControllerInfo(ctrl.ODataID)
+		require.NoError(t, err, ctrl.ODataID)
+		assert.NotEmpty(t, details.ID, "controller %s has no Id", ctrl.ODataID)
+
+		for _, ref := range details.Drives {
+			drive, err := storage.GetStorageDriveDetails(ref.ODataID)
+			require.NoError(t, err, ref.ODataID)
+			assert.Equal(t, e.resource(t, ref.ODataID)["Id"], drive.ID)
+			walked++
+		}
+	}
+	assert.NotZero(t, walked, "no drives were reachable from the storage controllers")
+}
+
+func checkNotFound(t *testing.T, b Backend) {
+	e := start(t, b, emulator.Options{})
+	storage := need[client.StorageInspector](t, e.client)
+	missing := e.system + "/Storage/DoesNotExist"
+
+	ctrl, err := storage.GetStorageControllerInfo(missing)
+	assert.ErrorIs(t, err, httpclient.ErrNotFound)
+	assert.Nil(t, ctrl)
+
+	drive, err := storage.GetStorageDriveDetails(missing + "/Drives/DoesNotExist")
+	assert.ErrorIs(t, err, httpclient.ErrNotFound)
+	assert.Nil(t, drive)
+
+	volume, err := storage.GetRAIDVolumeInfo(missing + "/Volumes/DoesNotExist")
+	assert.ErrorIs(t, err, httpclient.ErrNotFound)
+	assert.Nil(t, volume)
+}
+
+func checkErrorPropagation(t *testing.T, b Backend) {
+	faults := map[string]fault.Rule{
+		"Server error":    {Status: 500},
+		"Malformed JSON":  {Truncate: true},
+		"Dropped":         {Drop: true},
+		"Expired session": {AuthExpiry: true},
+	}
+	for name, rule := range faults {
+		t.Run(name, func(t *testing.T) {
+			// Discover valid identifiers on a healthy endpoint, then read
+			// them through a second endpoint of the same emulator where
+			// every GET fails.
+			e := start(t, b, emulator.Options{})
+			ctrlID, driveID, _ := firstDrive(t, e.client)
+
+			rule.Path = regexp.MustCompile(".")
+			rule.Method = "GET"
+			srv, err := e.emu.Start("127.0.0.1:0", emulator.StartOptions{Faults: fault.New(rule)})
+			require.NoError(t, err)
+			t.Cleanup(func() { srv.Close() })
+			faulty := &env{host: srv.Host}
+			assertReadsFail(t, faulty.connect(b, b.Password), ctrlID, driveID, nil)
+		})
+	}
+
+	t.Run("Bad credentials", func(t *testing.T) {
+		e := start(t, b, emulator.Options{})
+		ctrlID, driveID, _ := firstDrive(t, e.client)
+		bad := e.connect(b, b.Password+"-wrong")
+		assertReadsFail(t, bad, ctrlID, driveID, httpclient.ErrAuthentication)
+		if power, ok := bad.(client.PowerManager); ok {
+			assert.Error(t, power.SetPowerState("ForceOff"), "SetPowerState")
+		}
+		if boot, ok := bad.(client.BootManager); ok {
+			assert.Error(t, boot.SetBootOrder("Pxe"), "SetBootOrder")
+		}
+		assert.Equal(t, "On", e.resource(t, e.system)["PowerState"])
+	})
+}
+
+// assertReadsFail calls every read method the backend implements and
+// requires an error and no result from each, so failures cannot be mistaken
+// for empty data.
+func assertReadsFail(t *testing.T, c client.ServerClient, ctrlID, driveID string, target error) {
+	t.Helper()
+	check := func(name string, empty bool, err error) {
+		t.Helper()
+		if assert.Error(t, err, "%s must return the error", name) && target != nil {
+			assert.ErrorIs(t, err, target, name)
+		}
+		assert.True(t, empty, "%s must not return a result along with an error", name)
+	}
+
+	info, err := c.GetServerInfo()
+	check("GetServerInfo", info == nil, err)
+	if storage, ok := c.(client.StorageInspector); ok {
+		info, err := storage.GetStorageInfo()
+		check("GetStorageInfo", info == nil, err)
+		controllers, err := storage.GetStorageControllers(&model.StorageControllerConfig{Type: "RAID"})
+		check("GetStorageControllers", len(controllers) == 0, err)
+		details, err := storage.GetStorageControllerInfo(ctrlID)
+		check("GetStorageControllerInfo", details == nil, err)
+		drive, err := storage.GetStorageDriveDetails(driveID)
+		check("GetStorageDriveDetails", drive == nil, err)
+	}
+	if boot, ok := c.(client.BootManager); ok {
+		info, err := boot.GetBootInfo()
+		check("GetBootInfo", info == nil, err)
+	}
+	if logs, ok := c.(client.LogReader); ok {
+		entries, err := logs.GetSystemEventLog()
+		check("GetSystemEventLog", len(entries) == 0, err)
+	}
+	if power, ok := c.(client.PowerManager); ok {
+		state, err := power.GetPowerState()
+		check("GetPowerState", state == "", err)
+	}
+}
+
+func checkPowerTransitions(t *testing.T, b Backend) {
+	e := start(t, b, emulator.Options{})
+	power := need[client.PowerManager](t, e.client)
+
+	require.NoError(t, power.SetPowerState("ForceOff"))
+	state, err := power.GetPowerState()
+	require.NoError(t, err)
+	assert.Equal(t, "Off", state)
+	assert.Equal(t, "Off", e.resource(t, e.system)["PowerState"])
+
+	require.NoError(t, power.SetPowerState("On"))
+	state, err = power.GetPowerState()
+	require.NoError(t, err)
+	assert.Equal(t, "On", state)
+
+	entries := func() int {
+		if logs, ok := e.client.(client.LogReader); ok {
+			list, err := logs.GetSystemEventLog()
+			require.NoError(t, err)
+			return len(list)
+		}
+		return 0
+	}
+	before := entries()
+	require.NoError(t, power.Reboot())
+	state, err = power.GetPowerState()
+	require.NoError(t, err)
+	assert.Equal(t, "On", state)
+	if _, ok := e.client.(client.LogReader); ok {
+		assert.Greater(t, entries(), before, "a reboot should be logged")
+	}
+
+	assert.Error(t, power.SetPowerState("Sideways"), "invalid reset types must be rejected")
+	assert.Equal(t, "On", e.resource(t, e.system)["PowerState"])
+}
+
+func checkBootOverride(t *testing.T, b Backend) {
+	e := start(t, b, emulator.Options{})
+	boot := need[client.BootManager](t, e.client)
+
+	require.NoError(t, boot.SetBootOrder("Pxe"))
+	info, err := boot.GetBootInfo()
+	require.NoError(t, err)
+	assert.Equal(t, model.BootSourcePxe, info.BootSourceOverrideTarget)
+	assert.Equal(t, model.BootSourceOverrideEnabledOnce, info.BootSourceOverrideEnabled)
+
+	require.NoError(t, boot.SetBootOrder("Hdd"))
+	info, err = boot.GetBootInfo()
+	require.NoError(t, err)
+	assert.Equal(t, model.BootSourceHdd, info.BootSourceOverrideTarget)
+
+	assert.Error(t, boot.SetBootOrder("Floppy9"), "invalid boot targets must be rejected")
+	info, err = boot.GetBootInfo()
+	require.NoError(t, err)
+	assert.Equal(t, model.BootSourceHdd, info.BootSourceOverrideTarget)
+}
+
+func checkAllowableValues(t *testing.T, b Backend) {
+	e := start(t, b, emulator.Options{})
+	reader := need[client.AllowableValuesReader](t, e.client)
+
+	values, err := reader.AllowableValues()
+	require.NoError(t, err)
+	system := e.resource(t, e.system)
+	boot, _ := system["Boot"].(map[string]interface{})
+	if _, ok := boot["BootSourceOverrideTarget@Redfish.AllowableValues"]; ok {
+		assert.NotEmpty(t, values.BootTargets, "advertised boot targets must be read")
+	}
+
+	if pm, ok := e.client.(client.PowerManager); ok && len(values.ResetTypes) > 0 {
+		typo := strings.ToLower(values.ResetTypes[0])
+		err := pm.SetPowerState(typo)
+		assert.ErrorIs(t, err, client.ErrInvalidValue, "invalid reset types must be rejected before sending")
+		var invalid *client.InvalidValueError
+		if assert.ErrorAs(t, err, &invalid) {
+			assert.Equal(t, values.ResetTypes[0], invalid.Suggestion)
+		}
+	}
+	if bm, ok := e.client.(client.BootManager); ok && len(values.BootTargets) > 0 {
+		err := bm.SetBootOrder(strings.ToUpper(values.BootTargets[0]) + "x")
+		assert.ErrorIs(t, err, client.ErrInvalidValue, "invalid boot targets must be rejected before sending")
+	}
+}
+
+func checkSELPaging(t *testing.T, b Backend) {
+	const pageSize = 2
+	e := start(t, b, emulator.Options{PageSize: pageSize})
+	logs := need[client.LogReader](t, e.client)
+	require.NotEmpty(t, b.Profile.SELPath, "profile has no SEL path")
+	for i := 0; i < 3*pageSize; i++ {
+		e.emu.AddLogEntry("OK", "Conformance entry")
+	}
+	coll := e.resource(t, b.Profile.SELPath)
+	members, _ := coll["Members"].([]interface{})
+	require.Greater(t, len(members), 2*pageSize)
+
+	entries, err := logs.GetSystemEventLog()
+	require.NoError(t, err)
+	require.Len(t, entries, len(members), "every page of the log must be read")
+
+	seen := make(map[string]bool)
+	for i, entry := range entries {
+		want, _ := members[i].(map[string]interface{})
+		assert.Equal(t, want["Id"], entry.ID, "entry %d out of order", i)
+		assert.False(t, seen[entry.ID], "entry %s returned twice", entry.ID)
+		seen[entry.ID] = true
+		assert.True(t, strings.TrimSpace(entry.Message) != "", "entry %s has no message", entry.ID)
+	}
+}
+
+// memberCount returns the number of members of the collection the property
+// link of res points to, or 0 when res has no such link.
+func (e *env) memberCount(t *testing.T, res map[string]interface{}, link string) int {
+	t.Helper()
+	uri := odataID(res[link])
+	if uri == "" {
+		return 0
+	}
+	members, _ := e.resource(t, uri)["Members"].([]interface{})
+	return len(members)
+}
+
+func checkInventory(t *testing.T, b Backend) {
+	e := start(t, b, emulator.Options{})
+	system := e.resource(t, e.system)
+
+	inv, err := inventory.Collect(e.client, e.host, inventory.PartAll)
+	require.NoError(t, err)
+	assert.Equal(t, system["Id"], inv.System.ID)
+	assert.NotEmpty(t, inv.Vendor)
+	assert.NotEqual(t, inventory.PowerUnknown, inv.System.PowerState)
+	assert.NotEqual(t, inventory.HealthUnknown, inv.System.Status.Health)
+	assert.Positive(t, inv.System.MemoryBytes, "memory is reported in bytes")
+
+	if _, ok := e.client.(client.StorageInspector); ok {
+		assert.Len(t, inv.Controllers, e.memberCount(t, system, "Storage"))
+		for _, ctrl := range inv.Controllers {
+			assert.Len(t, ctrl.Drives, ctrl.DriveCount, "controller %s", ctrl.ID)
+		}
+	}
+	if _, ok := e.client.(inventory.Source); !ok {
+		return
+	}
+	assert.Len(t, inv.Processors, e.memberCount(t, system, "Processors"))
+	assert.Len(t, inv.Memory, e.memberCount(t, system, "Memory"))
+	assert.Len(t, inv.NICs, e.memberCount(t, system, "EthernetInterfaces"))
+	assert.NotEmpty(t, inv.Chassis)
+	for _, fan := range inv.Fans {
+		assert.True(t, fan.SpeedRPM != nil || fan.SpeedPercent != nil, "fan %s has no reading", fan.ID)
+	}
+	for _, cpu := range inv.Processors {
+		assert.NotEqual(t, inventory.StateUnknown, cpu.Status.State, "processor %s", cpu.ID)
+	}
+	for _, dimm := range inv.Memory {
+		assert.NotEqual(t, inventory.StateUnknown, dimm.Status.State, "DIMM %s", dimm.ID)
+	}
+	assert.NotEmpty(t, inv.Firmware)
+}
@@ -5,25 +5,34 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
-// MockServerClient is a mock implementation of ServerClient
+var (
+	_ FullClient       = (*MockServerClient)(nil)
+	_ PowerManager     = (*MockPowerManager)(nil)
+	_ BootManager      = (*MockBootManager)(nil)
+	_ StorageInspector = (*MockStorageInspector)(nil)
+	_ LogReader        = (*MockLogReader)(nil)
+	_ CapabilityProber = (*MockCapabilityProber)(nil)
+)
+
+// MockServerClient is a mock backend implementing every capability.
 type MockServerClient struct {
 	mock.Mock
 }
 
-func (m *MockServerClient) GetServerInfo() (*model.ServerInfo, error) {
+func (m *MockServerClient) GetServerInfo() (*model.ComputerSystem, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*model.ServerInfo), args.Error(1)
+	return args.Get(0).(*model.ComputerSystem), args.Error(1)
 }
 
-func (m *MockServerClient) GetStorageInfo() (*model.StorageInfo, error) {
+func (m *MockServerClient) GetStorageInfo() (*model.Collection[model.IDRef], error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*model.StorageInfo), args.Error(1)
+	return args.Get(0).(*model.Collection[model.IDRef]), args.Error(1)
 }
 
 func (m *MockServerClient) GetStorageControllers(config *model.StorageControllerConfig) ([]model.StorageController, error) {
@@ -34,20 +43,20 @@ func (m *MockServerClient) GetStorageControllers(config *model.StorageController
 	return args.Get(0).([]model.StorageController), args.Error(1)
 }
 
-func (m *MockServerClient) GetRAIDVolumeInfo(volumeEndpoint string) (*model.RAIDVolume, error) {
+func (m *MockServerClient) GetRAIDVolumeInfo(volumeEndpoint string) (*model.Volume, error) {
 	args := m.Called(volumeEndpoint)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*model.RAIDVolume), args.Error(1)
+	return args.Get(0).(*model.Volume), args.Error(1)
 }
 
-func (m *MockServerClient) GetStorageControllerInfo(endpoint string) (*model.StorageControllerDetails, error) {
+func (m *MockServerClient) GetStorageControllerInfo(endpoint string) (*model.Storage, error) {
 	args := m.Called(endpoint)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*model.StorageControllerDetails), args.Error(1)
+	return args.Get(0).(*model.Storage), args.Error(1)
 }
 
 func (m *MockServerClient) GetStorageDriveDetails(driveEndpoint string) (*model.Drive, error) {
@@ -73,12 +82,12 @@ func (m *MockServerClient) Reboot() error {
 	return args.Error(0)
 }
 
-func (m *MockServerClient) GetBootInfo() (*model.BootInfo, error) {
+func (m *MockServerClient) GetBootInfo() (*model.Boot, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*model.BootInfo), args.Error(1)
+	return args.Get(0).(*model.Boot), args.Error(1)
 }
 
 func (m *MockServerClient) SetBootOrder(device string) error {
@@ -86,10 +95,135 @@ func (m *MockServerClient) SetBootOrder(device string) error {
 	return args.Error(0)
 }
 
-func (m *MockServerClient) GetSystemEventLog() ([]model.EventLogEntry, error) {
+func (m *MockServerClient) GetSystemEventLog() ([]model.LogEntry, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.LogEntry), args.Error(1)
+}
+
+// MockSystemInspector mocks the base ServerClient. The capability mocks
+// below embed it, so each is a ServerClient offering exactly one capability,
+// which is what tests of "not supported" paths need.
+type MockSystemInspector struct {
+	mock.Mock
+}
+
+func (m *MockSystemInspector) GetServerInfo() (*model.ComputerSystem, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ComputerSystem), args.Error(1)
+}
+
+// MockPowerManager is a ServerClient that only manages power.
+type MockPowerManager struct {
+	MockSystemInspector
+}
+
+func (m *MockPowerManager) GetPowerState() (string, error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockPowerManager) SetPowerState(state string) error {
+	args := m.Called(state)
+	return args.Error(0)
+}
+
+func (m *MockPowerManager) Reboot() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+// MockBootManager is a ServerClient that only manages boot settings.
+type MockBootManager struct {
+	MockSystemInspector
+}
+
+func (m *MockBootManager) GetBootInfo() (*model.Boot, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Boot), args.Error(1)
+}
+
+func (m *MockBootManager) SetBootOrder(device string) error {
+	args := m.Called(device)
+	return args.Error(0)
+}
+
+// MockStorageInspector is a ServerClient that only inspects storage.
+type MockStorageInspector struct {
+	MockSystemInspector
+}
+
+func (m *MockStorageInspector) GetStorageInfo() (*model.Collection[model.IDRef], error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Collection[model.IDRef]), args.Error(1)
+}
+
+func (m *MockStorageInspector) GetStorageControllers(config *model.StorageControllerConfig) ([]model.StorageController, error) {
+	args := m.Called(config)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.StorageController), args.Error(1)
+}
+
+func (m *MockStorageInspector) GetRAIDVolumeInfo(volumeEndpoint string) (*model.Volume, error) {
+	args := m.Called(volumeEndpoint)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Volume), args.Error(1)
+}
+
+func (m *MockStorageInspector) GetStorageControllerInfo(endpoint string) (*model.Storage, error) {
+	args := m.Called(endpoint)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Storage), args.Error(1)
+}
+
+func (m *MockStorageInspector) GetStorageDriveDetails(driveEndpoint string) (*model.Drive, error) {
+	args := m.Called(driveEndpoint)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Drive), args.Error(1)
+}
+
+// MockLogReader is a ServerClient that only reads the event log.
+type MockLogReader struct {
+	MockSystemInspector
+}
+
+func (m *MockLogReader) GetSystemEventLog() ([]model.LogEntry, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.LogEntry), args.Error(1)
+}
+
+// MockCapabilityProber is a power-managing ServerClient that can also be
+// probed, for testing how failures are explained.
+type MockCapabilityProber struct {
+	MockPowerManager
+}
+
+func (m *MockCapabilityProber) Capabilities() (*Capabilities, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]model.EventLogEntry), args.Error(1)
+	return args.Get(0).(*Capabilities), args.Error(1)
 }
@@ -0,0 +1,336 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	RegisterSource("ansible", func(spec SourceSpec) (Source, error) {
+		if spec.Path == "" {
+			return nil, fmt.Errorf("ansible inventory needs a path")
+		}
+		return &AnsibleSource{Path: spec.Path}, nil
+	})
+}
+
+// AnsibleSource reads servers from an Ansible inventory, in INI or, for
+// .yaml and .yml files, YAML format. The BMC of a host is its bmc_host
+// variable, or else its ansible_host, or else its inventory name; the
+// bmc_type, bmc_username and bmc_password variables set the rest. Hosts
+// join their Ansible groups, parents included, and are labelled with their
+// inventory name.
+type AnsibleSource struct {
+	Path string
+}
+
+// ansibleRange is an Ansible host range such as [01:50] or [a:f].
+var ansibleRange = regexp.MustCompile(`\[([0-9A-Za-z]+):([0-9A-Za-z]+)\]`)
+
+type ansibleGroup struct {
+	vars     map[string]string
+	hosts    []string
+	children []string
+}
+
+// ansibleInventory is an inventory once parsed, whatever its format.
+type ansibleInventory struct {
+	groups   map[string]*ansibleGroup
+	hosts    []string
+	hostVars map[string]map[string]string
+}
+
+func (inv *ansibleInventory) group(name string) *ansibleGroup {
+	g, ok := inv.groups[name]
+	if !ok {
+		g = &ansibleGroup{vars: make(map[string]string)}
+		inv.groups[name] = g
+	}
+	return g
+}
+
+// declare adds host to the inventory unless it is there already, and
+// returns its name.
+func (inv *ansibleInventory) declare(host string) string {
+	// Ansible writes ranges as [01:50], ExpandHost as [01-50].
+	host = ansibleRange.ReplaceAllString(host, "[$1-$2]")
+	if _, ok := inv.hostVars[host]; !ok {
+		inv.hosts = append(inv.hosts, host)
+		inv.hostVars[host] = make(map[string]string)
+	}
+	return host
+}
+
+func (inv *ansibleInventory) addHost(group, host string, vars map[string]string) {
+	host = inv.declare(host)
+	for k, v := range vars {
+		inv.hostVars[host][k] = v
+	}
+	g := inv.group(group)
+	g.hosts = append(g.hosts, host)
+}
+
+// Servers implements Source.
+func (s *AnsibleSource) Servers(ctx context.Context) ([]ServerConfig, error) {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	inv := &ansibleInventory{groups: make(map[string]*ansibleGroup), hostVars: make(map[string]map[string]string)}
+	switch filepath.Ext(s.Path) {
+	case ".yaml", ".yml":
+		err = inv.parseYAML(data)
+	default:
+		err = inv.parseINI(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", s.Path, err)
+	}
+	return inv.servers(), nil
+}
+
+func (inv *ansibleInventory) parseINI(data []byte) error {
+	section, kind := "ungrouped", "hosts"
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return fmt.Errorf("line %d: malformed section %q", n, line)
+			}
+			section, kind = line[1:len(line)-1], "hosts"
+			if name, suffix, ok := strings.Cut(section, ":"); ok {
+				section, kind = name, suffix
+			}
+			inv.group(section)
+			continue
+		}
+		fields := splitINIFields(line)
+		switch kind {
+		case "hosts":
+			vars := make(map[string]string)
+			for _, f := range fields[1:] {
+				k, v, ok := strings.Cut(f, "=")
+				if !ok {
+					return fmt.Errorf("line %d: malformed variable %q", n, f)
+				}
+				vars[k] = v
+			}
+			inv.addHost(section, fields[0], vars)
+		case "vars":
+			k, v, ok := strings.Cut(line, "=")
+			if !ok {
+				return fmt.Errorf("line %d: malformed variable %q", n, line)
+			}
+			inv.group(section).vars[strings.TrimSpace(k)] = unquote(strings.TrimSpace(v))
+		case "children":
+			g := inv.group(section)
+			g.children = append(g.children, fields[0])
+			inv.group(fields[0])
+		default:
+			return fmt.Errorf("line %d: unknown section kind %q", n, kind)
+		}
+	}
+	return scanner.Err()
+}
+
+// splitINIFields splits a host line on spaces outside quotes, removing the
+// quotes.
+func splitINIFields(line string) []string {
+	var fields []string
+	var field strings.Builder
+	var quote rune
+	for _, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == 0 && (r == '"' || r == '\''):
+			quote = r
+		case quote == 0 && (r == ' ' || r == '\t'):
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+				field.Reset()
+			}
+		default:
+			field.WriteRune(r)
+		}
+	}
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// ansibleYAMLGroup is a group of a YAML inventory.
+type ansibleYAMLGroup struct {
+	Hosts    map[string]map[string]interface{} `yaml:"hosts"`
+	Vars     map[string]interface{}            `yaml:"vars"`
+	Children map[string]*ansibleYAMLGroup      `yaml:"children"`
+}
+
+func (inv *ansibleInventory) parseYAML(data []byte) error {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return err
+	}
+	var top map[string]*ansibleYAMLGroup
+	if err := root.Decode(&top); err != nil {
+		return err
+	}
+	// Declare the hosts in file order, which the maps lose.
+	order := yamlHostOrder(&root)
+	for _, host := range order {
+		inv.declare(host)
+	}
+	for name, g := range top {
+		inv.addYAMLGroup(name, g, order)
+	}
+	return nil
+}
+
+func (inv *ansibleInventory) addYAMLGroup(name string, g *ansibleYAMLGroup, order []string) {
+	group := inv.group(name)
+	if g == nil {
+		return
+	}
+	for k, v := range g.Vars {
+		group.vars[k] = fmt.Sprint(v)
+	}
+	for _, host := range order {
+		vars, ok := g.Hosts[host]
+		if !ok {
+			continue
+		}
+		strs := make(map[string]string, len(vars))
+		for k, v := range vars {
+			strs[k] = fmt.Sprint(v)
+		}
+		inv.addHost(name, host, strs)
+	}
+	for child, cg := range g.Children {
+		group.children = append(group.children, child)
+		inv.addYAMLGroup(child, cg, order)
+	}
+}
+
+// yamlHostOrder lists the keys of every hosts mapping in document order.
+func yamlHostOrder(node *yaml.Node) []string {
+	var order []string
+	var walk func(n *yaml.Node)
+	walk = func(n *yaml.Node) {
+		if n.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				if n.Content[i].Value == "hosts" && n.Content[i+1].Kind == yaml.MappingNode {
+					hosts := n.Content[i+1].Content
+					for j := 0; j < len(hosts); j += 2 {
+						order = append(order, hosts[j].Value)
+					}
+					continue
+				}
+				walk(n.Content[i+1])
+			}
+			return
+		}
+		for _, c := range n.Content {
+			walk(c)
+		}
+	}
+	walk(node)
+	return order
+}
+
+// servers turns the hosts of the inventory into servers, in file order.
+func (inv *ansibleInventory) servers() []ServerConfig {
+	parents := make(map[string][]string)
+	for name, g := range inv.groups {
+		for _, child := range g.children {
+			parents[child] = append(parents[child], name)
+		}
+	}
+
+	var servers []ServerConfig
+	for _, host := range inv.hosts {
+		// The host's groups, nearest first, without repeats.
+		var groups []string
+		seen := make(map[string]bool)
+		queue := []string{}
+		for name, g := range inv.groups {
+			for _, h := range g.hosts {
+				if h == host && !seen[name] {
+					seen[name] = true
+					queue = append(queue, name)
+				}
+			}
+		}
+		sort.Strings(queue)
+		for len(queue) > 0 {
+			name := queue[0]
+			queue = queue[1:]
+			groups = append(groups, name)
+			next := append([]string{}, parents[name]...)
+			sort.Strings(next)
+			for _, p := range next {
+				if !seen[p] {
+					seen[p] = true
+					queue = append(queue, p)
+				}
+			}
+		}
+
+		// Variables of farther groups first, so nearer ones and the host's
+		// own win; "all" is the farthest of all.
+		vars := make(map[string]string)
+		for k, v := range inv.groupVars("all") {
+			vars[k] = v
+		}
+		for i := len(groups) - 1; i >= 0; i-- {
+			for k, v := range inv.groupVars(groups[i]) {
+				vars[k] = v
+			}
+		}
+		for k, v := range inv.hostVars[host] {
+			vars[k] = v
+		}
+
+		server := ServerConfig{
+			Hostname: first(vars["bmc_host"], vars["ansible_host"], host),
+			Type:     vars["bmc_type"],
+			Username: first(vars["bmc_username"], vars["bmc_user"]),
+			Password: vars["bmc_password"],
+			Labels:   map[string]string{"name": host},
+		}
+		for _, g := range groups {
+			if g != "all" && g != "ungrouped" {
+				server.Groups = append(server.Groups, g)
+			}
+		}
+		servers = append(servers, server)
+	}
+	return servers
+}
+
+func (inv *ansibleInventory) groupVars(name string) map[string]string {
+	if g, ok := inv.groups[name]; ok {
+		return g.vars
+	}
+	return nil
+}
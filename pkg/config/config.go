@@ -1,21 +1,30 @@
 package config
 
 import (
+	"context"
 	"os"
 
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
 	"github.com/angelhvargas/redfishcli/pkg/logger"
-	"gopkg.in/yaml.v3"
 )
 
 type BMCConfig struct {
-	Servers []ServerConfig `yaml:"servers"`
+	Servers []ServerConfig `yaml:"servers" json:"servers"`
+	// Groups names groups of servers by their hostnames, which may use the
+	// wildcards of path.Match. Servers can also join groups themselves.
+	Groups map[string][]string `yaml:"groups,omitempty" json:"groups,omitempty"`
 }
 
 type ServerConfig struct {
-	Type     string `yaml:"type"`
-	Hostname string `yaml:"hostname"`
-	Username string `yaml:"username"`
-	Password string `yaml:"password"`
+	Type     string `yaml:"type" json:"type"`
+	Hostname string `yaml:"hostname" json:"hostname"`
+	Username string `yaml:"username" json:"username"`
+	Password string `yaml:"password" json:"password"`
+	// Labels describe the server, e.g. rack, site, role, model and owner,
+	// for selectors to match.
+	Labels map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	// Groups are the named groups the server belongs to.
+	Groups []string `yaml:"groups,omitempty" json:"groups,omitempty"`
 }
 
 type BMCConnConfig struct {
@@ -23,6 +32,17 @@ type BMCConnConfig struct {
 	Username       string
 	Password       string
 	ControllerType string
+	// HTTPClient overrides the backend's default HTTP settings, e.g. to add
+	// middlewares or replace the transport. Nil means httpclient.DefaultConfig().
+	HTTPClient *httpclient.Config
+}
+
+// HTTPClientConfig returns the HTTP settings to use for this connection.
+func (c BMCConnConfig) HTTPClientConfig() httpclient.Config {
+	if c.HTTPClient != nil {
+		return *c.HTTPClient
+	}
+	return httpclient.DefaultConfig()
 }
 
 type IDRACConfig struct {
@@ -33,21 +53,11 @@ type XClarityConfig struct {
 	BMCConnConfig
 }
 
-// LoadConfig reads a YAML configuration file and unmarshals it into a BMCConfig struct.
+// LoadConfig reads a YAML configuration file, with the files it includes,
+// and expands its host patterns and templates into a BMCConfig struct.
 func LoadConfig(path string) (*BMCConfig, error) {
 	logger.Log.Infof("Loading configuration from %s", path)
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
-
-	var cfg BMCConfig
-	err = yaml.Unmarshal(data, &cfg)
-	if err != nil {
-		return nil, err
-	}
-
-	return &cfg, nil
+	return loadFile(path)
 }
 
 // GetDefaultConfigPath returns the default configuration file path.
@@ -61,6 +71,8 @@ func LoadConfigFromDefaultPath() (*BMCConfig, error) {
 }
 
 // LoadConfigOrEnv loads the configuration from the specified path, or from environment variables if the path is empty.
+// Servers missing a username or password take them from the arguments or environment first, and only what those leave
+// out from the credential store, if one exists; the store is not opened when nothing is missing.
 func LoadConfigOrEnv(path, bmcType, username, password, hostname string) (*BMCConfig, error) {
 	var cfg *BMCConfig
 	var err error
@@ -76,20 +88,12 @@ func LoadConfigOrEnv(path, bmcType, username, password, hostname string) (*BMCCo
 		}
 	}
 
-	// Override with CLI or environment variables if necessary
-	for i, server := range cfg.Servers {
-		if server.Username == "" {
-			cfg.Servers[i].Username = username
-			if envUser := os.Getenv("BMC_USERNAME"); envUser != "" {
-				cfg.Servers[i].Username = envUser
-			}
-		}
-		if server.Password == "" {
-			cfg.Servers[i].Password = password
-			if envPass := os.Getenv("BMC_PASSWORD"); envPass != "" {
-				cfg.Servers[i].Password = envPass
-			}
-		}
+	// Fill in missing credentials from the CLI or environment variables,
+	// then what they leave out from the credential store
+	DefaultCredentials(cfg.Servers, username, password)
+	store := &storeFiller{cfg: cfg}
+	if err := store.fill(cfg.Servers); err != nil {
+		return nil, err
 	}
 
 	// Add a single server configuration if CLI flags are provided and no servers are defined
@@ -107,7 +111,40 @@ func LoadConfigOrEnv(path, bmcType, username, password, hostname string) (*BMCCo
 			server.Password = envPass
 		}
 		cfg.Servers = append(cfg.Servers, server)
+		if err := store.fill(cfg.Servers); err != nil {
+			return nil, err
+		}
 	}
 
 	return cfg, nil
 }
+
+// DefaultCredentials gives the servers without a username or password those
+// of the BMC_USERNAME and BMC_PASSWORD environment variables, or else the
+// given ones.
+func DefaultCredentials(servers []ServerConfig, username, password string) {
+	for i, server := range servers {
+		if server.Username == "" {
+			servers[i].Username = username
+			if envUser := os.Getenv("BMC_USERNAME"); envUser != "" {
+				servers[i].Username = envUser
+			}
+		}
+		if server.Password == "" {
+			servers[i].Password = password
+			if envPass := os.Getenv("BMC_PASSWORD"); envPass != "" {
+				servers[i].Password = envPass
+			}
+		}
+	}
+}
+
+// LoadSources returns the servers of the inventory sources, in order.
+func LoadSources(ctx context.Context, specs ...SourceSpec) ([]ServerConfig, error) {
+	f := &File{Sources: specs}
+	cfg, err := f.expand(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Servers, nil
+}
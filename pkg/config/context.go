@@ -0,0 +1,180 @@
+package config
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+	"gopkg.in/yaml.v3"
+)
+
+// Context is a named set of defaults for the commands: which configuration
+// file to load, which of its servers to select, which credentials to fall
+// back on and how to reach the BMCs. Flags given on the command line win
+// over the context.
+type Context struct {
+	// Config is the configuration file the commands load.
+	Config string `yaml:"config,omitempty" json:"config,omitempty"`
+	// Selector is the --selector used when none is given.
+	Selector string `yaml:"selector,omitempty" json:"selector,omitempty"`
+	// Username and Password are used when --username and --password are
+	// not given. The password may be a secret reference.
+	Username string `yaml:"username,omitempty" json:"username,omitempty"`
+	Password string `yaml:"password,omitempty" json:"password,omitempty"`
+	// Vault is the credential store file, when it is not the default one.
+	Vault string `yaml:"vault,omitempty" json:"vault,omitempty"`
+	// Output is the --output used when none is given.
+	Output string `yaml:"output,omitempty" json:"output,omitempty"`
+	// TLS is how the BMC certificates are checked.
+	TLS ContextTLS `yaml:"tls,omitempty" json:"tls,omitempty"`
+	// Proxy is the URL of the HTTP proxy the BMCs are reached through.
+	Proxy string `yaml:"proxy,omitempty" json:"proxy,omitempty"`
+}
+
+// ContextTLS is the TLS policy of a context.
+type ContextTLS struct {
+	// Verify checks the BMC certificates, which are not checked by default.
+	Verify bool `yaml:"verify,omitempty" json:"verify,omitempty"`
+	// CA is a PEM file of the authorities the BMC certificates are checked
+	// against instead of the system ones.
+	CA string `yaml:"ca,omitempty" json:"ca,omitempty"`
+}
+
+// HTTP returns base with the context's TLS policy and proxy applied.
+func (c *Context) HTTP(base httpclient.Config) (httpclient.Config, error) {
+	if c.TLS.Verify {
+		base.SkipTLSVerify = false
+	}
+	if c.TLS.CA != "" {
+		pem, err := os.ReadFile(c.TLS.CA)
+		if err != nil {
+			return base, fmt.Errorf("tls ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return base, fmt.Errorf("tls ca: no certificates in %s", c.TLS.CA)
+		}
+		base.RootCAs = pool
+	}
+	if c.Proxy != "" {
+		proxy, err := url.Parse(c.Proxy)
+		if err != nil {
+			return base, fmt.Errorf("proxy: %w", err)
+		}
+		base.Proxy = proxy
+	}
+	return base, nil
+}
+
+// Contexts is the contexts file: the named contexts and the one in use.
+type Contexts struct {
+	Current  string             `yaml:"current-context,omitempty"`
+	Contexts map[string]Context `yaml:"contexts,omitempty"`
+}
+
+// ContextsPath returns the contexts file path: REDFISHCLI_CONTEXTS, or else
+// ~/.redfishcli/contexts.yaml.
+func ContextsPath() string {
+	if path := os.Getenv("REDFISHCLI_CONTEXTS"); path != "" {
+		return path
+	}
+	return filepath.Join(os.Getenv("HOME"), ".redfishcli", "contexts.yaml")
+}
+
+// LoadContexts reads the contexts file at path. A missing file has no
+// contexts. The paths of the contexts are made absolute, relative to the
+// file's directory.
+func LoadContexts(path string) (*Contexts, error) {
+	cs := &Contexts{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, cs); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	dir := filepath.Dir(path)
+	for name, c := range cs.Contexts {
+		c.Config = expandPath(c.Config, dir)
+		c.Vault = expandPath(c.Vault, dir)
+		c.TLS.CA = expandPath(c.TLS.CA, dir)
+		cs.Contexts[name] = c
+	}
+	return cs, nil
+}
+
+// Save writes the contexts to path.
+func (cs *Contexts) Save(path string) error {
+	data, err := yaml.Marshal(cs)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	// Contexts may hold passwords, or references to them.
+	return os.WriteFile(path, data, 0600)
+}
+
+// Get returns the context called name.
+func (cs *Contexts) Get(name string) (*Context, error) {
+	c, ok := cs.Contexts[name]
+	if !ok {
+		return nil, fmt.Errorf("no context %q", name)
+	}
+	return &c, nil
+}
+
+// Names returns the names of the contexts, sorted.
+func (cs *Contexts) Names() []string {
+	names := make([]string, 0, len(cs.Contexts))
+	for name := range cs.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ConfigPath returns the configuration file to load: path when given, else
+// the file of the context, if there is one, else GetDefaultConfigPath when
+// that file exists, else the empty string.
+func ConfigPath(path string, ctx *Context) string {
+	if path != "" {
+		return path
+	}
+	if ctx != nil && ctx.Config != "" {
+		return ctx.Config
+	}
+	if def := GetDefaultConfigPath(); fileExists(def) {
+		return def
+	}
+	return ""
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// expandPath expands a leading ~/ to the home directory and makes a
+// relative path relative to dir, unless dir is empty.
+func expandPath(path, dir string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	if path != "" && dir != "" && !filepath.IsAbs(path) {
+		return filepath.Join(dir, path)
+	}
+	return path
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContexts(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"contexts.yaml": `
+current-context: lab
+contexts:
+  lab:
+    config: fleets/lab.yaml
+    selector: site=ams
+    vault: /secure/vault.json
+    tls: {verify: true, ca: lab-ca.pem}
+  prod:
+    config: /etc/redfishcli/prod.yaml
+`})
+	path := filepath.Join(dir, "contexts.yaml")
+	cs, err := LoadContexts(path)
+	require.NoError(t, err)
+	assert.Equal(t, "lab", cs.Current)
+	assert.Equal(t, []string{"lab", "prod"}, cs.Names())
+	lab, err := cs.Get("lab")
+	require.NoError(t, err)
+	assert.Equal(t, &Context{
+		Config:   filepath.Join(dir, "fleets/lab.yaml"),
+		Selector: "site=ams",
+		Vault:    "/secure/vault.json",
+		TLS:      ContextTLS{Verify: true, CA: filepath.Join(dir, "lab-ca.pem")},
+	}, lab)
+	_, err = cs.Get("staging")
+	assert.EqualError(t, err, `no context "staging"`)
+
+	cs.Current = "prod"
+	require.NoError(t, cs.Save(path))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	saved, err := LoadContexts(path)
+	require.NoError(t, err)
+	assert.Equal(t, cs, saved)
+
+	missing, err := LoadContexts(filepath.Join(dir, "missing.yaml"))
+	require.NoError(t, err)
+	assert.Empty(t, missing.Names())
+}
+
+func TestConfigPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	ctx := &Context{Config: "/fleets/prod.yaml"}
+
+	assert.Equal(t, "given.yaml", ConfigPath("given.yaml", ctx))
+	assert.Equal(t, "/fleets/prod.yaml", ConfigPath("", ctx))
+	assert.Equal(t, "", ConfigPath("", nil), "the default file does not exist")
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".redfishcli"), 0700))
+	require.NoError(t, os.WriteFile(GetDefaultConfigPath(), nil, 0600))
+	assert.Equal(t, GetDefaultConfigPath(), ConfigPath("", &Context{}))
+}
+
+func TestContextHTTP(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	ca := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(ca, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0600))
+
+	get := func(c *Context) error {
+		cfg, err := c.HTTP(httpclient.DefaultConfig())
+		require.NoError(t, err)
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+		resp, err := cfg.RoundTripper().RoundTrip(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+	assert.NoError(t, get(&Context{}), "certificates are not checked by default")
+	assert.Error(t, get(&Context{TLS: ContextTLS{Verify: true}}))
+	assert.NoError(t, get(&Context{TLS: ContextTLS{Verify: true, CA: ca}}))
+
+	cfg, err := (&Context{Proxy: "http://proxy.example.net:3128"}).HTTP(httpclient.DefaultConfig())
+	require.NoError(t, err)
+	assert.Equal(t, &url.URL{Scheme: "http", Host: "proxy.example.net:3128"}, cfg.Proxy)
+
+	_, err = (&Context{TLS: ContextTLS{CA: srv.URL}}).HTTP(httpclient.DefaultConfig())
+	assert.ErrorContains(t, err, "tls ca:")
+}
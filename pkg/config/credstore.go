@@ -0,0 +1,97 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/angelhvargas/redfishcli/pkg/credstore"
+)
+
+// VaultPassphrase asks for the passphrase of the credential store when
+// LoadConfigOrEnv has to open it and no agent holds its key. Nil means the
+// store can only be opened through the agent.
+var VaultPassphrase func() (string, error)
+
+// CredentialStorePath returns the path of the credential store
+// LoadConfigOrEnv fills credentials from.
+var CredentialStorePath = credstore.DefaultPath
+
+// StoredCredentials returns the entry of the store for the server: one for
+// its hostname, else one for a hostname pattern it matches, else one for a
+// group it is in, else one for a selector its labels match. Within a kind,
+// the entry set first wins.
+func (c *BMCConfig) StoredCredentials(store *credstore.Store, server ServerConfig) (credstore.Entry, bool) {
+	matches := []func(credstore.Key) bool{
+		func(k credstore.Key) bool { return k.Kind == credstore.KeyHost && k.Value == server.Hostname },
+		func(k credstore.Key) bool {
+			ok, _ := path.Match(k.Value, server.Hostname)
+			return k.Kind == credstore.KeyHost && ok
+		},
+		func(k credstore.Key) bool { return k.Kind == credstore.KeyGroup && c.InGroup(server, k.Value) },
+		func(k credstore.Key) bool {
+			if k.Kind != credstore.KeySelector {
+				return false
+			}
+			sel, err := ParseSelector(k.Value)
+			return err == nil && sel.Matches(server.Labels)
+		},
+	}
+	for _, match := range matches {
+		for _, e := range store.Entries {
+			if match(e.Key) {
+				return e, true
+			}
+		}
+	}
+	return credstore.Entry{}, false
+}
+
+// storeFiller fills in the credentials servers lack from the credential
+// store, opening it the first time a server needs it.
+type storeFiller struct {
+	cfg   *BMCConfig
+	store *credstore.Store
+	// absent is set once the store turned out not to exist.
+	absent bool
+}
+
+func (f *storeFiller) fill(servers []ServerConfig) error {
+	for i, server := range servers {
+		if server.Username != "" && server.Password != "" {
+			continue
+		}
+		if f.store == nil {
+			if f.absent {
+				return nil
+			}
+			storePath := CredentialStorePath()
+			if !credstore.Exists(storePath) {
+				f.absent = true
+				return nil
+			}
+			passphrase := VaultPassphrase
+			if passphrase == nil {
+				passphrase = func() (string, error) {
+					return "", errors.New("the store is locked")
+				}
+			}
+			store, err := credstore.Open(storePath, passphrase)
+			if err != nil {
+				return fmt.Errorf("opening the credential store: %w", err)
+			}
+			f.store = store
+		}
+		entry, ok := f.cfg.StoredCredentials(f.store, server)
+		if !ok {
+			continue
+		}
+		if server.Username == "" {
+			servers[i].Username = entry.Username
+		}
+		if server.Password == "" {
+			servers[i].Password = entry.Password
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/angelhvargas/redfishcli/pkg/credstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfigOrEnvCredentialStore(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("REDFISHCLI_VAULT_FILE", filepath.Join(dir, "vault.json"))
+	t.Setenv("REDFISHCLI_VAULT_AGENT", filepath.Join(dir, "agent.sock"))
+	t.Setenv("BMC_USERNAME", "")
+	t.Setenv("BMC_PASSWORD", "")
+	store, err := credstore.Init(credstore.DefaultPath(), "correct horse")
+	require.NoError(t, err)
+	for _, e := range []credstore.Entry{
+		{Key: credstore.Key{Kind: credstore.KeySelector, Value: "site=ams"}, Username: "ams", Password: "by-selector"},
+		{Key: credstore.Key{Kind: credstore.KeyGroup, Value: "db"}, Username: "db", Password: "by-group"},
+		{Key: credstore.Key{Kind: credstore.KeyHost, Value: "r12-*"}, Username: "root", Password: "by-pattern"},
+		{Key: credstore.Key{Kind: credstore.KeyHost, Value: "r12-bmc01"}, Username: "root", Password: "by-host"},
+	} {
+		store.Set(e)
+	}
+	require.NoError(t, store.Save())
+
+	prompts := 0
+	VaultPassphrase = func() (string, error) {
+		prompts++
+		return "correct horse", nil
+	}
+	t.Cleanup(func() { VaultPassphrase = nil })
+
+	cfgDir := writeFiles(t, map[string]string{"config.yaml": `
+servers:
+  - {type: idrac, hostname: r12-bmc01, labels: {site: ams}, groups: [db]}
+  - {type: idrac, hostname: r12-bmc02, labels: {site: ams}, groups: [db]}
+  - {type: idrac, hostname: r14-bmc01, labels: {site: ams}, groups: [db]}
+  - {type: idrac, hostname: r14-bmc02, labels: {site: ams}}
+  - {type: idrac, hostname: r14-bmc03, username: admin}
+  - {type: idrac, hostname: r16-bmc01, username: admin, password: own}
+`})
+	credentials := func(cfg *BMCConfig) [][2]string {
+		var got [][2]string
+		for _, s := range cfg.Servers {
+			got = append(got, [2]string{s.Username, s.Password})
+		}
+		return got
+	}
+	configFile := filepath.Join(cfgDir, "config.yaml")
+
+	cfg, err := LoadConfigOrEnv(configFile, "", "", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, [][2]string{
+		{"root", "by-host"},
+		{"root", "by-pattern"},
+		{"db", "by-group"},
+		{"ams", "by-selector"},
+		{"admin", ""},
+		{"admin", "own"},
+	}, credentials(cfg))
+	assert.Equal(t, 1, prompts)
+
+	// The arguments win over the store, which fills in only what they leave
+	// out.
+	cfg, err = LoadConfigOrEnv(configFile, "", "", "flag-password", "")
+	require.NoError(t, err)
+	assert.Equal(t, [][2]string{
+		{"root", "flag-password"},
+		{"root", "flag-password"},
+		{"db", "flag-password"},
+		{"ams", "flag-password"},
+		{"admin", "flag-password"},
+		{"admin", "own"},
+	}, credentials(cfg))
+	assert.Equal(t, 2, prompts)
+
+	cfg, err = LoadConfigOrEnv(configFile, "", "flag-user", "flag-password", "")
+	require.NoError(t, err)
+	assert.Equal(t, [][2]string{
+		{"flag-user", "flag-password"},
+		{"flag-user", "flag-password"},
+		{"flag-user", "flag-password"},
+		{"flag-user", "flag-password"},
+		{"admin", "flag-password"},
+		{"admin", "own"},
+	}, credentials(cfg))
+	assert.Equal(t, 2, prompts, "the store is not opened when the arguments supply the credentials")
+
+	cfg, err = LoadConfigOrEnv("", "idrac", "admin", "", "r12-bmc01")
+	require.NoError(t, err)
+	assert.Equal(t, "admin", cfg.Servers[0].Username, "the arguments win over the store")
+	assert.Equal(t, "by-host", cfg.Servers[0].Password)
+
+	_, err = LoadConfigOrEnv("", "idrac", "admin", "given", "r12-bmc01")
+	require.NoError(t, err)
+	assert.Equal(t, 3, prompts, "the store is not opened when nothing is missing")
+
+	// A locked store only matters when something is missing.
+	VaultPassphrase = nil
+	_, err = LoadConfigOrEnv(configFile, "", "flag-user", "flag-password", "")
+	require.NoError(t, err)
+	_, err = LoadConfigOrEnv("", "idrac", "admin", "given", "r12-bmc01")
+	require.NoError(t, err)
+	_, err = LoadConfigOrEnv("", "idrac", "admin", "", "r12-bmc01")
+	assert.EqualError(t, err, "opening the credential store: the store is locked")
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func init() {
+	RegisterSource("csv", func(spec SourceSpec) (Source, error) {
+		if spec.Path == "" {
+			return nil, fmt.Errorf("csv inventory needs a path")
+		}
+		return &CSVSource{Path: spec.Path}, nil
+	})
+}
+
+// CSVSource reads servers from a CSV file whose header names the columns:
+// hostname (or host), type, username, password and groups, separated by
+// spaces or semicolons. Every other column is a label; empty cells are
+// left out.
+type CSVSource struct {
+	Path string
+}
+
+// Servers implements Source.
+func (s *CSVSource) Servers(ctx context.Context) ([]ServerConfig, error) {
+	f, err := os.Open(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.Comment = '#'
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	header := make([]string, len(records[0]))
+	hasHost := false
+	for i, name := range records[0] {
+		header[i] = strings.ToLower(strings.TrimSpace(name))
+		if header[i] == "host" {
+			header[i] = "hostname"
+		}
+		hasHost = hasHost || header[i] == "hostname"
+	}
+	if !hasHost {
+		return nil, fmt.Errorf("%s: no hostname column", s.Path)
+	}
+
+	var servers []ServerConfig
+	for n, record := range records[1:] {
+		var server ServerConfig
+		for i, value := range record {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			switch header[i] {
+			case "hostname":
+				server.Hostname = value
+			case "type":
+				server.Type = value
+			case "username":
+				server.Username = value
+			case "password":
+				server.Password = value
+			case "groups":
+				server.Groups = strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == ' ' })
+			default:
+				if server.Labels == nil {
+					server.Labels = make(map[string]string)
+				}
+				server.Labels[header[i]] = value
+			}
+		}
+		if server.Hostname == "" {
+			return nil, fmt.Errorf("%s: record %d: no hostname", s.Path, n+1)
+		}
+		servers = append(servers, server)
+	}
+	return servers, nil
+}
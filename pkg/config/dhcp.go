@@ -0,0 +1,164 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func init() {
+	RegisterSource("dhcp", func(spec SourceSpec) (Source, error) {
+		if spec.Path == "" {
+			return nil, fmt.Errorf("dhcp inventory needs a path")
+		}
+		return &DHCPSource{Path: spec.Path}, nil
+	})
+}
+
+// dhcpTypes guesses the BMC type from the hostname a BMC asks DHCP for,
+// e.g. "idrac-CNIVC00" or "XCC-7X06-J30A1B2C".
+var dhcpTypes = map[string]string{
+	"idrac": "idrac",
+	"xcc":   "xclarity",
+	"imm":   "xclarity",
+}
+
+// DHCPSource discovers BMCs in the lease file of ISC dhcpd or dnsmasq,
+// whichever format the file is in. Each active lease is a server at its IP
+// address, labelled with the client's MAC address and, when it sent one,
+// its hostname as "name"; the spec's match patterns usually pick the BMCs
+// out by that name. Hostnames starting with idrac, xcc or imm get the
+// matching type.
+type DHCPSource struct {
+	Path string
+}
+
+type dhcpLease struct {
+	ip, mac, name string
+}
+
+// Servers implements Source.
+func (s *DHCPSource) Servers(ctx context.Context) ([]ServerConfig, error) {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	var leases []dhcpLease
+	if bytes.Contains(data, []byte("lease ")) && bytes.Contains(data, []byte("{")) {
+		leases, err = parseISCLeases(data)
+	} else {
+		leases, err = parseDnsmasqLeases(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", s.Path, err)
+	}
+
+	var servers []ServerConfig
+	for _, l := range leases {
+		server := ServerConfig{Hostname: l.ip, Labels: map[string]string{}}
+		if l.mac != "" {
+			server.Labels["mac"] = l.mac
+		}
+		if l.name != "" {
+			server.Labels["name"] = l.name
+			lower := strings.ToLower(l.name)
+			for prefix, bmcType := range dhcpTypes {
+				if strings.HasPrefix(lower, prefix) {
+					server.Type = bmcType
+				}
+			}
+		}
+		servers = append(servers, server)
+	}
+	return servers, nil
+}
+
+// parseISCLeases parses a dhcpd.leases file. dhcpd appends a lease each
+// time it changes, so the last one for an address wins; leases no longer
+// active are left out.
+func parseISCLeases(data []byte) ([]dhcpLease, error) {
+	var order []string
+	latest := make(map[string]dhcpLease)
+	active := make(map[string]bool)
+
+	var cur *dhcpLease
+	curActive := true
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		switch {
+		case cur == nil && strings.HasPrefix(line, "lease ") && strings.HasSuffix(line, "{"):
+			cur = &dhcpLease{ip: strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(line, "lease "), "{"))}
+			curActive = true
+		case cur == nil:
+			// Other declarations, e.g. server-duid or failover peer state.
+			if strings.HasSuffix(line, "{") {
+				cur = &dhcpLease{}
+			}
+		case line == "}":
+			if cur.ip != "" {
+				if _, ok := latest[cur.ip]; !ok {
+					order = append(order, cur.ip)
+				}
+				latest[cur.ip] = *cur
+				active[cur.ip] = curActive
+			}
+			cur = nil
+		default:
+			value := strings.TrimSuffix(line, ";")
+			switch {
+			case strings.HasPrefix(value, "binding state "):
+				curActive = strings.TrimPrefix(value, "binding state ") == "active"
+			case strings.HasPrefix(value, "hardware ethernet "):
+				cur.mac = strings.ToLower(strings.TrimPrefix(value, "hardware ethernet "))
+			case strings.HasPrefix(value, "client-hostname "):
+				cur.name = unquote(strings.TrimPrefix(value, "client-hostname "))
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if cur != nil {
+		return nil, fmt.Errorf("unterminated lease %s", cur.ip)
+	}
+
+	var leases []dhcpLease
+	for _, ip := range order {
+		if active[ip] {
+			leases = append(leases, latest[ip])
+		}
+	}
+	return leases, nil
+}
+
+// parseDnsmasqLeases parses a dnsmasq.leases file, whose lines read
+// "expiry mac ip hostname client-id" with "*" for an unknown hostname.
+func parseDnsmasqLeases(data []byte) ([]dhcpLease, error) {
+	var leases []dhcpLease
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if fields[0] == "duid" {
+			continue
+		}
+		if len(fields) < 4 {
+			return nil, fmt.Errorf("line %d: malformed lease", n)
+		}
+		l := dhcpLease{mac: strings.ToLower(fields[1]), ip: fields[2]}
+		if fields[3] != "*" {
+			l.name = fields[3]
+		}
+		leases = append(leases, l)
+	}
+	return leases, scanner.Err()
+}
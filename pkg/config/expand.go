@@ -0,0 +1,164 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxExpansion bounds how many hostnames one pattern may expand to, so a
+// mistyped range or CIDR block cannot produce millions of servers.
+const maxExpansion = 65536
+
+// rangeTerm is one item between brackets: a number, a letter, or a range of
+// either.
+var rangeTerm = regexp.MustCompile(`^([0-9]+|[A-Za-z])(?:-([0-9]+|[A-Za-z]))?$`)
+
+// ExpandHost expands a hostname pattern into the hostnames it stands for:
+//
+//   - "r12-bmc[01-40].ams" counts from 01 to 40, keeping the width of the
+//     first number; "[a-d]" runs through letters and "[1-3,7]" lists items;
+//   - "bmc.{ams,fra}.example.net" takes each alternative in turn;
+//   - "10.0.4.0/28" is every host address of the CIDR block, leaving out the
+//     network and broadcast addresses of IPv4 blocks larger than /31.
+//
+// Patterns can be combined and are expanded left to right. A hostname
+// without any is returned as is; bracketed IPv6 addresses are not patterns.
+func ExpandHost(pattern string) ([]string, error) {
+	if strings.Contains(pattern, "/") {
+		if _, _, err := net.ParseCIDR(pattern); err == nil {
+			return expandCIDR(pattern)
+		}
+	}
+	hosts, err := expandPattern(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("host pattern %q: %w", pattern, err)
+	}
+	return hosts, nil
+}
+
+func expandPattern(pattern string) ([]string, error) {
+	start := strings.IndexAny(pattern, "[{")
+	if start < 0 {
+		return []string{pattern}, nil
+	}
+	closing := map[byte]byte{'[': ']', '{': '}'}[pattern[start]]
+	end := strings.IndexByte(pattern[start:], closing)
+	if end < 0 {
+		return nil, fmt.Errorf("unbalanced %q", pattern[start])
+	}
+	end += start
+	body := pattern[start+1 : end]
+
+	var items []string
+	if pattern[start] == '{' {
+		items = strings.Split(body, ",")
+	} else {
+		var err error
+		if items, err = expandRange(body); err != nil {
+			return nil, err
+		}
+		if items == nil {
+			// Not a range, e.g. an IPv6 address: keep the brackets.
+			rest, err := expandPattern(pattern[end+1:])
+			if err != nil {
+				return nil, err
+			}
+			var hosts []string
+			for _, r := range rest {
+				hosts = append(hosts, pattern[:end+1]+r)
+			}
+			return hosts, nil
+		}
+	}
+
+	rest, err := expandPattern(pattern[end+1:])
+	if err != nil {
+		return nil, err
+	}
+	if len(items)*len(rest) > maxExpansion {
+		return nil, fmt.Errorf("expands to more than %d hosts", maxExpansion)
+	}
+	hosts := make([]string, 0, len(items)*len(rest))
+	for _, item := range items {
+		for _, r := range rest {
+			hosts = append(hosts, pattern[:start]+item+r)
+		}
+	}
+	return hosts, nil
+}
+
+// expandRange expands the body of a bracket. It returns nil when the body
+// is not a list of ranges.
+func expandRange(body string) ([]string, error) {
+	var items []string
+	for _, term := range strings.Split(body, ",") {
+		m := rangeTerm.FindStringSubmatch(term)
+		if m == nil {
+			return nil, nil
+		}
+		from, to := m[1], m[2]
+		if to == "" {
+			items = append(items, from)
+			continue
+		}
+		fromN, fromErr := strconv.Atoi(from)
+		toN, toErr := strconv.Atoi(to)
+		switch {
+		case fromErr == nil && toErr == nil:
+			if toN < fromN {
+				return nil, fmt.Errorf("range %s runs backwards", term)
+			}
+			if toN-fromN >= maxExpansion {
+				return nil, fmt.Errorf("range %s expands to more than %d hosts", term, maxExpansion)
+			}
+			for n := fromN; n <= toN; n++ {
+				items = append(items, fmt.Sprintf("%0*d", len(from), n))
+			}
+		case fromErr != nil && toErr != nil:
+			if to[0] < from[0] {
+				return nil, fmt.Errorf("range %s runs backwards", term)
+			}
+			for c := from[0]; c <= to[0]; c++ {
+				items = append(items, string(c))
+			}
+		default:
+			return nil, fmt.Errorf("range %s mixes numbers and letters", term)
+		}
+	}
+	return items, nil
+}
+
+func expandCIDR(cidr string) ([]string, error) {
+	ip, block, _ := net.ParseCIDR(cidr)
+	ones, bits := block.Mask.Size()
+	if bits-ones > 16 {
+		return nil, fmt.Errorf("CIDR block %s expands to more than %d hosts", cidr, maxExpansion)
+	}
+	size := 1 << (bits - ones)
+
+	var hosts []string
+	addr := ip.Mask(block.Mask)
+	for i := 0; i < size; i++ {
+		hosts = append(hosts, addr.String())
+		addr = nextIP(addr)
+	}
+	if ip.To4() != nil && size > 2 {
+		hosts = hosts[1 : len(hosts)-1]
+	}
+	return hosts, nil
+}
+
+func nextIP(ip net.IP) net.IP {
+	next := make(net.IP, len(ip))
+	copy(next, ip)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandHost(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"bmc01.example.net", []string{"bmc01.example.net"}},
+		{"r12-bmc[08-11].ams", []string{"r12-bmc08.ams", "r12-bmc09.ams", "r12-bmc10.ams", "r12-bmc11.ams"}},
+		{"node[1-3,7]", []string{"node1", "node2", "node3", "node7"}},
+		{"rack[a-c]", []string{"racka", "rackb", "rackc"}},
+		{"bmc.{ams,fra}.example.net", []string{"bmc.ams.example.net", "bmc.fra.example.net"}},
+		{"r[1-2]-bmc{a,b}", []string{"r1-bmca", "r1-bmcb", "r2-bmca", "r2-bmcb"}},
+		{"10.0.4.0/30", []string{"10.0.4.1", "10.0.4.2"}},
+		{"10.0.4.8/31", []string{"10.0.4.8", "10.0.4.9"}},
+		{"10.0.4.7/32", []string{"10.0.4.7"}},
+		{"[fe80::1]", []string{"[fe80::1]"}},
+	}
+	for _, tt := range tests {
+		hosts, err := ExpandHost(tt.pattern)
+		require.NoError(t, err, tt.pattern)
+		assert.Equal(t, tt.want, hosts, tt.pattern)
+	}
+
+	hosts, err := ExpandHost("10.1.0.0/24")
+	require.NoError(t, err)
+	assert.Len(t, hosts, 254)
+	assert.Equal(t, "10.1.0.254", hosts[253])
+
+	for _, bad := range []string{"bmc[01-10", "bmc{a,b", "bmc[10-01]", "bmc[1-c]", "10.0.0.0/8", "bmc[0-9999][0-99]"} {
+		_, err := ExpandHost(bad)
+		assert.Error(t, err, bad)
+	}
+}
@@ -0,0 +1,295 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Credentials are a named username and password that servers and templates
+// refer to instead of repeating them.
+type Credentials struct {
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// Template holds what many servers share. A server entry naming the
+// template takes every field it does not set itself; labels are merged and
+// groups joined.
+type Template struct {
+	Type        string            `yaml:"type,omitempty"`
+	Username    string            `yaml:"username,omitempty"`
+	Password    string            `yaml:"password,omitempty"`
+	Credentials string            `yaml:"credentials,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Groups      []string          `yaml:"groups,omitempty"`
+}
+
+// ServerEntry is a server as written in a configuration file. Its hostname
+// and hosts are patterns that ExpandHost turns into one server each.
+type ServerEntry struct {
+	ServerConfig `yaml:",inline"`
+	Hosts        []string `yaml:"hosts,omitempty"`
+	Template     string   `yaml:"template,omitempty"`
+	Credentials  string   `yaml:"credentials,omitempty"`
+}
+
+// File is a configuration file as written, before includes are read and
+// server entries expanded.
+type File struct {
+	// Include lists other configuration files, directories of them (every
+	// *.yaml and *.yml file, in name order) or glob patterns, relative to
+	// the including file.
+	Include     []string               `yaml:"include,omitempty"`
+	Credentials map[string]Credentials `yaml:"credentials,omitempty"`
+	Templates   map[string]Template    `yaml:"templates,omitempty"`
+	Groups      map[string][]string    `yaml:"groups,omitempty"`
+	Servers     []ServerEntry          `yaml:"servers"`
+	// Sources add the servers of inventories kept elsewhere.
+	Sources []SourceSpec `yaml:"sources,omitempty"`
+}
+
+// loadFile reads the configuration file at path with everything it
+// includes, and expands its servers.
+func loadFile(path string) (*BMCConfig, error) {
+	merged := &File{}
+	if err := merged.read(path, map[string]bool{}); err != nil {
+		return nil, err
+	}
+	return merged.Expand()
+}
+
+// read merges the file at path, after the files it includes, into f. A file
+// already read is skipped, which also ends include cycles.
+func (f *File) read(path string, seen map[string]bool) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if seen[abs] {
+		return nil
+	}
+	seen[abs] = true
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	for i, spec := range file.Sources {
+		file.Sources[i] = spec.resolve(filepath.Dir(path))
+	}
+	for _, include := range file.Include {
+		paths, err := includePaths(filepath.Join(filepath.Dir(path), include))
+		if err != nil {
+			return fmt.Errorf("%s: include %q: %w", path, include, err)
+		}
+		for _, p := range paths {
+			if err := f.read(p, seen); err != nil {
+				return err
+			}
+		}
+	}
+	if err := f.merge(&file); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// includePaths lists the files an include names.
+func includePaths(include string) ([]string, error) {
+	if strings.ContainsAny(include, "*?[") {
+		paths, err := filepath.Glob(include)
+		if err != nil {
+			return nil, err
+		}
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("no file matches")
+		}
+		sort.Strings(paths)
+		return paths, nil
+	}
+	info, err := os.Stat(include)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{include}, nil
+	}
+	entries, err := os.ReadDir(include)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if !e.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			paths = append(paths, filepath.Join(include, e.Name()))
+		}
+	}
+	return paths, nil
+}
+
+// merge adds other to f. Credentials and templates may be defined only
+// once; groups defined in several files gather all their members.
+func (f *File) merge(other *File) error {
+	for name, c := range other.Credentials {
+		if _, ok := f.Credentials[name]; ok {
+			return fmt.Errorf("credentials %q are defined twice", name)
+		}
+		if f.Credentials == nil {
+			f.Credentials = make(map[string]Credentials)
+		}
+		f.Credentials[name] = c
+	}
+	for name, t := range other.Templates {
+		if _, ok := f.Templates[name]; ok {
+			return fmt.Errorf("template %q is defined twice", name)
+		}
+		if f.Templates == nil {
+			f.Templates = make(map[string]Template)
+		}
+		f.Templates[name] = t
+	}
+	for name, members := range other.Groups {
+		if f.Groups == nil {
+			f.Groups = make(map[string][]string)
+		}
+		f.Groups[name] = append(f.Groups[name], members...)
+	}
+	f.Servers = append(f.Servers, other.Servers...)
+	f.Sources = append(f.Sources, other.Sources...)
+	return nil
+}
+
+// Expand applies templates and credentials to the server entries and
+// expands their host patterns into the configuration commands use. The
+// servers of the inventory sources follow those of the entries.
+func (f *File) Expand() (*BMCConfig, error) {
+	return f.expand(context.Background())
+}
+
+func (f *File) expand(ctx context.Context) (*BMCConfig, error) {
+	cfg := &BMCConfig{Servers: []ServerConfig{}, Groups: f.Groups}
+	for i, entry := range f.Servers {
+		servers, err := f.expandEntry(entry)
+		if err != nil {
+			return nil, fmt.Errorf("server %d: %w", i+1, err)
+		}
+		cfg.Servers = append(cfg.Servers, servers...)
+	}
+	for _, spec := range f.Sources {
+		entries, err := spec.fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			servers, err := f.expandEntry(entry)
+			if err != nil {
+				return nil, fmt.Errorf("%s inventory: server %s: %w", spec.Kind, entry.Hostname, err)
+			}
+			cfg.Servers = append(cfg.Servers, servers...)
+		}
+	}
+	return cfg, nil
+}
+
+func (f *File) expandEntry(entry ServerEntry) ([]ServerConfig, error) {
+	var tmpl Template
+	if entry.Template != "" {
+		t, ok := f.Templates[entry.Template]
+		if !ok {
+			return nil, fmt.Errorf("unknown template %q", entry.Template)
+		}
+		tmpl = t
+	}
+	entryCreds, err := f.credentials(entry.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	tmplCreds, err := f.credentials(tmpl.Credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	// The entry wins over its template; at each level, a username or
+	// password wins over the credentials reference.
+	base := entry.ServerConfig
+	base.Type = first(entry.Type, tmpl.Type)
+	base.Username = first(entry.Username, entryCreds.Username, tmpl.Username, tmplCreds.Username)
+	base.Password = first(entry.Password, entryCreds.Password, tmpl.Password, tmplCreds.Password)
+	if len(tmpl.Labels) > 0 {
+		base.Labels = make(map[string]string)
+		for k, v := range tmpl.Labels {
+			base.Labels[k] = v
+		}
+		for k, v := range entry.Labels {
+			base.Labels[k] = v
+		}
+	}
+	if len(tmpl.Groups) > 0 {
+		base.Groups = append(append([]string{}, tmpl.Groups...), entry.Groups...)
+	}
+
+	patterns := entry.Hosts
+	if entry.Hostname != "" {
+		patterns = append([]string{entry.Hostname}, patterns...)
+	}
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("no hostname or hosts")
+	}
+	var servers []ServerConfig
+	for _, pattern := range patterns {
+		hosts, err := ExpandHost(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, host := range hosts {
+			server := base
+			server.Hostname = host
+			// Each server gets its own labels and groups, so changing one
+			// server's cannot change the others'.
+			if base.Labels != nil {
+				server.Labels = make(map[string]string, len(base.Labels))
+				for k, v := range base.Labels {
+					server.Labels[k] = v
+				}
+			}
+			if base.Groups != nil {
+				server.Groups = append([]string{}, base.Groups...)
+			}
+			servers = append(servers, server)
+		}
+	}
+	return servers, nil
+}
+
+func (f *File) credentials(name string) (Credentials, error) {
+	if name == "" {
+		return Credentials{}, nil
+	}
+	c, ok := f.Credentials[name]
+	if !ok {
+		return Credentials{}, fmt.Errorf("unknown credentials %q", name)
+	}
+	return c, nil
+}
+
+// first returns the first of values that is not empty.
+func first(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
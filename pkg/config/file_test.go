@@ -0,0 +1,122 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+	return dir
+}
+
+func TestLoadConfigTemplates(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.yaml": `
+include: [credentials.yaml, sites]
+templates:
+  dell-ams:
+    type: idrac
+    credentials: dell
+    labels: {site: ams, vendor: dell}
+    groups: [dell]
+servers:
+  - hostname: r12-bmc[01-03].ams
+    template: dell-ams
+    labels: {rack: r12}
+  - hosts: [r14-bmc01.ams, r14-bmc02.ams]
+    template: dell-ams
+    credentials: legacy
+    labels: {site: ams-2}
+  - type: idrac
+    hostname: standalone
+    username: root
+    password: calvin
+`,
+		"credentials.yaml": `
+credentials:
+  dell: {username: root, password: calvin}
+  legacy: {username: admin, password: old}
+`,
+		"sites/fra.yaml": `
+groups:
+  fra: ["*.fra"]
+servers:
+  - type: xclarity
+    hostname: 10.2.0.0/30
+    credentials: dell
+  - {type: xclarity, hostname: "bmc.{a,b}.fra", username: USERID, password: PASSW0RD}
+`,
+		"sites/notes.txt": "not a config file",
+	})
+
+	cfg, err := LoadConfig(filepath.Join(dir, "config.yaml"))
+	require.NoError(t, err)
+
+	var hostnames []string
+	for _, s := range cfg.Servers {
+		hostnames = append(hostnames, s.Hostname)
+	}
+	assert.Equal(t, []string{
+		"10.2.0.1", "10.2.0.2", "bmc.a.fra", "bmc.b.fra",
+		"r12-bmc01.ams", "r12-bmc02.ams", "r12-bmc03.ams",
+		"r14-bmc01.ams", "r14-bmc02.ams", "standalone",
+	}, hostnames, "included servers come first")
+
+	assert.Equal(t, ServerConfig{Type: "xclarity", Hostname: "10.2.0.1", Username: "root", Password: "calvin"}, cfg.Servers[0])
+	assert.Equal(t, ServerConfig{
+		Type: "idrac", Hostname: "r12-bmc02.ams", Username: "root", Password: "calvin",
+		Labels: map[string]string{"site": "ams", "vendor": "dell", "rack": "r12"},
+		Groups: []string{"dell"},
+	}, cfg.Servers[5])
+	assert.Equal(t, "admin", cfg.Servers[7].Username, "the entry's credentials beat the template's")
+	assert.Equal(t, "ams-2", cfg.Servers[7].Labels["site"])
+	assert.Equal(t, ServerConfig{Type: "idrac", Hostname: "standalone", Username: "root", Password: "calvin"}, cfg.Servers[9])
+	assert.Equal(t, map[string][]string{"fra": {"*.fra"}}, cfg.Groups)
+
+	cfg.Servers[5].Labels["rack"] = "changed"
+	assert.Equal(t, "r12", cfg.Servers[6].Labels["rack"], "expanded servers do not share labels")
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	tests := map[string]string{
+		"unknown template":    "servers:\n  - {hostname: a, template: dell}\n",
+		"unknown credentials": "servers:\n  - {hostname: a, credentials: dell}\n",
+		"missing hostname":    "servers:\n  - {type: idrac}\n",
+		"bad pattern":         "servers:\n  - {hostname: \"a[01-\"}\n",
+		"missing include":     "include: [missing.yaml]\nservers: []\n",
+		"duplicate template":  "include: [config.yaml, other.yaml]\ntemplates: {t: {type: idrac}}\nservers: []\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := writeFiles(t, map[string]string{
+				"config.yaml": content,
+				"other.yaml":  "templates: {t: {type: xclarity}}\n",
+			})
+			_, err := LoadConfig(filepath.Join(dir, "config.yaml"))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadConfigIncludeCycle(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.yaml": "include: [b.yaml]\nservers:\n  - {type: idrac, hostname: a}\n",
+		"b.yaml": "include: [a.yaml]\nservers:\n  - {type: idrac, hostname: b}\n",
+	})
+	cfg, err := LoadConfig(filepath.Join(dir, "a.yaml"))
+	require.NoError(t, err)
+	require.Len(t, cfg.Servers, 2)
+	assert.Equal(t, "b", cfg.Servers[0].Hostname)
+	assert.Equal(t, "a", cfg.Servers[1].Hostname)
+}
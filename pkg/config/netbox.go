@@ -0,0 +1,174 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+func init() {
+	RegisterSource("netbox", func(spec SourceSpec) (Source, error) {
+		if spec.URL == "" {
+			return nil, fmt.Errorf("netbox inventory needs a url")
+		}
+		token := spec.Token
+		if token == "" {
+			token = os.Getenv("NETBOX_TOKEN")
+		}
+		return &NetBoxSource{URL: spec.URL, Token: token, Filter: spec.Filter}, nil
+	})
+}
+
+// netboxTypes maps NetBox manufacturer slugs to BMC types.
+var netboxTypes = map[string]string{
+	"dell":   "idrac",
+	"lenovo": "xclarity",
+}
+
+// NetBoxSource reads the devices with an out-of-band IP from the NetBox
+// API. Each device's OOB IP is its BMC; its name, site, rack, role, model,
+// manufacturer and tenant become labels and its tags groups. Dell and
+// Lenovo devices get the idrac and xclarity types.
+type NetBoxSource struct {
+	// URL is the NetBox base URL, e.g. https://netbox.example.net.
+	URL string
+	// Token is the API token, which may be a secret reference;
+	// NETBOX_TOKEN is used when the spec has none.
+	Token string
+	// Filter adds query parameters to the device list, e.g. site=ams or
+	// role=server.
+	Filter map[string]string
+	// Client sends the requests; nil means a client with a 30s timeout.
+	Client *http.Client
+}
+
+type netboxRef struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type netboxDevice struct {
+	Name  string `json:"name"`
+	OOBIP *struct {
+		Address string `json:"address"`
+	} `json:"oob_ip"`
+	Site       *netboxRef `json:"site"`
+	Rack       *netboxRef `json:"rack"`
+	Role       *netboxRef `json:"role"`
+	DeviceRole *netboxRef `json:"device_role"`
+	Tenant     *netboxRef `json:"tenant"`
+	DeviceType *struct {
+		Model        string     `json:"model"`
+		Manufacturer *netboxRef `json:"manufacturer"`
+	} `json:"device_type"`
+	Tags []netboxRef `json:"tags"`
+}
+
+type netboxPage struct {
+	Next    string         `json:"next"`
+	Results []netboxDevice `json:"results"`
+}
+
+// Servers implements Source.
+func (s *NetBoxSource) Servers(ctx context.Context) ([]ServerConfig, error) {
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	query := url.Values{"has_oob_ip": {"true"}, "limit": {"1000"}}
+	for k, v := range s.Filter {
+		query.Set(k, v)
+	}
+	next := strings.TrimSuffix(s.URL, "/") + "/api/dcim/devices/?" + query.Encode()
+
+	token, err := ResolveSecret(ctx, s.Token)
+	if err != nil {
+		return nil, fmt.Errorf("token: %w", err)
+	}
+
+	var servers []ServerConfig
+	for next != "" {
+		page, err := s.get(ctx, client, next, token)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range page.Results {
+			if server, ok := d.server(); ok {
+				servers = append(servers, server)
+			}
+		}
+		next = page.Next
+	}
+	return servers, nil
+}
+
+func (s *NetBoxSource) get(ctx context.Context, client *http.Client, uri, token string) (*netboxPage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Token "+token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", req.URL.Path, resp.Status)
+	}
+	var page netboxPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("GET %s: %w", req.URL.Path, err)
+	}
+	return &page, nil
+}
+
+// server returns the server of the device, if it has an OOB IP.
+func (d netboxDevice) server() (ServerConfig, bool) {
+	if d.OOBIP == nil || d.OOBIP.Address == "" {
+		return ServerConfig{}, false
+	}
+	host, _, _ := strings.Cut(d.OOBIP.Address, "/")
+	server := ServerConfig{Hostname: host, Labels: make(map[string]string)}
+
+	setLabel := func(key, value string) {
+		if value != "" {
+			server.Labels[key] = value
+		}
+	}
+	setLabel("name", d.Name)
+	if d.Site != nil {
+		setLabel("site", d.Site.Slug)
+	}
+	if d.Rack != nil {
+		setLabel("rack", d.Rack.Name)
+	}
+	// NetBox 3.6 renamed device_role to role.
+	if d.Role != nil {
+		setLabel("role", d.Role.Slug)
+	} else if d.DeviceRole != nil {
+		setLabel("role", d.DeviceRole.Slug)
+	}
+	if d.Tenant != nil {
+		setLabel("tenant", d.Tenant.Slug)
+	}
+	if d.DeviceType != nil {
+		setLabel("model", d.DeviceType.Model)
+		if d.DeviceType.Manufacturer != nil {
+			setLabel("manufacturer", d.DeviceType.Manufacturer.Slug)
+			server.Type = netboxTypes[d.DeviceType.Manufacturer.Slug]
+		}
+	}
+	for _, tag := range d.Tags {
+		server.Groups = append(server.Groups, tag.Slug)
+	}
+	return server, true
+}
@@ -0,0 +1,241 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SecretResolver returns the secret a reference names. It gets the
+// reference without its scheme, e.g. "BMC_PASSWORD" for "env:BMC_PASSWORD".
+// Errors must not contain the secret.
+type SecretResolver func(ctx context.Context, ref string) (string, error)
+
+var (
+	schemesMu sync.RWMutex
+	schemes   = map[string]SecretResolver{
+		"plain": func(ctx context.Context, ref string) (string, error) { return ref, nil },
+		"env":   resolveEnv,
+		"file":  resolveFile,
+		"cmd":   resolveCmd,
+		"vault": resolveVault,
+	}
+)
+
+// RegisterSecretScheme registers a resolver for the secret references
+// starting with scheme and a colon.
+func RegisterSecretScheme(scheme string, resolver SecretResolver) {
+	schemesMu.Lock()
+	defer schemesMu.Unlock()
+	schemes[scheme] = resolver
+}
+
+func secretScheme(value string) (SecretResolver, string, bool) {
+	scheme, ref, ok := strings.Cut(value, ":")
+	if !ok {
+		return nil, "", false
+	}
+	schemesMu.RLock()
+	defer schemesMu.RUnlock()
+	resolver, ok := schemes[scheme]
+	return resolver, ref, ok
+}
+
+// IsSecretRef reports whether value is a secret reference rather than the
+// secret itself.
+func IsSecretRef(value string) bool {
+	_, _, ok := secretScheme(value)
+	return ok
+}
+
+// ResolveSecret returns the secret value refers to:
+//
+//   - "env:VAR", the value of the environment variable;
+//   - "file:/path", the content of the file without its trailing newline;
+//   - "cmd:pass show bmc/root", the first line the shell command prints;
+//   - "vault:secret/bmc#root", the root field of the secret at secret/bmc
+//     in HashiCorp Vault's KV engine, version 2 or 1, at VAULT_ADDR with
+//     VAULT_TOKEN or ~/.vault-token. The field defaults to "password".
+//
+// "plain:" keeps the rest as is, for secrets that look like references.
+// Any other value is the secret itself.
+func ResolveSecret(ctx context.Context, value string) (string, error) {
+	resolver, ref, ok := secretScheme(value)
+	if !ok {
+		return value, nil
+	}
+	secret, err := resolver(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", value, err)
+	}
+	return secret, nil
+}
+
+// Secrets resolves secret references, each once however many servers share
+// it. The zero Secrets is ready to use; a nil *Secrets resolves every time.
+type Secrets struct {
+	mu     sync.Mutex
+	values map[string]*secretValue
+}
+
+type secretValue struct {
+	once   sync.Once
+	secret string
+	err    error
+}
+
+// Resolve is ResolveSecret, remembering the result.
+func (s *Secrets) Resolve(ctx context.Context, value string) (string, error) {
+	if s == nil || !IsSecretRef(value) {
+		return ResolveSecret(ctx, value)
+	}
+	s.mu.Lock()
+	if s.values == nil {
+		s.values = make(map[string]*secretValue)
+	}
+	v, ok := s.values[value]
+	if !ok {
+		v = &secretValue{}
+		s.values[value] = v
+	}
+	s.mu.Unlock()
+
+	v.once.Do(func() { v.secret, v.err = ResolveSecret(ctx, value) })
+	return v.secret, v.err
+}
+
+// ResolveSecrets returns the server with its password resolved, which
+// commands do just before connecting to it so that only the servers they
+// reach need their secrets.
+func (s ServerConfig) ResolveSecrets(ctx context.Context, secrets *Secrets) (ServerConfig, error) {
+	password, err := secrets.Resolve(ctx, s.Password)
+	if err != nil {
+		return s, fmt.Errorf("password of %s: %w", s.Hostname, err)
+	}
+	s.Password = password
+	return s, nil
+}
+
+func resolveEnv(ctx context.Context, name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("%s is not set", name)
+	}
+	return value, nil
+}
+
+func resolveFile(ctx context.Context, path string) (string, error) {
+	data, err := os.ReadFile(expandPath(path, ""))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+func resolveCmd(ctx context.Context, command string) (string, error) {
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	var stdout, stderr bytes.Buffer
+	c := exec.CommandContext(ctx, shell, flag, command)
+	c.Stdout, c.Stderr = &stdout, &stderr
+	if err := c.Run(); err != nil {
+		// What the command printed on stdout may be (part of) the secret,
+		// so only stderr explains the failure.
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, firstLine(msg))
+		}
+		return "", err
+	}
+	return firstLine(stdout.String()), nil
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return strings.TrimRight(line, "\r")
+}
+
+func resolveVault(ctx context.Context, ref string) (string, error) {
+	path, field, _ := strings.Cut(ref, "#")
+	if field == "" {
+		field = "password"
+	}
+	mount, rest, ok := strings.Cut(strings.Trim(path, "/"), "/")
+	if !ok {
+		return "", fmt.Errorf("want mount/path#field")
+	}
+	addr := strings.TrimSuffix(os.Getenv("VAULT_ADDR"), "/")
+	if addr == "" {
+		return "", fmt.Errorf("VAULT_ADDR is not set")
+	}
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			if data, err := os.ReadFile(filepath.Join(home, ".vault-token")); err == nil {
+				token = strings.TrimSpace(string(data))
+			}
+		}
+	}
+
+	// KV version 2 keeps the secret under data/, inside a data object;
+	// version 1 at the path itself.
+	data, err := vaultRead(ctx, addr+"/v1/"+mount+"/data/"+rest, token)
+	if err == nil {
+		data, _ = data["data"].(map[string]interface{})
+	} else if errors.Is(err, errVaultNotFound) {
+		data, err = vaultRead(ctx, addr+"/v1/"+mount+"/"+rest, token)
+	}
+	if err != nil {
+		return "", err
+	}
+	value, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("the secret has no field %q", field)
+	}
+	return fmt.Sprint(value), nil
+}
+
+var errVaultNotFound = errors.New("no such secret")
+
+func vaultRead(ctx context.Context, uri, token string) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if ns := os.Getenv("VAULT_NAMESPACE"); ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+	resp, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, errVaultNotFound
+	default:
+		return nil, fmt.Errorf("vault answered %s", resp.Status)
+	}
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	return body.Data, nil
+}
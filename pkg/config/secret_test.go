@@ -0,0 +1,96 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSecret(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("REDFISHCLI_TEST_SECRET", "calvin")
+	file := filepath.Join(t.TempDir(), "password")
+	require.NoError(t, os.WriteFile(file, []byte("s3cret\n"), 0600))
+
+	tests := map[string]string{
+		"calvin":                      "calvin",
+		"env:REDFISHCLI_TEST_SECRET":  "calvin",
+		"file:" + file:                "s3cret",
+		"cmd:printf 'p@ss\\nmeta\\n'": "p@ss",
+		"plain:env:not-a-ref":         "env:not-a-ref",
+		"https://not-a-ref":           "https://not-a-ref",
+	}
+	for ref, want := range tests {
+		secret, err := ResolveSecret(ctx, ref)
+		require.NoError(t, err, ref)
+		assert.Equal(t, want, secret, ref)
+	}
+
+	_, err := ResolveSecret(ctx, "env:REDFISHCLI_TEST_UNSET")
+	assert.EqualError(t, err, "env:REDFISHCLI_TEST_UNSET: REDFISHCLI_TEST_UNSET is not set")
+
+	_, err = ResolveSecret(ctx, "cmd:cat "+file+"; echo 'not in the store' >&2; exit 1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not in the store")
+	assert.NotContains(t, err.Error(), "s3cret", "what the command printed stays out of the error")
+}
+
+func TestResolveVaultSecret(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.test" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/bmc":
+			w.Write([]byte(`{"data": {"data": {"root": "calvin", "password": "default"}, "metadata": {"version": 3}}}`))
+		case "/v1/kv1/bmc":
+			w.Write([]byte(`{"data": {"root": "legacy"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors": []}`))
+		}
+	}))
+	defer srv.Close()
+	t.Setenv("VAULT_ADDR", srv.URL)
+	t.Setenv("VAULT_TOKEN", "s.test")
+	ctx := context.Background()
+
+	for ref, want := range map[string]string{"vault:secret/bmc#root": "calvin", "vault:secret/bmc": "default", "vault:kv1/bmc#root": "legacy"} {
+		secret, err := ResolveSecret(ctx, ref)
+		require.NoError(t, err, ref)
+		assert.Equal(t, want, secret, ref)
+	}
+
+	_, err := ResolveSecret(ctx, "vault:secret/bmc#admin")
+	assert.EqualError(t, err, `vault:secret/bmc#admin: the secret has no field "admin"`)
+	_, err = ResolveSecret(ctx, "vault:secret/missing")
+	assert.ErrorIs(t, err, errVaultNotFound)
+
+	t.Setenv("VAULT_TOKEN", "s.wrong")
+	_, err = ResolveSecret(ctx, "vault:secret/bmc#root")
+	assert.EqualError(t, err, "vault:secret/bmc#root: vault answered 403 Forbidden")
+}
+
+func TestSecretsResolveOnce(t *testing.T) {
+	counter := filepath.Join(t.TempDir(), "runs")
+	ref := "cmd:echo run >> " + counter + "; echo calvin"
+	var secrets Secrets
+	for _, host := range []string{"bmc01", "bmc02", "bmc03"} {
+		server, err := ServerConfig{Hostname: host, Username: "root", Password: ref}.ResolveSecrets(context.Background(), &secrets)
+		require.NoError(t, err)
+		assert.Equal(t, "calvin", server.Password)
+	}
+	runs, err := os.ReadFile(counter)
+	require.NoError(t, err)
+	assert.Equal(t, "run\n", string(runs))
+
+	_, err = ServerConfig{Hostname: "bmc04", Password: "env:REDFISHCLI_TEST_UNSET"}.ResolveSecrets(context.Background(), &secrets)
+	assert.EqualError(t, err, "password of bmc04: env:REDFISHCLI_TEST_UNSET: REDFISHCLI_TEST_UNSET is not set")
+}
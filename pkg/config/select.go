@@ -0,0 +1,218 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Operator is how a Requirement compares a label.
+type Operator string
+
+const (
+	// OpEquals requires the label to have the value.
+	OpEquals Operator = "="
+	// OpNotEquals requires the label to be missing or have another value.
+	OpNotEquals Operator = "!="
+	// OpExists requires the label to be set, whatever its value.
+	OpExists Operator = "exists"
+	// OpNotExists requires the label to be missing.
+	OpNotExists Operator = "!exists"
+)
+
+// Requirement is one condition of a Selector.
+type Requirement struct {
+	Key   string
+	Op    Operator
+	Value string
+}
+
+// Matches reports whether labels meet the requirement.
+func (r Requirement) Matches(labels map[string]string) bool {
+	value, ok := labels[r.Key]
+	switch r.Op {
+	case OpEquals:
+		return ok && value == r.Value
+	case OpNotEquals:
+		return !ok || value != r.Value
+	case OpExists:
+		return ok
+	case OpNotExists:
+		return !ok
+	}
+	return false
+}
+
+func (r Requirement) String() string {
+	switch r.Op {
+	case OpExists:
+		return r.Key
+	case OpNotExists:
+		return "!" + r.Key
+	}
+	return r.Key + string(r.Op) + r.Value
+}
+
+// Selector selects servers by their labels. A server matches when it meets
+// every requirement; the empty Selector matches every server.
+type Selector []Requirement
+
+// ParseSelector parses a comma-separated list of requirements:
+// "key=value" (or "key==value"), "key!=value", "key" for a label that is
+// set and "!key" for one that is not, e.g. "site=ams,role!=storage".
+func ParseSelector(s string) (Selector, error) {
+	var sel Selector
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		var r Requirement
+		switch {
+		case strings.Contains(term, "!="):
+			key, value, _ := strings.Cut(term, "!=")
+			r = Requirement{Key: key, Op: OpNotEquals, Value: value}
+		case strings.Contains(term, "=="):
+			key, value, _ := strings.Cut(term, "==")
+			r = Requirement{Key: key, Op: OpEquals, Value: value}
+		case strings.Contains(term, "="):
+			key, value, _ := strings.Cut(term, "=")
+			r = Requirement{Key: key, Op: OpEquals, Value: value}
+		case strings.HasPrefix(term, "!"):
+			r = Requirement{Key: term[1:], Op: OpNotExists}
+		default:
+			r = Requirement{Key: term, Op: OpExists}
+		}
+		r.Key = strings.TrimSpace(r.Key)
+		r.Value = strings.TrimSpace(r.Value)
+		if r.Key == "" || strings.ContainsAny(r.Key, "=! ") || strings.ContainsAny(r.Value, "=!") {
+			return nil, fmt.Errorf("invalid selector term %q", term)
+		}
+		sel = append(sel, r)
+	}
+	return sel, nil
+}
+
+// Matches reports whether labels meet every requirement.
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		if !r.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s Selector) String() string {
+	terms := make([]string, len(s))
+	for i, r := range s {
+		terms[i] = r.String()
+	}
+	return strings.Join(terms, ",")
+}
+
+// Filter narrows the servers of a configuration. Each field that is set
+// must be met; the zero Filter keeps every server.
+type Filter struct {
+	// Selector is matched against the labels of each server.
+	Selector Selector
+	// Groups keeps the servers in any of these groups.
+	Groups []string
+	// Limit keeps the servers whose hostname matches any of these
+	// patterns, which may use the wildcards of path.Match.
+	Limit []string
+	// Exclude drops the servers whose hostname matches any of these
+	// patterns.
+	Exclude []string
+}
+
+// IsZero reports whether f keeps every server.
+func (f Filter) IsZero() bool {
+	return len(f.Selector) == 0 && len(f.Groups) == 0 && len(f.Limit) == 0 && len(f.Exclude) == 0
+}
+
+// InGroup reports whether the server belongs to the named group, either by
+// listing it in its groups or by being listed in the group of cfg.
+func (c *BMCConfig) InGroup(server ServerConfig, group string) bool {
+	for _, g := range server.Groups {
+		if g == group {
+			return true
+		}
+	}
+	return matchAny(c.Groups[group], server.Hostname)
+}
+
+// Select returns the servers f keeps, in configuration order. It fails
+// when f names a group no server belongs to or a pattern is malformed, and
+// when f keeps no server at all, so a typo cannot silently turn a command
+// into a no-op.
+func (c *BMCConfig) Select(f Filter) ([]ServerConfig, error) {
+	if f.IsZero() {
+		return c.Servers, nil
+	}
+	for _, pattern := range append(append([]string{}, f.Limit...), f.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid host pattern %q: %w", pattern, err)
+		}
+	}
+	for _, group := range f.Groups {
+		if !c.hasGroup(group) {
+			return nil, fmt.Errorf("unknown group %q", group)
+		}
+	}
+
+	var selected []ServerConfig
+	for _, server := range c.Servers {
+		if c.keeps(f, server) {
+			selected = append(selected, server)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no configured server matches the selection")
+	}
+	return selected, nil
+}
+
+func (c *BMCConfig) keeps(f Filter, server ServerConfig) bool {
+	if !f.Selector.Matches(server.Labels) {
+		return false
+	}
+	if len(f.Groups) > 0 {
+		in := false
+		for _, group := range f.Groups {
+			in = in || c.InGroup(server, group)
+		}
+		if !in {
+			return false
+		}
+	}
+	if len(f.Limit) > 0 && !matchAny(f.Limit, server.Hostname) {
+		return false
+	}
+	return !matchAny(f.Exclude, server.Hostname)
+}
+
+// hasGroup reports whether group is defined in cfg or by any server.
+func (c *BMCConfig) hasGroup(group string) bool {
+	if _, ok := c.Groups[group]; ok {
+		return true
+	}
+	for _, server := range c.Servers {
+		for _, g := range server.Groups {
+			if g == group {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchAny reports whether hostname matches any of the patterns.
+func matchAny(patterns []string, hostname string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, hostname); ok {
+			return true
+		}
+	}
+	return false
+}
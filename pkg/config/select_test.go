@@ -0,0 +1,87 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseSelector(t *testing.T) {
+	sel, err := ParseSelector("site=ams, role!=storage,rack==r12,owner,!decommissioned")
+	require.NoError(t, err)
+	assert.Equal(t, Selector{
+		{Key: "site", Op: OpEquals, Value: "ams"},
+		{Key: "role", Op: OpNotEquals, Value: "storage"},
+		{Key: "rack", Op: OpEquals, Value: "r12"},
+		{Key: "owner", Op: OpExists},
+		{Key: "decommissioned", Op: OpNotExists},
+	}, sel)
+	assert.Equal(t, "site=ams,role!=storage,rack=r12,owner,!decommissioned", sel.String())
+
+	assert.True(t, sel.Matches(map[string]string{"site": "ams", "role": "compute", "rack": "r12", "owner": "db-team"}))
+	assert.True(t, sel.Matches(map[string]string{"site": "ams", "rack": "r12", "owner": ""}), "a missing label is not equal")
+	assert.False(t, sel.Matches(map[string]string{"site": "ams", "role": "storage", "rack": "r12", "owner": "x"}))
+	assert.False(t, sel.Matches(map[string]string{"site": "ams", "rack": "r12"}))
+	assert.False(t, sel.Matches(map[string]string{"site": "ams", "rack": "r12", "owner": "x", "decommissioned": "2024"}))
+
+	empty, err := ParseSelector("")
+	require.NoError(t, err)
+	assert.True(t, empty.Matches(nil))
+
+	for _, bad := range []string{"=ams", "site=a=b", "!", "site!=a!b"} {
+		_, err := ParseSelector(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	var cfg BMCConfig
+	require.NoError(t, yaml.Unmarshal([]byte(`
+groups:
+  canary: [r12-bmc01.ams, "r14-*"]
+servers:
+  - type: idrac
+    hostname: r12-bmc01.ams
+    labels: {site: ams, role: compute, rack: r12}
+    groups: [db]
+  - type: idrac
+    hostname: r12-bmc02.ams
+    labels: {site: ams, role: storage, rack: r12}
+  - type: xclarity
+    hostname: r14-bmc01.fra
+    labels: {site: fra, role: compute, rack: r14}
+    groups: [db]
+`), &cfg))
+
+	hostnames := func(f Filter) []string {
+		t.Helper()
+		servers, err := cfg.Select(f)
+		require.NoError(t, err)
+		var names []string
+		for _, s := range servers {
+			names = append(names, s.Hostname)
+		}
+		return names
+	}
+	selector := func(s string) Selector {
+		sel, err := ParseSelector(s)
+		require.NoError(t, err)
+		return sel
+	}
+
+	assert.Len(t, hostnames(Filter{}), 3)
+	assert.Equal(t, []string{"r12-bmc01.ams"}, hostnames(Filter{Selector: selector("site=ams,role!=storage")}))
+	assert.Equal(t, []string{"r12-bmc01.ams", "r14-bmc01.fra"}, hostnames(Filter{Groups: []string{"db"}}))
+	assert.Equal(t, []string{"r12-bmc01.ams", "r14-bmc01.fra"}, hostnames(Filter{Groups: []string{"canary"}}))
+	assert.Equal(t, []string{"r12-bmc02.ams"}, hostnames(Filter{Limit: []string{"*.ams"}, Exclude: []string{"r12-bmc01.ams"}}))
+	assert.Equal(t, []string{"r14-bmc01.fra"}, hostnames(Filter{Groups: []string{"db"}, Selector: selector("site=fra")}))
+
+	_, err := cfg.Select(Filter{Groups: []string{"web"}})
+	assert.EqualError(t, err, `unknown group "web"`)
+	_, err = cfg.Select(Filter{Selector: selector("site=nyc")})
+	assert.EqualError(t, err, "no configured server matches the selection")
+	_, err = cfg.Select(Filter{Limit: []string{"r12-[bmc"}})
+	assert.Error(t, err)
+}
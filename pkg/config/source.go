@@ -0,0 +1,127 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Source provides servers from an inventory kept outside redfishcli, such as
+// a CSV export, an Ansible inventory, NetBox or a DHCP server's leases.
+type Source interface {
+	Servers(ctx context.Context) ([]ServerConfig, error)
+}
+
+// SourceSpec configures one inventory source. Which fields matter depends
+// on the kind; the servers it provides take the template and credentials
+// named here like any server entry.
+type SourceSpec struct {
+	// Kind selects the provider: csv, ansible, netbox, dhcp or one added
+	// with RegisterSource.
+	Kind string `yaml:"kind"`
+	// Path is the file the csv, ansible and dhcp providers read, relative
+	// to the configuration file naming it.
+	Path string `yaml:"path,omitempty"`
+	// URL and Token locate and authenticate to an API such as NetBox's.
+	URL   string `yaml:"url,omitempty"`
+	Token string `yaml:"token,omitempty"`
+	// Filter narrows what the source returns, e.g. the query parameters of
+	// the NetBox device list.
+	Filter map[string]string `yaml:"filter,omitempty"`
+	// Match keeps the servers whose name matches any of these patterns,
+	// which may use the wildcards of path.Match, e.g. "idrac-*" to pick
+	// BMCs out of DHCP leases.
+	Match []string `yaml:"match,omitempty"`
+
+	Template    string `yaml:"template,omitempty"`
+	Credentials string `yaml:"credentials,omitempty"`
+}
+
+// SourceFactory creates a Source from its specification.
+type SourceFactory func(spec SourceSpec) (Source, error)
+
+var (
+	sourcesMu sync.RWMutex
+	sources   = make(map[string]SourceFactory)
+)
+
+// RegisterSource registers a factory for a kind of inventory source.
+func RegisterSource(kind string, factory SourceFactory) {
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+	sources[kind] = factory
+}
+
+// NewSource creates the Source spec describes.
+func NewSource(spec SourceSpec) (Source, error) {
+	sourcesMu.RLock()
+	factory, ok := sources[spec.Kind]
+	sourcesMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unsupported inventory source: %q", spec.Kind)
+	}
+	return factory(spec)
+}
+
+// SourceKinds returns the registered kinds of inventory source, sorted.
+func SourceKinds() []string {
+	sourcesMu.RLock()
+	defer sourcesMu.RUnlock()
+	var kinds []string
+	for k := range sources {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// ParseSourceSpec parses the short form of a source given on the command
+// line, "kind:location", where the location is the URL of API sources and
+// the path of the others, e.g. "ansible:hosts.ini" or
+// "netbox:https://netbox.example.net".
+func ParseSourceSpec(s string) (SourceSpec, error) {
+	kind, location, ok := strings.Cut(s, ":")
+	if !ok || kind == "" || location == "" {
+		return SourceSpec{}, fmt.Errorf("invalid inventory source %q, want kind:location", s)
+	}
+	spec := SourceSpec{Kind: kind}
+	if strings.Contains(location, "://") {
+		spec.URL = location
+	} else {
+		spec.Path = location
+	}
+	return spec, nil
+}
+
+// fetch returns the server entries the source provides, keeping those
+// Match selects.
+func (spec SourceSpec) fetch(ctx context.Context) ([]ServerEntry, error) {
+	src, err := NewSource(spec)
+	if err != nil {
+		return nil, err
+	}
+	servers, err := src.Servers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s inventory: %w", spec.Kind, err)
+	}
+	var entries []ServerEntry
+	for _, server := range servers {
+		if len(spec.Match) > 0 && !matchAny(spec.Match, server.Hostname) && !matchAny(spec.Match, server.Labels["name"]) {
+			continue
+		}
+		entries = append(entries, ServerEntry{ServerConfig: server, Template: spec.Template, Credentials: spec.Credentials})
+	}
+	return entries, nil
+}
+
+// resolve makes the path of spec relative to dir.
+func (spec SourceSpec) resolve(dir string) SourceSpec {
+	if spec.Path != "" && !filepath.IsAbs(spec.Path) {
+		spec.Path = filepath.Join(dir, spec.Path)
+	}
+	return spec
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sourceServers(t *testing.T, spec SourceSpec) []ServerConfig {
+	t.Helper()
+	src, err := NewSource(spec)
+	require.NoError(t, err)
+	servers, err := src.Servers(context.Background())
+	require.NoError(t, err)
+	return servers
+}
+
+func TestCSVSource(t *testing.T) {
+	servers := sourceServers(t, SourceSpec{Kind: "csv", Path: "testdata/servers.csv"})
+	assert.Equal(t, []ServerConfig{
+		{Type: "idrac", Hostname: "r12-bmc01.ams", Username: "root", Password: "calvin", Labels: map[string]string{"site": "ams", "rack": "r12"}, Groups: []string{"db", "canary"}},
+		{Type: "idrac", Hostname: "r12-bmc02.ams", Labels: map[string]string{"site": "ams", "rack": "r12"}},
+		{Type: "xclarity", Hostname: "10.2.0.15", Username: "USERID", Password: "PASSW0RD", Labels: map[string]string{"site": "fra"}, Groups: []string{"db"}},
+	}, servers)
+
+	path := filepath.Join(t.TempDir(), "bad.csv")
+	require.NoError(t, os.WriteFile(path, []byte("name,site\nbmc01,ams\n"), 0600))
+	src, err := NewSource(SourceSpec{Kind: "csv", Path: path})
+	require.NoError(t, err)
+	_, err = src.Servers(context.Background())
+	assert.ErrorContains(t, err, "no hostname column")
+}
+
+func TestAnsibleSource(t *testing.T) {
+	servers := sourceServers(t, SourceSpec{Kind: "ansible", Path: "testdata/hosts.ini"})
+	assert.Equal(t, []ServerConfig{
+		{Type: "idrac", Hostname: "10.1.0.11", Username: "root", Password: "calvin", Labels: map[string]string{"name": "db01.ams"}, Groups: []string{"ams", "dc"}},
+		{Type: "idrac", Hostname: "10.1.0.12", Username: "root", Password: "s3cret pass", Labels: map[string]string{"name": "db02.ams"}, Groups: []string{"ams", "dc"}},
+		{Type: "idrac", Hostname: "web[01-02].ams", Username: "root", Password: "calvin", Labels: map[string]string{"name": "web[01-02].ams"}, Groups: []string{"ams", "dc"}},
+		{Type: "xclarity", Hostname: "10.2.0.21", Username: "root", Labels: map[string]string{"name": "storage01.fra"}, Groups: []string{"fra", "dc"}},
+	}, servers)
+
+	servers = sourceServers(t, SourceSpec{Kind: "ansible", Path: "testdata/hosts.yaml"})
+	assert.Equal(t, []ServerConfig{
+		{Type: "idrac", Hostname: "10.1.0.11", Username: "root", Password: "calvin", Labels: map[string]string{"name": "db01.ams"}, Groups: []string{"ams"}},
+		{Type: "idrac", Hostname: "10.1.0.12", Username: "root", Password: "other", Labels: map[string]string{"name": "db02.ams"}, Groups: []string{"ams"}},
+		{Type: "xclarity", Hostname: "10.2.0.21", Username: "root", Labels: map[string]string{"name": "storage01.fra"}, Groups: []string{"fra"}},
+	}, servers)
+}
+
+func TestNetBoxSource(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/netbox-devices.json")
+	require.NoError(t, err)
+	var page struct {
+		Results []json.RawMessage `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal(fixture, &page))
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Token abc123" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		assert.Equal(t, "/api/dcim/devices/", r.URL.Path)
+		assert.Equal(t, "true", r.URL.Query().Get("has_oob_ip"))
+		assert.Equal(t, "production", r.URL.Query().Get("status"))
+		// Serve the fixture in two pages.
+		body := map[string]interface{}{"results": page.Results[:2], "next": srv.URL + r.URL.Path + "?" + r.URL.RawQuery + "&offset=2"}
+		if r.URL.Query().Get("offset") == "2" {
+			body = map[string]interface{}{"results": page.Results[2:], "next": nil}
+		}
+		json.NewEncoder(w).Encode(body)
+	}))
+	defer srv.Close()
+
+	servers := sourceServers(t, SourceSpec{Kind: "netbox", URL: srv.URL, Token: "abc123", Filter: map[string]string{"status": "production"}})
+	assert.Equal(t, []ServerConfig{
+		{Type: "idrac", Hostname: "10.1.0.11", Labels: map[string]string{"name": "db01.ams", "site": "ams", "rack": "r12", "role": "database", "model": "PowerEdge R740", "manufacturer": "dell"}, Groups: []string{"canary"}},
+		{Type: "xclarity", Hostname: "10.2.0.21", Labels: map[string]string{"name": "storage01.fra", "site": "fra", "role": "storage", "tenant": "storage-team", "model": "ThinkSystem SR650", "manufacturer": "lenovo"}},
+	}, servers)
+
+	src, err := NewSource(SourceSpec{Kind: "netbox", URL: srv.URL, Token: "wrong"})
+	require.NoError(t, err)
+	_, err = src.Servers(context.Background())
+	assert.ErrorContains(t, err, "403")
+}
+
+func TestDHCPSource(t *testing.T) {
+	want := []ServerConfig{
+		{Type: "idrac", Hostname: "10.3.0.10", Labels: map[string]string{"mac": "d0:94:66:aa:bb:01", "name": "idrac-CNIVC01"}},
+		{Type: "xclarity", Hostname: "10.3.0.11", Labels: map[string]string{"mac": "08:94:ef:aa:bb:02", "name": "XCC-7X06-J30A1B2C"}},
+	}
+	for _, file := range []string{"dhcpd.leases", "dnsmasq.leases"} {
+		t.Run(file, func(t *testing.T) {
+			servers := sourceServers(t, SourceSpec{Kind: "dhcp", Path: "testdata/" + file})
+			require.Len(t, servers, 3)
+			assert.Equal(t, want, servers[:2])
+			assert.Equal(t, "10.3.0.12", servers[2].Hostname)
+		})
+	}
+}
+
+func TestLoadConfigSources(t *testing.T) {
+	testdata, err := filepath.Abs("testdata")
+	require.NoError(t, err)
+	dir := writeFiles(t, map[string]string{
+		"config.yaml": `
+credentials:
+  dell: {username: root, password: calvin}
+templates:
+  discovered:
+    credentials: dell
+    labels: {source: dhcp}
+servers:
+  - {type: idrac, hostname: static.ams, username: admin, password: admin}
+sources:
+  - kind: dhcp
+    path: ` + filepath.Join(testdata, "dhcpd.leases") + `
+    match: ["idrac-*", "XCC-*"]
+    template: discovered
+  - kind: csv
+    path: servers.csv
+`,
+		"servers.csv": "hostname,type\nr14-bmc01.fra,xclarity\n",
+	})
+
+	cfg, err := LoadConfig(filepath.Join(dir, "config.yaml"))
+	require.NoError(t, err)
+	var hostnames []string
+	for _, s := range cfg.Servers {
+		hostnames = append(hostnames, s.Hostname)
+	}
+	assert.Equal(t, []string{"static.ams", "10.3.0.10", "10.3.0.11", "r14-bmc01.fra"}, hostnames)
+	assert.Equal(t, "root", cfg.Servers[1].Username)
+	assert.Equal(t, "dhcp", cfg.Servers[2].Labels["source"])
+	assert.Equal(t, "xclarity", cfg.Servers[2].Type)
+
+	servers, err := LoadSources(context.Background(), SourceSpec{Kind: "csv", Path: filepath.Join(dir, "servers.csv")})
+	require.NoError(t, err)
+	assert.Equal(t, []ServerConfig{{Type: "xclarity", Hostname: "r14-bmc01.fra"}}, servers)
+
+	_, err = LoadSources(context.Background(), SourceSpec{Kind: "ldap"})
+	assert.EqualError(t, err, `unsupported inventory source: "ldap"`)
+}
+
+func TestParseSourceSpec(t *testing.T) {
+	spec, err := ParseSourceSpec("ansible:inventory/hosts.ini")
+	require.NoError(t, err)
+	assert.Equal(t, SourceSpec{Kind: "ansible", Path: "inventory/hosts.ini"}, spec)
+	spec, err = ParseSourceSpec("netbox:https://netbox.example.net")
+	require.NoError(t, err)
+	assert.Equal(t, SourceSpec{Kind: "netbox", URL: "https://netbox.example.net"}, spec)
+	_, err = ParseSourceSpec("hosts.ini")
+	assert.Error(t, err)
+}
@@ -0,0 +1,236 @@
+package credstore
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// AgentSocket returns the agent's socket path: REDFISHCLI_VAULT_AGENT, or
+// else ~/.redfishcli/vault-agent.sock.
+func AgentSocket() string {
+	if path := os.Getenv("REDFISHCLI_VAULT_AGENT"); path != "" {
+		return path
+	}
+	return filepath.Join(os.Getenv("HOME"), ".redfishcli", "vault-agent.sock")
+}
+
+type agentRequest struct {
+	Op  string `json:"op"`
+	ID  string `json:"id,omitempty"`
+	Key []byte `json:"key,omitempty"`
+}
+
+type agentResponse struct {
+	Key   []byte `json:"key,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Agent keeps the keys of opened stores in memory, so that commands run
+// one after the other ask for the passphrase once. It forgets each key
+// Timeout after it was handed over; zero keeps them until Forget.
+type Agent struct {
+	Timeout time.Duration
+	// UID is the user whose processes the agent answers, where the peer of
+	// a connection can be told; the connections of other users are closed
+	// unanswered.
+	UID int
+
+	mu   sync.Mutex
+	keys map[string]*agentKey
+}
+
+type agentKey struct {
+	key   []byte
+	timer *time.Timer
+}
+
+// Put keeps key for the store id.
+func (a *Agent) Put(id string, key []byte) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.keys == nil {
+		a.keys = make(map[string]*agentKey)
+	}
+	a.forget(id)
+	k := &agentKey{key: append([]byte(nil), key...)}
+	if a.Timeout > 0 {
+		k.timer = time.AfterFunc(a.Timeout, func() {
+			a.mu.Lock()
+			defer a.mu.Unlock()
+			if a.keys[id] == k {
+				a.forget(id)
+			}
+		})
+	}
+	a.keys[id] = k
+}
+
+// Get returns the key kept for the store id.
+func (a *Agent) Get(id string) ([]byte, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	k, ok := a.keys[id]
+	if !ok {
+		return nil, false
+	}
+	return append([]byte(nil), k.key...), true
+}
+
+// Forget drops every key.
+func (a *Agent) Forget() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for id := range a.keys {
+		a.forget(id)
+	}
+}
+
+func (a *Agent) forget(id string) {
+	k, ok := a.keys[id]
+	if !ok {
+		return
+	}
+	if k.timer != nil {
+		k.timer.Stop()
+	}
+	for i := range k.key {
+		k.key[i] = 0
+	}
+	delete(a.keys, id)
+}
+
+// Serve answers the requests of the connections l accepts until l is
+// closed.
+func (a *Agent) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go a.handle(conn)
+	}
+}
+
+func (a *Agent) handle(conn net.Conn) {
+	defer conn.Close()
+	if !trustedPeer(conn, a.UID) {
+		return
+	}
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	var req agentRequest
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&req); err != nil {
+		return
+	}
+	var resp agentResponse
+	switch req.Op {
+	case "get":
+		if key, ok := a.Get(req.ID); ok {
+			resp.Key = key
+		} else {
+			resp.Error = "unknown store"
+		}
+	case "put":
+		a.Put(req.ID, req.Key)
+	case "forget":
+		a.Forget()
+	default:
+		resp.Error = fmt.Sprintf("unknown operation %q", req.Op)
+	}
+	json.NewEncoder(conn).Encode(resp)
+}
+
+// RunAgent runs an agent on the socket until ctx is done. The socket is
+// only accessible to the current user, and the agent only answers processes
+// of that user.
+func RunAgent(ctx context.Context, socket string, timeout time.Duration) error {
+	if _, err := os.Stat(socket); err == nil {
+		if conn, err := net.DialTimeout("unix", socket, time.Second); err == nil {
+			conn.Close()
+			return fmt.Errorf("an agent is already running on %s", socket)
+		}
+		// Left over by an agent that did not exit cleanly.
+		os.Remove(socket)
+	}
+	if err := os.MkdirAll(filepath.Dir(socket), 0700); err != nil {
+		return err
+	}
+	l, err := listen(socket)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(socket)
+	if err := os.Chmod(socket, 0600); err != nil {
+		l.Close()
+		return err
+	}
+
+	agent := &Agent{Timeout: timeout, UID: os.Getuid()}
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+	err = agent.Serve(l)
+	agent.Forget()
+	return err
+}
+
+// callAgent sends req to the agent, if one runs.
+func callAgent(req agentRequest) (agentResponse, error) {
+	conn, err := net.DialTimeout("unix", AgentSocket(), time.Second)
+	if err != nil {
+		return agentResponse{}, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return agentResponse{}, err
+	}
+	var resp agentResponse
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return agentResponse{}, err
+	}
+	if resp.Error != "" {
+		return resp, errors.New(resp.Error)
+	}
+	return resp, nil
+}
+
+func agentGet(id string) ([]byte, bool) {
+	resp, err := callAgent(agentRequest{Op: "get", ID: id})
+	return resp.Key, err == nil && len(resp.Key) > 0
+}
+
+// agentPut hands the key to the agent, if one runs.
+func agentPut(id string, key []byte) {
+	callAgent(agentRequest{Op: "put", ID: id, Key: key})
+}
+
+// Lock makes the running agent forget every key. It fails when no agent
+// runs.
+func Lock() error {
+	if _, err := callAgent(agentRequest{Op: "forget"}); err != nil {
+		return fmt.Errorf("no agent on %s: %w", AgentSocket(), err)
+	}
+	return nil
+}
+
+// AgentRunning reports whether an agent answers on AgentSocket.
+func AgentRunning() bool {
+	conn, err := net.DialTimeout("unix", AgentSocket(), time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
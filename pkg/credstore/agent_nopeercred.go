@@ -0,0 +1,11 @@
+//go:build netbsd || openbsd || dragonfly
+
+package credstore
+
+import "errors"
+
+// peerUID is not supported on this platform; the agent relies on the
+// permissions of its socket.
+func peerUID(fd int) (int, error) {
+	return 0, errors.ErrUnsupported
+}
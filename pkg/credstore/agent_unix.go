@@ -0,0 +1,43 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package credstore
+
+import (
+	"errors"
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// listen listens on the socket with a umask that keeps other users out of
+// it from the moment it is created.
+func listen(socket string) (net.Listener, error) {
+	old := unix.Umask(0077)
+	defer unix.Umask(old)
+	return net.Listen("unix", socket)
+}
+
+// trustedPeer reports whether the process at the other end of conn runs as
+// uid. Where the peer's credentials cannot be read, the permissions of
+// the socket are relied on.
+func trustedPeer(conn net.Conn, uid int) bool {
+	uc, ok := conn.(*net.UnixConn)
+	if !ok {
+		return false
+	}
+	raw, err := uc.SyscallConn()
+	if err != nil {
+		return false
+	}
+	var (
+		peer    int
+		peerErr error
+	)
+	if err := raw.Control(func(fd uintptr) { peer, peerErr = peerUID(int(fd)) }); err != nil {
+		return false
+	}
+	if errors.Is(peerErr, errors.ErrUnsupported) {
+		return true
+	}
+	return peerErr == nil && peer == uid
+}
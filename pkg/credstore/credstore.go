@@ -0,0 +1,275 @@
+// Package credstore keeps BMC credentials in a local file encrypted with a
+// key derived from a passphrase, for machines without a secrets manager.
+package credstore
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// ErrWrongPassphrase is returned when the passphrase does not open the store.
+var ErrWrongPassphrase = errors.New("wrong passphrase")
+
+// KeyKind is what an entry's key names.
+type KeyKind string
+
+const (
+	// KeyHost is a hostname, which may use the wildcards of path.Match.
+	KeyHost KeyKind = "host"
+	// KeyGroup is a group of servers.
+	KeyGroup KeyKind = "group"
+	// KeySelector is a label selector, e.g. "site=ams,role=compute".
+	KeySelector KeyKind = "selector"
+)
+
+// Key says which servers an entry's credentials are for.
+type Key struct {
+	Kind  KeyKind `json:"kind"`
+	Value string  `json:"value"`
+}
+
+// ParseKey parses "host:PATTERN", "group:NAME" or "selector:SELECTOR"; a
+// key without a kind is a host.
+func ParseKey(s string) (Key, error) {
+	kind, value, ok := strings.Cut(s, ":")
+	switch KeyKind(kind) {
+	case KeyHost, KeyGroup, KeySelector:
+		if ok && value != "" {
+			return Key{Kind: KeyKind(kind), Value: value}, nil
+		}
+		return Key{}, fmt.Errorf("invalid key %q: no %s", s, kind)
+	}
+	if s == "" {
+		return Key{}, errors.New("empty key")
+	}
+	// Hostnames may contain colons, e.g. a port.
+	return Key{Kind: KeyHost, Value: s}, nil
+}
+
+func (k Key) String() string {
+	return string(k.Kind) + ":" + k.Value
+}
+
+// Entry is the credentials stored for a key.
+type Entry struct {
+	Key      Key    `json:"key"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// kdf are the argon2id parameters a store's key was derived with.
+type kdf struct {
+	Name    string `json:"name"`
+	Salt    []byte `json:"salt"`
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+}
+
+// file is a store as written on disk. Only the KDF parameters are in the
+// clear; the entries, keys included, are sealed with XChaCha20-Poly1305.
+type file struct {
+	Version    int    `json:"version"`
+	KDF        kdf    `json:"kdf"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+const version = 1
+
+// Store is an open credential store.
+type Store struct {
+	path string
+	kdf  kdf
+	key  []byte
+	// Entries are the stored credentials, in the order they were set.
+	Entries []Entry
+}
+
+// DefaultPath returns the store path: REDFISHCLI_VAULT_FILE, or else
+// ~/.redfishcli/vault.json.
+func DefaultPath() string {
+	if path := os.Getenv("REDFISHCLI_VAULT_FILE"); path != "" {
+		return path
+	}
+	return filepath.Join(os.Getenv("HOME"), ".redfishcli", "vault.json")
+}
+
+// Exists reports whether there is a store at path.
+func Exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// Init creates an empty store at path, encrypted with a key derived from
+// passphrase. It fails if a store exists there already.
+func Init(path, passphrase string) (*Store, error) {
+	if Exists(path) {
+		return nil, fmt.Errorf("%s already exists", path)
+	}
+	if passphrase == "" {
+		return nil, errors.New("empty passphrase")
+	}
+	params := kdf{Name: "argon2id", Salt: make([]byte, 16), Time: 3, Memory: 64 * 1024, Threads: 4}
+	if _, err := rand.Read(params.Salt); err != nil {
+		return nil, err
+	}
+	s := &Store{path: path, kdf: params, key: params.derive(passphrase)}
+	if err := s.Save(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Open opens the store at path. It takes the key from the agent when one
+// runs and has it, and otherwise derives it from the passphrase, which it
+// then hands to the agent.
+func Open(path string, passphrase func() (string, error)) (*Store, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if f.Version != version || f.KDF.Name != "argon2id" {
+		return nil, fmt.Errorf("%s: unsupported store version %d (%s)", path, f.Version, f.KDF.Name)
+	}
+	s := &Store{path: path, kdf: f.KDF}
+
+	id := s.ID()
+	if key, ok := agentGet(id); ok {
+		if err := s.open(&f, key); err == nil {
+			return s, nil
+		}
+	}
+	p, err := passphrase()
+	if err != nil {
+		return nil, err
+	}
+	key := f.KDF.derive(p)
+	if err := s.open(&f, key); err != nil {
+		return nil, err
+	}
+	agentPut(id, key)
+	return s, nil
+}
+
+func (s *Store) open(f *file, key []byte) error {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return err
+	}
+	plaintext, err := aead.Open(nil, f.Nonce, f.Ciphertext, s.additionalData())
+	if err != nil {
+		return ErrWrongPassphrase
+	}
+	var entries []Entry
+	if err := json.Unmarshal(plaintext, &entries); err != nil {
+		return err
+	}
+	s.key, s.Entries = key, entries
+	return nil
+}
+
+// ID identifies the store, and its key, to the agent.
+func (s *Store) ID() string {
+	return fmt.Sprintf("%x", s.kdf.Salt)
+}
+
+// Path returns where the store is kept.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Save encrypts the entries under a fresh nonce and writes the store.
+func (s *Store) Save() error {
+	plaintext, err := json.Marshal(s.Entries)
+	if err != nil {
+		return err
+	}
+	aead, err := chacha20poly1305.NewX(s.key)
+	if err != nil {
+		return err
+	}
+	f := file{Version: version, KDF: s.kdf, Nonce: make([]byte, aead.NonceSize())}
+	if _, err := rand.Read(f.Nonce); err != nil {
+		return err
+	}
+	f.Ciphertext = aead.Seal(nil, f.Nonce, plaintext, s.additionalData())
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".vault-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// additionalData binds the KDF parameters to the ciphertext, so they
+// cannot be changed without the store failing to open.
+func (s *Store) additionalData() []byte {
+	return []byte(fmt.Sprintf("redfishcli-vault/%d/%s/%x/%d/%d/%d", version, s.kdf.Name, s.kdf.Salt, s.kdf.Time, s.kdf.Memory, s.kdf.Threads))
+}
+
+// Get returns the entry for key.
+func (s *Store) Get(key Key) (Entry, bool) {
+	for _, e := range s.Entries {
+		if e.Key == key {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Set adds the entry, or replaces the one with the same key.
+func (s *Store) Set(entry Entry) {
+	for i, e := range s.Entries {
+		if e.Key == entry.Key {
+			s.Entries[i] = entry
+			return
+		}
+	}
+	s.Entries = append(s.Entries, entry)
+}
+
+// Remove removes the entry for key, and reports whether there was one.
+func (s *Store) Remove(key Key) bool {
+	for i, e := range s.Entries {
+		if e.Key == key {
+			s.Entries = append(s.Entries[:i], s.Entries[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (k kdf) derive(passphrase string) []byte {
+	return argon2.IDKey([]byte(passphrase), k.Salt, k.Time, k.Memory, k.Threads, chacha20poly1305.KeySize)
+}
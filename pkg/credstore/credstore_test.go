@@ -0,0 +1,159 @@
+package credstore
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func passphrase(p string, calls *int) func() (string, error) {
+	return func() (string, error) {
+		*calls++
+		return p, nil
+	}
+}
+
+func TestStore(t *testing.T) {
+	t.Setenv("REDFISHCLI_VAULT_AGENT", filepath.Join(t.TempDir(), "no-agent.sock"))
+	path := filepath.Join(t.TempDir(), "vault.json")
+
+	s, err := Init(path, "correct horse")
+	require.NoError(t, err)
+	s.Set(Entry{Key: Key{Kind: KeyHost, Value: "r12-bmc*.ams"}, Username: "root", Password: "calvin"})
+	s.Set(Entry{Key: Key{Kind: KeyGroup, Value: "db"}, Username: "admin", Password: "first"})
+	s.Set(Entry{Key: Key{Kind: KeyGroup, Value: "db"}, Username: "admin", Password: "second"})
+	require.NoError(t, s.Save())
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	for _, plain := range []string{"calvin", "r12-bmc", "admin"} {
+		assert.NotContains(t, string(data), plain, "the entries are encrypted, keys included")
+	}
+
+	calls := 0
+	s, err = Open(path, passphrase("correct horse", &calls))
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, []Entry{
+		{Key: Key{Kind: KeyHost, Value: "r12-bmc*.ams"}, Username: "root", Password: "calvin"},
+		{Key: Key{Kind: KeyGroup, Value: "db"}, Username: "admin", Password: "second"},
+	}, s.Entries)
+	e, ok := s.Get(Key{Kind: KeyGroup, Value: "db"})
+	assert.True(t, ok)
+	assert.Equal(t, "second", e.Password)
+	assert.True(t, s.Remove(Key{Kind: KeyGroup, Value: "db"}))
+	assert.False(t, s.Remove(Key{Kind: KeyGroup, Value: "db"}))
+
+	_, err = Open(path, passphrase("wrong", &calls))
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+
+	// Weakening the KDF parameters breaks the authentication.
+	var f file
+	require.NoError(t, json.Unmarshal(data, &f))
+	f.KDF.Time = 1
+	tampered, _ := json.Marshal(f)
+	require.NoError(t, os.WriteFile(path, tampered, 0600))
+	_, err = Open(path, passphrase("correct horse", &calls))
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+
+	_, err = Init(path, "again")
+	assert.Error(t, err, "an existing store is not overwritten")
+}
+
+func TestParseKey(t *testing.T) {
+	for s, want := range map[string]Key{
+		"r12-bmc01.ams":          {Kind: KeyHost, Value: "r12-bmc01.ams"},
+		"10.0.0.1:8443":          {Kind: KeyHost, Value: "10.0.0.1:8443"},
+		"host:r12-*":             {Kind: KeyHost, Value: "r12-*"},
+		"group:db":               {Kind: KeyGroup, Value: "db"},
+		"selector:site=ams,role": {Kind: KeySelector, Value: "site=ams,role"},
+	} {
+		key, err := ParseKey(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, want, key, s)
+		if s != "r12-bmc01.ams" && s != "10.0.0.1:8443" {
+			assert.Equal(t, s, key.String())
+		}
+	}
+	for _, bad := range []string{"", "group:", "selector:"} {
+		_, err := ParseKey(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestAgent(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+	t.Setenv("REDFISHCLI_VAULT_AGENT", socket)
+	path := filepath.Join(t.TempDir(), "vault.json")
+	_, err := Init(path, "correct horse")
+	require.NoError(t, err)
+	assert.False(t, AgentRunning())
+	assert.Error(t, Lock())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- RunAgent(ctx, socket, time.Minute) }()
+	require.Eventually(t, AgentRunning, 5*time.Second, 10*time.Millisecond)
+	info, err := os.Stat(socket)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	assert.Error(t, RunAgent(context.Background(), socket, 0), "one agent per socket")
+
+	calls := 0
+	for i := 0; i < 3; i++ {
+		_, err := Open(path, passphrase("correct horse", &calls))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, calls, "the agent hands out the key after the first open")
+
+	require.NoError(t, Lock())
+	_, err = Open(path, passphrase("correct horse", &calls))
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls, "a locked agent has forgotten the key")
+
+	cancel()
+	require.NoError(t, <-done)
+	assert.NoFileExists(t, socket)
+}
+
+func TestAgentTimeout(t *testing.T) {
+	a := &Agent{Timeout: 50 * time.Millisecond}
+	a.Put("store", []byte("key"))
+	key, ok := a.Get("store")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("key"), key)
+	assert.Eventually(t, func() bool { _, ok := a.Get("store"); return !ok }, time.Second, 10*time.Millisecond)
+}
+
+func TestAgentOtherUser(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" && runtime.GOOS != "freebsd" {
+		t.Skip("the agent cannot read the peer's credentials on " + runtime.GOOS)
+	}
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+	t.Setenv("REDFISHCLI_VAULT_AGENT", socket)
+	l, err := listen(socket)
+	require.NoError(t, err)
+	a := &Agent{UID: os.Getuid() + 1}
+	a.Put("store", []byte("key"))
+	done := make(chan error)
+	go func() { done <- a.Serve(l) }()
+
+	_, ok := agentGet("store")
+	assert.False(t, ok, "the agent does not answer other users")
+	assert.Error(t, Lock())
+	_, ok = a.Get("store")
+	assert.True(t, ok)
+
+	l.Close()
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,690 @@
+// Package emulator serves a stateful Redfish service tree loaded from mockup
+// directories. It understands enough of the Redfish protocol (sessions,
+// PATCH, actions, log growth) to exercise every redfishcli backend without
+// a real BMC.
+package emulator
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const serviceRoot = "/redfish/v1"
+
+// Options tunes the emulated service.
+type Options struct {
+	// Username and Password are the only accepted credentials.
+	Username string
+	Password string
+	// Now returns the timestamp used for new log entries. Defaults to time.Now.
+	Now func() time.Time
+	// PageSize, when non-zero, splits collections into pages of this many
+	// members linked by Members@odata.nextLink, as large BMC logs are.
+	PageSize int
+}
+
+type resource = map[string]interface{}
+
+// Emulator is an http.Handler serving a Redfish tree.
+type Emulator struct {
+	profile Profile
+	opts    Options
+
+	mu        sync.Mutex
+	resources map[string]resource
+	sessions  map[string]string // token -> session URI
+	nextID    int
+}
+
+// New loads the profile's mockup and returns an emulator serving it.
+func New(profile Profile, opts Options) (*Emulator, error) {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	resources, err := LoadMockup(profile.Mockup)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := resources[serviceRoot]; !ok {
+		return nil, fmt.Errorf("mockup %s has no service root (%s/index.json)", profile.Name, serviceRoot)
+	}
+
+	e := &Emulator{
+		profile:   profile,
+		opts:      opts,
+		resources: resources,
+		sessions:  make(map[string]string),
+		nextID:    1,
+	}
+	if e.profile.SELPath == "" {
+		e.profile.SELPath = e.findLogEntries()
+	}
+	return e, nil
+}
+
+// LoadMockup reads every index.json below the mockup root and returns the
+// resources keyed by URI. Members carried inline by a collection are also
+// returned under their own URI.
+func LoadMockup(fsys fs.FS) (map[string]map[string]interface{}, error) {
+	prefix := serviceRoot
+	if _, err := fs.Stat(fsys, "redfish/v1/index.json"); err == nil {
+		prefix = ""
+	}
+
+	resources := make(map[string]resource)
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || d.Name() != "index.json" {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		var res resource
+		if err := json.Unmarshal(data, &res); err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		uri := normalize(prefix + "/" + path.Dir(p))
+		resources[uri] = res
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Collections such as log entries often carry their members inline;
+	// make each of them addressable on its own as well.
+	for _, res := range resources {
+		members, _ := res["Members"].([]interface{})
+		for _, m := range members {
+			member, ok := m.(resource)
+			if !ok || len(member) <= 1 {
+				continue
+			}
+			if id, ok := member["@odata.id"].(string); ok {
+				if _, exists := resources[normalize(id)]; !exists {
+					resources[normalize(id)] = member
+				}
+			}
+		}
+	}
+	return resources, nil
+}
+
+func normalize(uri string) string {
+	uri = path.Clean("/" + uri)
+	if i := strings.IndexByte(uri, '#'); i >= 0 {
+		uri = uri[:i]
+	}
+	return uri
+}
+
+func (e *Emulator) findLogEntries() string {
+	var candidates []string
+	for uri, res := range e.resources {
+		if t, _ := res["@odata.type"].(string); strings.Contains(t, "LogEntryCollection") {
+			candidates = append(candidates, uri)
+		}
+	}
+	sort.Strings(candidates)
+	if len(candidates) == 0 {
+		return ""
+	}
+	return candidates[0]
+}
+
+// Profile returns the profile the emulator was built from.
+func (e *Emulator) Profile() Profile {
+	return e.profile
+}
+
+// Resource returns a copy of the resource at uri.
+func (e *Emulator) Resource(uri string) (map[string]interface{}, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	res, ok := e.resources[normalize(uri)]
+	if !ok {
+		return nil, false
+	}
+	return deepCopy(res).(resource), true
+}
+
+// SetResource replaces or creates the resource at uri. It lets tests put the
+// service into states that are awkward to reach through the API.
+func (e *Emulator) SetResource(uri string, res map[string]interface{}) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	res = deepCopy(res).(resource)
+	res["@odata.id"] = normalize(uri)
+	e.resources[normalize(uri)] = res
+}
+
+// AddLogEntry appends an entry to the profile's system event log.
+func (e *Emulator) AddLogEntry(severity, message string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.addLogEntryLocked(severity, message)
+}
+
+func (e *Emulator) addLogEntryLocked(severity, message string) {
+	coll, ok := e.resources[e.profile.SELPath]
+	if !ok {
+		return
+	}
+	members, _ := coll["Members"].([]interface{})
+	id := strconv.Itoa(len(members) + 1)
+	for {
+		if _, taken := e.resources[e.profile.SELPath+"/"+id]; !taken {
+			break
+		}
+		n, _ := strconv.Atoi(id)
+		id = strconv.Itoa(n + 1)
+	}
+	uri := e.profile.SELPath + "/" + id
+	entry := resource{
+		"@odata.id":   uri,
+		"@odata.type": "#LogEntry.v1_6_1.LogEntry",
+		"Id":          id,
+		"Name":        "Log Entry " + id,
+		"Created":     e.opts.Now().Format(time.RFC3339),
+		"EntryType":   "SEL",
+		"Severity":    severity,
+		"Message":     message,
+	}
+	e.resources[uri] = entry
+	coll["Members"] = append(members, deepCopy(entry))
+	coll["Members@odata.count"] = len(members) + 1
+}
+
+// ServeHTTP implements http.Handler.
+func (e *Emulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("OData-Version", "4.0")
+	uri := normalize(r.URL.Path)
+
+	if uri == "/redfish" {
+		writeJSON(w, http.StatusOK, resource{"v1": "/redfish/v1/"})
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.public(r.Method, uri) && !e.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="Redfish"`)
+		writeError(w, http.StatusUnauthorized, "NoValidSession", "There is no valid session established with the implementation.")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		e.get(w, r, uri)
+	case http.MethodPatch:
+		e.patch(w, r, uri)
+	case http.MethodPost:
+		e.post(w, r, uri)
+	case http.MethodDelete:
+		e.delete(w, uri)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "OperationNotAllowed", fmt.Sprintf("The %s method is not allowed on %s.", r.Method, uri))
+	}
+}
+
+func (e *Emulator) public(method, uri string) bool {
+	if method == http.MethodGet && (uri == serviceRoot || uri == serviceRoot+"/odata" || uri == serviceRoot+"/$metadata") {
+		return true
+	}
+	return method == http.MethodPost && uri == e.sessionsURI()
+}
+
+func (e *Emulator) authorized(r *http.Request) bool {
+	if token := r.Header.Get("X-Auth-Token"); token != "" {
+		uri, ok := e.sessions[token]
+		if !ok {
+			return false
+		}
+		_, ok = e.resources[uri]
+		return ok
+	}
+	user, pass, ok := r.BasicAuth()
+	return ok && user == e.opts.Username && pass == e.opts.Password
+}
+
+func (e *Emulator) sessionsURI() string {
+	root := e.resources[serviceRoot]
+	if links, ok := root["Links"].(resource); ok {
+		if s, ok := links["Sessions"].(resource); ok {
+			if id, ok := s["@odata.id"].(string); ok {
+				return normalize(id)
+			}
+		}
+	}
+	return serviceRoot + "/SessionService/Sessions"
+}
+
+func (e *Emulator) get(w http.ResponseWriter, r *http.Request, uri string) {
+	res, ok := e.resources[uri]
+	if !ok {
+		writeError(w, http.StatusNotFound, "ResourceMissingAtURI", fmt.Sprintf("The resource at the URI %s was not found.", uri))
+		return
+	}
+	if e.opts.PageSize > 0 && isCollection(res) {
+		skip, err := strconv.Atoi(r.URL.Query().Get("$skip"))
+		if err != nil && r.URL.Query().Has("$skip") {
+			writeError(w, http.StatusBadRequest, "QueryParameterValueTypeError", "The value for the parameter $skip is of a different type than the parameter can accept.")
+			return
+		}
+		res = e.page(res, uri, skip)
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// page returns the slice of a collection starting at skip, with a nextLink
+// to the following page when there is one.
+func (e *Emulator) page(res resource, uri string, skip int) resource {
+	members, _ := res["Members"].([]interface{})
+	if skip < 0 {
+		skip = 0
+	}
+	if skip > len(members) {
+		skip = len(members)
+	}
+	end := skip + e.opts.PageSize
+	if end > len(members) {
+		end = len(members)
+	}
+
+	out := make(resource, len(res)+1)
+	for k, v := range res {
+		out[k] = v
+	}
+	out["Members"] = members[skip:end]
+	if end < len(members) {
+		out["Members@odata.nextLink"] = fmt.Sprintf("%s?$skip=%d", uri, end)
+	}
+	return out
+}
+
+func isCollection(res resource) bool {
+	_, ok := res["Members"]
+	return ok
+}
+
+func (e *Emulator) patch(w http.ResponseWriter, r *http.Request, uri string) {
+	res, ok := e.resources[uri]
+	if !ok {
+		writeError(w, http.StatusNotFound, "ResourceMissingAtURI", fmt.Sprintf("The resource at the URI %s was not found.", uri))
+		return
+	}
+	if isCollection(res) {
+		writeError(w, http.StatusMethodNotAllowed, "OperationNotAllowed", "Collections cannot be patched.")
+		return
+	}
+
+	var body resource
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "MalformedJSON", "The request body submitted was malformed JSON and could not be parsed by the receiving service.")
+		return
+	}
+	if status, id, msg := validatePatch(res, body, ""); status != 0 {
+		writeError(w, status, id, msg)
+		return
+	}
+
+	merge(res, body)
+	if boot, ok := body["Boot"].(resource); ok {
+		if target, ok := boot["BootSourceOverrideTarget"].(string); ok {
+			if _, explicit := boot["BootSourceOverrideEnabled"]; !explicit {
+				current := res["Boot"].(resource)
+				if target == "None" {
+					current["BootSourceOverrideEnabled"] = "Disabled"
+				} else if current["BootSourceOverrideEnabled"] == "Disabled" {
+					current["BootSourceOverrideEnabled"] = "Once"
+				}
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// validatePatch checks that every patched property exists and respects its
+// @Redfish.AllowableValues annotation.
+func validatePatch(res, patch resource, prefix string) (int, string, string) {
+	for key, value := range patch {
+		if strings.HasPrefix(key, "@") || strings.Contains(key, "@odata.") {
+			continue
+		}
+		current, exists := res[key]
+		if !exists {
+			return http.StatusBadRequest, "PropertyUnknown", fmt.Sprintf("The property %s%s is not in the list of valid properties for the resource.", prefix, key)
+		}
+		if nested, ok := value.(resource); ok {
+			currentNested, ok := current.(resource)
+			if !ok {
+				return http.StatusBadRequest, "PropertyValueTypeError", fmt.Sprintf("The value for the property %s%s is of a different type than the property can accept.", prefix, key)
+			}
+			if status, id, msg := validatePatch(currentNested, nested, prefix+key+"/"); status != 0 {
+				return status, id, msg
+			}
+			continue
+		}
+		if allowed, ok := res[key+"@Redfish.AllowableValues"].([]interface{}); ok {
+			if !containsValue(allowed, value) {
+				return http.StatusBadRequest, "PropertyValueNotInList", fmt.Sprintf("The value %v for the property %s%s is not in the list of acceptable values.", value, prefix, key)
+			}
+		}
+	}
+	return 0, "", ""
+}
+
+func containsValue(list []interface{}, v interface{}) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+func merge(dst, src resource) {
+	for k, v := range src {
+		if nested, ok := v.(resource); ok {
+			if existing, ok := dst[k].(resource); ok {
+				merge(existing, nested)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+}
+
+func (e *Emulator) post(w http.ResponseWriter, r *http.Request, uri string) {
+	if uri == e.sessionsURI() {
+		e.createSession(w, r)
+		return
+	}
+	if i := strings.Index(uri, "/Actions/"); i >= 0 {
+		e.action(w, r, uri[:i], uri[i+len("/Actions/"):])
+		return
+	}
+	if _, ok := e.resources[uri]; !ok {
+		writeError(w, http.StatusNotFound, "ResourceMissingAtURI", fmt.Sprintf("The resource at the URI %s was not found.", uri))
+		return
+	}
+	writeError(w, http.StatusMethodNotAllowed, "OperationNotAllowed", fmt.Sprintf("The POST method is not allowed on %s.", uri))
+}
+
+func (e *Emulator) createSession(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		UserName string
+		Password string
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "MalformedJSON", "The request body submitted was malformed JSON and could not be parsed by the receiving service.")
+		return
+	}
+	if body.UserName != e.opts.Username || body.Password != e.opts.Password {
+		writeError(w, http.StatusUnauthorized, "InvalidCredentials", "The credentials provided are not valid.")
+		return
+	}
+
+	token := randomToken()
+	id := strconv.Itoa(e.nextID)
+	e.nextID++
+	sessions := e.sessionsURI()
+	uri := sessions + "/" + id
+	session := resource{
+		"@odata.id":   uri,
+		"@odata.type": "#Session.v1_3_0.Session",
+		"Id":          id,
+		"Name":        "User Session",
+		"UserName":    body.UserName,
+	}
+	e.resources[uri] = session
+	e.sessions[token] = uri
+	if coll, ok := e.resources[sessions]; ok {
+		members, _ := coll["Members"].([]interface{})
+		coll["Members"] = append(members, resource{"@odata.id": uri})
+		coll["Members@odata.count"] = len(members) + 1
+	}
+
+	w.Header().Set("X-Auth-Token", token)
+	w.Header().Set("Location", uri)
+	writeJSON(w, http.StatusCreated, session)
+}
+
+func randomToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ExpireSessions invalidates every session token, as a BMC does when its
+// session timeout elapses.
+func (e *Emulator) ExpireSessions() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, uri := range e.sessions {
+		e.removeMember(uri)
+	}
+	e.sessions = make(map[string]string)
+}
+
+func (e *Emulator) delete(w http.ResponseWriter, uri string) {
+	if path.Dir(uri) != e.sessionsURI() {
+		writeError(w, http.StatusMethodNotAllowed, "OperationNotAllowed", fmt.Sprintf("The DELETE method is not allowed on %s.", uri))
+		return
+	}
+	if _, ok := e.resources[uri]; !ok {
+		writeError(w, http.StatusNotFound, "ResourceMissingAtURI", fmt.Sprintf("The resource at the URI %s was not found.", uri))
+		return
+	}
+	for token, sessionURI := range e.sessions {
+		if sessionURI == uri {
+			delete(e.sessions, token)
+		}
+	}
+	e.removeMember(uri)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (e *Emulator) removeMember(uri string) {
+	delete(e.resources, uri)
+	coll, ok := e.resources[path.Dir(uri)]
+	if !ok {
+		return
+	}
+	members, _ := coll["Members"].([]interface{})
+	kept := make([]interface{}, 0, len(members))
+	for _, m := range members {
+		if ref, ok := m.(resource); ok && ref["@odata.id"] == uri {
+			continue
+		}
+		kept = append(kept, m)
+	}
+	coll["Members"] = kept
+	coll["Members@odata.count"] = len(kept)
+}
+
+func (e *Emulator) action(w http.ResponseWriter, r *http.Request, ownerURI, name string) {
+	owner, ok := e.resources[ownerURI]
+	if !ok {
+		writeError(w, http.StatusNotFound, "ResourceMissingAtURI", fmt.Sprintf("The resource at the URI %s was not found.", ownerURI))
+		return
+	}
+	actions, _ := owner["Actions"].(resource)
+	def, ok := actions["#"+name].(resource)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "ActionNotSupported", fmt.Sprintf("The action %s is not supported by the resource.", name))
+		return
+	}
+
+	params := resource{}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil && err != io.EOF {
+			writeError(w, http.StatusBadRequest, "MalformedJSON", "The request body submitted was malformed JSON and could not be parsed by the receiving service.")
+			return
+		}
+	}
+	for param, allowed := range e.allowableValues(def) {
+		value, given := params[param]
+		if !given {
+			writeError(w, http.StatusBadRequest, "ActionParameterMissing", fmt.Sprintf("The action %s requires the parameter %s to be present in the request body.", name, param))
+			return
+		}
+		if !containsValue(allowed, value) {
+			writeError(w, http.StatusBadRequest, "ActionParameterValueNotInList", fmt.Sprintf("The value %v for the parameter %s in the action %s is not in the list of acceptable values.", value, param, name))
+			return
+		}
+	}
+
+	switch name {
+	case "ComputerSystem.Reset":
+		e.resetSystem(owner, params["ResetType"])
+	case "LogService.ClearLog":
+		if entries, ok := e.resources[ownerURI+"/Entries"]; ok {
+			members, _ := entries["Members"].([]interface{})
+			for _, m := range members {
+				if ref, ok := m.(resource); ok {
+					if id, ok := ref["@odata.id"].(string); ok {
+						delete(e.resources, normalize(id))
+					}
+				}
+			}
+			entries["Members"] = []interface{}{}
+			entries["Members@odata.count"] = 0
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// allowableValues collects parameter constraints from an action definition,
+// either inline (Param@Redfish.AllowableValues) or via its ActionInfo.
+func (e *Emulator) allowableValues(def resource) map[string][]interface{} {
+	values := make(map[string][]interface{})
+	for key, v := range def {
+		if param, ok := strings.CutSuffix(key, "@Redfish.AllowableValues"); ok {
+			if list, ok := v.([]interface{}); ok {
+				values[param] = list
+			}
+		}
+	}
+	if infoURI, ok := def["@Redfish.ActionInfo"].(string); ok {
+		if info, ok := e.resources[normalize(infoURI)]; ok {
+			params, _ := info["Parameters"].([]interface{})
+			for _, p := range params {
+				param, ok := p.(resource)
+				if !ok {
+					continue
+				}
+				name, _ := param["Name"].(string)
+				if list, ok := param["AllowableValues"].([]interface{}); ok && name != "" {
+					values[name] = list
+				}
+			}
+		}
+	}
+	return values
+}
+
+func (e *Emulator) resetSystem(system resource, resetType interface{}) {
+	before, _ := system["PowerState"].(string)
+	var after, message string
+	switch resetType {
+	case "On", "ForceOn":
+		after, message = "On", "The system has been powered on."
+	case "ForceOff", "GracefulShutdown":
+		after, message = "Off", "The system has been powered off."
+	case "PushPowerButton":
+		if before == "On" {
+			after, message = "Off", "The system has been powered off."
+		} else {
+			after, message = "On", "The system has been powered on."
+		}
+	case "Nmi":
+		e.addLogEntryLocked("Critical", "An NMI was asserted on the system.")
+		return
+	default:
+		after, message = "On", "The system has been reset."
+	}
+
+	system["PowerState"] = after
+	if links, ok := system["Links"].(resource); ok {
+		chassis, _ := links["Chassis"].([]interface{})
+		for _, c := range chassis {
+			if ref, ok := c.(resource); ok {
+				if id, ok := ref["@odata.id"].(string); ok {
+					if res, ok := e.resources[normalize(id)]; ok {
+						if _, hasPower := res["PowerState"]; hasPower {
+							res["PowerState"] = after
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// A one-time boot override is consumed by the next boot.
+	if boot, ok := system["Boot"].(resource); ok && after == "On" && boot["BootSourceOverrideEnabled"] == "Once" {
+		boot["BootSourceOverrideEnabled"] = "Disabled"
+		boot["BootSourceOverrideTarget"] = "None"
+	}
+	e.addLogEntryLocked("OK", message)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError answers with a Redfish error payload built from a Base
+// registry message.
+func writeError(w http.ResponseWriter, status int, messageID, message string) {
+	writeJSON(w, status, resource{
+		"error": resource{
+			"code":    "Base.1.8.GeneralError",
+			"message": "A general error has occurred. See ExtendedInfo for more information.",
+			"@Message.ExtendedInfo": []interface{}{
+				resource{
+					"@odata.type": "#Message.v1_1_1.Message",
+					"MessageId":   "Base.1.8." + messageID,
+					"Message":     message,
+					"Severity":    "Warning",
+				},
+			},
+		},
+	})
+}
+
+func deepCopy(v interface{}) interface{} {
+	switch t := v.(type) {
+	case resource:
+		out := make(resource, len(t))
+		for k, child := range t {
+			out[k] = deepCopy(child)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, child := range t {
+			out[i] = deepCopy(child)
+		}
+		return out
+	default:
+		return v
+	}
+}
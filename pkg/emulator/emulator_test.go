@@ -0,0 +1,283 @@
+package emulator
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testBMC struct {
+	t   *testing.T
+	emu *Emulator
+	srv *httptest.Server
+}
+
+func newTestBMC(t *testing.T, profile Profile) *testBMC {
+	emu, err := New(profile, Options{Username: "root", Password: "calvin"})
+	require.NoError(t, err)
+	srv := httptest.NewServer(emu)
+	t.Cleanup(srv.Close)
+	return &testBMC{t: t, emu: emu, srv: srv}
+}
+
+func (b *testBMC) do(method, uri string, body interface{}, auth func(*http.Request)) (*http.Response, map[string]interface{}) {
+	var buf bytes.Buffer
+	if body != nil {
+		require.NoError(b.t, json.NewEncoder(&buf).Encode(body))
+	}
+	req, err := http.NewRequest(method, b.srv.URL+uri, &buf)
+	require.NoError(b.t, err)
+	if auth != nil {
+		auth(req)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(b.t, err)
+	defer resp.Body.Close()
+
+	var out map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&out)
+	return resp, out
+}
+
+func basic(req *http.Request) { req.SetBasicAuth("root", "calvin") }
+
+func TestAuthentication(t *testing.T) {
+	bmc := newTestBMC(t, IDRAC())
+
+	resp, root := bmc.do("GET", "/redfish/v1", nil, nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "Dell", root["Vendor"])
+
+	resp, _ = bmc.do("GET", "/redfish/v1/Systems/System.Embedded.1", nil, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, _ = bmc.do("GET", "/redfish/v1/Systems/System.Embedded.1", nil, func(r *http.Request) { r.SetBasicAuth("root", "wrong") })
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, system := bmc.do("GET", "/redfish/v1/Systems/System.Embedded.1/", nil, basic)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "On", system["PowerState"])
+
+	resp, _ = bmc.do("GET", "/redfish/v1/Nope", nil, basic)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestSessions(t *testing.T) {
+	bmc := newTestBMC(t, IDRAC())
+
+	resp, _ := bmc.do("POST", "/redfish/v1/SessionService/Sessions", map[string]string{"UserName": "root", "Password": "bad"}, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, _ = bmc.do("POST", "/redfish/v1/SessionService/Sessions", map[string]string{"UserName": "root", "Password": "calvin"}, nil)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	token := resp.Header.Get("X-Auth-Token")
+	location := resp.Header.Get("Location")
+	require.NotEmpty(t, token)
+	withToken := func(r *http.Request) { r.Header.Set("X-Auth-Token", token) }
+
+	resp, _ = bmc.do("GET", "/redfish/v1/Systems", nil, withToken)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	_, coll := bmc.do("GET", "/redfish/v1/SessionService/Sessions", nil, withToken)
+	assert.EqualValues(t, 1, coll["Members@odata.count"])
+
+	resp, _ = bmc.do("DELETE", location, nil, withToken)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp, _ = bmc.do("GET", "/redfish/v1/Systems", nil, withToken)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestExpireSessions(t *testing.T) {
+	bmc := newTestBMC(t, XCC())
+
+	resp, _ := bmc.do("POST", "/redfish/v1/SessionService/Sessions", map[string]string{"UserName": "root", "Password": "calvin"}, nil)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	token := resp.Header.Get("X-Auth-Token")
+
+	bmc.emu.ExpireSessions()
+
+	resp, _ = bmc.do("GET", "/redfish/v1/Systems", nil, func(r *http.Request) { r.Header.Set("X-Auth-Token", token) })
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestPowerReset(t *testing.T) {
+	bmc := newTestBMC(t, IDRAC())
+	system := "/redfish/v1/Systems/System.Embedded.1"
+	sel := "/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Entries"
+
+	_, before := bmc.do("GET", sel, nil, basic)
+
+	resp, _ := bmc.do("POST", system+"/Actions/ComputerSystem.Reset", map[string]string{"ResetType": "ForceOff"}, basic)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	_, state := bmc.do("GET", system, nil, basic)
+	assert.Equal(t, "Off", state["PowerState"])
+	_, chassis := bmc.do("GET", "/redfish/v1/Chassis/System.Embedded.1", nil, basic)
+	assert.Equal(t, "Off", chassis["PowerState"])
+
+	_, after := bmc.do("GET", sel, nil, basic)
+	assert.Equal(t, before["Members@odata.count"].(float64)+1, after["Members@odata.count"])
+
+	t.Run("Rejects values outside AllowableValues", func(t *testing.T) {
+		resp, body := bmc.do("POST", system+"/Actions/ComputerSystem.Reset", map[string]string{"ResetType": "Reboot"}, basic)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		info := body["error"].(map[string]interface{})["@Message.ExtendedInfo"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "Base.1.8.ActionParameterValueNotInList", info["MessageId"])
+	})
+
+	t.Run("Rejects missing parameters", func(t *testing.T) {
+		resp, _ := bmc.do("POST", system+"/Actions/ComputerSystem.Reset", map[string]string{}, basic)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Rejects unknown actions", func(t *testing.T) {
+		resp, _ := bmc.do("POST", system+"/Actions/ComputerSystem.Explode", map[string]string{}, basic)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestActionInfoValidation(t *testing.T) {
+	bmc := newTestBMC(t, XCC())
+	target := "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset"
+
+	resp, _ := bmc.do("POST", target, map[string]string{"ResetType": "PowerCycle"}, basic)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, _ = bmc.do("POST", target, map[string]string{"ResetType": "GracefulShutdown"}, basic)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func TestBootOverride(t *testing.T) {
+	bmc := newTestBMC(t, IDRAC())
+	system := "/redfish/v1/Systems/System.Embedded.1"
+
+	resp, _ := bmc.do("PATCH", system, map[string]interface{}{"Boot": map[string]string{"BootSourceOverrideTarget": "Pxe"}}, basic)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	_, state := bmc.do("GET", system, nil, basic)
+	boot := state["Boot"].(map[string]interface{})
+	assert.Equal(t, "Pxe", boot["BootSourceOverrideTarget"])
+	assert.Equal(t, "Once", boot["BootSourceOverrideEnabled"])
+
+	// The one-time override is consumed by the next boot.
+	bmc.do("POST", system+"/Actions/ComputerSystem.Reset", map[string]string{"ResetType": "ForceRestart"}, basic)
+	_, state = bmc.do("GET", system, nil, basic)
+	boot = state["Boot"].(map[string]interface{})
+	assert.Equal(t, "None", boot["BootSourceOverrideTarget"])
+	assert.Equal(t, "Disabled", boot["BootSourceOverrideEnabled"])
+
+	t.Run("Rejects invalid targets", func(t *testing.T) {
+		resp, _ := bmc.do("PATCH", system, map[string]interface{}{"Boot": map[string]string{"BootSourceOverrideTarget": "Network"}}, basic)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Rejects unknown properties", func(t *testing.T) {
+		resp, _ := bmc.do("PATCH", system, map[string]interface{}{"Colour": "Blue"}, basic)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Rejects POST to resources", func(t *testing.T) {
+		resp, _ := bmc.do("POST", system, map[string]interface{}{"Boot": map[string]string{"BootSourceOverrideTarget": "Pxe"}}, basic)
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	})
+}
+
+func TestLogGrowthAndClear(t *testing.T) {
+	bmc := newTestBMC(t, XCC())
+	entries := "/redfish/v1/Systems/1/LogServices/PlatformLog/Entries"
+
+	bmc.emu.AddLogEntry("Critical", "Fan 3 failed.")
+	_, coll := bmc.do("GET", entries, nil, basic)
+	members := coll["Members"].([]interface{})
+	last := members[len(members)-1].(map[string]interface{})
+	assert.Equal(t, "Fan 3 failed.", last["Message"])
+
+	resp, entry := bmc.do("GET", last["@odata.id"].(string), nil, basic)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "Critical", entry["Severity"])
+
+	resp, _ = bmc.do("POST", "/redfish/v1/Systems/1/LogServices/PlatformLog/Actions/LogService.ClearLog", map[string]string{}, basic)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	_, coll = bmc.do("GET", entries, nil, basic)
+	assert.EqualValues(t, 0, coll["Members@odata.count"])
+}
+
+func TestPaging(t *testing.T) {
+	emu, err := New(XCC(), Options{Username: "root", Password: "calvin", PageSize: 2})
+	require.NoError(t, err)
+	srv := httptest.NewServer(emu)
+	t.Cleanup(srv.Close)
+	bmc := &testBMC{t: t, emu: emu, srv: srv}
+
+	entries := "/redfish/v1/Systems/1/LogServices/PlatformLog/Entries"
+	for i := 0; i < 5; i++ {
+		emu.AddLogEntry("OK", "Entry")
+	}
+	full, _ := emu.Resource(entries)
+	total := len(full["Members"].([]interface{}))
+
+	var seen int
+	next := entries
+	for next != "" {
+		resp, page := bmc.do("GET", next, nil, basic)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.LessOrEqual(t, len(page["Members"].([]interface{})), 2)
+		assert.EqualValues(t, total, page["Members@odata.count"])
+		seen += len(page["Members"].([]interface{}))
+		next, _ = page["Members@odata.nextLink"].(string)
+	}
+	assert.Equal(t, total, seen)
+
+	resp, _ := bmc.do("GET", entries+"?$skip=abc", nil, basic)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestProfileFromDir(t *testing.T) {
+	dir := t.TempDir()
+	write := func(uri string, body string) {
+		p := filepath.Join(dir, uri, "index.json")
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(body), 0o644))
+	}
+	// Short-form mockup: the directory itself is /redfish/v1.
+	write("", `{"@odata.id": "/redfish/v1", "Name": "Mock Root"}`)
+	write("Systems", `{"@odata.id": "/redfish/v1/Systems", "Members": []}`)
+
+	bmc := newTestBMC(t, ProfileFromDir(dir))
+	_, root := bmc.do("GET", "/redfish/v1", nil, nil)
+	assert.Equal(t, "Mock Root", root["Name"])
+	resp, _ := bmc.do("GET", "/redfish/v1/Systems", nil, basic)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestStartTLS(t *testing.T) {
+	emu, err := New(IDRAC(), Options{Username: "root", Password: "calvin"})
+	require.NoError(t, err)
+	srv, err := emu.Start("127.0.0.1:0", StartOptions{})
+	require.NoError(t, err)
+	defer srv.Close()
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	resp, err := client.Get(srv.URL + "/redfish/v1")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestLookupProfile(t *testing.T) {
+	assert.Equal(t, []string{"idrac", "xcc"}, ProfileNames())
+	p, err := LookupProfile("xcc")
+	require.NoError(t, err)
+	assert.Equal(t, "xclarity", p.BMCType)
+	_, err = LookupProfile("ilo")
+	assert.Error(t, err)
+}
@@ -0,0 +1,77 @@
+package emulator
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+)
+
+//go:embed profiles
+var builtinProfiles embed.FS
+
+// Profile describes a Redfish service to emulate.
+type Profile struct {
+	// Name identifies the profile on the command line.
+	Name string
+	// BMCType is the redfishcli backend that talks to this profile.
+	BMCType string
+	// Mockup is a tree in the DMTF Redfish-Mockup-Creator layout: one
+	// index.json per resource, rooted either at redfish/v1 or at the
+	// directory containing it.
+	Mockup fs.FS
+	// SELPath is the LogEntry collection that grows when events happen.
+	// When empty the first log entry collection in the mockup is used.
+	SELPath string
+}
+
+func builtin(name, bmcType, selPath string) Profile {
+	sub, err := fs.Sub(builtinProfiles, "profiles/"+name)
+	if err != nil {
+		panic(err)
+	}
+	return Profile{Name: name, BMCType: bmcType, Mockup: sub, SELPath: selPath}
+}
+
+// IDRAC returns the built-in Dell iDRAC 9 profile (PowerEdge R740).
+func IDRAC() Profile {
+	return builtin("idrac", "idrac", "/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Entries")
+}
+
+// XCC returns the built-in Lenovo XClarity Controller profile (ThinkSystem SR650).
+func XCC() Profile {
+	return builtin("xcc", "xclarity", "/redfish/v1/Systems/1/LogServices/PlatformLog/Entries")
+}
+
+// Profiles returns the built-in profiles keyed by name.
+func Profiles() map[string]Profile {
+	return map[string]Profile{
+		"idrac": IDRAC(),
+		"xcc":   XCC(),
+	}
+}
+
+// ProfileNames returns the sorted names of the built-in profiles.
+func ProfileNames() []string {
+	var names []string
+	for name := range Profiles() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupProfile returns the built-in profile with the given name.
+func LookupProfile(name string) (Profile, error) {
+	p, ok := Profiles()[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("unknown emulator profile %q (available: %v)", name, ProfileNames())
+	}
+	return p, nil
+}
+
+// ProfileFromDir builds a profile from a mockup directory on disk.
+func ProfileFromDir(dir string) Profile {
+	return Profile{Name: dir, Mockup: os.DirFS(dir)}
+}
@@ -0,0 +1,120 @@
+package emulator
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/angelhvargas/redfishcli/pkg/fault"
+)
+
+// Server is a running HTTPS endpoint for an emulator.
+type Server struct {
+	// URL is the base URL of the service, e.g. https://127.0.0.1:8443.
+	URL string
+	// Host is the host:port pair, which is what redfishcli expects as the
+	// BMC hostname.
+	Host string
+
+	srv       *http.Server
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+// StartOptions configures a running emulator.
+type StartOptions struct {
+	// SELInterval, when non-zero, appends an informational entry to the
+	// system event log at this interval so log consumers see it grow.
+	SELInterval time.Duration
+	// Faults, when set, injects failures into matching requests.
+	Faults *fault.Injector
+}
+
+// Start serves the emulator over HTTPS on addr using a freshly generated
+// self-signed certificate. Use "127.0.0.1:0" to pick a free port.
+func (e *Emulator) Start(addr string, opts StartOptions) (*Server, error) {
+	cert, err := selfSignedCert()
+	if err != nil {
+		return nil, err
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	var handler http.Handler = e
+	if opts.Faults != nil {
+		handler = opts.Faults.Handler(handler)
+	}
+
+	s := &Server{
+		Host: ln.Addr().String(),
+		URL:  "https://" + ln.Addr().String(),
+		srv: &http.Server{
+			Handler:   handler,
+			TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+		},
+		stop: make(chan struct{}),
+	}
+	go s.srv.ServeTLS(ln, "", "")
+
+	if opts.SELInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(opts.SELInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-s.stop:
+					return
+				case <-ticker.C:
+					e.AddLogEntry("OK", "Periodic event generated by the emulator.")
+				}
+			}
+		}()
+	}
+	return s, nil
+}
+
+// Close stops the server.
+func (s *Server) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.stop)
+		err = s.srv.Close()
+	})
+	return err
+}
+
+func selfSignedCert() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 62))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{Organization: []string{"redfishcli emulator"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1"), net.IPv6loopback},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
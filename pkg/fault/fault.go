@@ -0,0 +1,343 @@
+// Package fault injects failures into Redfish traffic: slow responses, error
+// statuses, truncated bodies, TLS failures, dropped connections and expiring
+// credentials. The same rules drive a client-side httpclient middleware and a
+// server-side handler wrapper for the emulator.
+package fault
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+)
+
+// Rule describes one fault and the requests it applies to.
+type Rule struct {
+	// Path is a regular expression matched against the request path.
+	Path *regexp.Regexp
+	// Method restricts the rule to one HTTP method. Empty matches any.
+	Method string
+	// After skips the first After matching requests, so a fault can hit
+	// half-way through a walk.
+	After int
+	// Times limits how often the rule fires (0 = every time).
+	Times int
+
+	// Latency delays the response.
+	Latency time.Duration
+	// Status answers with this HTTP status and a Redfish error body.
+	Status int
+	// Truncate cuts the response body in half, leaving malformed JSON.
+	Truncate bool
+	// TLSFailure fails the request with a TLS alert.
+	TLSFailure bool
+	// Drop closes the connection without a response.
+	Drop bool
+	// AuthExpiry invalidates the credentials: this and every later request,
+	// on any path, is answered with 401.
+	AuthExpiry bool
+}
+
+// ErrTLS is the error returned by the client-side middleware for TLSFailure.
+var ErrTLS error = tls.AlertError(40) // handshake_failure
+
+// ErrDropped is the error returned by the client-side middleware for Drop.
+var ErrDropped error = &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+
+// Injector applies a set of rules. Counters for After and Times are shared
+// by the middleware and the handler of the same Injector.
+type Injector struct {
+	rules []Rule
+
+	mu      sync.Mutex
+	seen    []int
+	fired   []int
+	expired bool
+}
+
+// New returns an injector for the given rules.
+func New(rules ...Rule) *Injector {
+	return &Injector{
+		rules: rules,
+		seen:  make([]int, len(rules)),
+		fired: make([]int, len(rules)),
+	}
+}
+
+// match returns the combined fault for a request, or nil when no rule fires.
+func (i *Injector) match(method, path string) *Rule {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	var out *Rule
+	for n, r := range i.rules {
+		if r.Method != "" && !strings.EqualFold(r.Method, method) {
+			continue
+		}
+		if r.Path != nil && !r.Path.MatchString(path) {
+			continue
+		}
+		i.seen[n]++
+		if i.seen[n] <= r.After || (r.Times > 0 && i.fired[n] >= r.Times) {
+			continue
+		}
+		i.fired[n]++
+		if out == nil {
+			out = &Rule{}
+		}
+		out.merge(r)
+	}
+
+	if out != nil && out.AuthExpiry {
+		i.expired = true
+	}
+	if i.expired {
+		if out == nil {
+			out = &Rule{}
+		}
+		out.AuthExpiry = true
+	}
+	return out
+}
+
+func (r *Rule) merge(o Rule) {
+	r.Latency += o.Latency
+	if o.Status != 0 {
+		r.Status = o.Status
+	}
+	r.Truncate = r.Truncate || o.Truncate
+	r.TLSFailure = r.TLSFailure || o.TLSFailure
+	r.Drop = r.Drop || o.Drop
+	r.AuthExpiry = r.AuthExpiry || o.AuthExpiry
+}
+
+// status returns the status to answer with instead of the real response, or 0.
+func (r *Rule) status() int {
+	if r.AuthExpiry {
+		return http.StatusUnauthorized
+	}
+	return r.Status
+}
+
+func sleep(d time.Duration, done <-chan struct{}) bool {
+	if d <= 0 {
+		return true
+	}
+	select {
+	case <-time.After(d):
+		return true
+	case <-done:
+		return false
+	}
+}
+
+// Middleware returns an httpclient middleware that injects the faults on the
+// client side, without any server involved.
+func (i *Injector) Middleware() httpclient.Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			f := i.match(req.Method, req.URL.Path)
+			if f == nil {
+				return next.RoundTrip(req)
+			}
+			if !sleep(f.Latency, req.Context().Done()) {
+				return nil, req.Context().Err()
+			}
+			switch {
+			case f.TLSFailure:
+				return nil, &net.OpError{Op: "remote error", Net: "tcp", Err: ErrTLS}
+			case f.Drop:
+				return nil, ErrDropped
+			}
+			if status := f.status(); status != 0 {
+				if req.Body != nil {
+					req.Body.Close()
+				}
+				body := errorBody(status)
+				return &http.Response{
+					Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+					StatusCode:    status,
+					Proto:         "HTTP/1.1",
+					ProtoMajor:    1,
+					ProtoMinor:    1,
+					Header:        http.Header{"Content-Type": {"application/json"}},
+					Body:          io.NopCloser(bytes.NewReader(body)),
+					ContentLength: int64(len(body)),
+					Request:       req,
+				}, nil
+			}
+
+			resp, err := next.RoundTrip(req)
+			if err != nil || !f.Truncate {
+				return resp, err
+			}
+			data, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			data = data[:len(data)/2]
+			resp.Body = io.NopCloser(bytes.NewReader(data))
+			resp.ContentLength = int64(len(data))
+			resp.Header.Del("Content-Length")
+			return resp, nil
+		})
+	}
+}
+
+// Handler wraps a server-side handler, such as the emulator, with the
+// faults. TLSFailure sends a TLS alert in place of the response and Drop
+// closes the connection; both need a connection that supports hijacking.
+func (i *Injector) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f := i.match(r.Method, r.URL.Path)
+		if f == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !sleep(f.Latency, r.Context().Done()) {
+			return
+		}
+		switch {
+		case f.TLSFailure:
+			hijack(w, []byte{0x15, 0x03, 0x03, 0x00, 0x02, 0x02, 0x28})
+			return
+		case f.Drop:
+			hijack(w, nil)
+			return
+		}
+		if status := f.status(); status != 0 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			w.Write(errorBody(status))
+			return
+		}
+		if !f.Truncate {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &bufferedWriter{header: make(http.Header), status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		for k, v := range rec.header {
+			w.Header()[k] = v
+		}
+		w.Header().Del("Content-Length")
+		w.WriteHeader(rec.status)
+		w.Write(rec.body.Bytes()[:rec.body.Len()/2])
+	})
+}
+
+// hijack takes over the connection, writes raw bytes below the TLS layer
+// and closes it.
+func hijack(w http.ResponseWriter, raw []byte) {
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	conn, _, err := hj.Hijack()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+	if len(raw) > 0 {
+		conn.Write(raw)
+	}
+}
+
+type bufferedWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedWriter) Header() http.Header         { return b.header }
+func (b *bufferedWriter) WriteHeader(status int)      { b.status = status }
+func (b *bufferedWriter) Write(p []byte) (int, error) { return b.body.Write(p) }
+
+func errorBody(status int) []byte {
+	id, msg := "Base.1.8.InternalError", "The request failed due to an injected fault."
+	switch status {
+	case http.StatusUnauthorized:
+		id, msg = "Base.1.8.NoValidSession", "There is no valid session established with the implementation."
+	case http.StatusServiceUnavailable:
+		id, msg = "Base.1.8.ServiceTemporarilyUnavailable", "The service is temporarily unavailable."
+	}
+	body, _ := json.Marshal(map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    id,
+			"message": msg,
+			"@Message.ExtendedInfo": []interface{}{
+				map[string]interface{}{"MessageId": id, "Message": msg, "Severity": "Critical"},
+			},
+		},
+	})
+	return body
+}
+
+// Parse reads a rule from the command-line form
+//
+//	<path-regex>:<option>[,<option>...]
+//
+// where options are latency=<duration>, status=<code>, truncate, tls, drop,
+// auth-expiry, method=<verb>, after=<n> and times=<n>. For example
+// "Drives/Disk.Bay.1$:status=500" or "/Storage/:latency=5s,after=2".
+func Parse(spec string) (Rule, error) {
+	var r Rule
+	idx := strings.LastIndex(spec, ":")
+	if idx < 0 {
+		return r, fmt.Errorf("fault %q: expected <path-regex>:<options>", spec)
+	}
+	path, opts := spec[:idx], spec[idx+1:]
+
+	re, err := regexp.Compile(path)
+	if err != nil {
+		return r, fmt.Errorf("fault %q: %w", spec, err)
+	}
+	r.Path = re
+
+	for _, opt := range strings.Split(opts, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(opt), "=")
+		switch key {
+		case "latency":
+			r.Latency, err = time.ParseDuration(value)
+		case "status":
+			r.Status, err = strconv.Atoi(value)
+		case "after":
+			r.After, err = strconv.Atoi(value)
+		case "times":
+			r.Times, err = strconv.Atoi(value)
+		case "method":
+			r.Method = strings.ToUpper(value)
+		case "truncate":
+			r.Truncate = true
+		case "tls":
+			r.TLSFailure = true
+		case "drop":
+			r.Drop = true
+		case "auth-expiry":
+			r.AuthExpiry = true
+		default:
+			err = fmt.Errorf("unknown option %q", key)
+		}
+		if err != nil {
+			return r, fmt.Errorf("fault %q: %w", spec, err)
+		}
+	}
+	return r, nil
+}
@@ -0,0 +1,137 @@
+package fault
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	r, err := Parse(`Drives/Disk\.Bay\.1$:status=500,latency=2s,after=1,times=3,method=get`)
+	require.NoError(t, err)
+	assert.Equal(t, `Drives/Disk\.Bay\.1$`, r.Path.String())
+	assert.Equal(t, 500, r.Status)
+	assert.Equal(t, 2*time.Second, r.Latency)
+	assert.Equal(t, 1, r.After)
+	assert.Equal(t, 3, r.Times)
+	assert.Equal(t, "GET", r.Method)
+
+	r, err = Parse("/Systems:truncate,tls,drop,auth-expiry")
+	require.NoError(t, err)
+	assert.True(t, r.Truncate && r.TLSFailure && r.Drop && r.AuthExpiry)
+
+	for _, spec := range []string{"no-options", "/x:status=abc", "/x:explode", "([:status=500"} {
+		_, err := Parse(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func newBMC() *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Id":"` + r.URL.Path + `","Status":{"Health":"OK"}}`))
+	}))
+}
+
+func TestMiddleware(t *testing.T) {
+	srv := newBMC()
+	defer srv.Close()
+
+	t.Run("After and Times select the requests that fail", func(t *testing.T) {
+		inj := New(Rule{Path: regexp.MustCompile(`/Drives/`), After: 1, Times: 1, Status: 503})
+		cfg := httpclient.DefaultConfig().Use(inj.Middleware())
+
+		var errs []error
+		for i := 0; i < 3; i++ {
+			_, err := httpclient.Get(srv.URL+"/redfish/v1/Drives/0", "u", "p", cfg)
+			errs = append(errs, err)
+		}
+		_, err := httpclient.Get(srv.URL+"/redfish/v1/Systems/1", "u", "p", cfg)
+		require.NoError(t, err)
+
+		assert.NoError(t, errs[0])
+		assert.Error(t, errs[1])
+		assert.NoError(t, errs[2])
+	})
+
+	t.Run("Truncate leaves malformed JSON", func(t *testing.T) {
+		cfg := httpclient.DefaultConfig().Use(New(Rule{Truncate: true}).Middleware())
+		body, err := httpclient.Get(srv.URL+"/redfish/v1", "u", "p", cfg)
+		require.NoError(t, err)
+		assert.Equal(t, `{"Id":"/redfish/v1","S`, string(body))
+	})
+
+	t.Run("Auth expiry sticks to every later request", func(t *testing.T) {
+		cfg := httpclient.DefaultConfig().Use(New(Rule{Path: regexp.MustCompile(`/Chassis`), AuthExpiry: true}).Middleware())
+		_, err := httpclient.Get(srv.URL+"/redfish/v1/Systems/1", "u", "p", cfg)
+		require.NoError(t, err)
+		_, err = httpclient.Get(srv.URL+"/redfish/v1/Chassis/1", "u", "p", cfg)
+		assert.ErrorIs(t, err, httpclient.ErrAuthentication)
+		_, err = httpclient.Get(srv.URL+"/redfish/v1/Systems/1", "u", "p", cfg)
+		assert.ErrorIs(t, err, httpclient.ErrAuthentication)
+	})
+
+	t.Run("TLS failure and dropped connections", func(t *testing.T) {
+		cfg := httpclient.DefaultConfig().Use(New(
+			Rule{Path: regexp.MustCompile(`/tls$`), TLSFailure: true},
+			Rule{Path: regexp.MustCompile(`/drop$`), Drop: true},
+		).Middleware())
+		_, err := httpclient.Get(srv.URL+"/tls", "u", "p", cfg)
+		assert.ErrorIs(t, err, ErrTLS)
+		_, err = httpclient.Get(srv.URL+"/drop", "u", "p", cfg)
+		assert.ErrorIs(t, err, ErrDropped)
+	})
+
+	t.Run("Latency honours the client timeout", func(t *testing.T) {
+		cfg := httpclient.DefaultConfig().Use(New(Rule{Latency: time.Second}).Middleware())
+		cfg.Timeout = 50 * time.Millisecond
+		start := time.Now()
+		_, err := httpclient.Get(srv.URL+"/redfish/v1", "u", "p", cfg)
+		assert.Error(t, err)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
+
+func TestHandler(t *testing.T) {
+	inj := New(
+		Rule{Path: regexp.MustCompile(`/status$`), Status: 500},
+		Rule{Path: regexp.MustCompile(`/truncate$`), Truncate: true},
+		Rule{Path: regexp.MustCompile(`/tls$`), TLSFailure: true},
+		Rule{Path: regexp.MustCompile(`/drop$`), Drop: true},
+	)
+	srv := httptest.NewTLSServer(inj.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Id":"1234"}`))
+	})))
+	defer srv.Close()
+	c := srv.Client()
+
+	resp, err := c.Get(srv.URL + "/status")
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, 500, resp.StatusCode)
+	assert.Contains(t, string(body), "Base.1.8.InternalError")
+
+	resp, err = c.Get(srv.URL + "/truncate")
+	require.NoError(t, err)
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, `{"Id":`, string(body))
+
+	_, err = c.Get(srv.URL + "/tls")
+	assert.ErrorContains(t, err, "tls")
+
+	_, err = c.Get(srv.URL + "/drop")
+	assert.Error(t, err)
+
+	resp, err = c.Get(srv.URL + "/ok")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+}
@@ -0,0 +1,82 @@
+package fleet
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+
+	"github.com/angelhvargas/redfishcli/pkg/client"
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+)
+
+// Class sorts a server's failure by what went wrong, so automation can
+// react to the kind of failure without parsing messages.
+type Class string
+
+const (
+	// ClassAuth is a BMC rejecting the credentials (HTTP 401 or 403).
+	ClassAuth Class = "auth"
+	// ClassUnreachable is a BMC that could not be connected to: refused
+	// connections, unknown hostnames, TLS failures.
+	ClassUnreachable Class = "unreachable"
+	// ClassTimeout is a server that did not answer in time.
+	ClassTimeout Class = "timeout"
+	// ClassUnsupported is a BMC or backend lacking what the command needs.
+	ClassUnsupported Class = "unsupported"
+	// ClassInvalid is a value the BMC does not accept, such as a boot target
+	// or reset type it does not list among its allowable values.
+	ClassInvalid Class = "invalid"
+	// ClassBMCError is any other failure: error responses, unexpected
+	// payloads, servers in the wrong state.
+	ClassBMCError Class = "bmc-error"
+)
+
+// HostError is a server's failure as reported in JSON and YAML output.
+type HostError struct {
+	Class   Class  `json:"class" yaml:"class"`
+	Message string `json:"message" yaml:"message"`
+	// URI is the path of the request that failed, when known.
+	URI string `json:"uri,omitempty" yaml:"uri,omitempty"`
+}
+
+// Describe classifies err. It returns nil for a nil err.
+func Describe(err error) *HostError {
+	if err == nil {
+		return nil
+	}
+	e := &HostError{Class: ClassBMCError, Message: err.Error()}
+
+	var (
+		httpErr *httpclient.HTTPError
+		urlErr  *url.Error
+		netErr  net.Error
+	)
+	switch {
+	case errors.Is(err, ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		e.Class = ClassTimeout
+	case errors.Is(err, client.ErrNotSupported):
+		e.Class = ClassUnsupported
+	case errors.Is(err, client.ErrInvalidValue):
+		e.Class = ClassInvalid
+	case errors.As(err, &httpErr):
+		if httpErr.StatusCode == 401 || httpErr.StatusCode == 403 {
+			e.Class = ClassAuth
+		}
+		e.URI = httpErr.URI
+	case errors.As(err, &urlErr):
+		e.Class = ClassUnreachable
+		if urlErr.Timeout() {
+			e.Class = ClassTimeout
+		}
+		if u, perr := url.Parse(urlErr.URL); perr == nil {
+			e.URI = u.Path
+		}
+	case errors.As(err, &netErr):
+		e.Class = ClassUnreachable
+		if netErr.Timeout() {
+			e.Class = ClassTimeout
+		}
+	}
+	return e
+}
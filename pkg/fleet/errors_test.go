@@ -0,0 +1,49 @@
+package fleet
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/angelhvargas/redfishcli/pkg/client"
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+	"github.com/stretchr/testify/assert"
+)
+
+type timeoutErr struct{}
+
+func (timeoutErr) Error() string   { return "i/o timeout" }
+func (timeoutErr) Timeout() bool   { return true }
+func (timeoutErr) Temporary() bool { return true }
+
+func TestDescribe(t *testing.T) {
+	assert.Nil(t, Describe(nil))
+
+	tests := []struct {
+		name  string
+		err   error
+		class Class
+		uri   string
+	}{
+		{"auth", fmt.Errorf("host bmc-1: %w", &httpclient.HTTPError{StatusCode: 401, Message: "authentication error", URI: "/redfish/v1/Systems"}), ClassAuth, "/redfish/v1/Systems"},
+		{"forbidden", httpclient.ErrAuthorization, ClassAuth, ""},
+		{"bmc error", &httpclient.HTTPError{StatusCode: 500, Message: "unexpected error", URI: "/redfish/v1/Chassis"}, ClassBMCError, "/redfish/v1/Chassis"},
+		{"refused", &url.Error{Op: "Get", URL: "https://10.0.0.1/redfish/v1/Systems", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}, ClassUnreachable, "/redfish/v1/Systems"},
+		{"client timeout", &url.Error{Op: "Get", URL: "https://10.0.0.1/redfish/v1", Err: timeoutErr{}}, ClassTimeout, "/redfish/v1"},
+		{"fleet timeout", &TimeoutError{After: time.Second}, ClassTimeout, ""},
+		{"unsupported", Skip(fmt.Errorf("event logs: %w", client.ErrNotSupported)), ClassUnsupported, ""},
+		{"invalid", fmt.Errorf("host bmc-1: %w", &client.InvalidValueError{Parameter: "ResetType", Value: "Reboot", Allowed: []string{"On", "ForceRestart"}}), ClassInvalid, ""},
+		{"other", errors.New("server is not powered on"), ClassBMCError, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := Describe(tt.err)
+			assert.Equal(t, tt.class, e.Class)
+			assert.Equal(t, tt.uri, e.URI)
+			assert.Equal(t, tt.err.Error(), e.Message)
+		})
+	}
+}
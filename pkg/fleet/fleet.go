@@ -0,0 +1,264 @@
+// Package fleet runs a task on many servers at once.
+//
+// Run bounds how many servers are worked on concurrently and how long each
+// one may take, delivers every server's result to one callback, in the order
+// of the servers or as they complete, and sums up how many succeeded, failed
+// or were skipped. A task can return a value together with an error, e.g. a
+// partial report, and both are kept.
+package fleet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/angelhvargas/redfishcli/pkg/config"
+)
+
+// Options control how Run schedules the servers.
+type Options struct {
+	// Parallel is how many servers are worked on at once; 0 or less works
+	// on all of them at once.
+	Parallel int
+	// Timeout bounds the time spent on each server; 0 means no limit.
+	Timeout time.Duration
+	// AsCompleted delivers results as servers complete instead of in the
+	// order of the servers.
+	AsCompleted bool
+}
+
+// Status is how a task ended on one server.
+type Status string
+
+const (
+	StatusOK      Status = "ok"
+	StatusFailed  Status = "failed"
+	StatusSkipped Status = "skipped"
+)
+
+// Result is the outcome of the task on one server.
+type Result[T any] struct {
+	Server config.ServerConfig
+	// Index is the position of the server in the list given to Run.
+	Index  int
+	Value  T
+	Err    error
+	Status Status
+	// Duration is how long the task ran.
+	Duration time.Duration
+}
+
+// Task works on one server. ctx is cancelled when the server's time is up;
+// the result of a task still running then is discarded, but the server
+// keeps its place among the Parallel ones until the task returns, so tasks
+// should pass ctx on to their requests.
+type Task[T any] func(ctx context.Context, server config.ServerConfig) (T, error)
+
+// ErrTimeout matches every TimeoutError with errors.Is.
+var ErrTimeout = errors.New("timed out")
+
+// TimeoutError is the error of a server that did not complete in time.
+type TimeoutError struct {
+	After time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("timed out after %s", e.After)
+}
+
+// Is makes errors.Is(err, ErrTimeout) match.
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
+// ErrSkipped matches every SkipError with errors.Is.
+var ErrSkipped = errors.New("skipped")
+
+// SkipError marks a server the task did not apply to, such as a BMC lacking
+// the feature, as opposed to one it failed on.
+type SkipError struct {
+	Err error
+}
+
+// Skip returns an error marking the server as skipped because of err.
+func Skip(err error) error {
+	return &SkipError{Err: err}
+}
+
+func (e *SkipError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *SkipError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes errors.Is(err, ErrSkipped) match.
+func (e *SkipError) Is(target error) bool {
+	return target == ErrSkipped
+}
+
+// HostStatus is how the task ended on one server, without its value.
+type HostStatus struct {
+	Hostname string
+	Status   Status
+	Err      error
+	Duration time.Duration
+}
+
+// Summary counts the outcomes of a Run.
+type Summary struct {
+	Succeeded int
+	Failed    int
+	Skipped   int
+	// Duration is the wall time of the whole Run.
+	Duration time.Duration
+	// Hosts lists every server in the order results were delivered.
+	Hosts []HostStatus
+}
+
+// Total is the number of servers.
+func (s Summary) Total() int {
+	return s.Succeeded + s.Failed + s.Skipped
+}
+
+// Problems lists the servers that failed or were skipped.
+func (s Summary) Problems() []HostStatus {
+	var problems []HostStatus
+	for _, h := range s.Hosts {
+		if h.Status != StatusOK {
+			problems = append(problems, h)
+		}
+	}
+	return problems
+}
+
+// Run runs task on every server and calls emit with each result, from one
+// goroutine at a time. It returns once every server has been delivered and
+// every task has returned.
+func Run[T any](ctx context.Context, servers []config.ServerConfig, opts Options, task Task[T], emit func(Result[T])) Summary {
+	start := time.Now()
+	parallel := opts.Parallel
+	if parallel <= 0 || parallel > len(servers) {
+		parallel = len(servers)
+	}
+
+	results := make(chan Result[T])
+	go func() {
+		slots := make(chan struct{}, max(parallel, 1))
+		var wg sync.WaitGroup
+		for i, server := range servers {
+			slots <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result, done := runOne(ctx, server, opts.Timeout, task)
+				result.Index = i
+				results <- result
+				// A task that timed out holds its slot until it returns.
+				<-done
+				<-slots
+			}()
+		}
+		wg.Wait()
+		close(results)
+	}()
+
+	var summary Summary
+	deliver := func(r Result[T]) {
+		switch r.Status {
+		case StatusOK:
+			summary.Succeeded++
+		case StatusFailed:
+			summary.Failed++
+		case StatusSkipped:
+			summary.Skipped++
+		}
+		summary.Hosts = append(summary.Hosts, HostStatus{Hostname: r.Server.Hostname, Status: r.Status, Err: r.Err, Duration: r.Duration})
+		if emit != nil {
+			emit(r)
+		}
+	}
+
+	// In order, results that complete early wait for the servers before
+	// them.
+	pending := make(map[int]Result[T])
+	next := 0
+	for r := range results {
+		if opts.AsCompleted {
+			deliver(r)
+			continue
+		}
+		pending[r.Index] = r
+		for {
+			r, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			deliver(r)
+			next++
+		}
+	}
+	summary.Duration = time.Since(start)
+	return summary
+}
+
+// Collect runs task on every server and returns the results in the order
+// of servers.
+func Collect[T any](ctx context.Context, servers []config.ServerConfig, opts Options, task Task[T]) ([]Result[T], Summary) {
+	results := make([]Result[T], len(servers))
+	opts.AsCompleted = false
+	summary := Run(ctx, servers, opts, task, func(r Result[T]) { results[r.Index] = r })
+	return results, summary
+}
+
+// runOne runs task on one server within timeout. The result is returned
+// when the task returns or its time is up, whichever comes first; done is
+// closed once the task has returned.
+func runOne[T any](ctx context.Context, server config.ServerConfig, timeout time.Duration, task Task[T]) (r Result[T], done <-chan struct{}) {
+	start := time.Now()
+	r = Result[T]{Server: server}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var (
+		value T
+		err   error
+	)
+	returned := make(chan struct{})
+	go func() {
+		defer close(returned)
+		defer func() {
+			if p := recover(); p != nil {
+				err = fmt.Errorf("panic: %v", p)
+			}
+		}()
+		value, err = task(ctx, server)
+	}()
+
+	select {
+	case <-returned:
+		r.Value, r.Err = value, err
+	case <-ctx.Done():
+		r.Err = ctx.Err()
+		if errors.Is(r.Err, context.DeadlineExceeded) {
+			r.Err = &TimeoutError{After: timeout}
+		}
+	}
+	r.Duration = time.Since(start)
+	switch {
+	case r.Err == nil:
+		r.Status = StatusOK
+	case errors.Is(r.Err, ErrSkipped):
+		r.Status = StatusSkipped
+	default:
+		r.Status = StatusFailed
+	}
+	return r, returned
+}
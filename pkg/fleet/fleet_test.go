@@ -0,0 +1,154 @@
+package fleet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func servers(n int) []config.ServerConfig {
+	var list []config.ServerConfig
+	for i := 0; i < n; i++ {
+		list = append(list, config.ServerConfig{Hostname: fmt.Sprintf("bmc-%02d", i)})
+	}
+	return list
+}
+
+func hostnames(results []HostStatus) []string {
+	var names []string
+	for _, r := range results {
+		names = append(names, r.Hostname)
+	}
+	return names
+}
+
+func TestRunBoundsConcurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	summary := Run(context.Background(), servers(20), Options{Parallel: 3}, func(ctx context.Context, server config.ServerConfig) (string, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		return server.Hostname, nil
+	}, nil)
+
+	assert.EqualValues(t, 3, peak.Load())
+	assert.Equal(t, 20, summary.Succeeded)
+	assert.Equal(t, 20, summary.Total())
+	assert.Empty(t, summary.Problems())
+}
+
+func TestRunOrder(t *testing.T) {
+	// The first server is the slowest.
+	task := func(ctx context.Context, server config.ServerConfig) (int, error) {
+		if server.Hostname == "bmc-00" {
+			time.Sleep(50 * time.Millisecond)
+		}
+		return 0, nil
+	}
+
+	var order []string
+	summary := Run(context.Background(), servers(4), Options{}, task, func(r Result[int]) {
+		order = append(order, r.Server.Hostname)
+	})
+	assert.Equal(t, []string{"bmc-00", "bmc-01", "bmc-02", "bmc-03"}, order)
+	assert.Equal(t, order, hostnames(summary.Hosts))
+
+	order = nil
+	Run(context.Background(), servers(4), Options{AsCompleted: true}, task, func(r Result[int]) {
+		order = append(order, r.Server.Hostname)
+	})
+	assert.Equal(t, "bmc-00", order[3])
+}
+
+func TestRunOutcomes(t *testing.T) {
+	results, summary := Collect(context.Background(), servers(5), Options{Parallel: 2, Timeout: 100 * time.Millisecond},
+		func(ctx context.Context, server config.ServerConfig) (string, error) {
+			switch server.Hostname {
+			case "bmc-01":
+				return "partial", errors.New("HTTP 500")
+			case "bmc-02":
+				return "", Skip(errors.New("power management not supported on this BMC"))
+			case "bmc-03":
+				<-ctx.Done()
+				time.Sleep(time.Second)
+				return "late", nil
+			case "bmc-04":
+				panic("boom")
+			}
+			return "ok", nil
+		})
+
+	require.Len(t, results, 5)
+	assert.Equal(t, StatusOK, results[0].Status)
+	assert.Equal(t, "ok", results[0].Value)
+
+	// A value returned with an error is kept.
+	assert.Equal(t, StatusFailed, results[1].Status)
+	assert.Equal(t, "partial", results[1].Value)
+
+	assert.Equal(t, StatusSkipped, results[2].Status)
+	assert.ErrorIs(t, results[2].Err, ErrSkipped)
+	assert.EqualError(t, results[2].Err, "power management not supported on this BMC")
+
+	assert.Equal(t, StatusFailed, results[3].Status)
+	assert.ErrorIs(t, results[3].Err, ErrTimeout)
+	assert.EqualError(t, results[3].Err, "timed out after 100ms")
+	assert.Empty(t, results[3].Value)
+	assert.Less(t, results[3].Duration, time.Second)
+
+	assert.EqualError(t, results[4].Err, "panic: boom")
+
+	assert.Equal(t, 1, summary.Succeeded)
+	assert.Equal(t, 3, summary.Failed)
+	assert.Equal(t, 1, summary.Skipped)
+	assert.Equal(t, []string{"bmc-01", "bmc-02", "bmc-03", "bmc-04"}, hostnames(summary.Problems()))
+}
+
+func TestRunNoServers(t *testing.T) {
+	summary := Run(context.Background(), nil, Options{Parallel: 4}, func(ctx context.Context, server config.ServerConfig) (int, error) {
+		return 0, nil
+	}, nil)
+	assert.Equal(t, 0, summary.Total())
+}
+
+func TestRunTimeoutHoldsSlot(t *testing.T) {
+	var running, peak atomic.Int32
+	returned := make(chan time.Time, 1)
+	var delivered time.Time
+	summary := Run(context.Background(), servers(2), Options{Parallel: 1, Timeout: 20 * time.Millisecond},
+		func(ctx context.Context, server config.ServerConfig) (string, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			if n > peak.Load() {
+				peak.Store(n)
+			}
+			if server.Hostname == "bmc-00" {
+				// Ignores ctx for a while, as a task stuck in a call would.
+				time.Sleep(200 * time.Millisecond)
+				returned <- time.Now()
+			}
+			return server.Hostname, nil
+		}, func(r Result[string]) {
+			if r.Server.Hostname == "bmc-00" {
+				delivered = time.Now()
+			}
+		})
+
+	assert.EqualValues(t, 1, peak.Load(), "the next server waits for the timed out task to return")
+	assert.Equal(t, 1, summary.Failed)
+	assert.Equal(t, 1, summary.Succeeded)
+	assert.True(t, delivered.Before(<-returned), "the timeout is reported before the task returns")
+}
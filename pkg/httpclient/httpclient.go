@@ -1,10 +1,14 @@
 package httpclient
 
 import (
+	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/angelhvargas/redfishcli/pkg/logger"
@@ -13,6 +17,21 @@ import (
 type Config struct {
 	Timeout       time.Duration
 	SkipTLSVerify bool
+	// RootCAs verifies the BMC certificates when SkipTLSVerify is false;
+	// nil means the system pool.
+	RootCAs *x509.CertPool
+	// Proxy is the HTTP proxy the BMCs are reached through; nil means
+	// they are reached directly.
+	Proxy *url.URL
+	// Transport is the base RoundTripper used to reach the BMC. When nil a
+	// TLS-configured http.Transport is built from SkipTLSVerify, RootCAs and
+	// Proxy.
+	Transport http.RoundTripper
+	// Middlewares wrap Transport, outermost first.
+	Middlewares []Middleware
+	// Context cancels the requests, e.g. once a server's time is up; nil
+	// means context.Background().
+	Context context.Context
 }
 
 // DefaultConfig provides default settings for the HTTP client.
@@ -23,13 +42,85 @@ func DefaultConfig() Config {
 	}
 }
 
+// Use returns a copy of the config with the given middlewares appended to
+// the pipeline.
+func (c Config) Use(middlewares ...Middleware) Config {
+	mws := make([]Middleware, 0, len(c.Middlewares)+len(middlewares))
+	mws = append(mws, c.Middlewares...)
+	c.Middlewares = append(mws, middlewares...)
+	return c
+}
+
+// WithContext returns a copy of the config whose requests are cancelled
+// with ctx.
+func (c Config) WithContext(ctx context.Context) Config {
+	c.Context = ctx
+	return c
+}
+
+func (c Config) context() context.Context {
+	if c.Context == nil {
+		return context.Background()
+	}
+	return c.Context
+}
+
+// RoundTripper returns the full pipeline: the base transport wrapped by all
+// configured middlewares.
+func (c Config) RoundTripper() http.RoundTripper {
+	base := c.Transport
+	if base == nil {
+		transport := &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: c.SkipTLSVerify, RootCAs: c.RootCAs},
+		}
+		if c.Proxy != nil {
+			transport.Proxy = http.ProxyURL(c.Proxy)
+		}
+		base = transport
+	}
+	return Chain(base, c.Middlewares...)
+}
+
 type HTTPError struct {
 	StatusCode int
 	Message    string
+	// Code and ExtendedInfo come from the Redfish error payload of the
+	// response, when the BMC sent one.
+	Code         string
+	ExtendedInfo []MessageInfo
+	// URI is the path of the request that failed, when known.
+	URI string
+}
+
+// MessageInfo is one entry of the @Message.ExtendedInfo of a Redfish error.
+// BMCs often send only the MessageId and MessageArgs; pkg/registry fills in
+// the rest from the message registries.
+type MessageInfo struct {
+	MessageID         string   `json:"MessageId" yaml:"message_id"`
+	Message           string   `json:"Message,omitempty" yaml:"message,omitempty"`
+	MessageArgs       []string `json:"MessageArgs,omitempty" yaml:"message_args,omitempty"`
+	Severity          string   `json:"Severity,omitempty" yaml:"severity,omitempty"`
+	Resolution        string   `json:"Resolution,omitempty" yaml:"resolution,omitempty"`
+	RelatedProperties []string `json:"RelatedProperties,omitempty" yaml:"related_properties,omitempty"`
 }
 
 func (e *HTTPError) Error() string {
-	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Message)
+	msg := fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Message)
+	for _, info := range e.ExtendedInfo {
+		if info.Message != "" {
+			msg += ": " + info.Message
+		} else if info.MessageID != "" {
+			msg += ": " + info.MessageID
+		}
+	}
+	return msg
+}
+
+// Is matches errors by status code, so a 404 carrying the BMC's messages is
+// still ErrNotFound.
+func (e *HTTPError) Is(target error) bool {
+	t, ok := target.(*HTTPError)
+	return ok && t.StatusCode == e.StatusCode
 }
 
 var (
@@ -38,19 +129,53 @@ var (
 	ErrNotFound       = &HTTPError{StatusCode: 404, Message: "endpoint not found"}
 )
 
-// Function variable for mocking purposes
-var DoRequest = doRequest
-var Do = do
+// DoRequest performs an HTTP GET request.
+//
+// The backends still send their GET requests through it, so swapping it
+// keeps working until it is removed, though not under parallel tests.
+//
+// Deprecated: use Get, and inject a Transport or Middlewares through
+// Config instead of swapping this variable.
+var DoRequest = Get
+
+// Do performs an HTTP request.
+//
+// The backends still send their POST and PATCH requests through it, so
+// swapping it keeps working until it is removed, though not under parallel
+// tests.
+//
+// Deprecated: use Request, and inject a Transport or Middlewares through
+// Config instead of swapping this variable.
+var Do = Request
 
-// doRequest performs an HTTP GET request.
-func doRequest(url, username, password string, config Config) ([]byte, error) {
-	return do("GET", url, username, password, nil, config)
+// Get performs an HTTP GET request through the pipeline of config.
+func Get(url, username, password string, config Config) ([]byte, error) {
+	return Request("GET", url, username, password, nil, config)
 }
 
-// do performs an HTTP request.
-func do(method, url, username, password string, body io.Reader, config Config) ([]byte, error) {
+// Request performs an HTTP request through the pipeline of config and
+// returns the body of the response.
+func Request(method, url, username, password string, body io.Reader, config Config) ([]byte, error) {
+	resp, err := Send(method, url, username, password, body, config)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Response is a complete HTTP response.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Send performs an HTTP request and returns the whole response. A non-2xx
+// status returns the response together with its HTTPError, so callers can
+// still show what the BMC sent.
+func Send(method, url, username, password string, body io.Reader, config Config) (*Response, error) {
 	logger.Log.Printf("API request: %s %s", method, url)
-	req, err := http.NewRequest(method, url, body)
+	req, err := http.NewRequestWithContext(config.context(), method, url, body)
 	if err != nil {
 		return nil, err
 	}
@@ -61,10 +186,8 @@ func do(method, url, username, password string, body io.Reader, config Config) (
 	}
 
 	httpClient := &http.Client{
-		Timeout: config.Timeout,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: config.SkipTLSVerify},
-		},
+		Timeout:   config.Timeout,
+		Transport: config.RoundTripper(),
 	}
 
 	resp, err := httpClient.Do(req)
@@ -75,21 +198,62 @@ func do(method, url, username, password string, body io.Reader, config Config) (
 	defer resp.Body.Close()
 
 	logger.Log.Info(resp.StatusCode)
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		var httpErr error
-		switch resp.StatusCode {
-		case 401:
-			httpErr = ErrAuthentication
-		case 403:
-			httpErr = ErrAuthorization
-		case 404:
-			httpErr = ErrNotFound
-		default:
-			httpErr = &HTTPError{StatusCode: resp.StatusCode, Message: "unexpected error"}
-		}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	result := &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: data}
+	if httpErr := ResponseError(resp.StatusCode, data); httpErr != nil {
 		logger.Log.Errorf("Error: %s", httpErr)
-		return nil, httpErr
+		return result, at(httpErr, req.URL.Path)
 	}
+	return result, nil
+}
+
+// at returns a copy of the HTTPError err, as the sentinels are shared, with
+// the URI of the request that failed.
+func at(err error, uri string) error {
+	httpErr := *err.(*HTTPError)
+	httpErr.URI = uri
+	return &httpErr
+}
+
+// StatusError maps a non-2xx status code to its HTTPError, or nil for success.
+func StatusError(statusCode int) error {
+	if statusCode >= 200 && statusCode < 300 {
+		return nil
+	}
+	switch statusCode {
+	case 401:
+		return ErrAuthentication
+	case 403:
+		return ErrAuthorization
+	case 404:
+		return ErrNotFound
+	default:
+		return &HTTPError{StatusCode: statusCode, Message: "unexpected error"}
+	}
+}
 
-	return io.ReadAll(resp.Body)
+// ResponseError is StatusError for a response whose body may hold a Redfish
+// error payload. The code and @Message.ExtendedInfo of the payload are kept
+// on the returned HTTPError.
+func ResponseError(statusCode int, body []byte) error {
+	err := StatusError(statusCode)
+	if err == nil {
+		return nil
+	}
+	var payload struct {
+		Error struct {
+			Code         string        `json:"code"`
+			ExtendedInfo []MessageInfo `json:"@Message.ExtendedInfo"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &payload) != nil || (payload.Error.Code == "" && len(payload.Error.ExtendedInfo) == 0) {
+		return err
+	}
+	httpErr := *err.(*HTTPError)
+	httpErr.Code = payload.Error.Code
+	httpErr.ExtendedInfo = payload.Error.ExtendedInfo
+	return &httpErr
 }
@@ -1,9 +1,12 @@
 package httpclient
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/angelhvargas/redfishcli/pkg/logger"
 	"github.com/sirupsen/logrus"
@@ -71,11 +74,52 @@ func TestDoRequest(t *testing.T) {
 		defer server.Close()
 
 		config := DefaultConfig()
-		_, err := DoRequest(server.URL, "user", "pass", config)
+		_, err := DoRequest(server.URL+"/redfish/v1/Systems", "user", "pass", config)
 		require.Error(t, err)
+		var httpErr *HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, "/redfish/v1/Systems", httpErr.URI)
 
 		require.Len(t, testLogHook.Entries, 6)
 		assert.Contains(t, testLogHook.Entries[4].Message, "500")
 		assert.Contains(t, testLogHook.Entries[5].Message, "Error: HTTP 500: unexpected error")
 	})
 }
+
+func TestResponseError(t *testing.T) {
+	err := ResponseError(404, []byte(`{"error": {"code": "Base.1.8.GeneralError", "message": "A general error has occurred.",
+		"@Message.ExtendedInfo": [{"MessageId": "Base.1.8.ResourceMissingAtURI", "MessageArgs": ["/redfish/v1/Foo"]}]}}`))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.NotSame(t, ErrNotFound, err)
+	assert.EqualError(t, err, "HTTP 404: endpoint not found: Base.1.8.ResourceMissingAtURI")
+	assert.Equal(t, []MessageInfo{{MessageID: "Base.1.8.ResourceMissingAtURI", MessageArgs: []string{"/redfish/v1/Foo"}}},
+		err.(*HTTPError).ExtendedInfo)
+
+	// Without a Redfish error payload the plain status error is returned.
+	assert.Same(t, ErrAuthentication, ResponseError(401, []byte("Unauthorized")))
+	assert.NoError(t, ResponseError(204, nil))
+}
+
+func TestRequestContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		select {
+		case <-req.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	config := DefaultConfig().WithContext(ctx)
+
+	start := time.Now()
+	_, err := Get(server.URL+"/redfish/v1", "user", "pass", config)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	_, err = Login(server.URL, "user", "pass", config)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), config.Timeout, "the requests are cancelled with the context")
+}
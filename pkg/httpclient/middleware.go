@@ -0,0 +1,319 @@
+package httpclient
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/angelhvargas/redfishcli/pkg/logger"
+)
+
+// RoundTripperFunc adapts an ordinary function to http.RoundTripper, the same
+// way http.HandlerFunc does for handlers. It is handy for stubbing a BMC in tests.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware decorates a RoundTripper with cross-cutting behavior.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// Chain wraps base with the given middlewares. The first middleware is the
+// outermost one, so it sees the request first and the response last.
+func Chain(base http.RoundTripper, middlewares ...Middleware) http.RoundTripper {
+	rt := base
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		rt = middlewares[i](rt)
+	}
+	return rt
+}
+
+// BasicAuth overrides the credentials sent with every request.
+func BasicAuth(username, password string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			req.SetBasicAuth(username, password)
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// TokenAuth authenticates with a Redfish session token (X-Auth-Token) instead
+// of basic credentials.
+func TokenAuth(token string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			req.Header.Del("Authorization")
+			req.Header.Set("X-Auth-Token", token)
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// Retry re-sends a request when the transport fails or the BMC answers with
+// 429 or a 5xx status. Requests whose body cannot be rewound are sent once.
+func Retry(attempts int, backoff time.Duration) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			tries := attempts
+			if tries < 1 || (req.Body != nil && req.GetBody == nil) {
+				tries = 1
+			}
+
+			var resp *http.Response
+			var err error
+			for i := 0; i < tries; i++ {
+				if i > 0 {
+					wait := backoff * time.Duration(i)
+					if resp != nil {
+						if after := retryAfter(resp); after > wait {
+							wait = after
+						}
+					}
+					select {
+					case <-req.Context().Done():
+						return nil, req.Context().Err()
+					case <-time.After(wait):
+					}
+					if req.Body != nil {
+						body, berr := req.GetBody()
+						if berr != nil {
+							return nil, berr
+						}
+						req = req.Clone(req.Context())
+						req.Body = body
+					}
+				}
+				resp, err = next.RoundTrip(req)
+				if err == nil && resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+					return resp, nil
+				}
+				if err == nil && i+1 < tries {
+					resp.Body.Close()
+				}
+			}
+			return resp, err
+		})
+	}
+}
+
+// retryAfter parses a Retry-After header given in seconds.
+func retryAfter(resp *http.Response) time.Duration {
+	secs, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || secs < 0 {
+		return 0
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// RateLimit spaces requests at least interval apart, so crawls and fleet
+// runs do not overwhelm a BMC. The limit is shared by every client built
+// from the same middleware value.
+func RateLimit(interval time.Duration) Middleware {
+	var mu sync.Mutex
+	var next time.Time
+
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			now := time.Now()
+			wait := next.Sub(now)
+			if wait < 0 {
+				wait = 0
+			}
+			next = now.Add(wait + interval)
+			mu.Unlock()
+
+			if wait > 0 {
+				select {
+				case <-req.Context().Done():
+					return nil, req.Context().Err()
+				case <-time.After(wait):
+				}
+			}
+			return rt.RoundTrip(req)
+		})
+	}
+}
+
+// Logging writes every request and its outcome to the debug log.
+func Logging() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			if err != nil {
+				logger.Log.Debugf("%s %s failed after %s: %s", req.Method, req.URL, time.Since(start), err)
+				return nil, err
+			}
+			logger.Log.Debugf("%s %s -> %d in %s", req.Method, req.URL, resp.StatusCode, time.Since(start))
+			return resp, nil
+		})
+	}
+}
+
+// Observation describes one completed round trip for metrics collection.
+type Observation struct {
+	Method   string
+	URL      string
+	Status   int
+	Duration time.Duration
+	Err      error
+}
+
+// Metrics reports every round trip to observe.
+func Metrics(observe func(Observation)) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			obs := Observation{Method: req.Method, URL: req.URL.String(), Duration: time.Since(start), Err: err}
+			if resp != nil {
+				obs.Status = resp.StatusCode
+			}
+			observe(obs)
+			return resp, err
+		})
+	}
+}
+
+// Tracing tags every request with an X-Request-ID header so BMC-side logs
+// can be correlated with ours. An existing header is left untouched.
+func Tracing() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("X-Request-ID") == "" {
+				id := make([]byte, 8)
+				rand.Read(id)
+				req = req.Clone(req.Context())
+				req.Header.Set("X-Request-ID", hex.EncodeToString(id))
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+type cachedResponse struct {
+	status  int
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+// Cache keeps successful GET responses in memory for ttl, keyed by URL. Any
+// other method invalidates the cache, since it may have changed BMC state.
+func Cache(ttl time.Duration) Middleware {
+	var mu sync.Mutex
+	entries := make(map[string]cachedResponse)
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			key := req.URL.String()
+			if req.Method != http.MethodGet {
+				mu.Lock()
+				entries = make(map[string]cachedResponse)
+				mu.Unlock()
+				return next.RoundTrip(req)
+			}
+
+			mu.Lock()
+			entry, ok := entries[key]
+			mu.Unlock()
+			if ok && time.Now().Before(entry.expires) {
+				return &http.Response{
+					Status:     http.StatusText(entry.status),
+					StatusCode: entry.status,
+					Header:     entry.header.Clone(),
+					Body:       io.NopCloser(bytes.NewReader(entry.body)),
+					Request:    req,
+				}, nil
+			}
+
+			resp, err := next.RoundTrip(req)
+			if err != nil || resp.StatusCode < 200 || resp.StatusCode >= 300 {
+				return resp, err
+			}
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+
+			mu.Lock()
+			entries[key] = cachedResponse{status: resp.StatusCode, header: resp.Header.Clone(), body: body, expires: time.Now().Add(ttl)}
+			mu.Unlock()
+			return resp, nil
+		})
+	}
+}
+
+// Exchange is a captured request/response pair.
+type Exchange struct {
+	Method         string
+	URL            string
+	RequestHeader  http.Header
+	RequestBody    []byte
+	StatusCode     int
+	ResponseHeader http.Header
+	ResponseBody   []byte
+	Duration       time.Duration
+	TransportError error
+}
+
+// Record hands a copy of every exchange to fn. Bodies are buffered so the
+// caller downstream still sees them intact.
+func Record(fn func(*Exchange)) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			ex := &Exchange{
+				Method:        req.Method,
+				URL:           req.URL.String(),
+				RequestHeader: req.Header.Clone(),
+			}
+			if req.Body != nil {
+				body, err := io.ReadAll(req.Body)
+				req.Body.Close()
+				if err != nil {
+					return nil, err
+				}
+				ex.RequestBody = body
+				req = req.Clone(req.Context())
+				req.Body = io.NopCloser(bytes.NewReader(body))
+				req.GetBody = func() (io.ReadCloser, error) {
+					return io.NopCloser(bytes.NewReader(body)), nil
+				}
+			}
+
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			ex.Duration = time.Since(start)
+			if err != nil {
+				ex.TransportError = err
+				fn(ex)
+				return nil, err
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+			ex.StatusCode = resp.StatusCode
+			ex.ResponseHeader = resp.Header.Clone()
+			ex.ResponseBody = body
+			fn(ex)
+			return resp, nil
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package httpclient
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stubResponse(status int, body string) RoundTripperFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: status,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	}
+}
+
+func TestChainOrder(t *testing.T) {
+	var order []string
+	tag := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	config := DefaultConfig().Use(tag("first"), tag("second"))
+	config.Transport = stubResponse(http.StatusOK, "OK")
+
+	body, err := Request("GET", "https://bmc/redfish/v1", "user", "pass", nil, config)
+	require.NoError(t, err)
+	assert.Equal(t, "OK", string(body))
+	assert.Equal(t, []string{"first", "second"}, order)
+}
+
+func TestUseDoesNotAlias(t *testing.T) {
+	base := DefaultConfig().Use(Logging())
+	a := base.Use(Tracing())
+	b := base.Use(Metrics(func(Observation) {}))
+	assert.Len(t, base.Middlewares, 1)
+	assert.Len(t, a.Middlewares, 2)
+	assert.Len(t, b.Middlewares, 2)
+}
+
+func TestTokenAuth(t *testing.T) {
+	var gotToken, gotAuth string
+	config := DefaultConfig().Use(TokenAuth("abc123"))
+	config.Transport = RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		gotToken = req.Header.Get("X-Auth-Token")
+		gotAuth = req.Header.Get("Authorization")
+		return stubResponse(http.StatusOK, "{}")(req)
+	})
+
+	_, err := Get("https://bmc/redfish/v1", "user", "pass", config)
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", gotToken)
+	assert.Empty(t, gotAuth)
+}
+
+func TestRetry(t *testing.T) {
+	t.Run("Retries server errors", func(t *testing.T) {
+		var calls int32
+		config := DefaultConfig().Use(Retry(3, time.Millisecond))
+		config.Transport = RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				return stubResponse(http.StatusServiceUnavailable, "")(req)
+			}
+			return stubResponse(http.StatusOK, "OK")(req)
+		})
+
+		body, err := Get("https://bmc/redfish/v1", "user", "pass", config)
+		require.NoError(t, err)
+		assert.Equal(t, "OK", string(body))
+		assert.EqualValues(t, 3, calls)
+	})
+
+	t.Run("Replays POST bodies", func(t *testing.T) {
+		var bodies []string
+		config := DefaultConfig().Use(Retry(2, time.Millisecond))
+		config.Transport = RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			b, _ := io.ReadAll(req.Body)
+			bodies = append(bodies, string(b))
+			return stubResponse(http.StatusInternalServerError, "")(req)
+		})
+
+		_, err := Request("POST", "https://bmc/redfish/v1", "user", "pass", bytes.NewBufferString(`{"a":1}`), config)
+		require.Error(t, err)
+		assert.Equal(t, []string{`{"a":1}`, `{"a":1}`}, bodies)
+	})
+
+	t.Run("Does not retry client errors", func(t *testing.T) {
+		var calls int32
+		config := DefaultConfig().Use(Retry(3, time.Millisecond))
+		config.Transport = RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&calls, 1)
+			return stubResponse(http.StatusNotFound, "")(req)
+		})
+
+		_, err := Get("https://bmc/redfish/v1", "user", "pass", config)
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.EqualValues(t, 1, calls)
+	})
+	t.Run("Honors Retry-After", func(t *testing.T) {
+		var calls int32
+		config := DefaultConfig().Use(Retry(2, time.Millisecond))
+		config.Transport = RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			resp, _ := stubResponse(http.StatusTooManyRequests, "")(req)
+			if atomic.AddInt32(&calls, 1) == 1 {
+				resp.Header.Set("Retry-After", "1")
+			} else {
+				resp.StatusCode = http.StatusOK
+			}
+			return resp, nil
+		})
+
+		start := time.Now()
+		_, err := Get("https://bmc/redfish/v1", "user", "pass", config)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), time.Second)
+	})
+}
+
+func TestRateLimit(t *testing.T) {
+	config := DefaultConfig().Use(RateLimit(20 * time.Millisecond))
+	config.Transport = stubResponse(http.StatusOK, "OK")
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		_, err := Get("https://bmc/redfish/v1", "user", "pass", config)
+		require.NoError(t, err)
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 60*time.Millisecond)
+}
+
+func TestCache(t *testing.T) {
+	var calls int32
+	config := DefaultConfig().Use(Cache(time.Minute))
+	config.Transport = RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&calls, 1)
+		return stubResponse(http.StatusOK, "OK")(req)
+	})
+
+	for i := 0; i < 3; i++ {
+		body, err := Get("https://bmc/redfish/v1", "user", "pass", config)
+		require.NoError(t, err)
+		assert.Equal(t, "OK", string(body))
+	}
+	assert.EqualValues(t, 1, calls)
+
+	_, err := Request("POST", "https://bmc/redfish/v1", "user", "pass", bytes.NewBufferString("{}"), config)
+	require.NoError(t, err)
+	_, err = Get("https://bmc/redfish/v1", "user", "pass", config)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, calls)
+}
+
+func TestMetricsAndRecord(t *testing.T) {
+	var observations []Observation
+	var exchanges []*Exchange
+	config := DefaultConfig().Use(
+		Metrics(func(o Observation) { observations = append(observations, o) }),
+		Record(func(e *Exchange) { exchanges = append(exchanges, e) }),
+	)
+	config.Transport = stubResponse(http.StatusCreated, `{"Id":"1"}`)
+
+	body, err := Request("POST", "https://bmc/redfish/v1/Sessions", "user", "pass", bytes.NewBufferString(`{"UserName":"user"}`), config)
+	require.NoError(t, err)
+	assert.Equal(t, `{"Id":"1"}`, string(body))
+
+	require.Len(t, observations, 1)
+	assert.Equal(t, http.StatusCreated, observations[0].Status)
+	assert.Equal(t, "POST", observations[0].Method)
+
+	require.Len(t, exchanges, 1)
+	assert.Equal(t, `{"UserName":"user"}`, string(exchanges[0].RequestBody))
+	assert.Equal(t, `{"Id":"1"}`, string(exchanges[0].ResponseBody))
+	assert.Equal(t, "https://bmc/redfish/v1/Sessions", exchanges[0].URL)
+}
+
+func TestTracing(t *testing.T) {
+	var id string
+	config := DefaultConfig().Use(Tracing())
+	config.Transport = RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		id = req.Header.Get("X-Request-ID")
+		return stubResponse(http.StatusOK, "")(req)
+	})
+
+	_, err := Get("https://bmc/redfish/v1", "user", "pass", config)
+	require.NoError(t, err)
+	assert.Len(t, id, 16)
+}
@@ -0,0 +1,74 @@
+package httpclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// SessionsPath is the standard location of the Redfish session collection.
+const SessionsPath = "/redfish/v1/SessionService/Sessions"
+
+// Session is an authenticated Redfish SessionService session. Using one
+// token for a whole run avoids re-authenticating on every request, which
+// many BMCs rate-limit.
+type Session struct {
+	// Token is the X-Auth-Token returned by the BMC.
+	Token string
+	// Location is the URL of the session resource, used to log out.
+	Location string
+
+	config Config
+}
+
+// Login opens a session on the BMC at baseURL (e.g. https://10.0.0.1).
+func Login(baseURL, username, password string, config Config) (*Session, error) {
+	payload, err := json.Marshal(map[string]string{"UserName": username, "Password": password})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(config.context(), "POST", strings.TrimSuffix(baseURL, "/")+SessionsPath, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	httpClient := &http.Client{Timeout: config.Timeout, Transport: config.RoundTripper()}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if err := ResponseError(resp.StatusCode, body); err != nil {
+		return nil, at(err, req.URL.Path)
+	}
+	token := resp.Header.Get("X-Auth-Token")
+	if token == "" {
+		return nil, fmt.Errorf("session created without an X-Auth-Token header")
+	}
+
+	location := resp.Header.Get("Location")
+	if location != "" && !strings.HasPrefix(location, "http") {
+		location = strings.TrimSuffix(baseURL, "/") + location
+	}
+	return &Session{Token: token, Location: location, config: config}, nil
+}
+
+// Middleware authenticates every request with the session token.
+func (s *Session) Middleware() Middleware {
+	return TokenAuth(s.Token)
+}
+
+// Logout deletes the session on the BMC.
+func (s *Session) Logout() error {
+	if s.Location == "" {
+		return nil
+	}
+	_, err := Request("DELETE", s.Location, "", "", nil, s.config.Use(s.Middleware()))
+	return err
+}
@@ -2,11 +2,15 @@ package idrac
 
 import (
 	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/angelhvargas/redfishcli/pkg/client"
 	"github.com/angelhvargas/redfishcli/pkg/config"
 	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+	"github.com/angelhvargas/redfishcli/pkg/inventory"
 	"github.com/angelhvargas/redfishcli/pkg/model"
+	"github.com/angelhvargas/redfishcli/pkg/registry"
 	"github.com/angelhvargas/redfishcli/pkg/request"
 )
 
@@ -14,20 +18,65 @@ import (
 type Client struct {
 	Config           config.IDRACConfig
 	HTTPClientConfig httpclient.Config
+
+	registriesOnce sync.Once
+	registries     *registry.Cache
 }
 
 // NewClient initializes a new iDRAC client with default HTTP client configuration.
 func NewClient(cfg config.IDRACConfig) *Client {
 	return &Client{
 		Config:           cfg,
-		HTTPClientConfig: httpclient.DefaultConfig(),
+		HTTPClientConfig: cfg.HTTPClientConfig(),
+	}
+}
+
+// endpoint turns a Redfish URI such as an @odata.id into an absolute URL on this BMC.
+func (c *Client) endpoint(uri string) string {
+	if strings.HasPrefix(uri, "https://") || strings.HasPrefix(uri, "http://") {
+		return uri
 	}
+	return fmt.Sprintf("https://%s%s", c.Config.Hostname, uri)
+}
+
+func (c *Client) fetch(uri string, target interface{}) error {
+	return request.FetchAndUnmarshal(c.endpoint(uri), c.Config.Username, c.Config.Password, c.HTTPClientConfig, target)
+}
+
+// Fetch reads any Redfish resource of the iDRAC into target.
+func (c *Client) Fetch(uri string, target interface{}) error {
+	return c.fetch(uri, target)
+}
+
+// Messages resolves MessageIds with the bundled registries and those the
+// iDRAC hosts, which are fetched once, when first needed.
+func (c *Client) Messages() *registry.Cache {
+	c.registriesOnce.Do(func() { c.registries = registry.NewCache(c) })
+	return c.registries
+}
+
+var (
+	_ client.FullClient            = (*Client)(nil)
+	_ client.CapabilityProber      = (*Client)(nil)
+	_ client.AllowableValuesReader = (*Client)(nil)
+	_ inventory.Source             = (*Client)(nil)
+	_ inventory.Normalizer         = (*Client)(nil)
+)
+
+// Capabilities probes the iDRAC for the features it exposes.
+func (c *Client) Capabilities() (*client.Capabilities, error) {
+	return client.ProbeCapabilities(c.fetch, "/redfish/v1/Systems/System.Embedded.1", "/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Entries")
+}
+
+// AllowableValues reads the reset types and boot targets the iDRAC accepts.
+func (c *Client) AllowableValues() (*client.AllowableValues, error) {
+	return client.ReadAllowableValues(c.fetch, "/redfish/v1/Systems/System.Embedded.1")
 }
 
 // GetServerInfo retrieves the server information from iDRAC.
-func (c *Client) GetServerInfo() (*model.ServerInfo, error) {
+func (c *Client) GetServerInfo() (*model.ComputerSystem, error) {
 	url := fmt.Sprintf("https://%s/redfish/v1/Systems/System.Embedded.1", c.Config.Hostname)
-	var info model.ServerInfo
+	var info model.ComputerSystem
 	if err := request.FetchAndUnmarshal(url, c.Config.Username, c.Config.Password, c.HTTPClientConfig, &info); err != nil {
 		return nil, err
 	}
@@ -35,9 +84,9 @@ func (c *Client) GetServerInfo() (*model.ServerInfo, error) {
 }
 
 // GetStorageInfo retrieves the storage information from iDRAC.
-func (c *Client) GetStorageInfo() (*model.StorageInfo, error) {
+func (c *Client) GetStorageInfo() (*model.Collection[model.IDRef], error) {
 	url := fmt.Sprintf("https://%s/redfish/v1/Systems/System.Embedded.1/Storage", c.Config.Hostname)
-	var info model.StorageInfo
+	var info model.Collection[model.IDRef]
 	if err := request.FetchAndUnmarshal(url, c.Config.Username, c.Config.Password, c.HTTPClientConfig, &info); err != nil {
 		return nil, err
 	}
@@ -47,7 +96,7 @@ func (c *Client) GetStorageInfo() (*model.StorageInfo, error) {
 // GetDrivesInfo retrieves information for all drives from iDRAC.
 func (c *Client) GetDrivesInfo() ([]model.Drive, error) {
 	url := fmt.Sprintf("https://%s/redfish/v1/Systems/System.Embedded.1/Storage", c.Config.Hostname)
-	var storageCollection model.StorageCollection
+	var storageCollection model.Collection[model.IDRef]
 	if err := request.FetchAndUnmarshal(url, c.Config.Username, c.Config.Password, c.HTTPClientConfig, &storageCollection); err != nil {
 		return nil, err
 	}
@@ -55,13 +104,13 @@ func (c *Client) GetDrivesInfo() ([]model.Drive, error) {
 	var drives []model.Drive
 	for _, member := range storageCollection.Members {
 		var storage model.Storage
-		if err := request.FetchAndUnmarshal(member.ID, c.Config.Username, c.Config.Password, c.HTTPClientConfig, &storage); err != nil {
+		if err := request.FetchAndUnmarshal(c.endpoint(member.ODataID), c.Config.Username, c.Config.Password, c.HTTPClientConfig, &storage); err != nil {
 			return nil, err
 		}
 
 		for _, driveRef := range storage.Drives {
 			var drive model.Drive
-			if err := request.FetchAndUnmarshal(driveRef.ID, c.Config.Username, c.Config.Password, c.HTTPClientConfig, &drive); err != nil {
+			if err := request.FetchAndUnmarshal(c.endpoint(driveRef.ODataID), c.Config.Username, c.Config.Password, c.HTTPClientConfig, &drive); err != nil {
 				return nil, err
 			}
 			drives = append(drives, drive)
@@ -74,23 +123,23 @@ func (c *Client) GetDrivesInfo() ([]model.Drive, error) {
 // GetStorageControllers retrieves RAID controller information from iDRAC.
 func (c *Client) GetStorageControllers(config *model.StorageControllerConfig) ([]model.StorageController, error) {
 	url := fmt.Sprintf("https://%s/redfish/v1/Systems/System.Embedded.1/Storage", c.Config.Hostname)
-	var storageResp model.StorageCollection
+	var storageResp model.Collection[model.IDRef]
 	if err := request.FetchAndUnmarshal(url, c.Config.Username, c.Config.Password, c.HTTPClientConfig, &storageResp); err != nil {
 		return nil, err
 	}
 
 	var StorageControllers []model.StorageController
 	for _, member := range storageResp.Members {
-		StorageControllers = append(StorageControllers, model.StorageController{ID: member.ID})
+		StorageControllers = append(StorageControllers, model.StorageController{ODataID: member.ODataID})
 	}
 
 	return StorageControllers, nil
 }
 
 // GetStorageControllerInfo retrieves detailed information for a specific RAID controller.
-func (c *Client) GetStorageControllerInfo(controllerID string) (*model.StorageControllerDetails, error) {
-	url := fmt.Sprintf("https://%s%s", c.Config.Hostname, controllerID)
-	var raidControllerDetails model.StorageControllerDetails
+func (c *Client) GetStorageControllerInfo(controllerID string) (*model.Storage, error) {
+	url := c.endpoint(controllerID)
+	var raidControllerDetails model.Storage
 	if err := request.FetchAndUnmarshal(url, c.Config.Username, c.Config.Password, c.HTTPClientConfig, &raidControllerDetails); err != nil {
 		return nil, err
 	}
@@ -98,9 +147,9 @@ func (c *Client) GetStorageControllerInfo(controllerID string) (*model.StorageCo
 }
 
 // GetRAIDVolumeInfo retrieves information for a specific RAID volume.
-func (c *Client) GetRAIDVolumeInfo(volumeEndpoint string) (*model.RAIDVolume, error) {
-	var volume model.RAIDVolume
-	if err := request.FetchAndUnmarshal(volumeEndpoint, c.Config.Username, c.Config.Password, c.HTTPClientConfig, &volume); err != nil {
+func (c *Client) GetRAIDVolumeInfo(volumeEndpoint string) (*model.Volume, error) {
+	var volume model.Volume
+	if err := request.FetchAndUnmarshal(c.endpoint(volumeEndpoint), c.Config.Username, c.Config.Password, c.HTTPClientConfig, &volume); err != nil {
 		return nil, err
 	}
 	return &volume, nil
@@ -109,7 +158,7 @@ func (c *Client) GetRAIDVolumeInfo(volumeEndpoint string) (*model.RAIDVolume, er
 // GetStorageDriveDetails retrieves detailed information for a specific drive.
 func (c *Client) GetStorageDriveDetails(driveURL string) (*model.Drive, error) {
 	var drive model.Drive
-	url := fmt.Sprintf("https://%s%s", c.Config.Hostname, driveURL)
+	url := c.endpoint(driveURL)
 	if err := request.FetchAndUnmarshal(url, c.Config.Username, c.Config.Password, c.HTTPClientConfig, &drive); err != nil {
 		return nil, err
 	}
@@ -122,16 +171,21 @@ func (c *Client) GetPowerState() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return info.PowerState, nil
+	return string(info.PowerState), nil
 }
 
 // SetPowerState sets the power state of the server (On, ForceOff, GracefulShutdown).
 func (c *Client) SetPowerState(state string) error {
+	if allowed, err := c.AllowableValues(); err == nil {
+		if err := client.Validate("ResetType", state, allowed.ResetTypes); err != nil {
+			return err
+		}
+	}
 	url := fmt.Sprintf("https://%s/redfish/v1/Systems/System.Embedded.1/Actions/ComputerSystem.Reset", c.Config.Hostname)
 	payload := map[string]string{
 		"ResetType": state,
 	}
-	return request.Post(url, c.Config.Username, c.Config.Password, c.HTTPClientConfig, payload)
+	return c.Messages().ResolveError(request.Post(url, c.Config.Username, c.Config.Password, c.HTTPClientConfig, payload))
 }
 
 // Reboot reboots the server (GracefulRestart).
@@ -140,34 +194,40 @@ func (c *Client) Reboot() error {
 }
 
 // GetBootInfo retrieves the boot information.
-func (c *Client) GetBootInfo() (*model.BootInfo, error) {
+func (c *Client) GetBootInfo() (*model.Boot, error) {
 	url := fmt.Sprintf("https://%s/redfish/v1/Systems/System.Embedded.1", c.Config.Hostname)
-	var info model.BootInfo
-	if err := request.FetchAndUnmarshal(url, c.Config.Username, c.Config.Password, c.HTTPClientConfig, &info); err != nil {
+	var system model.ComputerSystem
+	if err := request.FetchAndUnmarshal(url, c.Config.Username, c.Config.Password, c.HTTPClientConfig, &system); err != nil {
 		return nil, err
 	}
-	return &info, nil
+	return &system.Boot, nil
 }
 
 // SetBootOrder sets the boot order (e.g., PxE, Hdd, Cd).
 func (c *Client) SetBootOrder(device string) error {
+	if allowed, err := c.AllowableValues(); err == nil {
+		if err := client.Validate("BootSourceOverrideTarget", device, allowed.BootTargets); err != nil {
+			return err
+		}
+	}
 	url := fmt.Sprintf("https://%s/redfish/v1/Systems/System.Embedded.1", c.Config.Hostname)
 	payload := map[string]interface{}{
 		"Boot": map[string]string{
 			"BootSourceOverrideTarget": device,
 		},
 	}
-	return request.Post(url, c.Config.Username, c.Config.Password, c.HTTPClientConfig, payload)
+	return c.Messages().ResolveError(request.Patch(url, c.Config.Username, c.Config.Password, c.HTTPClientConfig, payload))
 }
 
 // GetSystemEventLog retrieves the system event log.
-func (c *Client) GetSystemEventLog() ([]model.EventLogEntry, error) {
-	url := fmt.Sprintf("https://%s/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/ Sel/Entries", c.Config.Hostname)
-	var log model.EventLog
-	if err := request.FetchAndUnmarshal(url, c.Config.Username, c.Config.Password, c.HTTPClientConfig, &log); err != nil {
-		return nil, err
+func (c *Client) GetSystemEventLog() ([]model.LogEntry, error) {
+	url := fmt.Sprintf("https://%s/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Entries", c.Config.Hostname)
+	entries, err := request.FetchMembers[model.LogEntry](url, c.Config.Username, c.Config.Password, c.HTTPClientConfig)
+	if err != nil {
+		return nil, c.Messages().ResolveError(err)
 	}
-	return log.Members, nil
+	c.Messages().ResolveEntries(entries)
+	return entries, nil
 }
 
 func init() {
@@ -175,3 +235,17 @@ func init() {
 		return NewClient(config.IDRACConfig{BMCConnConfig: cfg})
 	})
 }
+
+// NormalizeInventory strips the prefixes iDRAC puts on sensor member IDs,
+// "0x17||Fan.Embedded.1" and "iDRAC.Embedded.1#SystemBoardInletTemp", so
+// they read like the IDs of other vendors.
+func (c *Client) NormalizeInventory(inv *inventory.Inventory) {
+	for i := range inv.Fans {
+		if _, id, ok := strings.Cut(inv.Fans[i].ID, "||"); ok {
+			inv.Fans[i].ID = id
+		}
+	}
+	for i := range inv.Temperatures {
+		inv.Temperatures[i].ID = strings.TrimPrefix(inv.Temperatures[i].ID, "iDRAC.Embedded.1#")
+	}
+}
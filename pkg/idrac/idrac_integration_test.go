@@ -10,22 +10,33 @@ import (
 	"testing"
 
 	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/angelhvargas/redfishcli/pkg/emulator"
 	"github.com/angelhvargas/redfishcli/pkg/httpclient"
 	"github.com/angelhvargas/redfishcli/pkg/model"
 	"github.com/stretchr/testify/require"
 )
 
+// newTestIDRACClient targets the BMC named by BMC_HOSTNAME, or an in-process
+// iDRAC emulator when it is not set.
 func newTestIDRACClient(t *testing.T) *Client {
 	hostname := os.Getenv("BMC_HOSTNAME")
+	username := os.Getenv("BMC_USERNAME")
+	password := os.Getenv("BMC_PASSWORD")
 	if hostname == "" {
-		t.Skip("BMC_HOSTNAME environment variable not set")
+		username, password = "root", "calvin"
+		emu, err := emulator.New(emulator.IDRAC(), emulator.Options{Username: username, Password: password})
+		require.NoError(t, err)
+		srv, err := emu.Start("127.0.0.1:0", emulator.StartOptions{})
+		require.NoError(t, err)
+		t.Cleanup(func() { srv.Close() })
+		hostname = srv.Host
 	}
 
 	client := NewClient(config.IDRACConfig{
 		BMCConnConfig: config.BMCConnConfig{
 			Hostname: hostname,
-			Username: os.Getenv("BMC_USERNAME"),
-			Password: os.Getenv("BMC_PASSWORD"),
+			Username: username,
+			Password: password,
 		},
 	})
 	client.HTTPClientConfig = httpclient.DefaultConfig()
@@ -41,8 +52,8 @@ func TestIDRACClientIntegration(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, serverInfo)
 
-		require.Equal(t, "On", serverInfo.PowerState, "Server is not powered on")
-		require.Equal(t, "OK", serverInfo.Status.Health, "Server health is not OK")
+		require.Equal(t, model.PowerStateOn, serverInfo.PowerState, "Server is not powered on")
+		require.Equal(t, model.HealthOK, serverInfo.Status.Health, "Server health is not OK")
 	})
 
 	t.Run("GetStorageInfo", func(t *testing.T) {
@@ -59,7 +70,7 @@ func TestIDRACClientIntegration(t *testing.T) {
 		// Iterate through each drive and assert expected conditions
 		for _, drive := range drives {
 			require.NotEmpty(t, drive.ID, "Drive ID is empty")
-			require.Equal(t, "OK", drive.Status.Health, "Drive health is not OK")
+			require.Equal(t, model.HealthOK, drive.Status.Health, "Drive health is not OK")
 		}
 	})
 }
@@ -76,31 +87,37 @@ func TestIDRACClientIntegration_GetRAIDInfo(t *testing.T) {
 		require.NotEmpty(t, controllers, "No RAID controllers found")
 
 		for _, controller := range controllers {
-			require.NotEmpty(t, controller.ID, "RAID controller ID is empty")
+			require.NotEmpty(t, controller.ODataID, "RAID controller ID is empty")
 
 			// Add assertions for other fields in the controller if necessary
 
 			t.Run("GetStorageControllerInfo", func(t *testing.T) {
-				controllerInfo, err := client.GetStorageControllerInfo(controller.ID)
+				controllerInfo, err := client.GetStorageControllerInfo(controller.ODataID)
 				require.NoError(t, err)
 				require.NotNil(t, controllerInfo)
 
 				t.Run("GetStorageDriveDetails", func(t *testing.T) {
 					for _, driveRef := range controllerInfo.Drives {
-						drive, err := client.GetStorageDriveDetails(driveRef.ID)
+						drive, err := client.GetStorageDriveDetails(driveRef.ODataID)
 						require.NoError(t, err)
 						require.NotNil(t, drive)
-						require.Equal(t, "OK", drive.Status.Health, "RAID drive health is not OK")
+						require.Equal(t, model.HealthOK, drive.Status.Health, "RAID drive health is not OK")
 					}
 				})
 			})
 
 			t.Run("GetRAIDVolumeInfo", func(t *testing.T) {
-				for _, volumeRef := range controller.Volumes {
-					volume, err := client.GetRAIDVolumeInfo(volumeRef.ID)
+				controllerInfo, err := client.GetStorageControllerInfo(controller.ODataID)
+				require.NoError(t, err)
+				for _, driveRef := range controllerInfo.Drives {
+					drive, err := client.GetStorageDriveDetails(driveRef.ODataID)
 					require.NoError(t, err)
-					require.NotNil(t, volume)
-					require.Equal(t, "OK", volume.Status.Health, "RAID volume health is not OK")
+					for _, volumeRef := range drive.Links.Volumes {
+						volume, err := client.GetRAIDVolumeInfo(volumeRef.ODataID)
+						require.NoError(t, err)
+						require.NotNil(t, volume)
+						require.Equal(t, model.HealthOK, volume.Status.Health, "RAID volume health is not OK")
+					}
 				}
 			})
 		}
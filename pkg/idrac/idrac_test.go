@@ -1,23 +1,22 @@
 package idrac
 
 import (
+	"bytes"
 	"errors"
+	"io"
+	"net/http"
+	"sync"
 	"testing"
 
+	"github.com/angelhvargas/redfishcli/pkg/client"
 	"github.com/angelhvargas/redfishcli/pkg/config"
 	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+	"github.com/angelhvargas/redfishcli/pkg/model"
+	"github.com/angelhvargas/redfishcli/pkg/registry"
 	"github.com/stretchr/testify/assert"
 )
 
-func mockDoRequest(mockFunc func(url, username, password string, config httpclient.Config) ([]byte, error)) {
-	httpclient.DoRequest = mockFunc
-}
-
-func restoreDoRequest() {
-	httpclient.DoRequest = httpclient.DoRequest
-}
-
-func TestGetServerInfo(t *testing.T) {
+func newTestClient(transport http.RoundTripper) *Client {
 	client := NewClient(config.IDRACConfig{
 		BMCConnConfig: config.BMCConnConfig{
 			Hostname: "testhost",
@@ -25,14 +24,24 @@ func TestGetServerInfo(t *testing.T) {
 			Password: "pass",
 		},
 	})
+	client.HTTPClientConfig.Transport = transport
+	return client
+}
 
-	defer restoreDoRequest()
+func respondWith(status int, body string) http.RoundTripper {
+	return httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: status,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+			Request:    req,
+		}, nil
+	})
+}
 
+func TestGetServerInfo(t *testing.T) {
 	t.Run("success case", func(t *testing.T) {
-		mockResponse := []byte(`{"ID": "test-server"}`)
-		mockDoRequest(func(url, username, password string, config httpclient.Config) ([]byte, error) {
-			return mockResponse, nil
-		})
+		client := newTestClient(respondWith(http.StatusOK, `{"ID": "test-server"}`))
 
 		result, err := client.GetServerInfo()
 
@@ -41,9 +50,9 @@ func TestGetServerInfo(t *testing.T) {
 	})
 
 	t.Run("error in fetchAndUnmarshal", func(t *testing.T) {
-		mockDoRequest(func(url, username, password string, config httpclient.Config) ([]byte, error) {
+		client := newTestClient(httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
 			return nil, errors.New("request error")
-		})
+		}))
 
 		result, err := client.GetServerInfo()
 
@@ -51,3 +60,150 @@ func TestGetServerInfo(t *testing.T) {
 		assert.Nil(t, result)
 	})
 }
+
+const systemWithAllowableValues = `{
+	"Id": "System.Embedded.1",
+	"Boot": {"BootSourceOverrideTarget": "None", "BootSourceOverrideTarget@Redfish.AllowableValues": ["None", "Pxe", "Hdd"]},
+	"Actions": {"#ComputerSystem.Reset": {"ResetType@Redfish.AllowableValues": ["On", "ForceOff", "GracefulRestart"]}}
+}`
+
+func TestSetPowerState(t *testing.T) {
+	var gotMethod, gotPath, gotBody string
+	client := newTestClient(httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodGet {
+			return respondWith(http.StatusOK, systemWithAllowableValues).RoundTrip(req)
+		}
+		gotMethod = req.Method
+		gotPath = req.URL.Path
+		body, _ := io.ReadAll(req.Body)
+		gotBody = string(body)
+		return respondWith(http.StatusNoContent, "").RoundTrip(req)
+	}))
+
+	err := client.SetPowerState("ForceOff")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodPost, gotMethod)
+	assert.Equal(t, "/redfish/v1/Systems/System.Embedded.1/Actions/ComputerSystem.Reset", gotPath)
+	assert.JSONEq(t, `{"ResetType": "ForceOff"}`, gotBody)
+}
+
+func TestSetPowerStateRejectsInvalidValues(t *testing.T) {
+	var posted bool
+	c := newTestClient(httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodGet {
+			posted = true
+		}
+		return respondWith(http.StatusOK, systemWithAllowableValues).RoundTrip(req)
+	}))
+
+	err := c.SetPowerState("Forceoff")
+	assert.ErrorIs(t, err, client.ErrInvalidValue)
+	assert.EqualError(t, err, `invalid ResetType "Forceoff": did you mean "ForceOff"? (allowed: ForceOff, GracefulRestart, On)`)
+
+	err = c.SetBootOrder("PXE")
+	assert.EqualError(t, err, `invalid BootSourceOverrideTarget "PXE": did you mean "Pxe"? (allowed: Hdd, None, Pxe)`)
+	assert.False(t, posted)
+}
+
+func TestMessagesSharedCache(t *testing.T) {
+	client := &Client{Config: config.IDRACConfig{BMCConnConfig: config.BMCConnConfig{Hostname: "testhost"}}}
+
+	var wg sync.WaitGroup
+	caches := make([]*registry.Cache, 4)
+	for i := range caches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			caches[i] = client.Messages()
+		}()
+	}
+	wg.Wait()
+	for _, c := range caches {
+		assert.Same(t, caches[0], c, "every call resolves with the one cache")
+	}
+}
+
+func TestClientHonorsDeprecatedGlobals(t *testing.T) {
+	oldDoRequest, oldDo := httpclient.DoRequest, httpclient.Do
+	t.Cleanup(func() { httpclient.DoRequest, httpclient.Do = oldDoRequest, oldDo })
+	httpclient.DoRequest = func(url, username, password string, config httpclient.Config) ([]byte, error) {
+		return nil, errors.New("global DoRequest called")
+	}
+	httpclient.Do = func(method, url, username, password string, body io.Reader, config httpclient.Config) ([]byte, error) {
+		return nil, errors.New("global Do called")
+	}
+	client := newTestClient(respondWith(http.StatusOK, `{"Id": "System.Embedded.1"}`))
+
+	_, err := client.GetServerInfo()
+	assert.ErrorContains(t, err, "global DoRequest called")
+	err = client.SetPowerState("On")
+	assert.ErrorContains(t, err, "global Do called")
+}
+
+func TestGetBootInfo(t *testing.T) {
+	client := newTestClient(respondWith(http.StatusOK, `{"Id": "System.Embedded.1", "Boot": {"BootSourceOverrideTarget": "Pxe", "BootSourceOverrideEnabled": "Once", "BootOrder": ["NIC.1", "RAID.1"]}}`))
+
+	info, err := client.GetBootInfo()
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.BootSourcePxe, info.BootSourceOverrideTarget)
+	assert.Equal(t, model.BootSourceOverrideEnabledOnce, info.BootSourceOverrideEnabled)
+	assert.Equal(t, []string{"NIC.1", "RAID.1"}, info.BootOrder)
+}
+
+func TestSetBootOrder(t *testing.T) {
+	var gotMethod, gotPath, gotBody string
+	client := newTestClient(httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodGet {
+			// The allowable boot targets are read first.
+			return respondWith(http.StatusOK, "{}").RoundTrip(req)
+		}
+		gotMethod = req.Method
+		gotPath = req.URL.Path
+		body, _ := io.ReadAll(req.Body)
+		gotBody = string(body)
+		return respondWith(http.StatusOK, "{}").RoundTrip(req)
+	}))
+
+	err := client.SetBootOrder("Pxe")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodPatch, gotMethod, "the boot override is a property of the system")
+	assert.Equal(t, "/redfish/v1/Systems/System.Embedded.1", gotPath)
+	assert.JSONEq(t, `{"Boot": {"BootSourceOverrideTarget": "Pxe"}}`, gotBody)
+}
+
+func TestGetSystemEventLog(t *testing.T) {
+	var gotPath string
+	client := newTestClient(httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		gotPath = req.URL.Path
+		return respondWith(http.StatusOK, `{"Members": [{"Id": "1", "Message": "The chassis is open."}]}`).RoundTrip(req)
+	}))
+
+	entries, err := client.GetSystemEventLog()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Entries", gotPath)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "The chassis is open.", entries[0].Message)
+}
+
+func TestGetStorageDriveDetails(t *testing.T) {
+	for _, uri := range []string{
+		"/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1/Drives/Disk.Bay.0",
+		"https://testhost/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1/Drives/Disk.Bay.0",
+	} {
+		var gotURL string
+		client := newTestClient(httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			gotURL = req.URL.String()
+			return respondWith(http.StatusOK, `{"Id": "Disk.Bay.0"}`).RoundTrip(req)
+		}))
+
+		drive, err := client.GetStorageDriveDetails(uri)
+
+		assert.NoError(t, err, uri)
+		assert.Equal(t, "Disk.Bay.0", drive.ID, uri)
+		assert.Equal(t, "https://testhost/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1/Drives/Disk.Bay.0", gotURL, uri)
+	}
+}
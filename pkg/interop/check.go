@@ -0,0 +1,596 @@
+package interop
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Outcome is the result of checking one requirement.
+type Outcome string
+
+const (
+	Pass Outcome = "pass"
+	Fail Outcome = "fail"
+	// Warn is a Recommended requirement that is not met.
+	Warn Outcome = "warn"
+	// Skip is a requirement that does not apply, such as an IfImplemented
+	// property the service does not implement, or that cannot be verified
+	// from what the service reports.
+	Skip Outcome = "skip"
+)
+
+// Checks name what a Finding verified.
+const (
+	CheckRead       = "ReadRequirement"
+	CheckMinVersion = "MinVersion"
+	CheckMinCount   = "MinCount"
+	CheckValues     = "Values"
+	CheckMinSupport = "MinSupportValues"
+	CheckAction     = "ActionRequirement"
+	CheckParameter  = "Parameter"
+)
+
+// Finding is the outcome of one requirement for one resource instance.
+type Finding struct {
+	// Resource is the resource type, e.g. "ComputerSystem".
+	Resource string `json:"resource" yaml:"resource"`
+	URI      string `json:"uri,omitempty" yaml:"uri,omitempty"`
+	// Property is the path of the property inside the resource, e.g.
+	// "Status/Health" or "Actions/#ComputerSystem.Reset/ResetType".
+	Property    string      `json:"property,omitempty" yaml:"property,omitempty"`
+	Check       string      `json:"check" yaml:"check"`
+	Requirement Requirement `json:"requirement,omitempty" yaml:"requirement,omitempty"`
+	Outcome     Outcome     `json:"outcome" yaml:"outcome"`
+	Message     string      `json:"message" yaml:"message"`
+}
+
+// Report is the outcome of checking a service against a profile.
+type Report struct {
+	Profile  string    `json:"profile" yaml:"profile"`
+	Passed   int       `json:"passed" yaml:"passed"`
+	Failed   int       `json:"failed" yaml:"failed"`
+	Warnings int       `json:"warnings" yaml:"warnings"`
+	Skipped  int       `json:"skipped" yaml:"skipped"`
+	Findings []Finding `json:"findings" yaml:"findings"`
+}
+
+func (r *Report) add(f Finding) {
+	switch f.Outcome {
+	case Pass:
+		r.Passed++
+	case Fail:
+		r.Failed++
+	case Warn:
+		r.Warnings++
+	case Skip:
+		r.Skipped++
+	}
+	r.Findings = append(r.Findings, f)
+}
+
+// Service is the resources of a Redfish service keyed by URI, as collected
+// by mockup.Crawl or read by emulator.LoadMockup.
+type Service map[string]map[string]interface{}
+
+const serviceRoot = "/redfish/v1"
+
+// Check evaluates every requirement of the profile against the service.
+// Resource types are checked in name order and their instances in URI
+// order, so reports of the same service are identical.
+func Check(p *Profile, svc Service) *Report {
+	c := &checker{svc: svc, types: make(map[string]string), report: &Report{Profile: p.Name(), Findings: []Finding{}}}
+	instances := make(map[string][]string)
+	for uri, doc := range svc {
+		name, _ := resourceType(doc)
+		if name == "" {
+			continue
+		}
+		c.types[uri] = name
+		instances[name] = append(instances[name], uri)
+	}
+
+	if p.Protocol != nil && p.Protocol.MinVersion != "" {
+		c.protocol(p.Protocol.MinVersion)
+	}
+
+	names := make([]string, 0, len(p.Resources))
+	for name := range p.Resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		uris := instances[name]
+		sort.Strings(uris)
+		c.resource(name, p.Resources[name], uris)
+	}
+	return c.report
+}
+
+type checker struct {
+	svc Service
+	// types maps a URI to the resource type found there.
+	types  map[string]string
+	report *Report
+}
+
+func (c *checker) protocol(minVersion string) {
+	f := Finding{Resource: "ServiceRoot", URI: serviceRoot, Property: "RedfishVersion", Check: CheckMinVersion, Requirement: Mandatory}
+	version, _ := c.svc[serviceRoot]["RedfishVersion"].(string)
+	switch {
+	case version == "":
+		f.Outcome, f.Message = Fail, "the service root does not report RedfishVersion"
+	case compareVersions(version, minVersion) < 0:
+		f.Outcome, f.Message = Fail, fmt.Sprintf("Redfish %s is older than %s", version, minVersion)
+	default:
+		f.Outcome, f.Message = Pass, fmt.Sprintf("Redfish %s", version)
+	}
+	c.report.add(f)
+}
+
+func (c *checker) resource(name string, req ResourceRequirement, uris []string) {
+	read := orMandatory(req.ReadRequirement)
+	f := Finding{Resource: name, Check: CheckRead, Requirement: read}
+	if len(uris) == 0 {
+		f.Outcome = missing(read)
+		f.Message = fmt.Sprintf("no %s resource found", name)
+		c.report.add(f)
+		return
+	}
+	f.Outcome, f.Message = Pass, fmt.Sprintf("%d found", len(uris))
+	c.report.add(f)
+
+	for _, uri := range uris {
+		doc := c.svc[uri]
+		if req.MinVersion != "" {
+			c.minVersion(name, uri, doc, req.MinVersion)
+		}
+		c.properties(name, uri, doc, doc, "", req.PropertyRequirements)
+		c.actions(name, uri, doc, req.ActionRequirements)
+	}
+}
+
+func (c *checker) minVersion(name, uri string, doc map[string]interface{}, minVersion string) {
+	f := Finding{Resource: name, URI: uri, Check: CheckMinVersion, Requirement: Mandatory}
+	_, version := resourceType(doc)
+	switch {
+	case version == "":
+		f.Outcome, f.Message = Skip, "the resource has no schema version"
+	case compareVersions(version, minVersion) < 0:
+		f.Outcome, f.Message = Fail, fmt.Sprintf("schema version %s is older than %s", version, minVersion)
+	default:
+		f.Outcome, f.Message = Pass, fmt.Sprintf("schema version %s", version)
+	}
+	c.report.add(f)
+}
+
+func (c *checker) properties(resource, uri string, doc, obj map[string]interface{}, prefix string, reqs map[string]PropertyRequirement) {
+	names := make([]string, 0, len(reqs))
+	for name := range reqs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		c.property(resource, uri, doc, obj, name, join(prefix, name), reqs[name])
+	}
+}
+
+func (c *checker) property(resource, uri string, doc, obj map[string]interface{}, name, propPath string, req PropertyRequirement) {
+	value, present := obj[name]
+	present = present && value != nil
+
+	read := orMandatory(req.ReadRequirement)
+	comparison, values, minCount := req.Comparison, req.Values, req.MinCount
+	matched := false
+	for _, cond := range req.ConditionalRequirements {
+		if !c.matches(cond, uri, doc) {
+			continue
+		}
+		matched = true
+		if cond.ReadRequirement != "" {
+			read = cond.ReadRequirement
+		}
+		if cond.Values != nil {
+			comparison, values = cond.Comparison, cond.Values
+		}
+		if cond.MinCount != nil {
+			minCount = cond.MinCount
+		}
+	}
+	if read == Conditional && !matched {
+		read = IfImplemented
+	}
+
+	finding := func(check string, outcome Outcome, format string, args ...interface{}) {
+		c.report.add(Finding{
+			Resource: resource, URI: uri, Property: propPath, Check: check,
+			Requirement: read, Outcome: outcome, Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	if comparison == Absent {
+		if present {
+			finding(CheckValues, Fail, "must be absent")
+		} else {
+			finding(CheckValues, Pass, "absent")
+		}
+		return
+	}
+	if !present {
+		if outcome := missing(read); outcome == Skip {
+			finding(CheckRead, Skip, "not implemented")
+		} else {
+			finding(CheckRead, outcome, "missing")
+		}
+		return
+	}
+	finding(CheckRead, Pass, "present")
+
+	if minCount != nil {
+		n := count(value)
+		if n < *minCount {
+			finding(CheckMinCount, Fail, "%d found, want at least %d", n, *minCount)
+		} else {
+			finding(CheckMinCount, Pass, "%d found", n)
+		}
+	}
+
+	if len(values) > 0 && comparison != Present {
+		switch ok := c.compare(comparison, value, present, values); {
+		case ok:
+			finding(CheckValues, Pass, "%s is %s %s", describe(value), orAnyOf(comparison), list(values))
+		case read == Recommended:
+			finding(CheckValues, Warn, "%s is not %s %s", describe(value), orAnyOf(comparison), list(values))
+		default:
+			finding(CheckValues, Fail, "%s is not %s %s", describe(value), orAnyOf(comparison), list(values))
+		}
+	}
+
+	if len(req.MinSupportValues) > 0 {
+		allowable, ok := obj[name+"@Redfish.AllowableValues"].([]interface{})
+		if !ok {
+			finding(CheckMinSupport, Skip, "no @Redfish.AllowableValues to check %s against", list(req.MinSupportValues))
+		} else if lacking := missingValues(req.MinSupportValues, allowable); len(lacking) > 0 {
+			finding(CheckMinSupport, Fail, "%s not allowed", list(lacking))
+		} else {
+			finding(CheckMinSupport, Pass, "%s allowed", list(req.MinSupportValues))
+		}
+	}
+
+	if len(req.PropertyRequirements) == 0 {
+		return
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		c.properties(resource, uri, doc, v, propPath, req.PropertyRequirements)
+	case []interface{}:
+		for i, elem := range v {
+			if m, ok := elem.(map[string]interface{}); ok {
+				c.properties(resource, uri, doc, m, join(propPath, strconv.Itoa(i)), req.PropertyRequirements)
+			}
+		}
+	}
+}
+
+func (c *checker) actions(resource, uri string, doc map[string]interface{}, reqs map[string]ActionRequirement) {
+	names := make([]string, 0, len(reqs))
+	for name := range reqs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	actions, _ := doc["Actions"].(map[string]interface{})
+	for _, name := range names {
+		req := reqs[name]
+		read := orMandatory(req.ReadRequirement)
+		key := "#" + resource + "." + name
+		propPath := "Actions/" + key
+		action, ok := actions[key].(map[string]interface{})
+		f := Finding{Resource: resource, URI: uri, Property: propPath, Check: CheckAction, Requirement: read}
+		if !ok {
+			f.Outcome = missing(read)
+			f.Message = "not offered"
+			c.report.add(f)
+			continue
+		}
+		f.Outcome, f.Message = Pass, "offered"
+		c.report.add(f)
+
+		params := make([]string, 0, len(req.Parameters))
+		for param := range req.Parameters {
+			params = append(params, param)
+		}
+		sort.Strings(params)
+		for _, param := range params {
+			c.parameter(resource, uri, propPath+"/"+param, action, param, req.Parameters[param])
+		}
+	}
+}
+
+func (c *checker) parameter(resource, uri, propPath string, action map[string]interface{}, name string, req ParameterRequirement) {
+	read := orMandatory(req.ReadRequirement)
+	f := Finding{Resource: resource, URI: uri, Property: propPath, Check: CheckParameter, Requirement: read}
+	allowable, known, listed := c.allowableValues(action, name)
+	switch {
+	case known && !listed:
+		f.Outcome, f.Message = missing(read), "not accepted by the action"
+	case !known:
+		f.Outcome, f.Message = Skip, "the action advertises neither @Redfish.AllowableValues nor an ActionInfo"
+	case len(allowable) > 0 && len(missingValues(req.ParameterValues, allowable)) > 0:
+		f.Outcome, f.Message = Fail, fmt.Sprintf("%s not allowed", list(missingValues(req.ParameterValues, allowable)))
+	case len(allowable) > 0 && len(missingValues(req.RecommendedValues, allowable)) > 0:
+		f.Outcome, f.Message = Warn, fmt.Sprintf("recommended %s not allowed", list(missingValues(req.RecommendedValues, allowable)))
+	case len(allowable) == 0 && len(req.ParameterValues) > 0:
+		f.Outcome, f.Message = Skip, fmt.Sprintf("no allowable values to check %s against", list(req.ParameterValues))
+	default:
+		f.Outcome, f.Message = Pass, "accepted"
+		if len(allowable) > 0 {
+			f.Message = fmt.Sprintf("allows %s", list(allowable))
+		}
+	}
+	c.report.add(f)
+}
+
+// allowableValues returns the values a parameter accepts, from an
+// annotation on the action or from its ActionInfo. known reports whether
+// the service describes the action's parameters at all, and listed whether
+// it describes this one.
+func (c *checker) allowableValues(action map[string]interface{}, name string) (values []interface{}, known, listed bool) {
+	if v, ok := action[name+"@Redfish.AllowableValues"].([]interface{}); ok {
+		return v, true, true
+	}
+	link, _ := action["@Redfish.ActionInfo"].(string)
+	info, ok := c.svc[cleanURI(link)]
+	if !ok {
+		return nil, false, false
+	}
+	params, _ := info["Parameters"].([]interface{})
+	for _, p := range params {
+		param, _ := p.(map[string]interface{})
+		if param["Name"] == name {
+			v, _ := param["AllowableValues"].([]interface{})
+			return v, true, true
+		}
+	}
+	return nil, true, false
+}
+
+// matches reports whether a conditional requirement applies to the
+// resource at uri.
+func (c *checker) matches(cond ConditionalRequirement, uri string, doc map[string]interface{}) bool {
+	if len(cond.SubordinateToResource) > 0 && !c.subordinate(uri, cond.SubordinateToResource) {
+		return false
+	}
+	if cond.CompareProperty != "" {
+		value, present := lookup(doc, cond.CompareProperty)
+		return c.compare(cond.CompareType, value, present, cond.CompareValues)
+	}
+	return true
+}
+
+// subordinate reports whether the resource types of the ancestors of uri
+// contain chain in order.
+func (c *checker) subordinate(uri string, chain []string) bool {
+	var ancestors []string
+	for p := path.Dir(uri); strings.HasPrefix(p, serviceRoot); p = path.Dir(p) {
+		if t, ok := c.types[p]; ok {
+			ancestors = append([]string{t}, ancestors...)
+		}
+	}
+	i := 0
+	for _, t := range ancestors {
+		if i < len(chain) && t == chain[i] {
+			i++
+		}
+	}
+	return i == len(chain)
+}
+
+func (c *checker) compare(comparison Comparison, value interface{}, present bool, values []interface{}) bool {
+	switch comparison {
+	case Absent:
+		return !present
+	case Present:
+		return present
+	}
+	if !present {
+		return false
+	}
+	switch comparison {
+	case AllOf:
+		return len(missingValues(values, elements(value))) == 0
+	case Equal:
+		return len(values) > 0 && equal(value, values[0])
+	case NotEqual:
+		for _, v := range values {
+			if equal(value, v) {
+				return false
+			}
+		}
+		return true
+	case GreaterThan, GreaterThanOrEqual, LessThan, LessThanOrEqual:
+		got, ok1 := value.(float64)
+		want, ok2 := number(values)
+		if !ok1 || !ok2 {
+			return false
+		}
+		switch comparison {
+		case GreaterThan:
+			return got > want
+		case GreaterThanOrEqual:
+			return got >= want
+		case LessThan:
+			return got < want
+		default:
+			return got <= want
+		}
+	case LinkToResource:
+		link, _ := value.(map[string]interface{})
+		target, _ := link["@odata.id"].(string)
+		return len(values) > 0 && c.types[cleanURI(target)] == fmt.Sprint(values[0])
+	default:
+		for _, v := range elements(value) {
+			for _, want := range values {
+				if equal(v, want) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// resourceType splits an @odata.type such as
+// "#ComputerSystem.v1_5_0.ComputerSystem" into "ComputerSystem" and "1.5.0".
+// Collections and other unversioned types have no version.
+func resourceType(doc map[string]interface{}) (name, version string) {
+	t, _ := doc["@odata.type"].(string)
+	parts := strings.Split(strings.TrimPrefix(t, "#"), ".")
+	if len(parts) < 2 {
+		return "", ""
+	}
+	name = parts[len(parts)-1]
+	if len(parts) == 3 && strings.HasPrefix(parts[1], "v") {
+		version = strings.ReplaceAll(strings.TrimPrefix(parts[1], "v"), "_", ".")
+	}
+	return name, version
+}
+
+// compareVersions compares dotted versions such as "1.6.0" and "1.6";
+// missing components count as zero.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// missing is the outcome of a requirement whose subject is absent.
+func missing(read Requirement) Outcome {
+	switch read {
+	case Mandatory:
+		return Fail
+	case Recommended:
+		return Warn
+	default:
+		return Skip
+	}
+}
+
+func orMandatory(r Requirement) Requirement {
+	if r == "" {
+		return Mandatory
+	}
+	return r
+}
+
+func orAnyOf(c Comparison) Comparison {
+	if c == "" {
+		return AnyOf
+	}
+	return c
+}
+
+// lookup resolves a "/"-separated property path in doc.
+func lookup(doc map[string]interface{}, propPath string) (interface{}, bool) {
+	var v interface{} = doc
+	for _, name := range strings.Split(strings.Trim(propPath, "/"), "/") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[name]; !ok || v == nil {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+func count(v interface{}) int {
+	if list, ok := v.([]interface{}); ok {
+		return len(list)
+	}
+	return 1
+}
+
+// elements returns the elements of an array value, or the value itself.
+func elements(v interface{}) []interface{} {
+	if list, ok := v.([]interface{}); ok {
+		return list
+	}
+	return []interface{}{v}
+}
+
+// missingValues returns the wanted values that are not in have.
+func missingValues(want, have []interface{}) []interface{} {
+	var lacking []interface{}
+	for _, w := range want {
+		found := false
+		for _, h := range have {
+			if equal(w, h) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			lacking = append(lacking, w)
+		}
+	}
+	return lacking
+}
+
+func equal(a, b interface{}) bool {
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+func number(values []interface{}) (float64, bool) {
+	if len(values) == 0 {
+		return 0, false
+	}
+	n, ok := values[0].(float64)
+	return n, ok
+}
+
+func list(values []interface{}) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprint(v)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func describe(v interface{}) string {
+	if values, ok := v.([]interface{}); ok {
+		return "[" + list(values) + "]"
+	}
+	return fmt.Sprint(v)
+}
+
+func join(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "/" + name
+}
+
+func cleanURI(uri string) string {
+	if i := strings.IndexByte(uri, '#'); i >= 0 {
+		uri = uri[:i]
+	}
+	return strings.TrimSuffix(uri, "/")
+}
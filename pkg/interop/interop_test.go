@@ -0,0 +1,159 @@
+package interop
+
+import (
+	"testing"
+
+	"github.com/angelhvargas/redfishcli/pkg/emulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const baselineProfile = `{
+	"SchemaDefinition": "RedfishInteroperabilityProfile.v1_6_0",
+	"ProfileName": "TestBaseline",
+	"ProfileVersion": "1.0.0",
+	"OwningEntity": "redfishcli",
+	"Protocol": {"MinVersion": "1.6"},
+	"Resources": {
+		"ComputerSystemCollection": {
+			"PropertyRequirements": {"Members": {"MinCount": 1}}
+		},
+		"ComputerSystem": {
+			"MinVersion": "1.5.0",
+			"PropertyRequirements": {
+				"SystemType": {"Comparison": "AnyOf", "Values": ["Physical"]},
+				"PowerState": {},
+				"IndicatorLED": {"ReadRequirement": "Recommended", "Values": ["Lit", "Blinking", "Off"]},
+				"Status": {"PropertyRequirements": {"Health": {}, "State": {}}},
+				"HostName": {"ReadRequirement": "IfImplemented"}
+			},
+			"ActionRequirements": {
+				"Reset": {
+					"Parameters": {
+						"ResetType": {"ParameterValues": ["On", "ForceOff"], "RecommendedValues": ["PowerCycle"]}
+					}
+				}
+			}
+		},
+		"Drive": {
+			"PropertyRequirements": {
+				"Status": {"PropertyRequirements": {"Health": {}}},
+				"CapacityBytes": {}
+			}
+		},
+		"PCIeDevice": {"ReadRequirement": "IfImplemented"}
+	}
+}`
+
+func loadService(t *testing.T, profile emulator.Profile) Service {
+	resources, err := emulator.LoadMockup(profile.Mockup)
+	require.NoError(t, err)
+	return Service(resources)
+}
+
+func find(r *Report, uri, property, check string) Finding {
+	for _, f := range r.Findings {
+		if f.URI == uri && f.Property == property && f.Check == check {
+			return f
+		}
+	}
+	return Finding{}
+}
+
+func TestCheckBuiltinProfiles(t *testing.T) {
+	p, err := Parse([]byte(baselineProfile))
+	require.NoError(t, err)
+
+	dell := Check(p, loadService(t, emulator.IDRAC()))
+	assert.Equal(t, "TestBaseline 1.0.0", dell.Profile)
+	assert.Zero(t, dell.Failed, "%+v", dell.Findings)
+	assert.Zero(t, dell.Warnings, "%+v", dell.Findings)
+	assert.Equal(t, 1, dell.Skipped, "only PCIeDevice is not implemented")
+	assert.Equal(t, "allows On, ForceOff, ForceRestart, GracefulRestart, GracefulShutdown, PushPowerButton, Nmi, PowerCycle",
+		find(dell, "/redfish/v1/Systems/System.Embedded.1", "Actions/#ComputerSystem.Reset/ResetType", CheckParameter).Message)
+
+	// The XCC lists its reset types in an ActionInfo, without PowerCycle.
+	lenovo := Check(p, loadService(t, emulator.XCC()))
+	assert.Zero(t, lenovo.Failed, "%+v", lenovo.Findings)
+	assert.Equal(t, Finding{
+		Resource: "ComputerSystem", URI: "/redfish/v1/Systems/1", Property: "Actions/#ComputerSystem.Reset/ResetType",
+		Check: CheckParameter, Requirement: Mandatory, Outcome: Warn, Message: "recommended PowerCycle not allowed",
+	}, find(lenovo, "/redfish/v1/Systems/1", "Actions/#ComputerSystem.Reset/ResetType", CheckParameter))
+	assert.Equal(t, 1, lenovo.Warnings)
+}
+
+func TestCheckReportsUnmetRequirements(t *testing.T) {
+	p, err := Parse([]byte(`{
+		"ProfileName": "Strict",
+		"Protocol": {"MinVersion": "1.12.0"},
+		"Resources": {
+			"ComputerSystemCollection": {"PropertyRequirements": {"Members": {"MinCount": 4}}},
+			"ComputerSystem": {
+				"MinVersion": "1.13.0",
+				"PropertyRequirements": {
+					"IndicatorLED": {"Comparison": "Equal", "Values": ["Off"]},
+					"AssetTag": {
+						"ReadRequirement": "Conditional",
+						"ConditionalRequirements": [{
+							"SubordinateToResource": ["ComputerSystemCollection"],
+							"CompareProperty": "SystemType", "CompareType": "AnyOf", "CompareValues": ["Physical"],
+							"ReadRequirement": "Mandatory"
+						}]
+					},
+					"HostedServices": {
+						"ReadRequirement": "Conditional",
+						"ConditionalRequirements": [{"CompareProperty": "SystemType", "CompareValues": ["Virtual"], "ReadRequirement": "Mandatory"}]
+					},
+					"Boot": {"PropertyRequirements": {"BootSourceOverrideTarget": {"MinSupportValues": ["Pxe", "Usb"]}}},
+					"SKU": {"Comparison": "Absent"}
+				},
+				"ActionRequirements": {"SetDefaultBootOrder": {"ReadRequirement": "Recommended"}}
+			},
+			"PCIeDevice": {},
+			"SecureBoot": {"ReadRequirement": "Recommended"}
+		}
+	}`))
+	require.NoError(t, err)
+
+	r := Check(p, loadService(t, emulator.IDRAC()))
+	system := "/redfish/v1/Systems/System.Embedded.1"
+	for _, tc := range []struct {
+		uri, property, check string
+		outcome              Outcome
+		message              string
+	}{
+		{"/redfish/v1", "RedfishVersion", CheckMinVersion, Fail, "Redfish 1.11.0 is older than 1.12.0"},
+		{"/redfish/v1/Systems", "Members", CheckMinCount, Fail, "1 found, want at least 4"},
+		{system, "", CheckMinVersion, Fail, "schema version 1.12.0 is older than 1.13.0"},
+		{system, "IndicatorLED", CheckValues, Fail, "Lit is not Equal Off"},
+		{system, "AssetTag", CheckRead, Fail, "missing"},
+		{system, "HostedServices", CheckRead, Skip, "not implemented"},
+		{system, "Boot/BootSourceOverrideTarget", CheckMinSupport, Fail, "Usb not allowed"},
+		{system, "SKU", CheckValues, Fail, "must be absent"},
+		{system, "Actions/#ComputerSystem.SetDefaultBootOrder", CheckAction, Warn, "not offered"},
+	} {
+		f := find(r, tc.uri, tc.property, tc.check)
+		assert.Equal(t, tc.outcome, f.Outcome, "%s %s %s", tc.uri, tc.property, tc.check)
+		assert.Equal(t, tc.message, f.Message, "%s %s %s", tc.uri, tc.property, tc.check)
+	}
+	assert.Equal(t, []Finding{
+		{Resource: "PCIeDevice", Check: CheckRead, Requirement: Mandatory, Outcome: Fail, Message: "no PCIeDevice resource found"},
+		{Resource: "SecureBoot", Check: CheckRead, Requirement: Recommended, Outcome: Warn, Message: "no SecureBoot resource found"},
+	}, r.Findings[len(r.Findings)-2:])
+	assert.Equal(t, 8, r.Failed)
+	assert.Equal(t, 2, r.Warnings)
+}
+
+func TestParseRejectsOtherDocuments(t *testing.T) {
+	_, err := Parse([]byte(`{"@odata.type": "#ComputerSystem.v1_5_0.ComputerSystem"}`))
+	assert.EqualError(t, err, "not an interoperability profile: ProfileName and Resources are required")
+
+	_, err = Parse([]byte(`{`))
+	assert.Error(t, err)
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, 0, compareVersions("1.6", "1.6.0"))
+	assert.Equal(t, -1, compareVersions("1.5.9", "1.6"))
+	assert.Equal(t, 1, compareVersions("1.10.0", "1.9.0"))
+}
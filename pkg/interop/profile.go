@@ -0,0 +1,159 @@
+// Package interop checks a Redfish service against a DMTF interoperability
+// profile (DSP0272), such as the OCP baseline hardware management profile.
+//
+// A profile lists, per resource type, whether the resource must exist, which
+// properties it must carry and with which values, how many members a
+// collection needs and which actions and action parameters it must offer.
+// Check evaluates every requirement against the resources of a service and
+// reports one Finding per requirement and resource instance.
+package interop
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Requirement is a ReadRequirement value of a profile.
+type Requirement string
+
+const (
+	Mandatory     Requirement = "Mandatory"
+	Supported     Requirement = "Supported"
+	Recommended   Requirement = "Recommended"
+	IfImplemented Requirement = "IfImplemented"
+	IfPopulated   Requirement = "IfPopulated"
+	// Conditional requirements only apply when one of the
+	// ConditionalRequirements of the property matches.
+	Conditional Requirement = "Conditional"
+	None        Requirement = "None"
+)
+
+// Comparison is how a property value is compared with the listed Values.
+type Comparison string
+
+const (
+	AnyOf              Comparison = "AnyOf"
+	AllOf              Comparison = "AllOf"
+	Equal              Comparison = "Equal"
+	NotEqual           Comparison = "NotEqual"
+	GreaterThan        Comparison = "GreaterThan"
+	GreaterThanOrEqual Comparison = "GreaterThanOrEqual"
+	LessThan           Comparison = "LessThan"
+	LessThanOrEqual    Comparison = "LessThanOrEqual"
+	Absent             Comparison = "Absent"
+	Present            Comparison = "Present"
+	// LinkToResource requires the property to link to a resource of the
+	// type named by the first value.
+	LinkToResource Comparison = "LinkToResource"
+)
+
+// Profile is an interoperability profile document.
+type Profile struct {
+	SchemaDefinition string                         `json:"SchemaDefinition"`
+	ProfileName      string                         `json:"ProfileName"`
+	ProfileVersion   string                         `json:"ProfileVersion"`
+	Purpose          string                         `json:"Purpose"`
+	OwningEntity     string                         `json:"OwningEntity"`
+	Protocol         *ProtocolRequirement           `json:"Protocol"`
+	Resources        map[string]ResourceRequirement `json:"Resources"`
+}
+
+// ProtocolRequirement holds the protocol requirements that can be read
+// from the service root.
+type ProtocolRequirement struct {
+	// MinVersion is the lowest RedfishVersion the service may report.
+	MinVersion string `json:"MinVersion"`
+}
+
+// ResourceRequirement applies to every instance of a resource type.
+type ResourceRequirement struct {
+	Purpose string `json:"Purpose"`
+	// MinVersion is the lowest schema version, e.g. "1.2.0".
+	MinVersion           string                         `json:"MinVersion"`
+	ReadRequirement      Requirement                    `json:"ReadRequirement"`
+	PropertyRequirements map[string]PropertyRequirement `json:"PropertyRequirements"`
+	ActionRequirements   map[string]ActionRequirement   `json:"ActionRequirements"`
+}
+
+// PropertyRequirement applies to one property. Object properties nest
+// further PropertyRequirements; for arrays of objects they apply to each
+// element.
+type PropertyRequirement struct {
+	Purpose          string      `json:"Purpose"`
+	ReadRequirement  Requirement `json:"ReadRequirement"`
+	WriteRequirement Requirement `json:"WriteRequirement"`
+	// MinCount is the minimum number of elements of an array property.
+	MinCount   *int          `json:"MinCount"`
+	Comparison Comparison    `json:"Comparison"`
+	Values     []interface{} `json:"Values"`
+	// MinSupportValues must all be listed in the property's
+	// @Redfish.AllowableValues.
+	MinSupportValues        []interface{}                  `json:"MinSupportValues"`
+	PropertyRequirements    map[string]PropertyRequirement `json:"PropertyRequirements"`
+	ConditionalRequirements []ConditionalRequirement       `json:"ConditionalRequirements"`
+}
+
+// ActionRequirement applies to an action such as "Reset".
+type ActionRequirement struct {
+	Purpose         string                          `json:"Purpose"`
+	ReadRequirement Requirement                     `json:"ReadRequirement"`
+	Parameters      map[string]ParameterRequirement `json:"Parameters"`
+}
+
+// ParameterRequirement lists the values an action parameter must accept.
+type ParameterRequirement struct {
+	ReadRequirement   Requirement   `json:"ReadRequirement"`
+	ParameterValues   []interface{} `json:"ParameterValues"`
+	RecommendedValues []interface{} `json:"RecommendedValues"`
+}
+
+// ConditionalRequirement overrides the requirements of a property for the
+// instances it matches: those below the SubordinateToResource chain and
+// whose CompareProperty compares to CompareValues.
+type ConditionalRequirement struct {
+	Purpose string `json:"Purpose"`
+	// SubordinateToResource names resource types, outermost first, that
+	// must appear in this order among the resource's ancestors.
+	SubordinateToResource []string      `json:"SubordinateToResource"`
+	CompareProperty       string        `json:"CompareProperty"`
+	CompareType           Comparison    `json:"CompareType"`
+	CompareValues         []interface{} `json:"CompareValues"`
+	ReadRequirement       Requirement   `json:"ReadRequirement"`
+	MinCount              *int          `json:"MinCount"`
+	Comparison            Comparison    `json:"Comparison"`
+	Values                []interface{} `json:"Values"`
+}
+
+// Parse decodes a profile document.
+func Parse(data []byte) (*Profile, error) {
+	var p Profile
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("invalid profile: %w", err)
+	}
+	if p.ProfileName == "" || p.Resources == nil {
+		return nil, fmt.Errorf("not an interoperability profile: ProfileName and Resources are required")
+	}
+	return &p, nil
+}
+
+// Load reads a profile document from a file.
+func Load(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Name is the profile name and version, e.g. "OCPBaselineHardwareManagement 1.0.1".
+func (p *Profile) Name() string {
+	if p.ProfileVersion == "" {
+		return p.ProfileName
+	}
+	return p.ProfileName + " " + p.ProfileVersion
+}
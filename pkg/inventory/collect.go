@@ -0,0 +1,466 @@
+package inventory
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/angelhvargas/redfishcli/pkg/client"
+	"github.com/angelhvargas/redfishcli/pkg/model"
+)
+
+// Part selects what Read gathers.
+type Part uint
+
+const (
+	// PartSystem reads the ComputerSystem.
+	PartSystem Part = 1 << iota
+	// PartStorage reads the storage controllers.
+	PartStorage
+	// PartDrives reads the drives of each controller; implies PartStorage.
+	PartDrives
+	// PartVolumes reads the volumes of each controller; implies PartStorage.
+	PartVolumes
+	// PartChassis reads the chassis enclosing the system.
+	PartChassis
+	// PartPower reads the power supplies of the chassis.
+	PartPower
+	// PartThermal reads the fans and temperature sensors of the chassis.
+	PartThermal
+	PartProcessors
+	PartMemory
+	PartNetwork
+	PartFirmware
+
+	PartAll = PartSystem | PartStorage | PartDrives | PartVolumes | PartChassis | PartPower |
+		PartThermal | PartProcessors | PartMemory | PartNetwork | PartFirmware
+)
+
+// Source is implemented by backends that can read any Redfish resource of
+// their BMC. Every backend reads the system and storage through its
+// ServerClient methods; chassis, power, thermal, processors, memory, NICs,
+// volumes and firmware are only read from backends that are a Source, and
+// skipped otherwise.
+type Source interface {
+	Fetch(uri string, target interface{}) error
+}
+
+// Normalizer is implemented by backends whose BMC reports values in a
+// vendor-specific shape, such as prefixed sensor IDs. Read calls it after
+// every gathering pass.
+type Normalizer interface {
+	NormalizeInventory(inv *Inventory)
+}
+
+// Collect gathers the given parts of the server behind c.
+func Collect(c client.ServerClient, hostname string, parts Part) (*Inventory, error) {
+	inv := &Inventory{Hostname: hostname}
+	err := inv.Read(c, parts)
+	return inv, err
+}
+
+// Read gathers the given parts into inv. It can be called more than once,
+// e.g. to read the system first and storage only when the server is on.
+//
+// A failure reading one component does not discard the others: what could
+// be read is kept and the failures are returned joined.
+func (inv *Inventory) Read(c client.ServerClient, parts Part) error {
+	if parts&(PartDrives|PartVolumes) != 0 {
+		parts |= PartStorage
+	}
+	if parts&(PartPower|PartThermal) != 0 {
+		parts |= PartChassis
+	}
+
+	needsSystem := PartSystem | PartChassis | PartProcessors | PartMemory | PartNetwork
+	if parts&needsSystem != 0 && inv.system == nil {
+		system, err := c.GetServerInfo()
+		if err != nil {
+			return err
+		}
+		inv.setSystem(system)
+	}
+
+	var errs []error
+	if parts&PartStorage != 0 {
+		errs = append(errs, inv.readStorage(c, parts))
+	}
+
+	src, ok := c.(Source)
+	if ok {
+		if parts&PartChassis != 0 {
+			errs = append(errs, inv.readChassis(src, parts))
+		}
+		if parts&PartProcessors != 0 {
+			errs = append(errs, inv.readProcessors(src))
+		}
+		if parts&PartMemory != 0 {
+			errs = append(errs, inv.readMemory(src))
+		}
+		if parts&PartNetwork != 0 {
+			errs = append(errs, inv.readNICs(src))
+		}
+		if parts&PartFirmware != 0 {
+			errs = append(errs, inv.readFirmware(src))
+		}
+	}
+
+	if n, ok := c.(Normalizer); ok {
+		n.NormalizeInventory(inv)
+	}
+	return errors.Join(errs...)
+}
+
+func (inv *Inventory) setSystem(s *model.ComputerSystem) {
+	inv.system = s
+	inv.Vendor = VendorName(s.Manufacturer)
+	inv.System = System{
+		ID:             s.ID,
+		Manufacturer:   s.Manufacturer,
+		Model:          s.Model,
+		SerialNumber:   s.SerialNumber,
+		SKU:            s.SKU,
+		UUID:           s.UUID,
+		HostName:       s.HostName,
+		BIOSVersion:    s.BiosVersion,
+		PowerState:     powerOf(s.PowerState),
+		Status:         statusOf(s.Status),
+		ProcessorCount: intOf(s.ProcessorSummary.Count),
+		MemoryBytes:    int64(floatOf(s.MemorySummary.TotalSystemMemoryGiB) * gib),
+		Raw:            s.Raw,
+	}
+}
+
+const (
+	mib = 1 << 20
+	gib = 1 << 30
+)
+
+// members reads the collection at uri and then each of its members.
+func members[T any](src Source, uri string) ([]T, error) {
+	if uri == "" {
+		return nil, nil
+	}
+	var coll model.Collection[model.IDRef]
+	if err := src.Fetch(uri, &coll); err != nil {
+		return nil, err
+	}
+	var (
+		items []T
+		errs  []error
+	)
+	for _, ref := range coll.Members {
+		var item T
+		if err := src.Fetch(ref.ODataID, &item); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ref.ODataID, err))
+			continue
+		}
+		items = append(items, item)
+	}
+	return items, errors.Join(errs...)
+}
+
+func (inv *Inventory) readStorage(c client.ServerClient, parts Part) error {
+	storage, err := client.Storage(c)
+	if err != nil {
+		return err
+	}
+	controllers, err := storage.GetStorageControllers(&model.StorageControllerConfig{Type: "RAID"})
+	if err != nil {
+		return client.Explain(c, client.CapabilityStorage, err)
+	}
+	src, _ := c.(Source)
+
+	inv.Controllers = []Controller{}
+	var errs []error
+	for _, ref := range controllers {
+		s, err := storage.GetStorageControllerInfo(ref.ODataID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("controller %s: %w", ref.ODataID, err))
+			continue
+		}
+		ctrl := controllerOf(s)
+		if parts&PartDrives != 0 {
+			ctrl.Drives = []Drive{}
+			for _, driveRef := range s.Drives {
+				if driveRef.ODataID == "" {
+					continue
+				}
+				d, err := storage.GetStorageDriveDetails(driveRef.ODataID)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("drive %s: %w", driveRef.ODataID, err))
+					continue
+				}
+				ctrl.Drives = append(ctrl.Drives, driveOf(d))
+			}
+		}
+		if parts&PartVolumes != 0 && src != nil && s.Volumes.ODataID != "" {
+			var coll model.Collection[model.IDRef]
+			if err := src.Fetch(s.Volumes.ODataID, &coll); err != nil {
+				errs = append(errs, fmt.Errorf("volumes %s: %w", s.Volumes.ODataID, err))
+			}
+			for _, volumeRef := range coll.Members {
+				v, err := storage.GetRAIDVolumeInfo(volumeRef.ODataID)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("volume %s: %w", volumeRef.ODataID, err))
+					continue
+				}
+				ctrl.Volumes = append(ctrl.Volumes, volumeOf(v))
+			}
+		}
+		inv.Controllers = append(inv.Controllers, ctrl)
+	}
+	return errors.Join(errs...)
+}
+
+func controllerOf(s *model.Storage) Controller {
+	ctrl := Controller{
+		ID:         s.ID,
+		Name:       s.Name,
+		Status:     statusOf(s.Status),
+		DriveCount: len(s.Drives),
+		Raw:        s.Raw,
+	}
+	if len(s.StorageControllers) > 0 {
+		sc := s.StorageControllers[0]
+		ctrl.Manufacturer = sc.Manufacturer
+		ctrl.Model = sc.Model
+		ctrl.FirmwareVersion = sc.FirmwareVersion
+		if ctrl.Status.Health == HealthUnknown {
+			ctrl.Status = statusOf(sc.Status)
+		}
+	}
+	return ctrl
+}
+
+func driveOf(d *model.Drive) Drive {
+	return Drive{
+		ID:               d.ID,
+		Name:             d.Name,
+		Manufacturer:     d.Manufacturer,
+		Model:            d.Model,
+		SerialNumber:     d.SerialNumber,
+		FirmwareVersion:  d.Revision,
+		MediaType:        string(d.MediaType),
+		Protocol:         string(d.Protocol),
+		CapacityBytes:    int64Of(d.CapacityBytes),
+		FailurePredicted: boolOf(d.FailurePredicted),
+		Status:           statusOf(d.Status),
+		Raw:              d.Raw,
+	}
+}
+
+func volumeOf(v *model.Volume) Volume {
+	raid := string(v.RAIDType)
+	if raid == "" {
+		raid = string(v.VolumeType)
+	}
+	return Volume{
+		ID:            v.ID,
+		Name:          v.Name,
+		RAIDType:      raid,
+		CapacityBytes: int64Of(v.CapacityBytes),
+		Encrypted:     boolOf(v.Encrypted),
+		Status:        statusOf(v.Status),
+		Raw:           v.Raw,
+	}
+}
+
+func (inv *Inventory) readChassis(src Source, parts Part) error {
+	inv.Chassis, inv.PowerSupplies, inv.Fans, inv.Temperatures = nil, nil, nil, nil
+	var errs []error
+	for _, ref := range inv.system.Links.Chassis {
+		var ch model.Chassis
+		if err := src.Fetch(ref.ODataID, &ch); err != nil {
+			errs = append(errs, fmt.Errorf("chassis %s: %w", ref.ODataID, err))
+			continue
+		}
+		inv.Chassis = append(inv.Chassis, Chassis{
+			ID:           ch.ID,
+			Name:         ch.Name,
+			Type:         string(ch.ChassisType),
+			Manufacturer: ch.Manufacturer,
+			Model:        ch.Model,
+			SerialNumber: ch.SerialNumber,
+			PartNumber:   ch.PartNumber,
+			PowerState:   powerOf(ch.PowerState),
+			Status:       statusOf(ch.Status),
+		})
+
+		if parts&PartPower != 0 && ch.Power.ODataID != "" {
+			var power model.Power
+			if err := src.Fetch(ch.Power.ODataID, &power); err != nil {
+				errs = append(errs, fmt.Errorf("power %s: %w", ch.Power.ODataID, err))
+			}
+			for _, psu := range power.PowerSupplies {
+				inv.PowerSupplies = append(inv.PowerSupplies, PowerSupply{
+					ID:              memberID(psu.MemberID, psu.Name),
+					Name:            psu.Name,
+					Manufacturer:    psu.Manufacturer,
+					Model:           psu.Model,
+					SerialNumber:    psu.SerialNumber,
+					FirmwareVersion: psu.FirmwareVersion,
+					CapacityWatts:   floatOf(psu.PowerCapacityWatts),
+					OutputWatts:     floatOf(psu.LastPowerOutputWatts),
+					InputVolts:      floatOf(psu.LineInputVoltage),
+					Status:          statusOf(psu.Status),
+				})
+			}
+		}
+
+		if parts&PartThermal != 0 && ch.Thermal.ODataID != "" {
+			var thermal model.Thermal
+			if err := src.Fetch(ch.Thermal.ODataID, &thermal); err != nil {
+				errs = append(errs, fmt.Errorf("thermal %s: %w", ch.Thermal.ODataID, err))
+			}
+			for _, f := range thermal.Fans {
+				fan := Fan{ID: memberID(f.MemberID, f.Name), Name: f.Name, Status: statusOf(f.Status)}
+				if f.Reading != nil {
+					reading := float64(*f.Reading)
+					if f.ReadingUnits == model.ReadingUnitsPercent {
+						fan.SpeedPercent = &reading
+					} else {
+						fan.SpeedRPM = &reading
+					}
+				}
+				inv.Fans = append(inv.Fans, fan)
+			}
+			for _, t := range thermal.Temperatures {
+				inv.Temperatures = append(inv.Temperatures, Temperature{
+					ID:              memberID(t.MemberID, t.Name),
+					Name:            t.Name,
+					Celsius:         t.ReadingCelsius,
+					CriticalCelsius: t.UpperThresholdCritical,
+					Status:          statusOf(t.Status),
+				})
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (inv *Inventory) readProcessors(src Source) error {
+	cpus, err := members[model.Processor](src, inv.system.Processors.ODataID)
+	inv.Processors = nil
+	for _, p := range cpus {
+		inv.Processors = append(inv.Processors, Processor{
+			ID:           p.ID,
+			Socket:       p.Socket,
+			Manufacturer: p.Manufacturer,
+			Model:        p.Model,
+			Cores:        intOf(p.TotalCores),
+			Threads:      intOf(p.TotalThreads),
+			MaxSpeedMHz:  intOf(p.MaxSpeedMHz),
+			Status:       statusOf(p.Status),
+		})
+	}
+	return wrap("processors", err)
+}
+
+func (inv *Inventory) readMemory(src Source) error {
+	dimms, err := members[model.Memory](src, inv.system.Memory.ODataID)
+	inv.Memory = nil
+	for _, m := range dimms {
+		inv.Memory = append(inv.Memory, DIMM{
+			ID:            m.ID,
+			Locator:       m.DeviceLocator,
+			Type:          string(m.MemoryDeviceType),
+			CapacityBytes: int64Of(m.CapacityMiB) * mib,
+			SpeedMHz:      intOf(m.OperatingSpeedMhz),
+			Manufacturer:  m.Manufacturer,
+			PartNumber:    m.PartNumber,
+			SerialNumber:  m.SerialNumber,
+			Status:        statusOf(m.Status),
+		})
+	}
+	return wrap("memory", err)
+}
+
+func (inv *Inventory) readNICs(src Source) error {
+	nics, err := members[model.EthernetInterface](src, inv.system.EthernetInterfaces.ODataID)
+	inv.NICs = nil
+	for _, n := range nics {
+		mac := n.MACAddress
+		if mac == "" {
+			mac = n.PermanentMACAddress
+		}
+		inv.NICs = append(inv.NICs, NIC{
+			ID:         n.ID,
+			Name:       n.Name,
+			MACAddress: mac,
+			SpeedMbps:  intOf(n.SpeedMbps),
+			LinkUp:     n.LinkStatus == model.LinkStatusLinkUp,
+			Status:     statusOf(n.Status),
+		})
+	}
+	return wrap("network", err)
+}
+
+func (inv *Inventory) readFirmware(src Source) error {
+	var root struct {
+		UpdateService model.IDRef `json:"UpdateService"`
+	}
+	if err := src.Fetch("/redfish/v1", &root); err != nil {
+		return wrap("firmware", err)
+	}
+	if root.UpdateService.ODataID == "" {
+		return nil
+	}
+	var updateService struct {
+		FirmwareInventory model.IDRef `json:"FirmwareInventory"`
+	}
+	if err := src.Fetch(root.UpdateService.ODataID, &updateService); err != nil {
+		return wrap("firmware", err)
+	}
+	items, err := members[model.SoftwareInventory](src, updateService.FirmwareInventory.ODataID)
+	inv.Firmware = nil
+	for _, fw := range items {
+		inv.Firmware = append(inv.Firmware, Firmware{
+			ID:           fw.ID,
+			Name:         fw.Name,
+			Version:      fw.Version,
+			Manufacturer: fw.Manufacturer,
+			Updateable:   boolOf(fw.Updateable),
+		})
+	}
+	return wrap("firmware", err)
+}
+
+func wrap(what string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", what, err)
+}
+
+// memberID identifies an entry of an array property, which has no Id.
+func memberID(id, name string) string {
+	if id != "" {
+		return id
+	}
+	return name
+}
+
+func intOf(v *int64) int {
+	if v == nil {
+		return 0
+	}
+	return int(*v)
+}
+
+func int64Of(v *int64) int64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+func floatOf(v *float64) float64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+func boolOf(v *bool) bool {
+	return v != nil && *v
+}
@@ -0,0 +1,384 @@
+// Package inventory describes a server in vendor-neutral terms.
+//
+// Backends return Redfish resources as each BMC shapes them: health can be
+// missing or rolled up differently, fans report RPM on one vendor and percent
+// on another, memory is sized in GiB on the system and MiB on the DIMM. The
+// inventory normalizes all of that, so reports for a mixed fleet compare like
+// with like. Sizes are bytes, speeds MHz or Mbit/s, power watts and
+// temperatures degrees Celsius.
+package inventory
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/angelhvargas/redfishcli/pkg/model"
+)
+
+// Health is the normalized health of a component.
+type Health string
+
+const (
+	HealthOK       Health = "OK"
+	HealthWarning  Health = "Warning"
+	HealthCritical Health = "Critical"
+	HealthUnknown  Health = "Unknown"
+)
+
+var healthRank = map[Health]int{HealthOK: 0, HealthUnknown: 1, HealthWarning: 2, HealthCritical: 3}
+
+// Worse returns the worse of h and other; Unknown ranks above OK and below
+// Warning.
+func (h Health) Worse(other Health) Health {
+	if healthRank[other] > healthRank[h] {
+		return other
+	}
+	return h
+}
+
+func healthOf(h model.Health) Health {
+	switch h {
+	case model.HealthOK:
+		return HealthOK
+	case model.HealthWarning:
+		return HealthWarning
+	case model.HealthCritical:
+		return HealthCritical
+	}
+	return HealthUnknown
+}
+
+// State is the normalized state of a component.
+type State string
+
+const (
+	StateEnabled  State = "Enabled"
+	StateDisabled State = "Disabled"
+	// StateStandby is a spare or offline component waiting to be used.
+	StateStandby State = "Standby"
+	// StateBusy is a component that is starting, updating or under test.
+	StateBusy    State = "Busy"
+	StateAbsent  State = "Absent"
+	StateUnknown State = "Unknown"
+)
+
+func stateOf(s model.State) State {
+	switch s {
+	case model.StateEnabled, model.StateQualified:
+		return StateEnabled
+	case model.StateDisabled, model.StateUnavailableOffline, model.StateDeferring, model.StateQuiesced:
+		return StateDisabled
+	case model.StateStandbyOffline, model.StateStandbySpare:
+		return StateStandby
+	case model.StateStarting, model.StateInTest, model.StateUpdating:
+		return StateBusy
+	case model.StateAbsent:
+		return StateAbsent
+	}
+	return StateUnknown
+}
+
+// PowerState is the normalized power state of a system or chassis.
+type PowerState string
+
+const (
+	PowerOn  PowerState = "On"
+	PowerOff PowerState = "Off"
+	// PowerTransitioning covers powering on or off and paused systems.
+	PowerTransitioning PowerState = "Transitioning"
+	PowerUnknown       PowerState = "Unknown"
+)
+
+func powerOf(p model.PowerState) PowerState {
+	switch p {
+	case model.PowerStateOn:
+		return PowerOn
+	case model.PowerStateOff:
+		return PowerOff
+	case model.PowerStatePoweringOn, model.PowerStatePoweringOff, model.PowerStatePaused:
+		return PowerTransitioning
+	}
+	return PowerUnknown
+}
+
+// Status is the health and state of a component.
+type Status struct {
+	Health Health `json:"health" yaml:"health"`
+	State  State  `json:"state" yaml:"state"`
+}
+
+func statusOf(s model.Status) Status {
+	st := Status{Health: healthOf(s.Health), State: stateOf(s.State)}
+	if st.Health == HealthUnknown && s.Health == "" {
+		// Some BMCs only report the rollup.
+		st.Health = healthOf(s.HealthRollup)
+	}
+	return st
+}
+
+// counts reports whether the component's health should count towards the
+// server's: absent and disabled parts are not expected to be healthy.
+func (s Status) counts() bool {
+	return s.State != StateAbsent && s.State != StateDisabled
+}
+
+// Inventory describes one server.
+type Inventory struct {
+	Hostname string `json:"hostname" yaml:"hostname"`
+	// Vendor is the normalized manufacturer, e.g. Dell or Lenovo.
+	Vendor        string        `json:"vendor" yaml:"vendor"`
+	System        System        `json:"system" yaml:"system"`
+	Chassis       []Chassis     `json:"chassis,omitempty" yaml:"chassis,omitempty"`
+	Processors    []Processor   `json:"processors,omitempty" yaml:"processors,omitempty"`
+	Memory        []DIMM        `json:"memory,omitempty" yaml:"memory,omitempty"`
+	NICs          []NIC         `json:"nics,omitempty" yaml:"nics,omitempty"`
+	Controllers   []Controller  `json:"controllers,omitempty" yaml:"controllers,omitempty"`
+	PowerSupplies []PowerSupply `json:"power_supplies,omitempty" yaml:"power_supplies,omitempty"`
+	Fans          []Fan         `json:"fans,omitempty" yaml:"fans,omitempty"`
+	Temperatures  []Temperature `json:"temperatures,omitempty" yaml:"temperatures,omitempty"`
+	Firmware      []Firmware    `json:"firmware,omitempty" yaml:"firmware,omitempty"`
+
+	// system is the resource System was read from; its links lead to the
+	// other components.
+	system *model.ComputerSystem
+}
+
+// System is the ComputerSystem.
+type System struct {
+	ID           string     `json:"id" yaml:"id"`
+	Manufacturer string     `json:"manufacturer" yaml:"manufacturer"`
+	Model        string     `json:"model" yaml:"model"`
+	SerialNumber string     `json:"serial_number" yaml:"serial_number"`
+	SKU          string     `json:"sku,omitempty" yaml:"sku,omitempty"`
+	UUID         string     `json:"uuid,omitempty" yaml:"uuid,omitempty"`
+	HostName     string     `json:"host_name,omitempty" yaml:"host_name,omitempty"`
+	BIOSVersion  string     `json:"bios_version" yaml:"bios_version"`
+	PowerState   PowerState `json:"power_state" yaml:"power_state"`
+	Status       Status     `json:"status" yaml:"status"`
+	// ProcessorCount and MemoryBytes come from the system summary, so they
+	// are known even when the BMC does not list processors or DIMMs.
+	ProcessorCount int   `json:"processor_count" yaml:"processor_count"`
+	MemoryBytes    int64 `json:"memory_bytes" yaml:"memory_bytes"`
+	// Raw is the ComputerSystem payload as the BMC returned it.
+	Raw json.RawMessage `json:"-" yaml:"-"`
+}
+
+// Chassis is a physical enclosure.
+type Chassis struct {
+	ID           string     `json:"id" yaml:"id"`
+	Name         string     `json:"name" yaml:"name"`
+	Type         string     `json:"type" yaml:"type"`
+	Manufacturer string     `json:"manufacturer" yaml:"manufacturer"`
+	Model        string     `json:"model" yaml:"model"`
+	SerialNumber string     `json:"serial_number" yaml:"serial_number"`
+	PartNumber   string     `json:"part_number,omitempty" yaml:"part_number,omitempty"`
+	PowerState   PowerState `json:"power_state" yaml:"power_state"`
+	Status       Status     `json:"status" yaml:"status"`
+}
+
+// Processor is a CPU socket.
+type Processor struct {
+	ID           string `json:"id" yaml:"id"`
+	Socket       string `json:"socket" yaml:"socket"`
+	Manufacturer string `json:"manufacturer" yaml:"manufacturer"`
+	Model        string `json:"model" yaml:"model"`
+	Cores        int    `json:"cores" yaml:"cores"`
+	Threads      int    `json:"threads" yaml:"threads"`
+	MaxSpeedMHz  int    `json:"max_speed_mhz" yaml:"max_speed_mhz"`
+	Status       Status `json:"status" yaml:"status"`
+}
+
+// DIMM is a memory module slot.
+type DIMM struct {
+	ID            string `json:"id" yaml:"id"`
+	Locator       string `json:"locator" yaml:"locator"`
+	Type          string `json:"type,omitempty" yaml:"type,omitempty"`
+	CapacityBytes int64  `json:"capacity_bytes" yaml:"capacity_bytes"`
+	SpeedMHz      int    `json:"speed_mhz,omitempty" yaml:"speed_mhz,omitempty"`
+	Manufacturer  string `json:"manufacturer,omitempty" yaml:"manufacturer,omitempty"`
+	PartNumber    string `json:"part_number,omitempty" yaml:"part_number,omitempty"`
+	SerialNumber  string `json:"serial_number,omitempty" yaml:"serial_number,omitempty"`
+	Status        Status `json:"status" yaml:"status"`
+}
+
+// NIC is an Ethernet interface of the system.
+type NIC struct {
+	ID         string `json:"id" yaml:"id"`
+	Name       string `json:"name" yaml:"name"`
+	MACAddress string `json:"mac_address" yaml:"mac_address"`
+	SpeedMbps  int    `json:"speed_mbps" yaml:"speed_mbps"`
+	LinkUp     bool   `json:"link_up" yaml:"link_up"`
+	Status     Status `json:"status" yaml:"status"`
+}
+
+// Controller is a storage subsystem with its drives and volumes.
+type Controller struct {
+	ID              string `json:"id" yaml:"id"`
+	Name            string `json:"name" yaml:"name"`
+	Manufacturer    string `json:"manufacturer,omitempty" yaml:"manufacturer,omitempty"`
+	Model           string `json:"model,omitempty" yaml:"model,omitempty"`
+	FirmwareVersion string `json:"firmware_version,omitempty" yaml:"firmware_version,omitempty"`
+	Status          Status `json:"status" yaml:"status"`
+	// DriveCount is the number of drives the controller lists, including any
+	// whose details could not be read.
+	DriveCount int      `json:"drive_count" yaml:"drive_count"`
+	Drives     []Drive  `json:"drives,omitempty" yaml:"drives,omitempty"`
+	Volumes    []Volume `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+	// Raw is the Storage payload as the BMC returned it.
+	Raw json.RawMessage `json:"-" yaml:"-"`
+}
+
+// Drive is a physical disk.
+type Drive struct {
+	ID               string `json:"id" yaml:"id"`
+	Name             string `json:"name" yaml:"name"`
+	Manufacturer     string `json:"manufacturer" yaml:"manufacturer"`
+	Model            string `json:"model" yaml:"model"`
+	SerialNumber     string `json:"serial_number" yaml:"serial_number"`
+	FirmwareVersion  string `json:"firmware_version,omitempty" yaml:"firmware_version,omitempty"`
+	MediaType        string `json:"media_type,omitempty" yaml:"media_type,omitempty"`
+	Protocol         string `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+	CapacityBytes    int64  `json:"capacity_bytes" yaml:"capacity_bytes"`
+	FailurePredicted bool   `json:"failure_predicted" yaml:"failure_predicted"`
+	Status           Status `json:"status" yaml:"status"`
+	// Raw is the Drive payload as the BMC returned it.
+	Raw json.RawMessage `json:"-" yaml:"-"`
+}
+
+// Volume is a logical drive.
+type Volume struct {
+	ID            string `json:"id" yaml:"id"`
+	Name          string `json:"name" yaml:"name"`
+	RAIDType      string `json:"raid_type,omitempty" yaml:"raid_type,omitempty"`
+	CapacityBytes int64  `json:"capacity_bytes" yaml:"capacity_bytes"`
+	Encrypted     bool   `json:"encrypted" yaml:"encrypted"`
+	Status        Status `json:"status" yaml:"status"`
+	// Raw is the Volume payload as the BMC returned it.
+	Raw json.RawMessage `json:"-" yaml:"-"`
+}
+
+// PowerSupply is a PSU.
+type PowerSupply struct {
+	ID              string  `json:"id" yaml:"id"`
+	Name            string  `json:"name" yaml:"name"`
+	Manufacturer    string  `json:"manufacturer,omitempty" yaml:"manufacturer,omitempty"`
+	Model           string  `json:"model,omitempty" yaml:"model,omitempty"`
+	SerialNumber    string  `json:"serial_number,omitempty" yaml:"serial_number,omitempty"`
+	FirmwareVersion string  `json:"firmware_version,omitempty" yaml:"firmware_version,omitempty"`
+	CapacityWatts   float64 `json:"capacity_watts" yaml:"capacity_watts"`
+	OutputWatts     float64 `json:"output_watts" yaml:"output_watts"`
+	InputVolts      float64 `json:"input_volts,omitempty" yaml:"input_volts,omitempty"`
+	Status          Status  `json:"status" yaml:"status"`
+}
+
+// Fan is a cooling fan. Only the reading the BMC reports is set.
+type Fan struct {
+	ID           string   `json:"id" yaml:"id"`
+	Name         string   `json:"name" yaml:"name"`
+	SpeedRPM     *float64 `json:"speed_rpm,omitempty" yaml:"speed_rpm,omitempty"`
+	SpeedPercent *float64 `json:"speed_percent,omitempty" yaml:"speed_percent,omitempty"`
+	Status       Status   `json:"status" yaml:"status"`
+}
+
+// Temperature is a temperature sensor.
+type Temperature struct {
+	ID              string   `json:"id" yaml:"id"`
+	Name            string   `json:"name" yaml:"name"`
+	Celsius         *float64 `json:"celsius,omitempty" yaml:"celsius,omitempty"`
+	CriticalCelsius *float64 `json:"critical_celsius,omitempty" yaml:"critical_celsius,omitempty"`
+	Status          Status   `json:"status" yaml:"status"`
+}
+
+// Firmware is an installed firmware component.
+type Firmware struct {
+	ID           string `json:"id" yaml:"id"`
+	Name         string `json:"name" yaml:"name"`
+	Version      string `json:"version" yaml:"version"`
+	Manufacturer string `json:"manufacturer,omitempty" yaml:"manufacturer,omitempty"`
+	Updateable   bool   `json:"updateable" yaml:"updateable"`
+}
+
+// Issue is a component whose health is not OK.
+type Issue struct {
+	// Component is the kind of component, e.g. "drive" or "fan".
+	Component string `json:"component" yaml:"component"`
+	ID        string `json:"id" yaml:"id"`
+	Status    Status `json:"status" yaml:"status"`
+}
+
+// Issues lists the present, enabled components whose health is not OK.
+// Absent and disabled components are not expected to be healthy and are left
+// out.
+func (inv *Inventory) Issues() []Issue {
+	var issues []Issue
+	add := func(component, id string, s Status) {
+		if s.counts() && s.Health != HealthOK {
+			issues = append(issues, Issue{Component: component, ID: id, Status: s})
+		}
+	}
+	for _, c := range inv.Chassis {
+		add("chassis", c.ID, c.Status)
+	}
+	for _, p := range inv.Processors {
+		add("processor", p.ID, p.Status)
+	}
+	for _, d := range inv.Memory {
+		add("memory", d.ID, d.Status)
+	}
+	for _, n := range inv.NICs {
+		add("nic", n.ID, n.Status)
+	}
+	for _, c := range inv.Controllers {
+		add("controller", c.ID, c.Status)
+		for _, d := range c.Drives {
+			add("drive", d.ID, d.Status)
+		}
+		for _, v := range c.Volumes {
+			add("volume", v.ID, v.Status)
+		}
+	}
+	for _, p := range inv.PowerSupplies {
+		add("power_supply", p.ID, p.Status)
+	}
+	for _, f := range inv.Fans {
+		add("fan", f.ID, f.Status)
+	}
+	for _, t := range inv.Temperatures {
+		add("temperature", t.ID, t.Status)
+	}
+	return issues
+}
+
+// Health rolls up the health of the system and of every component to the
+// worst one, ignoring absent and disabled components.
+func (inv *Inventory) Health() Health {
+	h := inv.System.Status.Health
+	for _, issue := range inv.Issues() {
+		h = h.Worse(issue.Status.Health)
+	}
+	return h
+}
+
+var vendors = map[string]string{
+	"dell":                       "Dell",
+	"dell inc.":                  "Dell",
+	"lenovo":                     "Lenovo",
+	"hpe":                        "HPE",
+	"hp":                         "HPE",
+	"hewlett packard enterprise": "HPE",
+	"supermicro":                 "Supermicro",
+	"super micro computer, inc.": "Supermicro",
+	"inspur":                     "Inspur",
+	"cisco systems inc":          "Cisco",
+	"cisco systems, inc.":        "Cisco",
+}
+
+// VendorName normalizes a manufacturer as reported by a BMC, e.g. "Dell
+// Inc." and "DELL" both become Dell.
+func VendorName(manufacturer string) string {
+	m := strings.TrimSpace(manufacturer)
+	if v, ok := vendors[strings.ToLower(m)]; ok {
+		return v
+	}
+	return m
+}
@@ -0,0 +1,217 @@
+package inventory
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/angelhvargas/redfishcli/pkg/client"
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+	"github.com/angelhvargas/redfishcli/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBMC serves Redfish documents from a map. It is a ServerClient, a
+// StorageInspector and a Source.
+type fakeBMC map[string]string
+
+func (f fakeBMC) Fetch(uri string, target interface{}) error {
+	doc, ok := f[uri]
+	if !ok {
+		return httpclient.ErrNotFound
+	}
+	return json.Unmarshal([]byte(doc), target)
+}
+
+func fetchAs[T any](f fakeBMC, uri string) (*T, error) {
+	var v T
+	if err := f.Fetch(uri, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (f fakeBMC) GetServerInfo() (*model.ComputerSystem, error) {
+	return fetchAs[model.ComputerSystem](f, "/redfish/v1/Systems/1")
+}
+
+func (f fakeBMC) GetStorageInfo() (*model.Collection[model.IDRef], error) {
+	return fetchAs[model.Collection[model.IDRef]](f, "/redfish/v1/Systems/1/Storage")
+}
+
+func (f fakeBMC) GetStorageControllers(*model.StorageControllerConfig) ([]model.StorageController, error) {
+	coll, err := f.GetStorageInfo()
+	if err != nil {
+		return nil, err
+	}
+	var refs []model.StorageController
+	for _, m := range coll.Members {
+		refs = append(refs, model.StorageController{ODataID: m.ODataID})
+	}
+	return refs, nil
+}
+
+func (f fakeBMC) GetRAIDVolumeInfo(uri string) (*model.Volume, error) {
+	return fetchAs[model.Volume](f, uri)
+}
+
+func (f fakeBMC) GetStorageControllerInfo(uri string) (*model.Storage, error) {
+	return fetchAs[model.Storage](f, uri)
+}
+
+func (f fakeBMC) GetStorageDriveDetails(uri string) (*model.Drive, error) {
+	return fetchAs[model.Drive](f, uri)
+}
+
+func newFakeBMC() fakeBMC {
+	return fakeBMC{
+		"/redfish/v1": `{"UpdateService": {"@odata.id": "/redfish/v1/UpdateService"}}`,
+		"/redfish/v1/Systems/1": `{
+			"Id": "1", "Manufacturer": "Dell Inc.", "Model": "PowerEdge R740", "PowerState": "On",
+			"Status": {"HealthRollup": "OK"},
+			"MemorySummary": {"TotalSystemMemoryGiB": 32},
+			"Processors": {"@odata.id": "/redfish/v1/Systems/1/Processors"},
+			"Memory": {"@odata.id": "/redfish/v1/Systems/1/Memory"},
+			"Storage": {"@odata.id": "/redfish/v1/Systems/1/Storage"},
+			"Links": {"Chassis": [{"@odata.id": "/redfish/v1/Chassis/1"}]}
+		}`,
+		"/redfish/v1/Systems/1/Processors":      `{"Members": [{"@odata.id": "/redfish/v1/Systems/1/Processors/CPU1"}]}`,
+		"/redfish/v1/Systems/1/Processors/CPU1": `{"Id": "CPU1", "TotalCores": 16, "MaxSpeedMHz": 3200, "Status": {"Health": "OK", "State": "Enabled"}}`,
+		"/redfish/v1/Systems/1/Memory":          `{"Members": [{"@odata.id": "/redfish/v1/Systems/1/Memory/A1"}, {"@odata.id": "/redfish/v1/Systems/1/Memory/A2"}]}`,
+		"/redfish/v1/Systems/1/Memory/A1":       `{"Id": "A1", "CapacityMiB": 16384, "Status": {"Health": "OK", "State": "Enabled"}}`,
+		"/redfish/v1/Systems/1/Memory/A2":       `{"Id": "A2", "Status": {"Health": "Critical", "State": "Absent"}}`,
+		"/redfish/v1/Systems/1/Storage":         `{"Members": [{"@odata.id": "/redfish/v1/Systems/1/Storage/RAID"}]}`,
+		"/redfish/v1/Systems/1/Storage/RAID": `{
+			"Id": "RAID", "Status": {"Health": "OK", "State": "Enabled"},
+			"Drives": [{"@odata.id": "/redfish/v1/Systems/1/Storage/RAID/Drives/0"}, {"@odata.id": "/redfish/v1/Systems/1/Storage/RAID/Drives/1"}],
+			"Volumes": {"@odata.id": "/redfish/v1/Systems/1/Storage/RAID/Volumes"}
+		}`,
+		"/redfish/v1/Systems/1/Storage/RAID/Drives/0":  `{"Id": "0", "CapacityBytes": 1000, "Status": {"Health": "Warning", "State": "Enabled"}}`,
+		"/redfish/v1/Systems/1/Storage/RAID/Volumes":   `{"Members": [{"@odata.id": "/redfish/v1/Systems/1/Storage/RAID/Volumes/0"}]}`,
+		"/redfish/v1/Systems/1/Storage/RAID/Volumes/0": `{"Id": "0", "VolumeType": "Mirrored", "Status": {"Health": "OK", "State": "Enabled"}}`,
+		"/redfish/v1/Chassis/1": `{
+			"Id": "1", "PowerState": "PoweringOn", "Status": {"Health": "OK", "State": "Enabled"},
+			"Thermal": {"@odata.id": "/redfish/v1/Chassis/1/Thermal"}
+		}`,
+		"/redfish/v1/Chassis/1/Thermal": `{
+			"Fans": [
+				{"MemberId": "0", "Reading": 5400, "ReadingUnits": "RPM", "Status": {"Health": "OK", "State": "Enabled"}},
+				{"Name": "Fan 2", "Reading": 40, "ReadingUnits": "Percent", "Status": {"Health": "OK", "State": "Enabled"}}
+			],
+			"Temperatures": [{"MemberId": "Inlet", "ReadingCelsius": 23.5, "Status": {"Health": "OK", "State": "Enabled"}}]
+		}`,
+		"/redfish/v1/UpdateService":                        `{"FirmwareInventory": {"@odata.id": "/redfish/v1/UpdateService/FirmwareInventory"}}`,
+		"/redfish/v1/UpdateService/FirmwareInventory":      `{"Members": [{"@odata.id": "/redfish/v1/UpdateService/FirmwareInventory/BIOS"}]}`,
+		"/redfish/v1/UpdateService/FirmwareInventory/BIOS": `{"Id": "BIOS", "Version": "2.1.0", "Updateable": true}`,
+	}
+}
+
+func TestCollect(t *testing.T) {
+	inv, err := Collect(newFakeBMC(), "host1", PartAll)
+
+	// Drive 1 is listed but missing: the rest is still collected.
+	require.Error(t, err)
+	assert.ErrorIs(t, err, httpclient.ErrNotFound)
+	assert.Contains(t, err.Error(), "drive /redfish/v1/Systems/1/Storage/RAID/Drives/1")
+
+	assert.Equal(t, "host1", inv.Hostname)
+	assert.Equal(t, "Dell", inv.Vendor)
+	assert.Equal(t, HealthOK, inv.System.Status.Health, "falls back to the rollup")
+	assert.Equal(t, PowerOn, inv.System.PowerState)
+	assert.EqualValues(t, 32<<30, inv.System.MemoryBytes)
+
+	require.Len(t, inv.Processors, 1)
+	assert.Equal(t, 16, inv.Processors[0].Cores)
+	assert.Equal(t, 3200, inv.Processors[0].MaxSpeedMHz)
+
+	require.Len(t, inv.Memory, 2)
+	assert.EqualValues(t, 16<<30, inv.Memory[0].CapacityBytes)
+	assert.Equal(t, StateAbsent, inv.Memory[1].Status.State)
+
+	require.Len(t, inv.Controllers, 1)
+	ctrl := inv.Controllers[0]
+	assert.Equal(t, 2, ctrl.DriveCount)
+	require.Len(t, ctrl.Drives, 1)
+	assert.EqualValues(t, 1000, ctrl.Drives[0].CapacityBytes)
+	require.Len(t, ctrl.Volumes, 1)
+	assert.Equal(t, "Mirrored", ctrl.Volumes[0].RAIDType)
+
+	require.Len(t, inv.Chassis, 1)
+	assert.Equal(t, PowerTransitioning, inv.Chassis[0].PowerState)
+	require.Len(t, inv.Fans, 2)
+	assert.Equal(t, 5400.0, *inv.Fans[0].SpeedRPM)
+	assert.Nil(t, inv.Fans[0].SpeedPercent)
+	assert.Equal(t, "Fan 2", inv.Fans[1].ID)
+	assert.Equal(t, 40.0, *inv.Fans[1].SpeedPercent)
+	require.Len(t, inv.Temperatures, 1)
+	assert.Equal(t, 23.5, *inv.Temperatures[0].Celsius)
+
+	require.Len(t, inv.Firmware, 1)
+	assert.Equal(t, "2.1.0", inv.Firmware[0].Version)
+	assert.True(t, inv.Firmware[0].Updateable)
+
+	// The absent DIMM's Critical does not count, the Warning drive does.
+	assert.Equal(t, HealthWarning, inv.Health())
+	assert.Equal(t, []Issue{{Component: "drive", ID: "0", Status: Status{HealthWarning, StateEnabled}}}, inv.Issues())
+}
+
+func TestReadOnlyRequestedParts(t *testing.T) {
+	bmc := newFakeBMC()
+	inv := &Inventory{Hostname: "host1"}
+
+	require.NoError(t, inv.Read(bmc, PartSystem))
+	assert.Equal(t, "1", inv.System.ID)
+	assert.Nil(t, inv.Controllers)
+	assert.Nil(t, inv.Processors)
+
+	require.NoError(t, inv.Read(bmc, PartStorage))
+	require.Len(t, inv.Controllers, 1)
+	assert.Nil(t, inv.Controllers[0].Drives)
+	assert.Equal(t, 2, inv.Controllers[0].DriveCount)
+}
+
+// systemOnly is a backend implementing nothing but the ServerClient.
+type systemOnly struct{ bmc fakeBMC }
+
+func (s systemOnly) GetServerInfo() (*model.ComputerSystem, error) {
+	return s.bmc.GetServerInfo()
+}
+
+func TestCollectSkipsWhatTheBackendCannotRead(t *testing.T) {
+	inv, err := Collect(systemOnly{newFakeBMC()}, "host1", PartSystem|PartProcessors)
+	require.NoError(t, err)
+	assert.Equal(t, "1", inv.System.ID)
+	assert.Nil(t, inv.Processors)
+
+	_, err = Collect(systemOnly{newFakeBMC()}, "host1", PartStorage)
+	assert.ErrorIs(t, err, client.ErrNotSupported)
+}
+
+func TestStatusOf(t *testing.T) {
+	tests := []struct {
+		in   model.Status
+		want Status
+	}{
+		{model.Status{Health: model.HealthOK, State: model.StateEnabled}, Status{HealthOK, StateEnabled}},
+		{model.Status{Health: model.HealthCritical, State: model.StateUnavailableOffline}, Status{HealthCritical, StateDisabled}},
+		{model.Status{State: model.StateStandbySpare}, Status{HealthUnknown, StateStandby}},
+		{model.Status{Health: model.HealthWarning, State: model.StateUpdating}, Status{HealthWarning, StateBusy}},
+		{model.Status{Health: "Degraded", State: "Sleeping"}, Status{HealthUnknown, StateUnknown}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, statusOf(tt.in), "%+v", tt.in)
+	}
+}
+
+func TestHealthWorse(t *testing.T) {
+	assert.Equal(t, HealthUnknown, HealthOK.Worse(HealthUnknown))
+	assert.Equal(t, HealthWarning, HealthUnknown.Worse(HealthWarning))
+	assert.Equal(t, HealthCritical, HealthCritical.Worse(HealthWarning))
+}
+
+func TestVendorName(t *testing.T) {
+	assert.Equal(t, "Dell", VendorName("Dell Inc."))
+	assert.Equal(t, "Dell", VendorName("DELL"))
+	assert.Equal(t, "Lenovo", VendorName(" Lenovo "))
+	assert.Equal(t, "Acme", VendorName("Acme"))
+}
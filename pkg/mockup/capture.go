@@ -0,0 +1,224 @@
+// Package mockup captures a live Redfish service into the directory layout
+// used by the DMTF Redfish-Mockup-Creator, so it can be shared, diffed, or
+// served again by the emulator.
+package mockup
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+	"github.com/angelhvargas/redfishcli/pkg/logger"
+	"github.com/angelhvargas/redfishcli/pkg/redact"
+)
+
+// ServiceRoot is where every crawl starts.
+const ServiceRoot = "/redfish/v1"
+
+// Options controls a capture.
+type Options struct {
+	// Host is the BMC address (hostname or host:port).
+	Host     string
+	Username string
+	Password string
+	// HTTP is the client pipeline to use; it is extended with rate limiting
+	// and retries for the crawl.
+	HTTP httpclient.Config
+	// OutDir receives the mockup. Resources are written to
+	// OutDir/redfish/v1/.../index.json.
+	OutDir string
+	// Headers also writes a headers.json next to each index.json.
+	Headers bool
+	// Redact scrubs values before they are written.
+	Redact redact.Rules
+	// Interval is the minimum delay between two requests. Zero disables
+	// rate limiting.
+	Interval time.Duration
+	// MaxResources stops the crawl after this many resources (0 = no limit).
+	MaxResources int
+	// UseSession authenticates through the SessionService instead of
+	// sending basic credentials with every request.
+	UseSession bool
+}
+
+// Result summarises a capture.
+type Result struct {
+	Resources int
+	// Errors maps a URI to the error fetching it. Failed resources are
+	// skipped so one broken endpoint does not abort the crawl.
+	Errors map[string]error
+	// Truncated is set when MaxResources stopped the crawl early.
+	Truncated bool
+}
+
+// Capture crawls the Redfish tree of the BMC, following every @odata.id
+// link below the service root, and writes it to opts.OutDir.
+func Capture(opts Options) (*Result, error) {
+	return Crawl(opts, func(uri string, doc interface{}, body []byte, header http.Header) error {
+		return write(opts, uri, redact.Value(doc, opts.Redact), header)
+	})
+}
+
+// Crawl walks the Redfish tree of the BMC like Capture, but hands each
+// resource to visit instead of writing it: the decoded document, the body as
+// received and the response headers. An error from visit aborts the crawl.
+// OutDir, Headers and Redact are ignored.
+func Crawl(opts Options, visit func(uri string, doc interface{}, body []byte, header http.Header) error) (*Result, error) {
+	base := "https://" + opts.Host
+	cfg := opts.HTTP.Use(httpclient.Retry(3, time.Second))
+	if opts.Interval > 0 {
+		cfg = cfg.Use(httpclient.RateLimit(opts.Interval))
+	}
+
+	var mu sync.Mutex
+	headers := make(map[string]http.Header)
+	cfg = cfg.Use(httpclient.Record(func(ex *httpclient.Exchange) {
+		if ex.Method != http.MethodGet || ex.TransportError != nil {
+			return
+		}
+		if u, err := url.Parse(ex.URL); err == nil {
+			mu.Lock()
+			headers[u.Path] = ex.ResponseHeader
+			mu.Unlock()
+		}
+	}))
+
+	if opts.UseSession {
+		session, err := httpclient.Login(base, opts.Username, opts.Password, opts.HTTP)
+		if err != nil {
+			logger.Log.Warnf("Session login to %s failed, falling back to basic auth: %s", opts.Host, err)
+		} else {
+			defer session.Logout()
+			cfg = cfg.Use(session.Middleware())
+		}
+	}
+
+	result := &Result{Errors: make(map[string]error)}
+	visited := map[string]bool{ServiceRoot: true}
+	queue := []string{ServiceRoot}
+
+	for len(queue) > 0 {
+		if opts.MaxResources > 0 && result.Resources >= opts.MaxResources {
+			result.Truncated = true
+			break
+		}
+		uri := queue[0]
+		queue = queue[1:]
+
+		body, err := httpclient.Get(base+uri, opts.Username, opts.Password, cfg)
+		if err != nil {
+			result.Errors[uri] = err
+			if uri == ServiceRoot {
+				return result, fmt.Errorf("fetching service root: %w", err)
+			}
+			continue
+		}
+
+		var doc interface{}
+		if err := json.Unmarshal(body, &doc); err != nil {
+			result.Errors[uri] = fmt.Errorf("invalid JSON: %w", err)
+			continue
+		}
+		for _, link := range Links(doc) {
+			if !visited[link] {
+				visited[link] = true
+				queue = append(queue, link)
+			}
+		}
+
+		mu.Lock()
+		h := headers[uri]
+		mu.Unlock()
+		if err := visit(uri, doc, body, h); err != nil {
+			return result, err
+		}
+		result.Resources++
+	}
+	return result, nil
+}
+
+// Links returns every distinct @odata.id below the service root referenced
+// anywhere in doc, with fragments removed, in sorted order.
+func Links(doc interface{}) []string {
+	seen := make(map[string]bool)
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			for k, child := range t {
+				if k == "@odata.id" {
+					if s, ok := child.(string); ok {
+						if link := cleanLink(s); link != "" {
+							seen[link] = true
+						}
+					}
+					continue
+				}
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range t {
+				walk(child)
+			}
+		}
+	}
+	walk(doc)
+
+	links := make([]string, 0, len(seen))
+	for l := range seen {
+		links = append(links, l)
+	}
+	sort.Strings(links)
+	return links
+}
+
+func cleanLink(s string) string {
+	if u, err := url.Parse(s); err == nil {
+		s = u.Path
+	}
+	s = strings.TrimSuffix(s, "/")
+	if s != ServiceRoot && !strings.HasPrefix(s, ServiceRoot+"/") {
+		return ""
+	}
+	return s
+}
+
+var credentialHeaders = []string{"Authorization", "X-Auth-Token", "Set-Cookie", "Cookie"}
+
+func write(opts Options, uri string, doc interface{}, header http.Header) error {
+	dir := filepath.Join(opts.OutDir, filepath.FromSlash(strings.TrimPrefix(uri, "/")))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(doc, "", "    ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index.json"), append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	if !opts.Headers || header == nil {
+		return nil
+	}
+
+	flat := make(map[string]string)
+	for k := range header {
+		flat[k] = header.Get(k)
+	}
+	for _, h := range credentialHeaders {
+		delete(flat, h)
+	}
+	data, err = json.MarshalIndent(map[string]interface{}{"GET": flat}, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "headers.json"), append(data, '\n'), 0o644)
+}
@@ -0,0 +1,149 @@
+package mockup
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/angelhvargas/redfishcli/pkg/emulator"
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+	"github.com/angelhvargas/redfishcli/pkg/redact"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startEmulator(t *testing.T) (*emulator.Emulator, *emulator.Server) {
+	emu, err := emulator.New(emulator.IDRAC(), emulator.Options{Username: "root", Password: "calvin"})
+	require.NoError(t, err)
+
+	mgr := "/redfish/v1/Managers/iDRAC.Embedded.1"
+	manager, _ := emu.Resource(mgr)
+	manager["EthernetInterfaces"] = map[string]interface{}{"@odata.id": mgr + "/EthernetInterfaces"}
+	emu.SetResource(mgr, manager)
+	emu.SetResource(mgr+"/EthernetInterfaces", map[string]interface{}{
+		"Members": []interface{}{map[string]interface{}{"@odata.id": mgr + "/EthernetInterfaces/NIC.1"}},
+	})
+	emu.SetResource(mgr+"/EthernetInterfaces/NIC.1", map[string]interface{}{
+		"Id":            "NIC.1",
+		"MACAddress":    "d0:94:66:2a:1b:3c",
+		"IPv4Addresses": []interface{}{map[string]interface{}{"Address": "10.20.30.40"}},
+		"IPv6Addresses": []interface{}{map[string]interface{}{"Address": "fe80::d294:66ff:fe2a:1b3c"}},
+		"Description":   "Link to d0:94:66:2a:1b:3c",
+	})
+
+	srv, err := emu.Start("127.0.0.1:0", emulator.StartOptions{})
+	require.NoError(t, err)
+	t.Cleanup(func() { srv.Close() })
+	return emu, srv
+}
+
+func readResource(t *testing.T, dir, uri string) map[string]interface{} {
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(uri[1:]), "index.json"))
+	require.NoError(t, err)
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &doc))
+	return doc
+}
+
+func TestCapture(t *testing.T) {
+	_, srv := startEmulator(t)
+	out := t.TempDir()
+
+	result, err := Capture(Options{
+		Host:       srv.Host,
+		Username:   "root",
+		Password:   "calvin",
+		HTTP:       httpclient.DefaultConfig(),
+		OutDir:     out,
+		Headers:    true,
+		Redact:     redact.Rules{MACs: true, IPs: true}.WithKeys(redact.SerialKeys...),
+		UseSession: true,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, result.Errors)
+	assert.False(t, result.Truncated)
+	assert.Greater(t, result.Resources, 20)
+
+	t.Run("Follows links to leaf resources", func(t *testing.T) {
+		drive := readResource(t, out, "/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1/Drives/Disk.Bay.0")
+		assert.Equal(t, "Disk.Bay.0", drive["Id"])
+		entry := readResource(t, out, "/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Entries/2")
+		assert.Equal(t, "Warning", entry["Severity"])
+	})
+
+	t.Run("Redacts serials, MACs and IPs", func(t *testing.T) {
+		system := readResource(t, out, "/redfish/v1/Systems/System.Embedded.1")
+		assert.Equal(t, redact.Placeholder, system["SerialNumber"])
+		assert.Equal(t, "PowerEdge R740", system["Model"])
+
+		nic := readResource(t, out, "/redfish/v1/Managers/iDRAC.Embedded.1/EthernetInterfaces/NIC.1")
+		assert.Equal(t, "00:00:00:00:00:00", nic["MACAddress"])
+		assert.Equal(t, "Link to 00:00:00:00:00:00", nic["Description"])
+		assert.Equal(t, "192.0.2.1", nic["IPv4Addresses"].([]interface{})[0].(map[string]interface{})["Address"])
+		assert.Equal(t, "2001:db8::1", nic["IPv6Addresses"].([]interface{})[0].(map[string]interface{})["Address"])
+	})
+
+	t.Run("Writes headers without credentials", func(t *testing.T) {
+		data, err := os.ReadFile(filepath.Join(out, "redfish", "v1", "Systems", "index.json"))
+		require.NoError(t, err)
+		assert.NotEmpty(t, data)
+		data, err = os.ReadFile(filepath.Join(out, "redfish", "v1", "Systems", "headers.json"))
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "Content-Type")
+		assert.NotContains(t, string(data), "X-Auth-Token")
+	})
+
+	t.Run("Capture can be served by the emulator", func(t *testing.T) {
+		emu, err := emulator.New(emulator.ProfileFromDir(out), emulator.Options{Username: "root", Password: "calvin"})
+		require.NoError(t, err)
+		system, ok := emu.Resource("/redfish/v1/Systems/System.Embedded.1")
+		require.True(t, ok)
+		assert.Equal(t, "On", system["PowerState"])
+	})
+}
+
+func TestCaptureLimits(t *testing.T) {
+	_, srv := startEmulator(t)
+
+	result, err := Capture(Options{
+		Host:         srv.Host,
+		Username:     "root",
+		Password:     "calvin",
+		HTTP:         httpclient.DefaultConfig(),
+		OutDir:       t.TempDir(),
+		MaxResources: 5,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.Resources)
+	assert.True(t, result.Truncated)
+
+	// The service root is public, so bad credentials surface as per-URI
+	// errors rather than aborting the crawl.
+	result, err = Capture(Options{
+		Host:     srv.Host,
+		Username: "root",
+		Password: "wrong",
+		HTTP:     httpclient.DefaultConfig(),
+		OutDir:   t.TempDir(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Resources)
+	assert.ErrorIs(t, result.Errors["/redfish/v1/Systems"], httpclient.ErrAuthentication)
+}
+
+func TestLinks(t *testing.T) {
+	var doc interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"@odata.id": "/redfish/v1/Chassis/1/Power",
+		"PowerSupplies": [{"@odata.id": "/redfish/v1/Chassis/1/Power#/PowerSupplies/0"}],
+		"Links": {"Chassis": [{"@odata.id": "/redfish/v1/Chassis/1/"}], "Other": {"@odata.id": "https://bmc/redfish/v1/Managers/1"}},
+		"Elsewhere": {"@odata.id": "/some/other/api"}
+	}`), &doc))
+
+	assert.Equal(t, []string{
+		"/redfish/v1/Chassis/1",
+		"/redfish/v1/Chassis/1/Power",
+		"/redfish/v1/Managers/1",
+	}, Links(doc))
+}
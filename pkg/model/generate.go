@@ -0,0 +1,6 @@
+package model
+
+// The Redfish resource models in zz_generated.go come from the DMTF schemas
+// vendored in third_party/redfish-schema. Add a schema to the list below and
+// run `go generate ./pkg/model` to model another resource.
+//go:generate go run ../../tools/schemagen -schemas ../../third_party/redfish-schema -out zz_generated.go ComputerSystem Storage Storage/StorageController Drive Volume LogEntry Manager Chassis Power Thermal Processor Memory EthernetInterface SoftwareInventory
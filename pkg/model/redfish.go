@@ -0,0 +1,44 @@
+package model
+
+// IDRef is a link to another resource, which models hold instead of the
+// resource itself.
+type IDRef struct {
+	ODataID string `json:"@odata.id,omitempty"`
+}
+
+// Collection is a Redfish resource collection such as /Systems/1/Storage.
+type Collection[T any] struct {
+	ODataContext string `json:"@odata.context,omitempty"`
+	ODataID      string `json:"@odata.id,omitempty"`
+	ODataType    string `json:"@odata.type,omitempty"`
+	Name         string `json:"Name,omitempty"`
+	Members      []T    `json:"Members"`
+	MembersCount int64  `json:"Members@odata.count,omitempty"`
+	NextLink     string `json:"Members@odata.nextLink,omitempty"`
+}
+
+// Names used before the models were generated from the DMTF schemas.
+
+// Deprecated: use ComputerSystem.
+type ServerInfo = ComputerSystem
+
+// Deprecated: use Boot.
+type BootInfo = Boot
+
+// Deprecated: use LogEntry.
+type EventLogEntry = LogEntry
+
+// Deprecated: use Collection[LogEntry].
+type EventLog = Collection[LogEntry]
+
+// Deprecated: use Volume.
+type RAIDVolume = Volume
+
+// Deprecated: use Storage.
+type StorageControllerDetails = Storage
+
+// Deprecated: use Collection[IDRef].
+type StorageInfo = Collection[IDRef]
+
+// Deprecated: use IDRef.
+type OdataObject = IDRef
@@ -0,0 +1,94 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// Resource keeps what a model was decoded from: the original payload and its
+// Oem section. Embedding it in a model lets callers reach vendor properties
+// without a struct field for each one.
+type Resource struct {
+	// Raw is the JSON payload exactly as the BMC returned it.
+	Raw json.RawMessage `json:"-" yaml:"-"`
+	// Oem is the vendor extension block, keyed by vendor (Dell, Lenovo, ...).
+	Oem map[string]interface{} `json:"Oem,omitempty" yaml:"oem,omitempty"`
+}
+
+// decode unmarshals data into v, the model's plain (method-less) type, and
+// keeps a copy of data in res.
+//
+// Decoding is tolerant of properties whose JSON type differs from the schema,
+// as some firmware sends numbers as strings: such a property is skipped, the
+// rest of the model is still filled in and Raw keeps the original.
+func decode[T any](data []byte, v *T, res *Resource) error {
+	var typeErr *json.UnmarshalTypeError
+	if err := json.Unmarshal(data, v); err != nil && !errors.As(err, &typeErr) {
+		return err
+	}
+	res.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// RawJSON returns the original payload.
+func (r *Resource) RawJSON() json.RawMessage {
+	return r.Raw
+}
+
+// OemValue walks the Oem section along path, e.g. OemValue("Dell",
+// "DellSystem", "BIOSReleaseDate").
+func (r *Resource) OemValue(path ...string) (interface{}, bool) {
+	var v interface{} = r.Oem
+	for _, key := range path {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return v, v != nil
+}
+
+// OemString is OemValue for string properties; it returns "" when the path
+// is missing or not a string.
+func (r *Resource) OemString(path ...string) string {
+	v, _ := r.OemValue(path...)
+	s, _ := v.(string)
+	return s
+}
+
+// OemSection returns the object at path in the Oem section, or nil.
+func (r *Resource) OemSection(path ...string) map[string]interface{} {
+	v, _ := r.OemValue(path...)
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+// Dell returns the Oem.Dell section.
+func (r *Resource) Dell() map[string]interface{} {
+	return r.OemSection("Dell")
+}
+
+// Lenovo returns the Oem.Lenovo section.
+func (r *Resource) Lenovo() map[string]interface{} {
+	return r.OemSection("Lenovo")
+}
+
+// DellSystem returns Oem.Dell.DellSystem of an iDRAC ComputerSystem, which
+// carries the rollup health of CPUs, memory, fans, storage and more.
+func (s *ComputerSystem) DellSystem() map[string]interface{} {
+	return s.OemSection("Dell", "DellSystem")
+}
+
+// DellPhysicalDisk returns Oem.Dell.DellPhysicalDisk of an iDRAC drive.
+func (d *Drive) DellPhysicalDisk() map[string]interface{} {
+	return d.OemSection("Dell", "DellPhysicalDisk")
+}
+
+// DellController returns Oem.Dell.DellController of an iDRAC storage
+// subsystem.
+func (c *Storage) DellController() map[string]interface{} {
+	return c.OemSection("Dell", "DellController")
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceKeepsRawAndOem(t *testing.T) {
+	payload := `{
+		"Id": "System.Embedded.1",
+		"PowerState": "On",
+		"ProcessorSummary": {"Count": 2},
+		"Oem": {"Dell": {"DellSystem": {"BIOSReleaseDate": "07/19/2021", "CPURollupStatus": "OK"}}}
+	}`
+
+	var info ComputerSystem
+	require.NoError(t, json.Unmarshal([]byte(payload), &info))
+	assert.Equal(t, "System.Embedded.1", info.ID)
+	assert.Equal(t, PowerStateOn, info.PowerState)
+	assert.JSONEq(t, payload, string(info.Raw))
+
+	assert.Equal(t, "OK", info.DellSystem()["CPURollupStatus"])
+	assert.Equal(t, "07/19/2021", info.OemString("Dell", "DellSystem", "BIOSReleaseDate"))
+	assert.Empty(t, info.OemString("Dell", "DellSystem", "Missing"))
+	assert.Nil(t, info.Lenovo())
+}
+
+func TestNestedResourcesKeepTheirOwnPayload(t *testing.T) {
+	payload := `{"Members": [
+		{"@odata.id": "/Storage/RAID_Slot1", "Name": "RAID", "Oem": {"Lenovo": {"Temperature": 41}}},
+		{"@odata.id": "/Storage/AHCI", "Name": "AHCI"}
+	]}`
+
+	var resp Collection[StorageController]
+	require.NoError(t, json.Unmarshal([]byte(payload), &resp))
+	require.Len(t, resp.Members, 2)
+	assert.Equal(t, "RAID", resp.Members[0].Name)
+	assert.EqualValues(t, 41, resp.Members[0].Lenovo()["Temperature"])
+	assert.JSONEq(t, `{"@odata.id": "/Storage/AHCI", "Name": "AHCI"}`, string(resp.Members[1].Raw))
+}
+
+func TestOemRoundTrip(t *testing.T) {
+	var drive Drive
+	require.NoError(t, json.Unmarshal([]byte(`{"Id": "Disk.Bay.0", "Oem": {"Dell": {"DellPhysicalDisk": {"RaidStatus": "Online"}}}}`), &drive))
+	assert.Equal(t, "Online", drive.DellPhysicalDisk()["RaidStatus"])
+
+	data, err := json.Marshal(drive)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"Oem":{"Dell"`)
+	assert.NotContains(t, string(data), "Raw")
+}
+
+func TestDecodeToleratesMismatchedTypes(t *testing.T) {
+	// Some firmware reports numbers as strings; the rest of the model must
+	// still decode.
+	payload := `{"Id": "Disk.Bay.0", "CapacityBytes": "479559942144", "MediaType": "SSD", "Status": {"Health": "OK"}}`
+
+	var drive Drive
+	require.NoError(t, json.Unmarshal([]byte(payload), &drive))
+	assert.Equal(t, "Disk.Bay.0", drive.ID)
+	assert.Equal(t, MediaTypeSSD, drive.MediaType)
+	assert.Equal(t, HealthOK, drive.Status.Health)
+	assert.JSONEq(t, payload, string(drive.Raw))
+
+	var bad Drive
+	assert.Error(t, json.Unmarshal([]byte(`{"Id": `), &bad))
+}
+
+func TestEnumsAcceptValuesFromNewerSchemas(t *testing.T) {
+	var system ComputerSystem
+	require.NoError(t, json.Unmarshal([]byte(`{"PowerState": "On", "SystemType": "Composed"}`), &system))
+	assert.True(t, system.PowerState.IsKnown())
+	assert.Equal(t, SystemType("Composed"), system.SystemType)
+	assert.False(t, system.SystemType.IsKnown())
+}
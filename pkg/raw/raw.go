@@ -0,0 +1,247 @@
+// Package raw sends arbitrary Redfish requests to a BMC, for everything the
+// typed backends do not model. It reuses the client pipeline and session
+// handling of the rest of redfishcli and follows task monitors until the
+// operation they track finishes.
+package raw
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+	"github.com/angelhvargas/redfishcli/pkg/logger"
+)
+
+// Options controls a raw client.
+type Options struct {
+	// Host is the BMC address (hostname or host:port).
+	Host     string
+	Username string
+	Password string
+	// HTTP is the client pipeline to use.
+	HTTP httpclient.Config
+	// UseSession authenticates through the SessionService instead of
+	// sending basic credentials with every request. If the login fails the
+	// client falls back to basic credentials.
+	UseSession bool
+	// Wait follows the task monitor of a 202 Accepted response until the
+	// task finishes.
+	Wait bool
+	// PollInterval is the delay between two polls of a task monitor when
+	// the BMC sends no Retry-After header. Zero means 2 seconds.
+	PollInterval time.Duration
+	// TaskTimeout stops waiting for a task after this long. Zero waits
+	// until the task finishes.
+	TaskTimeout time.Duration
+}
+
+// Response is what the BMC answered to a request.
+type Response struct {
+	Method string `json:"method" yaml:"method"`
+	URI    string `json:"uri" yaml:"uri"`
+	Status int    `json:"status" yaml:"status"`
+	// Location is the Location header, e.g. of a created resource.
+	Location string `json:"location,omitempty" yaml:"location,omitempty"`
+	// TaskMonitor is the monitor that was followed, when the BMC accepted
+	// the request as a task. Status and Body are then the final response
+	// of the monitor.
+	TaskMonitor string `json:"task_monitor,omitempty" yaml:"task_monitor,omitempty"`
+	// Body is the decoded JSON body, or the text of a non-JSON body.
+	Body interface{} `json:"body,omitempty" yaml:"body,omitempty"`
+}
+
+// ErrTaskFailed is returned when a followed task ends in the Exception,
+// Killed or Cancelled state.
+var ErrTaskFailed = errors.New("task failed")
+
+// ErrTaskTimeout is returned when a task does not finish within
+// Options.TaskTimeout.
+var ErrTaskTimeout = errors.New("timed out waiting for task")
+
+// Client sends raw requests to one BMC.
+type Client struct {
+	opts    Options
+	base    string
+	cfg     httpclient.Config
+	session *httpclient.Session
+}
+
+// New returns a client for the BMC of opts, logging in if opts.UseSession
+// is set. Close the client to log out.
+func New(opts Options) *Client {
+	c := &Client{opts: opts, base: "https://" + opts.Host, cfg: opts.HTTP}
+	if opts.UseSession {
+		session, err := httpclient.Login(c.base, opts.Username, opts.Password, opts.HTTP)
+		if err != nil {
+			logger.Log.Warnf("Session login to %s failed, falling back to basic auth: %s", opts.Host, err)
+		} else {
+			c.session = session
+			c.cfg = c.cfg.Use(session.Middleware())
+		}
+	}
+	return c
+}
+
+// Close logs out of the session, if any.
+func (c *Client) Close() error {
+	if c.session == nil {
+		return nil
+	}
+	err := c.session.Logout()
+	c.session = nil
+	return err
+}
+
+// Get reads a resource.
+func (c *Client) Get(uri string) (*Response, error) {
+	return c.Do(http.MethodGet, uri, nil)
+}
+
+// Do sends a request with an optional JSON body. uri is a path such as
+// /redfish/v1/Systems or a full URL of the same BMC. A non-2xx answer
+// returns the response, with the BMC's error payload as Body, together with
+// its *httpclient.HTTPError.
+func (c *Client) Do(method, uri string, body []byte) (*Response, error) {
+	uri = c.path(uri)
+	if body != nil && !json.Valid(body) {
+		return nil, fmt.Errorf("the request body is not valid JSON")
+	}
+	resp, err := c.send(method, uri, body)
+	if resp == nil {
+		return nil, err
+	}
+	result := &Response{
+		Method:   method,
+		URI:      uri,
+		Status:   resp.StatusCode,
+		Location: resp.Header.Get("Location"),
+		Body:     decode(resp.Body),
+	}
+	if err != nil || resp.StatusCode != http.StatusAccepted || !c.opts.Wait || result.Location == "" {
+		return result, err
+	}
+	result.TaskMonitor = c.path(result.Location)
+	return result, c.wait(result, resp.Header)
+}
+
+// wait polls the task monitor of result until the task finishes and stores
+// the final answer in result. It stops early when the context of the client
+// pipeline is cancelled.
+func (c *Client) wait(result *Response, header http.Header) error {
+	ctx := c.cfg.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var deadline time.Time
+	if c.opts.TaskTimeout > 0 {
+		deadline = time.Now().Add(c.opts.TaskTimeout)
+	}
+	for {
+		delay := c.retryAfter(header)
+		if !deadline.IsZero() && time.Now().Add(delay).After(deadline) {
+			return fmt.Errorf("%s: %w", result.TaskMonitor, ErrTaskTimeout)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		resp, err := c.send(http.MethodGet, result.TaskMonitor, nil)
+		if resp == nil {
+			return err
+		}
+		result.Status = resp.StatusCode
+		result.Body = decode(resp.Body)
+		if err != nil {
+			return err
+		}
+		header = resp.Header
+		if resp.StatusCode == http.StatusAccepted {
+			continue
+		}
+		// Some BMCs point Location at the Task resource itself, which
+		// answers 200 while the task runs.
+		state, _ := taskState(result.Body)
+		switch state {
+		case "", "Completed":
+			return nil
+		case "Exception", "Killed", "Cancelled":
+			return fmt.Errorf("%s: %w: %s", result.TaskMonitor, ErrTaskFailed, state)
+		}
+		logger.Log.Debugf("Task %s is %s", result.TaskMonitor, state)
+	}
+}
+
+func (c *Client) send(method, uri string, body []byte) (*httpclient.Response, error) {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	username, password := c.opts.Username, c.opts.Password
+	if c.session != nil {
+		username, password = "", ""
+	}
+	return httpclient.Send(method, c.base+uri, username, password, r, c.cfg)
+}
+
+// path strips the scheme and host from a full URL of the BMC and makes
+// sure the path is absolute.
+func (c *Client) path(uri string) string {
+	if rest, ok := strings.CutPrefix(uri, c.base); ok {
+		uri = rest
+	} else if rest, ok := strings.CutPrefix(uri, "http://"+c.opts.Host); ok {
+		uri = rest
+	}
+	if !strings.HasPrefix(uri, "/") {
+		uri = "/" + uri
+	}
+	return uri
+}
+
+func (c *Client) retryAfter(header http.Header) time.Duration {
+	if secs, err := strconv.Atoi(header.Get("Retry-After")); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if c.opts.PollInterval > 0 {
+		return c.opts.PollInterval
+	}
+	return 2 * time.Second
+}
+
+// taskState returns the TaskState of a Task resource.
+func taskState(body interface{}) (string, bool) {
+	doc, ok := body.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	odataType, _ := doc["@odata.type"].(string)
+	if !strings.HasPrefix(odataType, "#Task.") {
+		return "", false
+	}
+	state, _ := doc["TaskState"].(string)
+	return state, true
+}
+
+// decode returns the JSON value of body, its text if it is not JSON, or nil
+// if it is empty.
+func decode(body []byte) interface{} {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return string(body)
+	}
+	return v
+}
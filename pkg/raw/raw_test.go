@@ -0,0 +1,125 @@
+package raw
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/angelhvargas/redfishcli/pkg/emulator"
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startEmulator(t *testing.T) string {
+	emu, err := emulator.New(emulator.IDRAC(), emulator.Options{Username: "root", Password: "calvin"})
+	require.NoError(t, err)
+	srv, err := emu.Start("127.0.0.1:0", emulator.StartOptions{})
+	require.NoError(t, err)
+	t.Cleanup(func() { srv.Close() })
+	return srv.Host
+}
+
+func TestDo(t *testing.T) {
+	host := startEmulator(t)
+	c := New(Options{Host: host, Username: "root", Password: "calvin", HTTP: httpclient.DefaultConfig(), UseSession: true})
+	defer c.Close()
+	require.NotNil(t, c.session)
+
+	resp, err := c.Get("redfish/v1/Systems/System.Embedded.1")
+	require.NoError(t, err)
+	assert.Equal(t, "/redfish/v1/Systems/System.Embedded.1", resp.URI)
+	assert.Equal(t, http.StatusOK, resp.Status)
+	assert.Equal(t, "System.Embedded.1", resp.Body.(map[string]interface{})["Id"])
+
+	resp, err = c.Do(http.MethodPatch, "https://"+host+"/redfish/v1/Systems/System.Embedded.1", []byte(`{"HostName": "rack12-u07"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "rack12-u07", resp.Body.(map[string]interface{})["HostName"])
+
+	// Errors keep the BMC's error payload.
+	resp, err = c.Do(http.MethodPatch, "/redfish/v1/Systems/System.Embedded.1", []byte(`{"AssetTag": "rack12-u07"}`))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, &httpclient.HTTPError{StatusCode: http.StatusBadRequest}))
+	assert.Equal(t, http.StatusBadRequest, resp.Status)
+	assert.Contains(t, resp.Body.(map[string]interface{})["error"], "@Message.ExtendedInfo")
+
+	_, err = c.Do(http.MethodPost, "/redfish/v1/Systems", []byte(`{`))
+	assert.EqualError(t, err, "the request body is not valid JSON")
+}
+
+// taskServer accepts every POST as a task whose monitor answers 202, asking
+// to be polled again after retryAfter seconds, until it has been polled
+// polls times, then the final answer.
+func taskServer(t *testing.T, polls int32, retryAfter string, final func(w http.ResponseWriter)) string {
+	var n atomic.Int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost:
+			w.Header().Set("Location", "/redfish/v1/TaskService/TaskMonitors/1")
+			w.WriteHeader(http.StatusAccepted)
+		case r.URL.Path == "/redfish/v1/TaskService/TaskMonitors/1" && n.Add(1) < polls:
+			w.Header().Set("Retry-After", retryAfter)
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			final(w)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return strings.TrimPrefix(srv.URL, "https://")
+}
+
+func TestDoFollowsTaskMonitor(t *testing.T) {
+	host := taskServer(t, 3, "0", func(w http.ResponseWriter) {
+		w.Write([]byte(`{"@odata.type": "#Task.v1_4_3.Task", "TaskState": "Completed", "PercentComplete": 100}`))
+	})
+	c := New(Options{Host: host, HTTP: httpclient.DefaultConfig(), Wait: true, PollInterval: time.Millisecond})
+
+	resp, err := c.Do(http.MethodPost, "/redfish/v1/UpdateService/Actions/UpdateService.SimpleUpdate", []byte(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, "/redfish/v1/TaskService/TaskMonitors/1", resp.TaskMonitor)
+	assert.Equal(t, http.StatusOK, resp.Status)
+	assert.Equal(t, "Completed", resp.Body.(map[string]interface{})["TaskState"])
+
+	// Without Wait the 202 is returned as it is.
+	c = New(Options{Host: host, HTTP: httpclient.DefaultConfig()})
+	resp, err = c.Do(http.MethodPost, "/redfish/v1/UpdateService/Actions/UpdateService.SimpleUpdate", []byte(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, resp.Status)
+	assert.Equal(t, "/redfish/v1/TaskService/TaskMonitors/1", resp.Location)
+	assert.Empty(t, resp.TaskMonitor)
+}
+
+func TestDoReportsFailedTasks(t *testing.T) {
+	host := taskServer(t, 1, "0", func(w http.ResponseWriter) {
+		w.Write([]byte(`{"@odata.type": "#Task.v1_4_3.Task", "TaskState": "Exception"}`))
+	})
+	c := New(Options{Host: host, HTTP: httpclient.DefaultConfig(), Wait: true, PollInterval: time.Millisecond})
+	resp, err := c.Do(http.MethodPost, "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset", nil)
+	assert.ErrorIs(t, err, ErrTaskFailed)
+	assert.EqualError(t, err, "/redfish/v1/TaskService/TaskMonitors/1: task failed: Exception")
+	assert.Equal(t, http.StatusOK, resp.Status)
+
+	// The BMC asks to be polled again after the timeout.
+	host = taskServer(t, 1000, "60", nil)
+	c = New(Options{Host: host, HTTP: httpclient.DefaultConfig(), Wait: true, PollInterval: time.Millisecond, TaskTimeout: time.Second})
+	_, err = c.Do(http.MethodPost, "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset", nil)
+	assert.ErrorIs(t, err, ErrTaskTimeout)
+}
+
+func TestWaitStopsOnCancel(t *testing.T) {
+	// The BMC asks to be polled again in a minute.
+	host := taskServer(t, 1000, "60", nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	c := New(Options{Host: host, HTTP: httpclient.DefaultConfig().WithContext(ctx), Wait: true})
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := c.Do(http.MethodPost, "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset", nil)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
@@ -0,0 +1,152 @@
+// Package redact scrubs sensitive values out of Redfish JSON documents before
+// they are written to disk or shared.
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+)
+
+// Placeholder replaces any redacted property value.
+const Placeholder = "REDACTED"
+
+var (
+	// CredentialKeys are properties carrying secrets.
+	CredentialKeys = []string{"Password", "UserName", "Token"}
+	// SerialKeys are properties identifying a specific piece of hardware.
+	SerialKeys = []string{"SerialNumber", "PartNumber", "UUID", "ServiceTag", "ChassisServiceTag", "SKU", "AssetTag"}
+)
+
+// Rules selects what to scrub.
+type Rules struct {
+	// Keys whose string values are replaced by Placeholder.
+	Keys map[string]bool
+	// MACs replaces any MAC address with 00:00:00:00:00:00.
+	MACs bool
+	// IPs replaces any IP address with an address from the documentation
+	// ranges (192.0.2.1 or 2001:db8::1).
+	IPs bool
+}
+
+// WithKeys returns a copy of r that also redacts the given keys.
+func (r Rules) WithKeys(keys ...string) Rules {
+	out := Rules{Keys: make(map[string]bool, len(r.Keys)+len(keys)), MACs: r.MACs, IPs: r.IPs}
+	for k := range r.Keys {
+		out.Keys[k] = true
+	}
+	for _, k := range keys {
+		out.Keys[k] = true
+	}
+	return out
+}
+
+// Empty reports whether the rules redact nothing.
+func (r Rules) Empty() bool {
+	return len(r.Keys) == 0 && !r.MACs && !r.IPs
+}
+
+// Options is the user-facing selection of what to redact, as given on the
+// command line (e.g. "credentials,serials").
+type Options struct {
+	Credentials bool
+	Serials     bool
+	MACs        bool
+	IPs         bool
+}
+
+// Parse turns a comma-separated list of credentials, serials, macs, ips,
+// all or none into Options.
+func Parse(s string) (Options, error) {
+	var o Options
+	for _, part := range strings.Split(s, ",") {
+		switch strings.TrimSpace(strings.ToLower(part)) {
+		case "", "none":
+		case "credentials":
+			o.Credentials = true
+		case "serials":
+			o.Serials = true
+		case "macs":
+			o.MACs = true
+		case "ips":
+			o.IPs = true
+		case "all":
+			o = Options{Credentials: true, Serials: true, MACs: true, IPs: true}
+		default:
+			return o, fmt.Errorf("unknown redaction %q (valid: credentials, serials, macs, ips, all, none)", part)
+		}
+	}
+	return o, nil
+}
+
+// Rules converts the options into redaction rules for JSON documents.
+func (o Options) Rules() Rules {
+	r := Rules{MACs: o.MACs, IPs: o.IPs}
+	if o.Credentials {
+		r = r.WithKeys(CredentialKeys...)
+	}
+	if o.Serials {
+		r = r.WithKeys(SerialKeys...)
+	}
+	return r
+}
+
+var macPattern = regexp.MustCompile(`\b([0-9A-Fa-f]{2}[:-]){5}[0-9A-Fa-f]{2}\b`)
+
+// JSON applies the rules to a JSON document. Bodies that are not valid JSON
+// are returned unchanged.
+func JSON(body []byte, r Rules) []byte {
+	if len(body) == 0 || r.Empty() {
+		return body
+	}
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return body
+	}
+	out, err := json.Marshal(Value(doc, r))
+	if err != nil {
+		return body
+	}
+	return out
+}
+
+// Value applies the rules to a decoded JSON value in place and returns it.
+func Value(v interface{}, r Rules) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, child := range t {
+			if s, ok := child.(string); ok && r.Keys[k] {
+				if s != "" {
+					t[k] = Placeholder
+				}
+				continue
+			}
+			t[k] = Value(child, r)
+		}
+	case []interface{}:
+		for i, child := range t {
+			t[i] = Value(child, r)
+		}
+	case string:
+		return String(t, r)
+	}
+	return v
+}
+
+// String applies the MAC and IP rules to a single string value.
+func String(s string, r Rules) string {
+	if r.IPs {
+		if ip := net.ParseIP(s); ip != nil {
+			if ip.To4() != nil {
+				return "192.0.2.1"
+			}
+			return "2001:db8::1"
+		}
+	}
+	if r.MACs {
+		s = macPattern.ReplaceAllString(s, "00:00:00:00:00:00")
+	}
+	return s
+}
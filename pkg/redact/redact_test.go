@@ -0,0 +1,35 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	o, err := Parse("serials, MACs")
+	require.NoError(t, err)
+	assert.Equal(t, Options{Serials: true, MACs: true}, o)
+
+	o, err = Parse("all")
+	require.NoError(t, err)
+	assert.Equal(t, Options{Credentials: true, Serials: true, MACs: true, IPs: true}, o)
+
+	o, err = Parse("none")
+	require.NoError(t, err)
+	assert.True(t, o.Rules().Empty())
+
+	_, err = Parse("passwords")
+	assert.Error(t, err)
+}
+
+func TestJSON(t *testing.T) {
+	body := []byte(`{"SerialNumber":"CN7475","Model":"R740","Password":"","HostName":"10.0.0.5","Nics":[{"MACAddress":"D0-94-66-2A-1B-3C"}]}`)
+
+	out := JSON(body, Options{Serials: true, Credentials: true, MACs: true, IPs: true}.Rules())
+	assert.JSONEq(t, `{"SerialNumber":"REDACTED","Model":"R740","Password":"","HostName":"192.0.2.1","Nics":[{"MACAddress":"00:00:00:00:00:00"}]}`, string(out))
+
+	assert.Equal(t, body, JSON(body, Rules{}))
+	assert.Equal(t, []byte("not json"), JSON([]byte("not json"), Rules{IPs: true}))
+}
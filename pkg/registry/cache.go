@@ -0,0 +1,168 @@
+package registry
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+	"github.com/angelhvargas/redfishcli/pkg/logger"
+	"github.com/angelhvargas/redfishcli/pkg/model"
+)
+
+// Source reads a Redfish resource of a BMC. The backends implement it with
+// their Fetch method.
+type Source interface {
+	Fetch(uri string, target interface{}) error
+}
+
+// registriesURI is where a service lists its registries.
+const registriesURI = "/redfish/v1/Registries"
+
+// Fetch reads the message registries a BMC hosts. Registry files that are
+// only published elsewhere (PublicationUri) are skipped, as are attribute
+// and privilege registries. A registry that cannot be read is logged and
+// skipped; only a failure to read the collection itself is returned.
+func Fetch(src Source) ([]*Registry, error) {
+	var coll model.Collection[model.IDRef]
+	if err := src.Fetch(registriesURI, &coll); err != nil {
+		return nil, err
+	}
+	var registries []*Registry
+	for _, member := range coll.Members {
+		var file struct {
+			Registry string `json:"Registry"`
+			Location []struct {
+				Language string `json:"Language"`
+				URI      string `json:"Uri"`
+			} `json:"Location"`
+		}
+		if err := src.Fetch(member.ODataID, &file); err != nil {
+			logger.Log.Warnf("Skipping message registry file %s: %s", member.ODataID, err)
+			continue
+		}
+		for _, loc := range file.Location {
+			if loc.URI == "" || (loc.Language != "" && !strings.HasPrefix(loc.Language, "en")) {
+				continue
+			}
+			var doc struct {
+				ODataType string `json:"@odata.type"`
+				Registry
+			}
+			if err := src.Fetch(loc.URI, &doc); err != nil {
+				logger.Log.Warnf("Skipping message registry %s: %s", loc.URI, err)
+				break
+			}
+			if !strings.Contains(doc.ODataType, "MessageRegistry.") || doc.RegistryPrefix == "" {
+				break
+			}
+			r := doc.Registry
+			registries = append(registries, &r)
+			break
+		}
+	}
+	return registries, nil
+}
+
+// Cache resolves messages for one BMC. The bundled registries are tried
+// first; the BMC's own registries are fetched once, the first time a
+// MessageId is not found, and kept for the life of the cache.
+type Cache struct {
+	src Source
+
+	mu      sync.Mutex
+	set     *Set
+	fetched bool
+}
+
+// NewCache returns a cache fetching registries from src. A nil src only
+// uses the bundled registries.
+func NewCache(src Source) *Cache {
+	return &Cache{src: src, set: Bundled()}
+}
+
+// Set returns the registries known so far.
+func (c *Cache) Set() *Set {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.set
+}
+
+// Resolve looks up a MessageId, fetching the BMC's registries if no known
+// registry defines it.
+func (c *Cache) Resolve(messageID string, args []string) (Message, bool) {
+	if msg, ok := c.Set().Resolve(messageID, args); ok {
+		return msg, true
+	}
+	if !c.fetch() {
+		return Message{}, false
+	}
+	return c.Set().Resolve(messageID, args)
+}
+
+// ResolveEntries resolves the MessageId of every entry, see Set.ResolveEntry.
+func (c *Cache) ResolveEntries(entries []model.LogEntry) {
+	for i := range entries {
+		if entries[i].MessageID == "" {
+			continue
+		}
+		if !c.Set().ResolveEntry(&entries[i]) && c.fetch() {
+			c.Set().ResolveEntry(&entries[i])
+		}
+	}
+}
+
+// ResolveError fills in the messages of the HTTPError in err's chain, see
+// Set.ResolveError.
+func (c *Cache) ResolveError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if unresolved(c.Set().ResolveError(err)) && c.fetch() {
+		c.Set().ResolveError(err)
+	}
+	return err
+}
+
+// fetch reads the BMC's registries unless that was already tried, and
+// reports whether any were added.
+func (c *Cache) fetch() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.fetched || c.src == nil {
+		return false
+	}
+	c.fetched = true
+	registries, err := Fetch(c.src)
+	if errors.Is(err, httpclient.ErrNotFound) {
+		logger.Log.Debugf("The BMC hosts no message registries")
+		return false
+	}
+	if err != nil {
+		logger.Log.Warnf("Could not read the message registries of the BMC: %s", err)
+		return false
+	}
+	if len(registries) == 0 {
+		return false
+	}
+	set := c.set.Clone()
+	for _, r := range registries {
+		set.Add(r)
+	}
+	c.set = set
+	return true
+}
+
+// unresolved reports whether err carries a message without a resolution.
+func unresolved(err error) bool {
+	var httpErr *httpclient.HTTPError
+	if !errors.As(err, &httpErr) {
+		return false
+	}
+	for _, info := range httpErr.ExtendedInfo {
+		if info.MessageID != "" && info.Resolution == "" {
+			return true
+		}
+	}
+	return false
+}
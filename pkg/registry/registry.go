@@ -0,0 +1,332 @@
+// Package registry resolves Redfish MessageIds into messages.
+//
+// Log entries and error payloads often carry only a MessageId such as
+// "Base.1.8.PropertyValueNotInList" and its MessageArgs. The message
+// registry named by the prefix and version of the MessageId holds the text,
+// with %1, %2, ... placeholders for the arguments, the severity and how to
+// resolve the problem. A Set holds the bundled DMTF registries and, through a
+// Cache, the registries a BMC hosts under /redfish/v1/Registries.
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+	"github.com/angelhvargas/redfishcli/pkg/model"
+	redfishregistry "github.com/angelhvargas/redfishcli/third_party/redfish-registry"
+)
+
+// Registry is a Redfish message registry.
+type Registry struct {
+	ID              string                `json:"Id"`
+	Name            string                `json:"Name"`
+	Language        string                `json:"Language"`
+	RegistryPrefix  string                `json:"RegistryPrefix"`
+	RegistryVersion string                `json:"RegistryVersion"`
+	OwningEntity    string                `json:"OwningEntity"`
+	Messages        map[string]Definition `json:"Messages"`
+}
+
+// Definition is one message of a registry.
+type Definition struct {
+	Description string `json:"Description"`
+	// Message is the text with %1, %2, ... standing for the arguments.
+	Message string `json:"Message"`
+	// Severity was replaced by MessageSeverity in newer registries; both
+	// are read.
+	Severity        string   `json:"Severity"`
+	MessageSeverity string   `json:"MessageSeverity"`
+	NumberOfArgs    int      `json:"NumberOfArgs"`
+	ParamTypes      []string `json:"ParamTypes"`
+	Resolution      string   `json:"Resolution"`
+}
+
+// Message is a resolved MessageId.
+type Message struct {
+	MessageID  string `json:"message_id" yaml:"message_id"`
+	Message    string `json:"message" yaml:"message"`
+	Severity   string `json:"severity,omitempty" yaml:"severity,omitempty"`
+	Resolution string `json:"resolution,omitempty" yaml:"resolution,omitempty"`
+	// Registry is the Id of the registry the message was found in, e.g.
+	// "Base.1.8.1".
+	Registry string `json:"registry" yaml:"registry"`
+}
+
+// Parse decodes a message registry.
+func Parse(data []byte) (*Registry, error) {
+	var r Registry
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("invalid message registry: %w", err)
+	}
+	if r.RegistryPrefix == "" || r.Messages == nil {
+		return nil, errors.New("not a message registry: RegistryPrefix and Messages are required")
+	}
+	return &r, nil
+}
+
+// Set looks messages up in a number of registries. It is safe for
+// concurrent use.
+type Set struct {
+	mu sync.RWMutex
+	// byPrefix holds the registries of each prefix, newest version first.
+	byPrefix map[string][]*Registry
+}
+
+// NewSet returns a set holding the given registries.
+func NewSet(registries ...*Registry) *Set {
+	s := &Set{byPrefix: make(map[string][]*Registry)}
+	for _, r := range registries {
+		s.Add(r)
+	}
+	return s
+}
+
+// Add adds a registry. It replaces a registry with the same prefix and
+// version, so a BMC's copy wins over the bundled one.
+func (s *Set) Add(r *Registry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := s.byPrefix[r.RegistryPrefix]
+	for i, old := range list {
+		if old.RegistryVersion == r.RegistryVersion {
+			list[i] = r
+			return
+		}
+	}
+	list = append(list, r)
+	sort.SliceStable(list, func(i, j int) bool {
+		return compareVersions(list[i].RegistryVersion, list[j].RegistryVersion) > 0
+	})
+	s.byPrefix[r.RegistryPrefix] = list
+}
+
+// Clone returns a copy of the set that registries can be added to without
+// changing s.
+func (s *Set) Clone() *Set {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	c := &Set{byPrefix: make(map[string][]*Registry, len(s.byPrefix))}
+	for prefix, list := range s.byPrefix {
+		c.byPrefix[prefix] = append([]*Registry(nil), list...)
+	}
+	return c
+}
+
+// Registries returns the Ids of the registries in the set, sorted.
+func (s *Set) Registries() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var ids []string
+	for _, list := range s.byPrefix {
+		for _, r := range list {
+			ids = append(ids, r.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Lookup finds the definition of a MessageId of the form
+// Prefix.Major.Minor.Key. The registry with the same major and minor
+// version is preferred; otherwise the newest registry of the same major
+// version that defines the key is used, as minor versions only add
+// messages.
+func (s *Set) Lookup(messageID string) (*Registry, Definition, bool) {
+	prefix, major, minor, key, ok := splitMessageID(messageID)
+	if !ok {
+		return nil, Definition{}, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var fallback *Registry
+	for _, r := range s.byPrefix[prefix] {
+		v := strings.Split(r.RegistryVersion, ".")
+		if len(v) < 2 || v[0] != major {
+			continue
+		}
+		if _, defined := r.Messages[key]; !defined {
+			continue
+		}
+		if v[1] == minor {
+			return r, r.Messages[key], true
+		}
+		if fallback == nil {
+			fallback = r
+		}
+	}
+	if fallback == nil {
+		return nil, Definition{}, false
+	}
+	return fallback, fallback.Messages[key], true
+}
+
+// Resolve looks up a MessageId and substitutes its arguments.
+func (s *Set) Resolve(messageID string, args []string) (Message, bool) {
+	r, def, ok := s.Lookup(messageID)
+	if !ok {
+		return Message{}, false
+	}
+	severity := def.MessageSeverity
+	if severity == "" {
+		severity = def.Severity
+	}
+	return Message{
+		MessageID:  messageID,
+		Message:    substitute(def.Message, args),
+		Severity:   severity,
+		Resolution: def.Resolution,
+		Registry:   r.ID,
+	}, true
+}
+
+// ResolveEntry fills in the Message, Severity and Resolution of a log entry
+// from its MessageId and MessageArgs, keeping what the BMC already set. It
+// reports whether the MessageId was found.
+func (s *Set) ResolveEntry(entry *model.LogEntry) bool {
+	if entry.MessageID == "" {
+		return false
+	}
+	msg, ok := s.Resolve(entry.MessageID, entry.MessageArgs)
+	if !ok {
+		return false
+	}
+	if entry.Message == "" {
+		entry.Message = msg.Message
+	}
+	if entry.Severity == "" {
+		entry.Severity = model.EventSeverity(msg.Severity)
+	}
+	if entry.Resolution == "" {
+		entry.Resolution = msg.Resolution
+	}
+	return true
+}
+
+// ResolveError fills in the @Message.ExtendedInfo of the HTTPError in err's
+// chain, if any, and returns err. The text of errors wrapping the HTTPError
+// was formatted when they were created and is not updated.
+func (s *Set) ResolveError(err error) error {
+	var httpErr *httpclient.HTTPError
+	if !errors.As(err, &httpErr) {
+		return err
+	}
+	for i := range httpErr.ExtendedInfo {
+		s.resolveInfo(&httpErr.ExtendedInfo[i])
+	}
+	return err
+}
+
+func (s *Set) resolveInfo(info *httpclient.MessageInfo) {
+	msg, ok := s.Resolve(info.MessageID, info.MessageArgs)
+	if !ok {
+		return
+	}
+	if info.Message == "" {
+		info.Message = msg.Message
+	}
+	if info.Severity == "" {
+		info.Severity = msg.Severity
+	}
+	if info.Resolution == "" {
+		info.Resolution = msg.Resolution
+	}
+}
+
+// Load reads every *.json registry at the root of fsys.
+func Load(fsys fs.FS) ([]*Registry, error) {
+	paths, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+	registries := make([]*Registry, 0, len(paths))
+	for _, p := range paths {
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return nil, err
+		}
+		r, err := Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		registries = append(registries, r)
+	}
+	return registries, nil
+}
+
+var (
+	bundledOnce sync.Once
+	bundled     *Set
+)
+
+// Bundled returns the DMTF registries embedded in the binary. The set is
+// shared; Clone it before adding registries.
+func Bundled() *Set {
+	bundledOnce.Do(func() {
+		registries, err := Load(redfishregistry.FS)
+		if err != nil {
+			panic(err)
+		}
+		bundled = NewSet(registries...)
+	})
+	return bundled
+}
+
+// splitMessageID splits "Base.1.8.PropertyValueNotInList" into its prefix,
+// major and minor version and key. A patch version before the key, as in
+// "Base.1.8.1.PropertyValueNotInList", is accepted and ignored.
+func splitMessageID(id string) (prefix, major, minor, key string, ok bool) {
+	parts := strings.Split(id, ".")
+	if len(parts) < 4 {
+		return "", "", "", "", false
+	}
+	prefix, major, minor, key = parts[0], parts[1], parts[2], parts[len(parts)-1]
+	return prefix, major, minor, key, true
+}
+
+// substitute replaces %1, %2, ... with the arguments. Placeholders without
+// an argument are left as they are.
+func substitute(text string, args []string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] == '%' {
+			j := i + 1
+			for j < len(text) && text[j] >= '0' && text[j] <= '9' {
+				j++
+			}
+			if n, err := strconv.Atoi(text[i+1 : j]); err == nil && n >= 1 && n <= len(args) {
+				b.WriteString(args[n-1])
+				i = j - 1
+				continue
+			}
+		}
+		b.WriteByte(text[i])
+	}
+	return b.String()
+}
+
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,153 @@
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/angelhvargas/redfishcli/pkg/emulator"
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+	"github.com/angelhvargas/redfishcli/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockupSource serves the resources of an emulator profile and counts the
+// requests.
+type mockupSource struct {
+	resources map[string]map[string]interface{}
+	requests  int
+}
+
+func newMockupSource(t *testing.T, profile emulator.Profile) *mockupSource {
+	resources, err := emulator.LoadMockup(profile.Mockup)
+	require.NoError(t, err)
+	return &mockupSource{resources: resources}
+}
+
+func (s *mockupSource) Fetch(uri string, target interface{}) error {
+	s.requests++
+	doc, ok := s.resources[uri]
+	if !ok {
+		return fmt.Errorf("GET %s: %w", uri, httpclient.ErrNotFound)
+	}
+	data, _ := json.Marshal(doc)
+	return json.Unmarshal(data, target)
+}
+
+func TestBundledResolve(t *testing.T) {
+	msg, ok := Bundled().Resolve("Base.1.8.PropertyValueNotInList", []string{"Sideways", "PowerState"})
+	require.True(t, ok)
+	assert.Equal(t, Message{
+		MessageID:  "Base.1.8.PropertyValueNotInList",
+		Message:    "The value Sideways for the property PowerState is not in the list of acceptable values.",
+		Severity:   "Warning",
+		Resolution: "Choose a value from the enumeration list that the implementation can support and resubmit the request if the operation failed.",
+		Registry:   "Base.1.8.1",
+	}, msg)
+
+	// Older minor versions resolve against the newest registry of the same
+	// major version; other major versions do not.
+	msg, ok = Bundled().Resolve("Base.1.5.1.ResourceMissingAtURI", []string{"/redfish/v1/Foo"})
+	require.True(t, ok)
+	assert.Equal(t, "The resource at the URI /redfish/v1/Foo was not found.", msg.Message)
+	_, ok = Bundled().Resolve("Base.2.0.ResourceMissingAtURI", nil)
+	assert.False(t, ok)
+	_, ok = Bundled().Resolve("Base.1.8.NoSuchMessage", nil)
+	assert.False(t, ok)
+	_, ok = Bundled().Resolve("GeneralError", nil)
+	assert.False(t, ok)
+}
+
+func TestSubstitute(t *testing.T) {
+	assert.Equal(t, "a 1 b 2 c %3", substitute("a %1 b %2 c %3", []string{"1", "2"}))
+	assert.Equal(t, "100% of x", substitute("100% of %1", []string{"x"}))
+	assert.Equal(t, "%10 y", substitute("%10 %2", []string{"x", "y"}))
+}
+
+func TestSetPrefersRegistryOfSameVersion(t *testing.T) {
+	older := &Registry{ID: "Acme.1.0.0", RegistryPrefix: "Acme", RegistryVersion: "1.0.0",
+		Messages: map[string]Definition{"Fan": {Message: "Fan %1 failed.", Severity: "Critical"}}}
+	newer := &Registry{ID: "Acme.1.2.0", RegistryPrefix: "Acme", RegistryVersion: "1.2.0",
+		Messages: map[string]Definition{"Fan": {Message: "Fan %1 has failed.", MessageSeverity: "Critical"}}}
+	s := NewSet(newer, older)
+	assert.Equal(t, []string{"Acme.1.0.0", "Acme.1.2.0"}, s.Registries())
+
+	msg, _ := s.Resolve("Acme.1.0.Fan", []string{"3"})
+	assert.Equal(t, "Fan 3 failed.", msg.Message)
+	msg, _ = s.Resolve("Acme.1.1.Fan", []string{"3"})
+	assert.Equal(t, "Fan 3 has failed.", msg.Message)
+	assert.Equal(t, "Critical", msg.Severity)
+}
+
+func TestCacheFetchesBMCRegistries(t *testing.T) {
+	src := newMockupSource(t, emulator.IDRAC())
+	cache := NewCache(src)
+
+	// Bundled messages do not touch the BMC.
+	_, ok := cache.Resolve("Base.1.8.Success", nil)
+	require.True(t, ok)
+	assert.Zero(t, src.requests)
+
+	entries := []model.LogEntry{
+		{MessageID: "IDRAC.2.9.TMP0120", MessageArgs: []string{"System Board Inlet Temp", "42"}},
+		{MessageID: "IDRAC.2.9.SEL9901", Message: "Cleared by root.", Severity: "Warning"},
+		{MessageID: "OEM.1.0.Unknown", Message: "Something happened."},
+		{Message: "No MessageId."},
+	}
+	cache.ResolveEntries(entries)
+	assert.Equal(t, "The System Board Inlet Temp temperature is greater than the upper warning threshold of 42 degrees Celsius.", entries[0].Message)
+	assert.Equal(t, model.EventSeverity("Warning"), entries[0].Severity)
+	assert.Contains(t, entries[0].Resolution, "Review the system operating environment")
+	// What the BMC set is kept.
+	assert.Equal(t, "Cleared by root.", entries[1].Message)
+	assert.Equal(t, model.EventSeverity("Warning"), entries[1].Severity)
+	assert.Equal(t, "No response action is required.", entries[1].Resolution)
+	assert.Empty(t, entries[2].Resolution)
+	assert.Contains(t, cache.Set().Registries(), "IDRAC.2.9")
+	assert.NotContains(t, Bundled().Registries(), "IDRAC.2.9")
+
+	// The registries are read once: the collection, two files and the
+	// registry the BMC hosts.
+	assert.Equal(t, 4, src.requests)
+	_, ok = cache.Resolve("OEM.1.0.StillUnknown", nil)
+	assert.False(t, ok)
+	assert.Equal(t, 4, src.requests)
+}
+
+func TestCacheWithoutBMCRegistries(t *testing.T) {
+	src := newMockupSource(t, emulator.XCC())
+	cache := NewCache(src)
+	_, ok := cache.Resolve("IDRAC.2.9.TMP0120", nil)
+	assert.False(t, ok)
+	assert.Equal(t, 1, src.requests)
+}
+
+func TestResolveError(t *testing.T) {
+	err := httpclient.ResponseError(400, []byte(`{"error": {
+		"code": "Base.1.8.GeneralError",
+		"message": "A general error has occurred. See ExtendedInfo for more information.",
+		"@Message.ExtendedInfo": [{"MessageId": "Base.1.8.PropertyValueNotInList", "MessageArgs": ["Sideways", "PowerState"]}]
+	}}`))
+	wrapped := fmt.Errorf("setting power state: %w", err)
+
+	assert.Same(t, wrapped, NewCache(nil).ResolveError(wrapped))
+	assert.True(t, errors.Is(wrapped, &httpclient.HTTPError{StatusCode: 400}))
+	assert.EqualError(t, err, "HTTP 400: unexpected error: The value Sideways for the property PowerState is not in the list of acceptable values.")
+
+	var httpErr *httpclient.HTTPError
+	require.True(t, errors.As(wrapped, &httpErr))
+	assert.Equal(t, "Base.1.8.GeneralError", httpErr.Code)
+	assert.Equal(t, []httpclient.MessageInfo{{
+		MessageID:   "Base.1.8.PropertyValueNotInList",
+		Message:     "The value Sideways for the property PowerState is not in the list of acceptable values.",
+		MessageArgs: []string{"Sideways", "PowerState"},
+		Severity:    "Warning",
+		Resolution:  "Choose a value from the enumeration list that the implementation can support and resubmit the request if the operation failed.",
+	}}, httpErr.ExtendedInfo)
+
+	assert.Nil(t, NewCache(nil).ResolveError(nil))
+	plain := errors.New("connection refused")
+	assert.Same(t, plain, NewCache(nil).ResolveError(plain))
+}
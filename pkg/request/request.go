@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	neturl "net/url"
 
 	"github.com/angelhvargas/redfishcli/pkg/httpclient"
 	"github.com/angelhvargas/redfishcli/pkg/logger"
@@ -19,27 +20,66 @@ func FetchAndUnmarshal(url, username, password string, config httpclient.Config,
 
 	if err := json.Unmarshal(body, target); err != nil {
 		logger.Log.Errorf("Error unmarshalling data: %s", err)
-		return err
+		return fmt.Errorf("url %s: invalid JSON - %w", url, err)
 	}
 
 	return nil
 }
 
+// page is one response of a paged Redfish collection.
+type page[T any] struct {
+	Members  []T    `json:"Members"`
+	NextLink string `json:"Members@odata.nextLink"`
+}
+
+// FetchMembers fetches a Redfish collection and returns the members of every
+// page, following Members@odata.nextLink until the collection is exhausted.
+func FetchMembers[T any](url, username, password string, config httpclient.Config) ([]T, error) {
+	var members []T
+	seen := make(map[string]bool)
+	for url != "" && !seen[url] {
+		seen[url] = true
+		var p page[T]
+		if err := FetchAndUnmarshal(url, username, password, config, &p); err != nil {
+			return nil, err
+		}
+		members = append(members, p.Members...)
+		url = resolve(url, p.NextLink)
+	}
+	return members, nil
+}
+
+// resolve turns a possibly relative link into an absolute URL based on base.
+func resolve(base, link string) string {
+	if link == "" {
+		return ""
+	}
+	b, err := neturl.Parse(base)
+	if err != nil {
+		return ""
+	}
+	ref, err := neturl.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return b.ResolveReference(ref).String()
+}
+
 // HandleHTTPError categorizes HTTP errors and returns appropriate error messages
 func HandleHTTPError(err error, url string) error {
 	if httpErr, ok := err.(*httpclient.HTTPError); ok {
 		switch httpErr.StatusCode {
 		case 401:
-			return fmt.Errorf("url %s: authentication error - %v", url, err)
+			return fmt.Errorf("url %s: authentication error - %w", url, err)
 		case 403:
-			return fmt.Errorf("url %s: authorization error - %v", url, err)
+			return fmt.Errorf("url %s: authorization error - %w", url, err)
 		case 404:
-			return fmt.Errorf("url %s: endpoint not found - %v", url, err)
+			return fmt.Errorf("url %s: endpoint not found - %w", url, err)
 		default:
-			return fmt.Errorf("url %s: unexpected error - %v", url, err)
+			return fmt.Errorf("url %s: unexpected error - %w", url, err)
 		}
 	}
-	return fmt.Errorf("url %s: unknown error - %v", url, err)
+	return fmt.Errorf("url %s: unknown error - %w", url, err)
 }
 
 // Post performs an HTTP POST request with a JSON payload.
@@ -57,3 +97,19 @@ func Post(url, username, password string, config httpclient.Config, payload inte
 
 	return nil
 }
+
+// Patch performs an HTTP PATCH request with a JSON payload.
+func Patch(url, username, password string, config httpclient.Config, payload interface{}) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error marshalling payload: %v", err)
+	}
+
+	_, err = httpclient.Do("PATCH", url, username, password, bytes.NewBuffer(jsonPayload), config)
+	if err != nil {
+		logger.Log.Errorf("Error patching data: %s", err)
+		return HandleHTTPError(err, url)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package schema
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+)
+
+// Middleware validates every resource a successful GET returns and hands the
+// result to fn. The response itself is passed on untouched.
+func Middleware(v *Validator, fn func(Result)) httpclient.Middleware {
+	return httpclient.Record(func(ex *httpclient.Exchange) {
+		if ex.Method != http.MethodGet || ex.TransportError != nil || ex.StatusCode/100 != 2 || len(ex.ResponseBody) == 0 {
+			return
+		}
+		uri := ex.URL
+		if u, err := url.Parse(ex.URL); err == nil {
+			uri = u.Path
+		}
+		fn(v.ValidateJSON(uri, ex.ResponseBody))
+	})
+}
+
+// Collector gathers results, possibly from concurrent requests.
+type Collector struct {
+	mu      sync.Mutex
+	results map[string]Result
+}
+
+// Add records r, replacing an earlier result for the same URI.
+func (c *Collector) Add(r Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.results == nil {
+		c.results = make(map[string]Result)
+	}
+	c.results[r.URI] = r
+}
+
+// Results returns the recorded results ordered by URI.
+func (c *Collector) Results() []Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	results := make([]Result, 0, len(c.results))
+	for _, r := range c.results {
+		results = append(results, r)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].URI < results[j].URI })
+	return results
+}
+
+// Invalid returns the results with violations.
+func Invalid(results []Result) []Result {
+	var invalid []Result
+	for _, r := range results {
+		if r.Status == StatusInvalid {
+			invalid = append(invalid, r)
+		}
+	}
+	return invalid
+}
@@ -0,0 +1,419 @@
+// Package schema validates Redfish responses against the DMTF JSON schemas.
+//
+// Each resource is checked against the schema its @odata.type names, e.g.
+// "#ComputerSystem.v1_13_0.ComputerSystem" against the ComputerSystem
+// definition of ComputerSystem.v1_13_0.json. The validator reports required
+// properties that are missing, properties whose JSON type is wrong and enum
+// values the schema does not list. Properties the schema does not describe
+// are not reported: Redfish allows OEM and newer properties, and the bundled
+// schemas are a trimmed subset.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	redfishschema "github.com/angelhvargas/redfishcli/third_party/redfish-schema"
+)
+
+// Status is the outcome of validating one resource.
+type Status string
+
+const (
+	StatusValid   Status = "valid"
+	StatusInvalid Status = "invalid"
+	// StatusSkipped is a resource without @odata.type or whose schema is
+	// not bundled, such as an OEM resource.
+	StatusSkipped Status = "skipped"
+)
+
+// Kind classifies a violation.
+type Kind string
+
+const (
+	KindMissingRequired Kind = "missing_required"
+	KindWrongType       Kind = "wrong_type"
+	KindInvalidEnum     Kind = "invalid_enum"
+)
+
+// Violation is one way a resource departs from its schema.
+type Violation struct {
+	// Path is a JSON pointer to the offending property, e.g. "/Status/Health".
+	Path    string `json:"path" yaml:"path"`
+	Kind    Kind   `json:"kind" yaml:"kind"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// Result is the validation of one resource.
+type Result struct {
+	URI       string `json:"uri" yaml:"uri"`
+	ODataType string `json:"odata_type,omitempty" yaml:"odata_type,omitempty"`
+	// Schema is the schema file the resource was checked against, without
+	// the .json extension.
+	Schema     string      `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Status     Status      `json:"status" yaml:"status"`
+	Note       string      `json:"note,omitempty" yaml:"note,omitempty"`
+	Violations []Violation `json:"violations,omitempty" yaml:"violations,omitempty"`
+}
+
+// Validator checks documents against a set of schema files.
+type Validator struct {
+	// files maps a schema file name without extension, such as
+	// "ComputerSystem.v1_20_0", to its parsed content.
+	files map[string]map[string]interface{}
+	// versions maps a namespace such as "ComputerSystem" to its versioned
+	// file names, oldest first.
+	versions map[string][]string
+}
+
+var versionedName = regexp.MustCompile(`^(\w+)\.v(\d+)_(\d+)_(\d+)$`)
+
+// New loads every *.json file at the root of fsys.
+func New(fsys fs.FS) (*Validator, error) {
+	paths, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+	v := &Validator{files: map[string]map[string]interface{}{}, versions: map[string][]string{}}
+	for _, p := range paths {
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return nil, err
+		}
+		var doc map[string]interface{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		name := strings.TrimSuffix(path.Base(p), ".json")
+		v.files[name] = doc
+		if m := versionedName.FindStringSubmatch(name); m != nil {
+			v.versions[m[1]] = append(v.versions[m[1]], name)
+		}
+	}
+	for _, names := range v.versions {
+		sort.Slice(names, func(i, j int) bool { return versionLess(names[i], names[j]) })
+	}
+	return v, nil
+}
+
+var (
+	bundledOnce sync.Once
+	bundled     *Validator
+	bundledErr  error
+)
+
+// Bundled returns the validator for the schemas compiled into redfishcli.
+func Bundled() (*Validator, error) {
+	bundledOnce.Do(func() {
+		bundled, bundledErr = New(redfishschema.FS)
+	})
+	return bundled, bundledErr
+}
+
+// version returns the numeric version of a versioned file name.
+func version(name string) [3]int {
+	var v [3]int
+	if m := versionedName.FindStringSubmatch(name); m != nil {
+		for i := range v {
+			v[i], _ = strconv.Atoi(m[i+2])
+		}
+	}
+	return v
+}
+
+func versionLess(a, b string) bool {
+	va, vb := version(a), version(b)
+	for i := range va {
+		if va[i] != vb[i] {
+			return va[i] < vb[i]
+		}
+	}
+	return false
+}
+
+// ValidateJSON decodes data and validates it.
+func (v *Validator) ValidateJSON(uri string, data []byte) Result {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return Result{URI: uri, Status: StatusInvalid, Violations: []Violation{{Path: "", Kind: KindWrongType, Message: "not valid JSON: " + err.Error()}}}
+	}
+	return v.Validate(uri, doc)
+}
+
+// Validate checks a decoded document against the schema its @odata.type
+// names.
+func (v *Validator) Validate(uri string, doc interface{}) Result {
+	res := Result{URI: uri, Status: StatusSkipped}
+	obj, ok := doc.(map[string]interface{})
+	if !ok {
+		res.Note = "not a JSON object"
+		return res
+	}
+	res.ODataType, _ = obj["@odata.type"].(string)
+	if res.ODataType == "" {
+		res.Note = "no @odata.type"
+		return res
+	}
+
+	file, definition, note := v.lookup(res.ODataType)
+	res.Note = note
+	if file == "" {
+		return res
+	}
+	res.Schema = file
+	node := v.definition(file, definition)
+	if node == nil {
+		res.Note = fmt.Sprintf("%s has no definition %s", file, definition)
+		return res
+	}
+	res.Violations = v.check(file, node, obj, "")
+	res.Status = StatusValid
+	if len(res.Violations) > 0 {
+		res.Status = StatusInvalid
+	}
+	return res
+}
+
+// lookup finds the schema file for an @odata.type. When the exact version is
+// not bundled, the oldest newer version is used, since Redfish minor
+// versions only add to a schema; failing that, the newest older one.
+func (v *Validator) lookup(odataType string) (file, definition, note string) {
+	t := strings.TrimPrefix(odataType, "#")
+	dot := strings.LastIndex(t, ".")
+	if dot < 0 {
+		return "", "", fmt.Sprintf("malformed @odata.type %q", odataType)
+	}
+	namespace, definition := t[:dot], t[dot+1:]
+	if _, ok := v.files[namespace]; ok {
+		if m := versionedName.FindStringSubmatch(namespace); m != nil || len(v.versions[namespace]) == 0 {
+			return namespace, definition, ""
+		}
+	}
+
+	base := namespace
+	if m := versionedName.FindStringSubmatch(namespace); m != nil {
+		base = m[1]
+	}
+	candidates := v.versions[base]
+	if len(candidates) == 0 {
+		if _, ok := v.files[base]; ok {
+			return base, definition, ""
+		}
+		return "", "", fmt.Sprintf("no schema for %s is bundled", base)
+	}
+	if base == namespace {
+		// An unversioned type: use the newest schema.
+		return candidates[len(candidates)-1], definition, ""
+	}
+	chosen := candidates[len(candidates)-1]
+	for _, c := range candidates {
+		if !versionLess(c, namespace) {
+			chosen = c
+			break
+		}
+	}
+	return chosen, definition, fmt.Sprintf("%s is not bundled; checked against %s", namespace, chosen)
+}
+
+func (v *Validator) definition(file, name string) map[string]interface{} {
+	defs, _ := v.files[file]["definitions"].(map[string]interface{})
+	def, _ := defs[name].(map[string]interface{})
+	return def
+}
+
+// resolve follows a $ref relative to file. It returns a nil node when the
+// target is not bundled.
+func (v *Validator) resolve(file, ref string) (string, map[string]interface{}) {
+	target, fragment, _ := strings.Cut(ref, "#")
+	if target != "" {
+		file = strings.TrimSuffix(path.Base(target), ".json")
+	}
+	name := strings.TrimPrefix(fragment, "/definitions/")
+	return file, v.definition(file, name)
+}
+
+// check validates value against node, a schema found in file, and returns
+// the violations under path.
+func (v *Validator) check(file string, node map[string]interface{}, value interface{}, ptr string) []Violation {
+	if ref, ok := node["$ref"].(string); ok {
+		refFile, target := v.resolve(file, ref)
+		if target == nil {
+			return nil
+		}
+		return v.check(refFile, target, value, ptr)
+	}
+
+	if alternatives, ok := node["anyOf"].([]interface{}); ok {
+		return v.checkAnyOf(file, alternatives, value, ptr)
+	}
+
+	if types := typesOf(node); len(types) > 0 && !hasType(types, value) {
+		return []Violation{wrongType(ptr, types, value)}
+	}
+
+	if enum, ok := node["enum"].([]interface{}); ok {
+		if s, isString := value.(string); isString && !containsString(enum, s) {
+			return []Violation{{Path: ptr, Kind: KindInvalidEnum, Message: fmt.Sprintf("%q is not one of %s", s, joinValues(enum))}}
+		}
+	}
+
+	var violations []Violation
+	switch t := value.(type) {
+	case map[string]interface{}:
+		required, _ := node["required"].([]interface{})
+		for _, r := range required {
+			name, _ := r.(string)
+			if _, ok := t[name]; !ok {
+				violations = append(violations, Violation{Path: ptr + "/" + escape(name), Kind: KindMissingRequired, Message: fmt.Sprintf("required property %s is missing", name)})
+			}
+		}
+		properties, _ := node["properties"].(map[string]interface{})
+		names := make([]string, 0, len(t))
+		for name := range t {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, ok := properties[name].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			violations = append(violations, v.check(file, prop, t[name], ptr+"/"+escape(name))...)
+		}
+	case []interface{}:
+		if items, ok := node["items"].(map[string]interface{}); ok {
+			for i, item := range t {
+				violations = append(violations, v.check(file, items, item, ptr+"/"+strconv.Itoa(i))...)
+			}
+		}
+	}
+	return violations
+}
+
+// checkAnyOf accepts value when one alternative does. Otherwise it reports
+// a type mismatch against all alternatives, or the violations of the first
+// alternative whose type the value has.
+func (v *Validator) checkAnyOf(file string, alternatives []interface{}, value interface{}, ptr string) []Violation {
+	var (
+		allTypes  []string
+		firstFail []Violation
+		typed     bool
+	)
+	for _, alt := range alternatives {
+		node, _ := alt.(map[string]interface{})
+		altFile := file
+		if ref, ok := node["$ref"].(string); ok {
+			altFile, node = v.resolve(file, ref)
+			if node == nil {
+				// An alternative that is not bundled cannot be ruled out.
+				return nil
+			}
+		}
+		violations := v.check(altFile, node, value, ptr)
+		if len(violations) == 0 {
+			return nil
+		}
+		types := typesOf(node)
+		allTypes = append(allTypes, types...)
+		if !typed && (len(types) == 0 || hasType(types, value)) {
+			typed = true
+			firstFail = violations
+		}
+	}
+	if typed {
+		return firstFail
+	}
+	return []Violation{wrongType(ptr, allTypes, value)}
+}
+
+func typesOf(node map[string]interface{}) []string {
+	switch t := node["type"].(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		var types []string
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	if _, ok := node["enum"]; ok {
+		return []string{"string"}
+	}
+	return nil
+}
+
+// jsonType names the JSON type of a decoded value.
+func jsonType(value interface{}) string {
+	switch t := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if t == float64(int64(t)) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func hasType(types []string, value interface{}) bool {
+	actual := jsonType(value)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func wrongType(ptr string, types []string, value interface{}) Violation {
+	seen := map[string]bool{}
+	var unique []string
+	for _, t := range types {
+		if !seen[t] {
+			seen[t] = true
+			unique = append(unique, t)
+		}
+	}
+	return Violation{Path: ptr, Kind: KindWrongType, Message: fmt.Sprintf("got %s, want %s", jsonType(value), strings.Join(unique, " or "))}
+}
+
+func containsString(values []interface{}, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func joinValues(values []interface{}) string {
+	parts := make([]string, 0, len(values))
+	for _, v := range values {
+		parts = append(parts, fmt.Sprint(v))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// escape encodes a property name as a JSON pointer token.
+func escape(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
@@ -0,0 +1,105 @@
+package schema
+
+import (
+	"encoding/json"
+	"io/fs"
+	"path"
+	"testing"
+
+	"github.com/angelhvargas/redfishcli/pkg/emulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundledProfilesAreValid(t *testing.T) {
+	v, err := Bundled()
+	require.NoError(t, err)
+
+	for name, profile := range emulator.Profiles() {
+		checked := 0
+		err := fs.WalkDir(profile.Mockup, ".", func(p string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || d.Name() != "index.json" {
+				return err
+			}
+			data, err := fs.ReadFile(profile.Mockup, p)
+			require.NoError(t, err)
+			res := v.ValidateJSON("/"+path.Dir(p), data)
+			assert.NotEqual(t, StatusInvalid, res.Status, "%s %s: %+v", name, res.URI, res.Violations)
+			if res.Status == StatusValid {
+				checked++
+			}
+			// Expanded members are served on their own URIs as well.
+			var coll struct{ Members []map[string]interface{} }
+			require.NoError(t, json.Unmarshal(data, &coll))
+			for _, m := range coll.Members {
+				if _, ok := m["@odata.type"]; !ok {
+					continue
+				}
+				uri, _ := m["@odata.id"].(string)
+				res := v.Validate(uri, m)
+				assert.NotEqual(t, StatusInvalid, res.Status, "%s %s: %+v", name, res.URI, res.Violations)
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Positive(t, checked, name)
+	}
+}
+
+func TestValidateReportsViolations(t *testing.T) {
+	v, err := Bundled()
+	require.NoError(t, err)
+
+	res := v.ValidateJSON("/redfish/v1/Systems/1", []byte(`{
+		"@odata.id": "/redfish/v1/Systems/1",
+		"@odata.type": "#ComputerSystem.v1_20_0.ComputerSystem",
+		"Name": "System",
+		"PowerState": "Onn",
+		"Status": {"Health": 5, "State": null},
+		"Boot": {"BootOrder": ["NIC.1", 2]},
+		"ProcessorSummary": {"Count": 1.5},
+		"Oem": {"Dell": {"Anything": true}},
+		"VendorProperty": 1
+	}`))
+
+	assert.Equal(t, StatusInvalid, res.Status)
+	assert.Equal(t, "ComputerSystem.v1_20_0", res.Schema)
+	assert.ElementsMatch(t, []Violation{
+		{Path: "/Id", Kind: KindMissingRequired, Message: "required property Id is missing"},
+		{Path: "/PowerState", Kind: KindInvalidEnum, Message: `"Onn" is not one of On, Off, PoweringOn, PoweringOff, Paused`},
+		{Path: "/Status/Health", Kind: KindWrongType, Message: "got integer, want string or null"},
+		{Path: "/Boot/BootOrder/1", Kind: KindWrongType, Message: "got integer, want string or null"},
+		{Path: "/ProcessorSummary/Count", Kind: KindWrongType, Message: "got number, want integer or null"},
+	}, res.Violations)
+}
+
+func TestValidateSchemaSelection(t *testing.T) {
+	v, err := Bundled()
+	require.NoError(t, err)
+	drive := func(odataType string) []byte {
+		return []byte(`{"@odata.id": "/d", "@odata.type": "` + odataType + `", "Id": "d", "Name": "d"}`)
+	}
+
+	tests := []struct {
+		odataType string
+		status    Status
+		schema    string
+		note      string
+	}{
+		{"#Drive.v1_9_0.Drive", StatusValid, "Drive.v1_9_0", ""},
+		{"#Drive.v1_12_0.Drive", StatusValid, "Drive.v1_15_0", "Drive.v1_12_0 is not bundled; checked against Drive.v1_15_0"},
+		{"#Drive.v1_99_0.Drive", StatusValid, "Drive.v1_15_0", "Drive.v1_99_0 is not bundled; checked against Drive.v1_15_0"},
+		{"#Drive.Drive", StatusValid, "Drive.v1_15_0", ""},
+		{"#DellRaidService.v1_0_0.DellRaidService", StatusSkipped, "", "no schema for DellRaidService is bundled"},
+		{"", StatusSkipped, "", "no @odata.type"},
+	}
+	for _, tt := range tests {
+		res := v.ValidateJSON("/d", drive(tt.odataType))
+		if tt.odataType == "" {
+			res = v.ValidateJSON("/d", []byte(`{"Id": "d"}`))
+		}
+		assert.Equal(t, tt.status, res.Status, tt.odataType)
+		assert.Equal(t, tt.schema, res.Schema, tt.odataType)
+		assert.Equal(t, tt.note, res.Note, tt.odataType)
+	}
+}
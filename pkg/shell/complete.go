@@ -0,0 +1,86 @@
+package shell
+
+import (
+	"sort"
+	"strings"
+)
+
+// pathCommands take a resource path as their first argument.
+var pathCommands = map[string]bool{"cd": true, "ls": true, "cat": true, "actions": true, "patch": true}
+
+// Complete returns the completions of the word at the end of line and the
+// offset in line where that word starts. The first word completes to a
+// command; the argument of a path command completes to the links of the
+// resource reached so far, ending in "/", and for cat also to property
+// names.
+func (s *Shell) Complete(line string) (int, []string) {
+	start := strings.LastIndexByte(line, ' ') + 1
+	word := line[start:]
+	if start == 0 {
+		return 0, withPrefix(append(Commands(), "quit"), word)
+	}
+	fields := strings.Fields(line[:start])
+	if len(fields) != 1 || !pathCommands[fields[0]] {
+		return start, nil
+	}
+
+	seen := make(map[string]bool)
+	var candidates []string
+	add := func(c string) {
+		if !seen[c] && c != word && strings.HasPrefix(c, word) {
+			seen[c] = true
+			candidates = append(candidates, c)
+		}
+	}
+
+	// Links of the current resource, including those whose name has a
+	// slash, such as Links/ManagedBy/0.
+	dir := ""
+	if i := strings.LastIndexByte(word, '/'); i >= 0 {
+		dir = word[:i+1]
+	}
+	if !strings.HasPrefix(word, "/") {
+		if doc, err := s.get(s.cwd); err == nil {
+			for name := range links(doc) {
+				add(name + "/")
+			}
+		}
+	}
+	if dir != "" {
+		if doc, err := s.get(s.resolve(dir)); err == nil {
+			for name := range links(doc) {
+				add(dir + name + "/")
+			}
+		}
+	}
+
+	if fields[0] == "cat" && !strings.Contains(word, "/") {
+		prefix, parent := "", interface{}(nil)
+		if i := strings.LastIndexByte(word, '.'); i >= 0 {
+			prefix = word[:i+1]
+			parent, _ = s.property(word[:i])
+		} else if doc, err := s.get(s.cwd); err == nil {
+			parent = doc
+		}
+		if m, ok := parent.(map[string]interface{}); ok {
+			for key, value := range m {
+				if !strings.HasPrefix(key, "@") && odataID(value) == "" {
+					add(prefix + key)
+				}
+			}
+		}
+	}
+	sort.Strings(candidates)
+	return start, candidates
+}
+
+func withPrefix(list []string, prefix string) []string {
+	var out []string
+	for _, item := range list {
+		if strings.HasPrefix(item, prefix) {
+			out = append(out, item)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,266 @@
+package shell
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// Editor reads command lines from a terminal with emacs-style editing keys,
+// history on the arrow keys and tab completion. The terminal is put in raw
+// mode while a line is read only, so command output is printed normally.
+type Editor struct {
+	in  *bufio.Reader
+	out io.Writer
+	// fd is the terminal put in raw mode, or -1.
+	fd int
+	// Complete returns the completions of the word at the end of line and
+	// where that word starts, see Shell.Complete.
+	Complete func(line string) (int, []string)
+
+	history []string
+}
+
+// NewEditor returns an editor reading keys from in and drawing on out. If
+// fd is a terminal it is switched to raw mode while reading a line; pass -1
+// when in is not a terminal, as in tests.
+func NewEditor(in io.Reader, out io.Writer, fd int) *Editor {
+	return &Editor{in: bufio.NewReader(in), out: out, fd: fd}
+}
+
+// AddHistory appends a line to the history the arrow keys walk through.
+func (e *Editor) AddHistory(line string) {
+	if line == "" || (len(e.history) > 0 && e.history[len(e.history)-1] == line) {
+		return
+	}
+	e.history = append(e.history, line)
+}
+
+// Key codes.
+const (
+	keyCtrlA     = 1
+	keyCtrlB     = 2
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyCtrlF     = 6
+	keyBackspace = 8
+	keyTab       = 9
+	keyLF        = 10
+	keyCtrlK     = 11
+	keyCtrlL     = 12
+	keyCR        = 13
+	keyCtrlN     = 14
+	keyCtrlP     = 16
+	keyCtrlU     = 21
+	keyCtrlW     = 23
+	keyEscape    = 27
+	keyDelete    = 127
+)
+
+// ReadLine reads one line. It returns io.EOF when Ctrl-D is pressed on an
+// empty line or the input ends. Ctrl-C discards the line being edited.
+func (e *Editor) ReadLine(prompt string) (string, error) {
+	if e.fd >= 0 {
+		restore, err := MakeRaw(e.fd)
+		if err != nil {
+			return "", err
+		}
+		defer restore()
+	}
+
+	var (
+		buf     []rune
+		pos     int
+		hist    = len(e.history)
+		saved   []rune
+		lastTab bool
+	)
+	render := func() {
+		fmt.Fprintf(e.out, "\r%s%s\x1b[K", prompt, string(buf))
+		if back := len(buf) - pos; back > 0 {
+			fmt.Fprintf(e.out, "\x1b[%dD", back)
+		}
+	}
+	setLine := func(line []rune) {
+		buf = append([]rune(nil), line...)
+		pos = len(buf)
+	}
+	render()
+
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			if len(buf) > 0 && err == io.EOF {
+				fmt.Fprint(e.out, "\r\n")
+				return string(buf), nil
+			}
+			return "", err
+		}
+		tab := false
+		switch r {
+		case keyCR, keyLF:
+			fmt.Fprint(e.out, "\r\n")
+			return string(buf), nil
+		case keyCtrlC:
+			fmt.Fprint(e.out, "^C\r\n")
+			buf, pos = nil, 0
+		case keyCtrlD:
+			if len(buf) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			if pos < len(buf) {
+				buf = append(buf[:pos], buf[pos+1:]...)
+			}
+		case keyBackspace, keyDelete:
+			if pos > 0 {
+				buf = append(buf[:pos-1], buf[pos:]...)
+				pos--
+			}
+		case keyCtrlA:
+			pos = 0
+		case keyCtrlE:
+			pos = len(buf)
+		case keyCtrlB:
+			if pos > 0 {
+				pos--
+			}
+		case keyCtrlF:
+			if pos < len(buf) {
+				pos++
+			}
+		case keyCtrlK:
+			buf = buf[:pos]
+		case keyCtrlU:
+			buf, pos = append([]rune(nil), buf[pos:]...), 0
+		case keyCtrlW:
+			start := pos
+			for start > 0 && buf[start-1] == ' ' {
+				start--
+			}
+			for start > 0 && buf[start-1] != ' ' {
+				start--
+			}
+			buf, pos = append(buf[:start], buf[pos:]...), start
+		case keyCtrlL:
+			fmt.Fprint(e.out, "\x1b[H\x1b[2J")
+		case keyCtrlP:
+			hist, saved = e.walkHistory(hist, -1, buf, saved, setLine)
+		case keyCtrlN:
+			hist, saved = e.walkHistory(hist, 1, buf, saved, setLine)
+		case keyTab:
+			tab = true
+			e.complete(&buf, &pos, lastTab, prompt)
+		case keyEscape:
+			switch e.escape() {
+			case "A":
+				hist, saved = e.walkHistory(hist, -1, buf, saved, setLine)
+			case "B":
+				hist, saved = e.walkHistory(hist, 1, buf, saved, setLine)
+			case "C":
+				if pos < len(buf) {
+					pos++
+				}
+			case "D":
+				if pos > 0 {
+					pos--
+				}
+			case "H", "1~":
+				pos = 0
+			case "F", "4~":
+				pos = len(buf)
+			case "3~":
+				if pos < len(buf) {
+					buf = append(buf[:pos], buf[pos+1:]...)
+				}
+			}
+		default:
+			if unicode.IsPrint(r) {
+				buf = append(buf[:pos], append([]rune{r}, buf[pos:]...)...)
+				pos++
+			}
+		}
+		lastTab = tab
+		render()
+	}
+}
+
+// escape reads the rest of an escape sequence such as ESC [ A and returns
+// what follows the bracket.
+func (e *Editor) escape() string {
+	r, _, err := e.in.ReadRune()
+	if err != nil || (r != '[' && r != 'O') {
+		return ""
+	}
+	var seq strings.Builder
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return seq.String()
+		}
+		seq.WriteRune(r)
+		if r >= 'A' && r <= 'Z' || r == '~' {
+			return seq.String()
+		}
+	}
+}
+
+// walkHistory moves dir steps through the history. The line being edited
+// is kept so walking back down past the newest entry restores it.
+func (e *Editor) walkHistory(hist, dir int, buf, saved []rune, setLine func([]rune)) (int, []rune) {
+	next := hist + dir
+	if next < 0 || next > len(e.history) {
+		return hist, saved
+	}
+	if hist == len(e.history) {
+		saved = append([]rune(nil), buf...)
+	}
+	if next == len(e.history) {
+		setLine(saved)
+	} else {
+		setLine([]rune(e.history[next]))
+	}
+	return next, saved
+}
+
+// complete completes the word before the cursor: a single candidate is
+// inserted, followed by a space unless it is a path; several insert their
+// common prefix, and a second tab lists them.
+func (e *Editor) complete(buf *[]rune, pos *int, listAll bool, prompt string) {
+	if e.Complete == nil {
+		return
+	}
+	line := string((*buf)[:*pos])
+	start, candidates := e.Complete(line)
+	if len(candidates) == 0 {
+		fmt.Fprint(e.out, "\a")
+		return
+	}
+	word := []rune(line[start:])
+	insert := commonPrefix(candidates)
+	if len(candidates) == 1 && !strings.HasSuffix(insert, "/") {
+		insert += " "
+	}
+	if ins := []rune(insert); len(ins) > len(word) {
+		rest := append([]rune(nil), (*buf)[*pos:]...)
+		*buf = append(append((*buf)[:*pos-len(word)], ins...), rest...)
+		*pos += len(ins) - len(word)
+		return
+	}
+	if len(candidates) > 1 && listAll {
+		fmt.Fprintf(e.out, "\r\n%s\r\n", strings.Join(candidates, "  "))
+	}
+}
+
+func commonPrefix(list []string) string {
+	prefix := list[0]
+	for _, s := range list[1:] {
+		for !strings.HasPrefix(s, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
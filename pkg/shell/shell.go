@@ -0,0 +1,544 @@
+// Package shell is an interactive browser for the Redfish resource tree of
+// a BMC. Commands such as cd, ls and cat move through the tree like a file
+// system, following @odata.id links; every resource is read once and
+// cached until it is patched.
+package shell
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/angelhvargas/redfishcli/pkg/raw"
+)
+
+// ServiceRoot is where a shell starts.
+const ServiceRoot = "/redfish/v1"
+
+// Client sends requests to the BMC. *raw.Client implements it.
+type Client interface {
+	Do(method, uri string, body []byte) (*raw.Response, error)
+}
+
+// ErrExit is returned by Exec for the exit command.
+var ErrExit = errors.New("exit")
+
+// Shell is a session over the Redfish tree of one BMC.
+type Shell struct {
+	client Client
+	out    io.Writer
+	cwd    string
+	cache  map[string]map[string]interface{}
+	// History holds the commands run so far, oldest first.
+	History []string
+	// MaxFind bounds the number of resources find reads.
+	MaxFind int
+}
+
+// New returns a shell at the service root, printing to out.
+func New(client Client, out io.Writer) *Shell {
+	return &Shell{
+		client:  client,
+		out:     out,
+		cwd:     ServiceRoot,
+		cache:   make(map[string]map[string]interface{}),
+		MaxFind: 500,
+	}
+}
+
+// Cwd returns the URI of the current resource.
+func (s *Shell) Cwd() string {
+	return s.cwd
+}
+
+type command struct {
+	usage string
+	help  string
+	run   func(s *Shell, args []string) error
+}
+
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"cd":      {"cd [path]", "change to a resource; no path goes back to the service root", (*Shell).cd},
+		"pwd":     {"pwd", "print the URI of the current resource", (*Shell).pwd},
+		"ls":      {"ls [path]", "list the links (ending in /) and properties of a resource", (*Shell).ls},
+		"cat":     {"cat [path|property]", "print a resource or one of its properties as JSON", (*Shell).cat},
+		"find":    {"find <text>", "list the resources below the current one whose URI or property names contain text", (*Shell).find},
+		"actions": {"actions [path]", "list the actions of a resource with their targets and allowed values", (*Shell).actions},
+		"patch":   {"patch [path] <json>", "PATCH a resource and print the answer", (*Shell).patch},
+		"history": {"history", "list the commands run in this session", (*Shell).history},
+		"help":    {"help", "list the commands", (*Shell).help},
+		"exit":    {"exit", "leave the shell", func(*Shell, []string) error { return ErrExit }},
+	}
+}
+
+// Commands returns the names of the shell commands, sorted.
+func Commands() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Exec runs one command line. Empty lines and comments starting with # are
+// ignored. It returns ErrExit for exit and quit.
+func (s *Shell) Exec(line string) error {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil
+	}
+	s.History = append(s.History, line)
+	name, rest, _ := strings.Cut(line, " ")
+	if name == "quit" {
+		name = "exit"
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		return fmt.Errorf("%s: unknown command, see help", name)
+	}
+	var args []string
+	if name == "patch" {
+		// The JSON body may contain spaces; it is everything after the
+		// optional path.
+		rest = strings.TrimSpace(rest)
+		if rest != "" && !strings.HasPrefix(rest, "{") {
+			p, body, _ := strings.Cut(rest, " ")
+			args = append(args, p)
+			rest = strings.TrimSpace(body)
+		}
+		if rest != "" {
+			args = append(args, rest)
+		}
+	} else {
+		args = strings.Fields(rest)
+	}
+	return cmd.run(s, args)
+}
+
+// Run reads commands from in until it ends or exit is run, printing errors
+// to errOut and going on with the next command. It returns the number of
+// commands that failed.
+func (s *Shell) Run(in io.Reader, errOut io.Writer) int {
+	failed := 0
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		err := s.Exec(scanner.Text())
+		if errors.Is(err, ErrExit) {
+			break
+		}
+		if err != nil {
+			failed++
+			fmt.Fprintln(errOut, err)
+		}
+	}
+	return failed
+}
+
+// get reads a resource through the cache.
+func (s *Shell) get(uri string) (map[string]interface{}, error) {
+	if doc, ok := s.cache[uri]; ok {
+		return doc, nil
+	}
+	resp, err := s.client.Do(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", uri, err)
+	}
+	doc, ok := resp.Body.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: not a Redfish resource", uri)
+	}
+	s.cache[uri] = doc
+	return doc, nil
+}
+
+// resolve turns a command argument into a URI. The segments of a relative
+// argument are first looked up as links of the resource reached so far, so
+// a member Id or "Links/ManagedBy/0" work, and otherwise appended to the
+// URI like a path.
+func (s *Shell) resolve(arg string) string {
+	if strings.HasPrefix(arg, "/") {
+		return path.Clean(arg)
+	}
+	uri := s.cwd
+	segs := strings.FieldsFunc(arg, func(r rune) bool { return r == '/' })
+	for i := 0; i < len(segs); i++ {
+		switch segs[i] {
+		case ".":
+			continue
+		case "..":
+			if uri != ServiceRoot {
+				uri = path.Dir(uri)
+			}
+			continue
+		}
+		target, n := s.link(uri, segs[i:])
+		if n == 0 {
+			uri = path.Join(uri, segs[i])
+			continue
+		}
+		uri = target
+		i += n - 1
+	}
+	return uri
+}
+
+// link finds the link of the resource at uri named by the longest prefix
+// of segs, and returns its target and the number of segments used.
+func (s *Shell) link(uri string, segs []string) (string, int) {
+	doc, err := s.get(uri)
+	if err != nil {
+		return "", 0
+	}
+	l := links(doc)
+	for n := len(segs); n > 0; n-- {
+		if target, ok := l[strings.Join(segs[:n], "/")]; ok {
+			return target, n
+		}
+	}
+	return "", 0
+}
+
+// links returns the links of a resource by name: properties whose value is
+// a reference, the references inside Links, and collection members by the
+// last segment of their URI.
+func links(doc map[string]interface{}) map[string]string {
+	found := make(map[string]string)
+	for key, value := range doc {
+		if uri := odataID(value); uri != "" {
+			found[key] = uri
+		}
+	}
+	if l, ok := doc["Links"].(map[string]interface{}); ok {
+		for key, value := range l {
+			if uri := odataID(value); uri != "" {
+				found["Links/"+key] = uri
+			}
+			if list, ok := value.([]interface{}); ok {
+				for i, item := range list {
+					if uri := odataID(item); uri != "" {
+						found[fmt.Sprintf("Links/%s/%d", key, i)] = uri
+					}
+				}
+			}
+		}
+	}
+	if members, ok := doc["Members"].([]interface{}); ok {
+		for _, m := range members {
+			if uri := odataID(m); uri != "" {
+				found[path.Base(uri)] = uri
+			}
+		}
+	}
+	return found
+}
+
+// odataID returns the @odata.id of a reference, that is an object holding
+// nothing else.
+func odataID(v interface{}) string {
+	ref, ok := v.(map[string]interface{})
+	if !ok || len(ref) != 1 {
+		return ""
+	}
+	uri, _ := ref["@odata.id"].(string)
+	return uri
+}
+
+func (s *Shell) cd(args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("usage: %s", commands["cd"].usage)
+	}
+	uri := ServiceRoot
+	if len(args) == 1 {
+		uri = s.resolve(args[0])
+	}
+	if _, err := s.get(uri); err != nil {
+		return fmt.Errorf("cd: %w", err)
+	}
+	s.cwd = uri
+	return nil
+}
+
+func (s *Shell) pwd([]string) error {
+	fmt.Fprintln(s.out, s.cwd)
+	return nil
+}
+
+func (s *Shell) ls(args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("usage: %s", commands["ls"].usage)
+	}
+	uri := s.cwd
+	if len(args) == 1 {
+		uri = s.resolve(args[0])
+	}
+	doc, err := s.get(uri)
+	if err != nil {
+		return fmt.Errorf("ls: %w", err)
+	}
+	l := links(doc)
+	names := make([]string, 0, len(l))
+	for name := range l {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(s.out, "%s/\t%s\n", name, l[name])
+	}
+	for _, key := range sortedKeys(doc) {
+		if _, isLink := l[key]; isLink || key == "Members" || key == "Links" || strings.Contains(key, "@odata.") {
+			continue
+		}
+		fmt.Fprintf(s.out, "%s\t%s\n", key, summary(doc[key]))
+	}
+	return nil
+}
+
+// summary is a one-line view of a property value.
+func summary(v interface{}) string {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		return fmt.Sprintf("{%d properties}", len(t))
+	case []interface{}:
+		return fmt.Sprintf("[%d items]", len(t))
+	case string:
+		return t
+	case nil:
+		return "null"
+	default:
+		data, _ := json.Marshal(t)
+		return string(data)
+	}
+}
+
+func (s *Shell) cat(args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("usage: %s", commands["cat"].usage)
+	}
+	var value interface{}
+	if len(args) == 0 {
+		doc, err := s.get(s.cwd)
+		if err != nil {
+			return fmt.Errorf("cat: %w", err)
+		}
+		value = doc
+	} else if v, ok := s.property(args[0]); ok {
+		value = v
+	} else {
+		doc, err := s.get(s.resolve(args[0]))
+		if err != nil {
+			return fmt.Errorf("cat: %w", err)
+		}
+		value = doc
+	}
+	return s.print(value)
+}
+
+// property looks up a property of the current resource by name; nested
+// properties are separated by dots or slashes, as in Status.Health.
+func (s *Shell) property(name string) (interface{}, bool) {
+	doc, err := s.get(s.cwd)
+	if err != nil {
+		return nil, false
+	}
+	var v interface{} = doc
+	for _, key := range strings.FieldsFunc(name, func(r rune) bool { return r == '.' || r == '/' }) {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[key]; !ok {
+			return nil, false
+		}
+		if odataID(v) != "" {
+			// A link; cat follows it instead.
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+func (s *Shell) print(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(s.out, string(data))
+	return nil
+}
+
+func (s *Shell) find(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: %s", commands["find"].usage)
+	}
+	text := strings.ToLower(args[0])
+	visited := map[string]bool{s.cwd: true}
+	queue := []string{s.cwd}
+	for read := 0; len(queue) > 0; read++ {
+		if read >= s.MaxFind {
+			fmt.Fprintf(s.out, "(stopped after %d resources)\n", s.MaxFind)
+			break
+		}
+		uri := queue[0]
+		queue = queue[1:]
+		doc, err := s.get(uri)
+		if err != nil {
+			continue
+		}
+		if strings.Contains(strings.ToLower(uri), text) {
+			fmt.Fprintln(s.out, uri)
+		}
+		for _, key := range sortedKeys(doc) {
+			if strings.HasPrefix(key, "@") || odataID(doc[key]) != "" {
+				continue
+			}
+			if strings.Contains(strings.ToLower(key), text) {
+				fmt.Fprintf(s.out, "%s\t%s = %s\n", uri, key, summary(doc[key]))
+			}
+		}
+		l := links(doc)
+		names := make([]string, 0, len(l))
+		for name := range l {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			next := l[name]
+			if !visited[next] && strings.HasPrefix(next, s.cwd+"/") {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Shell) actions(args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("usage: %s", commands["actions"].usage)
+	}
+	uri := s.cwd
+	if len(args) == 1 {
+		uri = s.resolve(args[0])
+	}
+	doc, err := s.get(uri)
+	if err != nil {
+		return fmt.Errorf("actions: %w", err)
+	}
+	acts, _ := doc["Actions"].(map[string]interface{})
+	if len(acts) == 0 {
+		fmt.Fprintf(s.out, "%s has no actions\n", uri)
+		return nil
+	}
+	for _, name := range sortedKeys(acts) {
+		def, ok := acts[name].(map[string]interface{})
+		if !ok || !strings.HasPrefix(name, "#") {
+			continue
+		}
+		target, _ := def["target"].(string)
+		fmt.Fprintf(s.out, "%s\t%s\n", name, target)
+		params := allowableValues(def)
+		if infoURI, _ := def["@Redfish.ActionInfo"].(string); infoURI != "" {
+			if info, err := s.get(infoURI); err == nil {
+				list, _ := info["Parameters"].([]interface{})
+				for _, p := range list {
+					param, _ := p.(map[string]interface{})
+					name, _ := param["Name"].(string)
+					if values := stringList(param["AllowableValues"]); name != "" && len(values) > 0 {
+						params[name] = values
+					} else if name != "" {
+						params[name] = nil
+					}
+				}
+			}
+		}
+		for _, p := range sortedKeys(params) {
+			values, _ := params[p].([]string)
+			if len(values) == 0 {
+				fmt.Fprintf(s.out, "  %s\n", p)
+				continue
+			}
+			fmt.Fprintf(s.out, "  %s: %s\n", p, strings.Join(values, ", "))
+		}
+	}
+	return nil
+}
+
+// allowableValues returns the <Parameter>@Redfish.AllowableValues of an
+// action definition.
+func allowableValues(def map[string]interface{}) map[string]interface{} {
+	params := make(map[string]interface{})
+	for key, value := range def {
+		if name, ok := strings.CutSuffix(key, "@Redfish.AllowableValues"); ok {
+			params[name] = stringList(value)
+		}
+	}
+	return params
+}
+
+func stringList(v interface{}) []string {
+	list, _ := v.([]interface{})
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func (s *Shell) patch(args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return fmt.Errorf("usage: %s", commands["patch"].usage)
+	}
+	uri, body := s.cwd, args[len(args)-1]
+	if len(args) == 2 {
+		uri = s.resolve(args[0])
+	}
+	if !json.Valid([]byte(body)) {
+		return fmt.Errorf("patch: the body is not valid JSON")
+	}
+	resp, err := s.client.Do(http.MethodPatch, uri, []byte(body))
+	delete(s.cache, uri)
+	if resp != nil && resp.Body != nil {
+		s.print(resp.Body)
+	}
+	if err != nil {
+		return fmt.Errorf("patch: %w", err)
+	}
+	return nil
+}
+
+func (s *Shell) history([]string) error {
+	for i, line := range s.History {
+		fmt.Fprintf(s.out, "%4d  %s\n", i+1, line)
+	}
+	return nil
+}
+
+func (s *Shell) help([]string) error {
+	for _, name := range Commands() {
+		c := commands[name]
+		fmt.Fprintf(s.out, "%-22s %s\n", c.usage, c.help)
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
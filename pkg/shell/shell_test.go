@@ -0,0 +1,152 @@
+package shell
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/angelhvargas/redfishcli/pkg/emulator"
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+	"github.com/angelhvargas/redfishcli/pkg/raw"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newShell(t *testing.T, profile emulator.Profile) (*Shell, *bytes.Buffer) {
+	emu, err := emulator.New(profile, emulator.Options{Username: "root", Password: "calvin"})
+	require.NoError(t, err)
+	srv, err := emu.Start("127.0.0.1:0", emulator.StartOptions{})
+	require.NoError(t, err)
+	t.Cleanup(func() { srv.Close() })
+
+	c := raw.New(raw.Options{Host: srv.Host, Username: "root", Password: "calvin", HTTP: httpclient.DefaultConfig(), UseSession: true})
+	t.Cleanup(func() { c.Close() })
+	var out bytes.Buffer
+	return New(c, &out), &out
+}
+
+// exec runs a command and returns what it printed.
+func exec(t *testing.T, s *Shell, out *bytes.Buffer, line string) string {
+	t.Helper()
+	out.Reset()
+	require.NoError(t, s.Exec(line))
+	return out.String()
+}
+
+func TestNavigation(t *testing.T) {
+	s, out := newShell(t, emulator.IDRAC())
+
+	ls := exec(t, s, out, "ls")
+	assert.Contains(t, ls, "Systems/\t/redfish/v1/Systems\n")
+	assert.Contains(t, ls, "RedfishVersion\t1.11.0\n")
+
+	exec(t, s, out, "cd Systems/System.Embedded.1")
+	assert.Equal(t, "/redfish/v1/Systems/System.Embedded.1", s.Cwd())
+	assert.Equal(t, "\"On\"\n", exec(t, s, out, "cat PowerState"))
+	assert.Equal(t, "\"OK\"\n", exec(t, s, out, "cat Status.Health"))
+
+	// Links are followed by name, .. goes up and absolute paths work.
+	exec(t, s, out, "cd Links/ManagedBy/0")
+	assert.Equal(t, "/redfish/v1/Managers/iDRAC.Embedded.1", s.Cwd())
+	exec(t, s, out, "cd ../..")
+	assert.Equal(t, "/redfish/v1", s.Cwd())
+	exec(t, s, out, "cd /redfish/v1/Chassis/System.Embedded.1/")
+	assert.Equal(t, "/redfish/v1/Chassis/System.Embedded.1", s.Cwd())
+	exec(t, s, out, "cd")
+	assert.Equal(t, "/redfish/v1", exec(t, s, out, "pwd")[:len("/redfish/v1")])
+
+	assert.EqualError(t, s.Exec("cd NoSuchThing"), "cd: /redfish/v1/NoSuchThing: HTTP 404: endpoint not found: The resource at the URI /redfish/v1/NoSuchThing was not found.")
+	assert.Equal(t, "/redfish/v1", s.Cwd())
+	assert.EqualError(t, s.Exec("rm -rf /"), "rm: unknown command, see help")
+}
+
+func TestFindActionsAndPatch(t *testing.T) {
+	s, out := newShell(t, emulator.IDRAC())
+
+	found := exec(t, s, out, "find Disk.Bay")
+	assert.Contains(t, found, "/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1/Drives/Disk.Bay.0\n")
+	assert.Contains(t, found, "/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1/Drives/Disk.Bay.1\n")
+	assert.Contains(t, exec(t, s, out, "find biosversion"), "/redfish/v1/Systems/System.Embedded.1\tBiosVersion = ")
+
+	exec(t, s, out, "cd Systems/System.Embedded.1")
+	assert.Contains(t, exec(t, s, out, "actions"),
+		"#ComputerSystem.Reset\t/redfish/v1/Systems/System.Embedded.1/Actions/ComputerSystem.Reset\n  ResetType: On, ForceOff")
+
+	exec(t, s, out, `patch {"HostName": "rack12-u07"}`)
+	assert.Equal(t, "\"rack12-u07\"\n", exec(t, s, out, "cat HostName"))
+	err := s.Exec(`patch . {"AssetTag": "x"}`)
+	assert.ErrorContains(t, err, "patch: HTTP 400")
+
+	assert.Equal(t, "   1  find Disk.Bay\n", strings.SplitAfter(exec(t, s, out, "history"), "\n")[0])
+}
+
+func TestXCCActionInfo(t *testing.T) {
+	s, out := newShell(t, emulator.XCC())
+	exec(t, s, out, "cd Systems/1")
+	assert.Contains(t, exec(t, s, out, "actions"), "  ResetType: On, Nmi, GracefulShutdown, GracefulRestart, ForceOn, ForceOff, ForceRestart\n")
+}
+
+func TestRunScript(t *testing.T) {
+	s, out := newShell(t, emulator.IDRAC())
+	var errOut bytes.Buffer
+	failed := s.Run(strings.NewReader("# a comment\ncd Systems\nls\ncat NoSuchThing\nexit\nls\n"), &errOut)
+	assert.Equal(t, 1, failed)
+	assert.Equal(t, "System.Embedded.1/\t/redfish/v1/Systems/System.Embedded.1\nName\tComputer System Collection\n", out.String())
+	assert.Contains(t, errOut.String(), "cat: /redfish/v1/Systems/NoSuchThing: HTTP 404")
+}
+
+func TestComplete(t *testing.T) {
+	s, _ := newShell(t, emulator.IDRAC())
+
+	start, c := s.Complete("h")
+	assert.Equal(t, 0, start)
+	assert.Equal(t, []string{"help", "history"}, c)
+
+	start, c = s.Complete("cd Sys")
+	assert.Equal(t, 3, start)
+	assert.Equal(t, []string{"Systems/"}, c)
+
+	_, c = s.Complete("ls Systems/")
+	assert.Equal(t, []string{"Systems/System.Embedded.1/"}, c)
+
+	_, c = s.Complete("cd /redfish/v1/Man")
+	assert.Equal(t, []string{"/redfish/v1/Managers/"}, c)
+
+	require.NoError(t, s.Exec("cd Systems/System.Embedded.1"))
+	_, c = s.Complete("cat Status.")
+	assert.Equal(t, []string{"Status.Health", "Status.HealthRollup", "Status.State"}, c)
+	_, c = s.Complete("cat Pow")
+	assert.Equal(t, []string{"PowerState"}, c)
+	_, c = s.Complete("cd Links/Ma")
+	assert.Equal(t, []string{"Links/ManagedBy/0/"}, c)
+
+	_, c = s.Complete("find Sys")
+	assert.Empty(t, c)
+}
+
+func TestEditor(t *testing.T) {
+	s, _ := newShell(t, emulator.IDRAC())
+	keys := "cd Sys\t\r" + // completes to Systems/
+		"x\x7fls\r" + // backspace
+		"\x1b[A\x1b[A\r" + // two lines back in the history
+		"ca\t\x01\x05Red\t\r" + // ctrl-A/ctrl-E move the cursor
+		"garbage\x03" + // ctrl-C drops the line
+		"\x04" // ctrl-D ends the input
+	var screen bytes.Buffer
+	e := NewEditor(strings.NewReader(keys), &screen, -1)
+	e.Complete = s.Complete
+
+	var lines []string
+	for {
+		line, err := e.ReadLine("> ")
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		lines = append(lines, line)
+		e.AddHistory(line)
+	}
+	assert.Equal(t, []string{"cd Systems/", "ls", "cd Systems/", "cat RedfishVersion "}, lines)
+	assert.Contains(t, screen.String(), "^C\r\n")
+}
@@ -0,0 +1,26 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package shell
+
+import "errors"
+
+// IsTerminal reports whether fd is a terminal. Line editing is only
+// supported on Unix systems; elsewhere commands are read line by line.
+func IsTerminal(fd int) bool {
+	return false
+}
+
+// MakeRaw is not supported outside Unix systems.
+func MakeRaw(fd int) (func(), error) {
+	return nil, errors.New("raw terminal mode is not supported on this platform")
+}
+
+// Size is not supported outside Unix systems.
+func Size(fd int) (width, height int, err error) {
+	return 0, 0, errors.New("terminal size is not supported on this platform")
+}
+
+// ReadPassword is not supported outside Unix systems.
+func ReadPassword(fd int) (string, error) {
+	return "", errors.New("reading a password is not supported on this platform")
+}
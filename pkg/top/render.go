@@ -0,0 +1,262 @@
+package top
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/angelhvargas/redfishcli/pkg/inventory"
+	"github.com/angelhvargas/redfishcli/pkg/model"
+)
+
+// ANSI colors.
+const (
+	colorReset  = "\x1b[0m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+	colorBold   = "\x1b[1m"
+	colorInvert = "\x1b[7m"
+)
+
+// recentEvents is how many of the latest SEL entries the host view lists.
+const recentEvents = 5
+
+// cell is a table cell with the color it is printed in, if any.
+type cell struct {
+	text  string
+	color string
+}
+
+// Render writes the dashboard, at most height lines of it when height is
+// positive. Lines end in "\n".
+func (d *Dashboard) Render(w io.Writer, height int) {
+	var lines []string
+	if d.detail {
+		lines = d.hostView()
+	} else {
+		lines = d.listView(height)
+	}
+	if d.status != "" {
+		lines = append(lines, "", d.status)
+	}
+	if height > 0 && len(lines) > height {
+		lines = lines[:height]
+	}
+	for _, line := range lines {
+		fmt.Fprintln(w, line)
+	}
+}
+
+func (d *Dashboard) header() string {
+	updated := "waiting for the first refresh"
+	if !d.updated.IsZero() {
+		updated = "refreshed " + d.updated.Format("15:04:05")
+	}
+	if d.interval > 0 {
+		updated += fmt.Sprintf(" every %s", d.interval)
+	}
+	return d.paint(fmt.Sprintf("redfishcli top - %d servers, %s", len(d.hosts), updated), colorBold)
+}
+
+// listView is the table of every server. When the table does not fit in
+// height lines it scrolls to keep the selected server visible.
+func (d *Dashboard) listView(height int) []string {
+	rows := [][]cell{{{"HOST", ""}, {"POWER", ""}, {"HEALTH", ""}, {"STORAGE", ""}, {"PSU/FAN", ""}, {"NEW SEL", ""}, {"ERROR", ""}}}
+	for _, h := range d.hosts {
+		rows = append(rows, d.hostRow(h))
+	}
+	table := d.table(rows)
+
+	lines := []string{d.header(), "", table[0]}
+	body := table[1:]
+	first := 0
+	// The header, the column names, the key help and the status take 6
+	// lines.
+	if room := height - 6; height > 0 && room > 0 && len(body) > room {
+		if d.selected >= room {
+			first = d.selected - room + 1
+		}
+		body = body[first : first+room]
+	}
+	for i, row := range body {
+		if first+i == d.selected {
+			row = d.paint("> ", colorInvert) + row
+		} else {
+			row = "  " + row
+		}
+		lines = append(lines, row)
+	}
+	return append(lines, "", "up/down select  enter details  r refresh  q quit")
+}
+
+func (d *Dashboard) hostRow(h *Host) []cell {
+	if h.Health == "" {
+		// Not read yet, or the client could not even be created.
+		if h.Err == nil {
+			return []cell{{h.Hostname, ""}, {"...", ""}}
+		}
+		return []cell{{h.Hostname, ""}, {}, {}, {}, {}, {}, {firstLine(h.Err.Error()), colorRed}}
+	}
+	storage := cell{"-", ""}
+	if h.Storage != "" && h.Storage != inventory.HealthUnknown {
+		storage = healthCell(h.Storage)
+		storage.text = fmt.Sprintf("%s %d drives", h.Storage, h.Drives)
+		if h.FailedDrives > 0 {
+			storage.text += fmt.Sprintf(", %d failed", h.FailedDrives)
+			storage.color = colorRed
+		}
+	}
+	alerts := cell{"-", ""}
+	if list := h.Alerts(); len(list) > 0 {
+		var ids []string
+		worst := inventory.HealthOK
+		for _, a := range list {
+			ids = append(ids, a.ID)
+			worst = worst.Worse(a.Status.Health)
+		}
+		alerts = healthCell(worst)
+		alerts.text = strings.Join(ids, ",")
+	}
+	events := cell{"-", ""}
+	if h.Events != nil {
+		events = cell{fmt.Sprint(len(d.fresh[h.Hostname])), ""}
+		if worst := worstSeverity(d.fresh[h.Hostname]); worst != "" {
+			events.color = severityColor(worst)
+		}
+	}
+	errText := cell{"", ""}
+	if h.Err != nil {
+		errText = cell{firstLine(h.Err.Error()), colorRed}
+	}
+	return []cell{{h.Hostname, ""}, {string(h.PowerState), ""}, healthCell(h.Health), storage, alerts, events, errText}
+}
+
+// hostView details the selected server.
+func (d *Dashboard) hostView() []string {
+	h := d.Selected()
+	lines := []string{d.header(), ""}
+	title := h.Hostname
+	if h.Vendor != "" || h.Model != "" {
+		title += " - " + strings.TrimSpace(h.Vendor+" "+h.Model)
+	}
+	lines = append(lines, d.paint(title, colorBold),
+		fmt.Sprintf("Power: %s  Health: %s  Storage: %s (%d drives, %d failed)",
+			h.PowerState, d.paintHealth(h.Health), d.paintHealth(h.Storage), h.Drives, h.FailedDrives))
+	if h.Err != nil {
+		lines = append(lines, d.paint("Error: "+firstLine(h.Err.Error()), colorRed))
+	}
+
+	lines = append(lines, "", "Issues:")
+	if len(h.Issues) == 0 {
+		lines = append(lines, "  none")
+	}
+	for _, issue := range h.Issues {
+		lines = append(lines, fmt.Sprintf("  %s %s: %s (%s)", issue.Component, issue.ID, d.paintHealth(issue.Status.Health), issue.Status.State))
+	}
+
+	lines = append(lines, "", "New SEL entries:")
+	lines = append(lines, d.events(d.fresh[h.Hostname])...)
+	recent := h.Events
+	if len(recent) > recentEvents {
+		recent = recent[len(recent)-recentEvents:]
+	}
+	lines = append(lines, "", fmt.Sprintf("Latest SEL entries (%d in the log):", len(h.Events)))
+	lines = append(lines, d.events(recent)...)
+
+	return append(lines, "",
+		"i identify LED on  I identify LED off  o power on  s shut down  f force off  b restart  B force restart",
+		"esc back  r refresh  q quit")
+}
+
+func (d *Dashboard) events(entries []model.EventLogEntry) []string {
+	if len(entries) == 0 {
+		return []string{"  none"}
+	}
+	var lines []string
+	for _, e := range entries {
+		lines = append(lines, fmt.Sprintf("  [%s] %s: %s", e.Created, d.paint(string(e.Severity), severityColor(e.Severity)), e.Message))
+	}
+	return lines
+}
+
+// table pads the cells of rows into aligned columns. Colors are added after
+// padding so they do not count towards the width.
+func (d *Dashboard) table(rows [][]cell) []string {
+	var widths []int
+	for _, row := range rows {
+		for i, c := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], utf8.RuneCountInString(c.text))
+		}
+	}
+	lines := make([]string, len(rows))
+	for r, row := range rows {
+		var b strings.Builder
+		for i, c := range row {
+			text := c.text
+			if i < len(row)-1 {
+				text += strings.Repeat(" ", widths[i]-utf8.RuneCountInString(c.text)+2)
+			}
+			b.WriteString(d.paint(text, c.color))
+		}
+		lines[r] = strings.TrimRight(b.String(), " ")
+	}
+	return lines
+}
+
+func (d *Dashboard) paint(text, color string) string {
+	if !d.Color || color == "" {
+		return text
+	}
+	trimmed := strings.TrimRight(text, " ")
+	return color + trimmed + colorReset + text[len(trimmed):]
+}
+
+func (d *Dashboard) paintHealth(h inventory.Health) string {
+	c := healthCell(h)
+	return d.paint(c.text, c.color)
+}
+
+func healthCell(h inventory.Health) cell {
+	switch h {
+	case inventory.HealthOK:
+		return cell{string(h), colorGreen}
+	case inventory.HealthWarning:
+		return cell{string(h), colorYellow}
+	case inventory.HealthCritical:
+		return cell{string(h), colorRed}
+	}
+	return cell{string(h), ""}
+}
+
+func severityColor(s model.EventSeverity) string {
+	switch s {
+	case model.EventSeverityCritical:
+		return colorRed
+	case model.EventSeverityWarning:
+		return colorYellow
+	}
+	return ""
+}
+
+func worstSeverity(entries []model.EventLogEntry) model.EventSeverity {
+	var worst model.EventSeverity
+	for _, e := range entries {
+		switch {
+		case e.Severity == model.EventSeverityCritical:
+			return e.Severity
+		case e.Severity == model.EventSeverityWarning:
+			worst = e.Severity
+		}
+	}
+	return worst
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
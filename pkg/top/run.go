@@ -0,0 +1,186 @@
+package top
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+)
+
+// Options connect a Dashboard to the servers it shows.
+type Options struct {
+	// Interval is the time between two refreshes.
+	Interval time.Duration
+	// Refresh takes a snapshot of every server.
+	Refresh func() []*Host
+	// Act runs a confirmed action.
+	Act func(Action) error
+	// Height returns the number of lines of the terminal, or 0 when it is
+	// not known.
+	Height func() int
+	// Now returns the current time; time.Now when nil.
+	Now func() time.Time
+}
+
+// Escape sequences drawing the screen.
+const (
+	cursorHome = "\x1b[H"
+	clearLine  = "\x1b[K"
+	clearBelow = "\x1b[J"
+)
+
+// Run shows the dashboard on out, reading keys from in, until q or Ctrl-C
+// is pressed or in ends. Snapshots are taken in the background every
+// Interval and after an action, so a slow BMC does not block the keys. The
+// terminal is expected to be in raw mode, so lines end in "\r\n".
+func (d *Dashboard) Run(in io.Reader, out io.Writer, opts Options) error {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	if opts.Height == nil {
+		opts.Height = func() int { return 0 }
+	}
+
+	// done stops the goroutines still running when Run returns.
+	done := make(chan struct{})
+	defer close(done)
+
+	keys := make(chan string)
+	keyErr := make(chan error, 1)
+	go func() {
+		r := bufio.NewReader(in)
+		for {
+			key, err := readKey(r)
+			if err != nil {
+				keyErr <- err
+				return
+			}
+			select {
+			case keys <- key:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	snapshots := make(chan []*Host, 1)
+	refreshing := false
+	refresh := func() {
+		if refreshing {
+			return
+		}
+		refreshing = true
+		go func() {
+			hosts := opts.Refresh()
+			select {
+			case snapshots <- hosts:
+			case <-done:
+			}
+		}()
+	}
+	results := make(chan string)
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	draw := func() {
+		var screen strings.Builder
+		d.Render(&screen, opts.Height())
+		frame := cursorHome + strings.ReplaceAll(screen.String(), "\n", clearLine+"\r\n") + clearBelow
+		io.WriteString(out, frame)
+	}
+
+	refresh()
+	draw()
+	for {
+		select {
+		case key := <-keys:
+			switch d.Key(key) {
+			case Quit:
+				return nil
+			case Refresh:
+				refresh()
+			case Execute:
+				action := *d.Pending()
+				go func() {
+					msg := "Done: " + action.String()
+					if err := opts.Act(action); err != nil {
+						msg = "Failed to " + action.String() + ": " + firstLine(err.Error())
+					}
+					select {
+					case results <- msg:
+					case <-done:
+					}
+				}()
+			}
+		case err := <-keyErr:
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		case hosts := <-snapshots:
+			refreshing = false
+			d.Update(hosts, opts.Now())
+		case msg := <-results:
+			d.SetStatus(msg)
+			refresh()
+		case <-ticker.C:
+			refresh()
+		}
+		draw()
+	}
+}
+
+// readKey reads one key press and names it: printable characters are
+// returned as is, other keys as up, down, left, right, enter, esc,
+// backspace or ctrl-c. Unknown sequences are returned empty.
+func readKey(r *bufio.Reader) (string, error) {
+	c, _, err := r.ReadRune()
+	if err != nil {
+		return "", err
+	}
+	switch c {
+	case '\r', '\n':
+		return "enter", nil
+	case 3:
+		return "ctrl-c", nil
+	case 8, 127:
+		return "backspace", nil
+	case 27:
+		// A lone escape is the escape key; arrows send ESC [ A to D.
+		if r.Buffered() == 0 {
+			return "esc", nil
+		}
+		next, _, err := r.ReadRune()
+		if err != nil {
+			return "esc", nil
+		}
+		if next != '[' && next != 'O' {
+			r.UnreadRune()
+			return "esc", nil
+		}
+		var seq strings.Builder
+		for {
+			c, _, err := r.ReadRune()
+			if err != nil {
+				return "", nil
+			}
+			seq.WriteRune(c)
+			if c >= 'A' && c <= 'Z' || c == '~' {
+				break
+			}
+		}
+		switch seq.String() {
+		case "A":
+			return "up", nil
+		case "B":
+			return "down", nil
+		case "C":
+			return "right", nil
+		case "D":
+			return "left", nil
+		}
+		return "", nil
+	}
+	return string(c), nil
+}
@@ -0,0 +1,307 @@
+// Package top is a continuously refreshing dashboard of a fleet of servers:
+// their power state, health, storage, power supply and fan alerts and new
+// system event log entries, with keys to drill into one server and to run
+// identify LED and power actions on it after confirmation.
+//
+// The Dashboard is a model driven by key names and snapshots, so it can be
+// rendered and tested without a terminal; Run connects it to one.
+package top
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/angelhvargas/redfishcli/pkg/client"
+	"github.com/angelhvargas/redfishcli/pkg/inventory"
+	"github.com/angelhvargas/redfishcli/pkg/model"
+)
+
+// Parts are the inventory parts a snapshot reads.
+const Parts = inventory.PartSystem | inventory.PartStorage | inventory.PartDrives | inventory.PartVolumes |
+	inventory.PartPower | inventory.PartThermal
+
+// Host is a snapshot of one server.
+type Host struct {
+	Hostname   string
+	Vendor     string
+	Model      string
+	PowerState inventory.PowerState
+	Health     inventory.Health
+	// Storage is the worst health of the controllers, volumes and drives.
+	Storage      inventory.Health
+	Drives       int
+	FailedDrives int
+	// Issues lists the components whose health is not OK.
+	Issues []inventory.Issue
+	// Events is the system event log as the BMC returned it; nil when the
+	// backend cannot read it.
+	Events []model.EventLogEntry
+	// Err is set when some or all of the snapshot could not be read.
+	Err error
+}
+
+// Snapshot reads the server behind c. Whatever could be read is kept when a
+// request fails, with the failures in Err.
+func Snapshot(c client.ServerClient, hostname string) *Host {
+	h := &Host{Hostname: hostname, PowerState: inventory.PowerUnknown, Health: inventory.HealthUnknown, Storage: inventory.HealthUnknown}
+	inv := &inventory.Inventory{Hostname: hostname}
+	err := inv.Read(c, Parts)
+	if inv.System.ID != "" {
+		h.Vendor = inv.Vendor
+		h.Model = inv.System.Model
+		h.PowerState = inv.System.PowerState
+		h.Health = inv.Health()
+		h.Issues = inv.Issues()
+	}
+	if inv.Controllers != nil {
+		h.Storage = inventory.HealthOK
+		for _, ctrl := range inv.Controllers {
+			h.Drives += ctrl.DriveCount
+			for _, d := range ctrl.Drives {
+				if d.FailurePredicted || d.Status.Health == inventory.HealthCritical {
+					h.FailedDrives++
+				}
+			}
+		}
+		for _, issue := range h.Issues {
+			if isStorage(issue.Component) {
+				h.Storage = h.Storage.Worse(issue.Status.Health)
+			}
+		}
+	}
+
+	if lr, lerr := client.Logs(c); lerr == nil {
+		events, lerr := lr.GetSystemEventLog()
+		switch lerr = client.Explain(c, client.CapabilityLogs, lerr); {
+		case errors.Is(lerr, client.ErrNotSupported):
+		case lerr != nil:
+			err = errors.Join(err, lerr)
+		default:
+			h.Events = events
+			if h.Events == nil {
+				h.Events = []model.EventLogEntry{}
+			}
+		}
+	}
+	h.Err = err
+	return h
+}
+
+// Alerts are the power supply and fan issues.
+func (h *Host) Alerts() []inventory.Issue {
+	var alerts []inventory.Issue
+	for _, issue := range h.Issues {
+		if issue.Component == "power_supply" || issue.Component == "fan" {
+			alerts = append(alerts, issue)
+		}
+	}
+	return alerts
+}
+
+func isStorage(component string) bool {
+	return component == "controller" || component == "drive" || component == "volume"
+}
+
+// ActionKind is what an Action does.
+type ActionKind string
+
+const (
+	// ActionIdentify turns the identify LED on or off.
+	ActionIdentify ActionKind = "identify"
+	// ActionPower resets the system with a Redfish ResetType.
+	ActionPower ActionKind = "power"
+)
+
+// Action is an operation the user asked for on one server.
+type Action struct {
+	Hostname string
+	Kind     ActionKind
+	// ResetType is the ResetType of a power action.
+	ResetType string
+	// On tells whether an identify action turns the LED on or off.
+	On bool
+}
+
+func (a Action) String() string {
+	if a.Kind == ActionIdentify {
+		if a.On {
+			return fmt.Sprintf("blink the identify LED of %s", a.Hostname)
+		}
+		return fmt.Sprintf("turn off the identify LED of %s", a.Hostname)
+	}
+	return fmt.Sprintf("%s %s", a.ResetType, a.Hostname)
+}
+
+// Request tells the caller of Dashboard.Key what to do next.
+type Request int
+
+const (
+	// Redraw asks to render the dashboard again.
+	Redraw Request = iota
+	// Refresh asks for new snapshots now.
+	Refresh
+	// Execute asks to run the confirmed action.
+	Execute
+	// Quit asks to leave.
+	Quit
+)
+
+// powerKeys are the keys of the power actions in the host view.
+var powerKeys = map[string]string{
+	"o": "On",
+	"s": "GracefulShutdown",
+	"f": "ForceOff",
+	"b": "GracefulRestart",
+	"B": "ForceRestart",
+}
+
+// Dashboard is the state of the dashboard: the latest snapshot of every
+// server, the SEL entries that appeared while it ran, the selected server
+// and whether it is shown in detail.
+type Dashboard struct {
+	hosts    []*Host
+	selected int
+	detail   bool
+	confirm  *Action
+	// confirmed tells whether confirm was answered with y.
+	confirmed bool
+	status    string
+	updated   time.Time
+	interval  time.Duration
+
+	// seen holds the SEL entries of each server already known, by key.
+	seen map[string]map[string]bool
+	// fresh holds the entries that appeared after the first snapshot.
+	fresh map[string][]model.EventLogEntry
+
+	// Color highlights health and alerts with ANSI colors.
+	Color bool
+}
+
+// New returns a dashboard of the given servers, waiting for their first
+// snapshot. interval is only shown in the header.
+func New(hostnames []string, interval time.Duration) *Dashboard {
+	d := &Dashboard{
+		interval: interval,
+		seen:     make(map[string]map[string]bool),
+		fresh:    make(map[string][]model.EventLogEntry),
+	}
+	for _, name := range hostnames {
+		d.hosts = append(d.hosts, &Host{Hostname: name})
+	}
+	return d
+}
+
+// Update replaces the snapshots of the given servers. SEL entries not seen
+// in an earlier snapshot of the same server are kept as new.
+func (d *Dashboard) Update(hosts []*Host, now time.Time) {
+	for _, h := range hosts {
+		if h.Events != nil {
+			seen, known := d.seen[h.Hostname]
+			if !known {
+				seen = make(map[string]bool)
+				d.seen[h.Hostname] = seen
+			}
+			for _, e := range h.Events {
+				key := e.ID + "\x00" + e.Created
+				if !seen[key] {
+					seen[key] = true
+					if known {
+						d.fresh[h.Hostname] = append(d.fresh[h.Hostname], e)
+					}
+				}
+			}
+		}
+		found := false
+		for i, old := range d.hosts {
+			if old.Hostname == h.Hostname {
+				d.hosts[i], found = h, true
+			}
+		}
+		if !found {
+			d.hosts = append(d.hosts, h)
+		}
+	}
+	d.updated = now
+}
+
+// NewEvents returns the SEL entries of hostname that appeared after the
+// first snapshot.
+func (d *Dashboard) NewEvents(hostname string) []model.EventLogEntry {
+	return d.fresh[hostname]
+}
+
+// Selected returns the selected server, or nil when there is none.
+func (d *Dashboard) Selected() *Host {
+	if d.selected < len(d.hosts) {
+		return d.hosts[d.selected]
+	}
+	return nil
+}
+
+// Pending returns the action waiting for confirmation or confirmed by the
+// last key, or nil.
+func (d *Dashboard) Pending() *Action {
+	return d.confirm
+}
+
+// SetStatus sets the message shown at the bottom, e.g. how an action went.
+func (d *Dashboard) SetStatus(msg string) {
+	d.status = msg
+}
+
+// Key handles a key: a printable character, or one of up, down, enter,
+// esc, backspace, left, right and ctrl-c. Actions are only asked for in the
+// host view and must be confirmed with y; Execute is returned once they are,
+// with the action in Pending until the next key.
+func (d *Dashboard) Key(key string) Request {
+	if d.confirm != nil && !d.confirmed {
+		if key == "y" || key == "Y" {
+			d.confirmed = true
+			d.status = fmt.Sprintf("Running: %s...", *d.confirm)
+			return Execute
+		}
+		d.confirm = nil
+		d.status = "Cancelled"
+		return Redraw
+	}
+	d.confirm, d.confirmed = nil, false
+
+	switch key {
+	case "q", "ctrl-c":
+		return Quit
+	case "r":
+		d.status = "Refreshing..."
+		return Refresh
+	case "up", "k":
+		if d.selected > 0 {
+			d.selected--
+		}
+	case "down", "j":
+		if d.selected < len(d.hosts)-1 {
+			d.selected++
+		}
+	case "enter", "right", "l":
+		d.detail = d.Selected() != nil
+	case "esc", "left", "h", "backspace":
+		d.detail = false
+	}
+	if !d.detail {
+		return Redraw
+	}
+
+	host := d.Selected()
+	var action *Action
+	switch {
+	case key == "i", key == "I":
+		action = &Action{Hostname: host.Hostname, Kind: ActionIdentify, On: key == "i"}
+	case powerKeys[key] != "":
+		action = &Action{Hostname: host.Hostname, Kind: ActionPower, ResetType: powerKeys[key]}
+	}
+	if action != nil {
+		d.confirm = action
+		d.status = fmt.Sprintf("Really %s? [y/N]", *action)
+	}
+	return Redraw
+}
@@ -0,0 +1,200 @@
+package top
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/angelhvargas/redfishcli/pkg/emulator"
+	"github.com/angelhvargas/redfishcli/pkg/idrac"
+	"github.com/angelhvargas/redfishcli/pkg/inventory"
+	"github.com/angelhvargas/redfishcli/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const powerURI = "/redfish/v1/Chassis/System.Embedded.1/Power"
+
+func startEmulator(t *testing.T) (*emulator.Emulator, *idrac.Client) {
+	emu, err := emulator.New(emulator.IDRAC(), emulator.Options{Username: "root", Password: "calvin"})
+	require.NoError(t, err)
+	srv, err := emu.Start("127.0.0.1:0", emulator.StartOptions{})
+	require.NoError(t, err)
+	t.Cleanup(func() { srv.Close() })
+	return emu, idrac.NewClient(config.IDRACConfig{BMCConnConfig: config.BMCConnConfig{Hostname: srv.Host, Username: "root", Password: "calvin"}})
+}
+
+func TestSnapshot(t *testing.T) {
+	emu, c := startEmulator(t)
+
+	h := Snapshot(c, "r740")
+	require.NoError(t, h.Err)
+	assert.Equal(t, "Dell", h.Vendor)
+	assert.Equal(t, inventory.PowerOn, h.PowerState)
+	assert.Equal(t, inventory.HealthOK, h.Health)
+	assert.Equal(t, inventory.HealthOK, h.Storage)
+	assert.Equal(t, 2, h.Drives)
+	assert.Empty(t, h.Alerts())
+	assert.NotEmpty(t, h.Events)
+
+	// A failed power supply is an alert and degrades the server.
+	power, ok := emu.Resource(powerURI)
+	require.True(t, ok)
+	psu := power["PowerSupplies"].([]interface{})[1].(map[string]interface{})
+	psu["Status"].(map[string]interface{})["Health"] = "Critical"
+	emu.SetResource(powerURI, power)
+
+	h = Snapshot(c, "r740")
+	require.NoError(t, h.Err)
+	assert.Equal(t, inventory.HealthCritical, h.Health)
+	require.Len(t, h.Alerts(), 1)
+	assert.Equal(t, "power_supply", h.Alerts()[0].Component)
+}
+
+func TestUpdateTracksNewEvents(t *testing.T) {
+	emu, c := startEmulator(t)
+	d := New([]string{"r740"}, time.Minute)
+
+	d.Update([]*Host{Snapshot(c, "r740")}, time.Now())
+	assert.Empty(t, d.NewEvents("r740"), "the log at start is not new")
+
+	emu.AddLogEntry("Critical", "The chassis was opened.")
+	d.Update([]*Host{Snapshot(c, "r740")}, time.Now())
+	d.Update([]*Host{Snapshot(c, "r740")}, time.Now())
+	events := d.NewEvents("r740")
+	require.Len(t, events, 1)
+	assert.Equal(t, "The chassis was opened.", events[0].Message)
+}
+
+func hosts() []*Host {
+	return []*Host{
+		{Hostname: "dell-01", Vendor: "Dell", Model: "PowerEdge R740", PowerState: inventory.PowerOn,
+			Health: inventory.HealthOK, Storage: inventory.HealthOK, Drives: 2, Events: []model.EventLogEntry{}},
+		{Hostname: "lenovo-01", Vendor: "Lenovo", PowerState: inventory.PowerOn, Health: inventory.HealthCritical,
+			Storage: inventory.HealthCritical, Drives: 4, FailedDrives: 1,
+			Issues: []inventory.Issue{
+				{Component: "drive", ID: "Disk.3", Status: inventory.Status{Health: inventory.HealthCritical, State: inventory.StateEnabled}},
+				{Component: "fan", ID: "Fan.2", Status: inventory.Status{Health: inventory.HealthWarning, State: inventory.StateEnabled}},
+			}},
+		{Hostname: "down-01", Err: errors.New("dial tcp: connection refused")},
+	}
+}
+
+func render(d *Dashboard) string {
+	var out bytes.Buffer
+	d.Render(&out, 0)
+	return out.String()
+}
+
+func TestRenderList(t *testing.T) {
+	d := New([]string{"dell-01", "lenovo-01", "down-01"}, 10*time.Second)
+	assert.Contains(t, render(d), "> dell-01    ...\n")
+
+	d.Update(hosts(), time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	screen := render(d)
+	assert.Contains(t, screen, "redfishcli top - 3 servers, refreshed 03:04:05 every 10s\n")
+	assert.Contains(t, screen, "> dell-01    On     OK        OK 2 drives                  -        0\n")
+	assert.Contains(t, screen, "  lenovo-01  On     Critical  Critical 4 drives, 1 failed  Fan.2    -\n")
+	assert.Regexp(t, `\n  down-01 +dial tcp: connection refused\n`, screen)
+
+	d.Color = true
+	assert.Contains(t, render(d), "\x1b[31mCritical 4 drives, 1 failed\x1b[0m")
+}
+
+func TestKeys(t *testing.T) {
+	d := New(nil, time.Minute)
+	d.Update(hosts(), time.Now())
+
+	// Actions are not available from the list.
+	assert.Equal(t, Redraw, d.Key("f"))
+	assert.Nil(t, d.Pending())
+
+	assert.Equal(t, Redraw, d.Key("down"))
+	assert.Equal(t, Redraw, d.Key("enter"))
+	screen := render(d)
+	assert.Contains(t, screen, "lenovo-01 - Lenovo\n")
+	assert.Contains(t, screen, "  fan Fan.2: Warning (Enabled)\n")
+
+	// Anything but y cancels.
+	d.Key("f")
+	assert.Contains(t, render(d), "Really ForceOff lenovo-01? [y/N]\n")
+	assert.Equal(t, Redraw, d.Key("n"))
+	assert.Nil(t, d.Pending())
+	assert.Contains(t, render(d), "Cancelled\n")
+
+	d.Key("i")
+	assert.Equal(t, Execute, d.Key("y"))
+	assert.Equal(t, &Action{Hostname: "lenovo-01", Kind: ActionIdentify, On: true}, d.Pending())
+	assert.Contains(t, render(d), "Running: blink the identify LED of lenovo-01...\n")
+
+	assert.Equal(t, Redraw, d.Key("esc"))
+	assert.Nil(t, d.Pending())
+	assert.Contains(t, render(d), "> lenovo-01")
+	assert.Equal(t, Refresh, d.Key("r"))
+	assert.Equal(t, Quit, d.Key("q"))
+}
+
+// screen collects what Run draws.
+type screen struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (s *screen) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.Write(p)
+}
+
+func (s *screen) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.String()
+}
+
+func TestRun(t *testing.T) {
+	keys, typing := io.Pipe()
+	acted := make(chan Action, 1)
+	d := New([]string{"dell-01", "lenovo-01", "down-01"}, time.Hour)
+	var out screen
+	done := make(chan error)
+	go func() {
+		done <- d.Run(keys, &out, Options{
+			Interval: time.Hour,
+			Refresh:  hosts,
+			Act: func(a Action) error {
+				acted <- a
+				return errors.New("HTTP 409: busy")
+			},
+		})
+	}()
+
+	io.WriteString(typing, "\x1b[B\rsy")
+	assert.Equal(t, Action{Hostname: "lenovo-01", Kind: ActionPower, ResetType: "GracefulShutdown"}, <-acted)
+	assert.Eventually(t, func() bool {
+		return strings.Contains(out.String(), "Failed to GracefulShutdown lenovo-01: HTTP 409: busy\x1b[K\r\n")
+	}, 5*time.Second, 10*time.Millisecond)
+
+	io.WriteString(typing, "q")
+	require.NoError(t, <-done)
+	assert.True(t, strings.HasPrefix(out.String(), "\x1b[H"))
+}
+
+func TestReadKey(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("j\x1b[A\x1b[B\x1b[C\x1b[D\x1bq\r\x7f\x03"))
+	var keys []string
+	for {
+		key, err := readKey(r)
+		if err != nil {
+			break
+		}
+		keys = append(keys, key)
+	}
+	assert.Equal(t, []string{"j", "up", "down", "right", "left", "esc", "q", "enter", "backspace", "ctrl-c"}, keys)
+}
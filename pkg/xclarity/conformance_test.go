@@ -0,0 +1,19 @@
+package xclarity
+
+import (
+	"testing"
+
+	"github.com/angelhvargas/redfishcli/pkg/client"
+	"github.com/angelhvargas/redfishcli/pkg/client/clienttest"
+	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/angelhvargas/redfishcli/pkg/emulator"
+)
+
+func TestConformance(t *testing.T) {
+	clienttest.Run(t, clienttest.Backend{
+		Factory: func(cfg config.BMCConnConfig) client.ServerClient {
+			return NewClient(config.XClarityConfig{BMCConnConfig: cfg})
+		},
+		Profile: emulator.XCC(),
+	})
+}
@@ -1,221 +1,161 @@
 package xclarity
 
 import (
-	"encoding/json"
 	"fmt"
-	"log"
+	"strings"
+	"sync"
 
 	"github.com/angelhvargas/redfishcli/pkg/client"
 	"github.com/angelhvargas/redfishcli/pkg/config"
 	"github.com/angelhvargas/redfishcli/pkg/httpclient"
-	"github.com/angelhvargas/redfishcli/pkg/logger"
+	"github.com/angelhvargas/redfishcli/pkg/inventory"
 	"github.com/angelhvargas/redfishcli/pkg/model"
+	"github.com/angelhvargas/redfishcli/pkg/registry"
 	"github.com/angelhvargas/redfishcli/pkg/request"
 )
 
+const (
+	// systemPath is the single ComputerSystem exposed by the XClarity Controller.
+	systemPath = "/redfish/v1/Systems/1"
+	selPath    = systemPath + "/LogServices/PlatformLog/Entries"
+)
+
 type Client struct {
 	Config           config.XClarityConfig
 	Debug            bool
 	HTTPClientConfig httpclient.Config
+
+	registriesOnce sync.Once
+	registries     *registry.Cache
 }
 
-// NewClient creates a new iDRAC client
+// NewClient creates a new XClarity client
 func NewClient(cfg config.XClarityConfig) *Client {
 	return &Client{
 		Config:           cfg,
 		Debug:            false,
-		HTTPClientConfig: httpclient.DefaultConfig(),
+		HTTPClientConfig: cfg.HTTPClientConfig(),
 	}
 }
 
-// GetServerInfo gets the server information from iDRAC
-func (c *Client) GetServerInfo() (*model.ServerInfo, error) {
-	// Construct the Endpoint
-	url := fmt.Sprintf("https://%s/redfish/v1/Systems/System.Embedded.1", c.Config.Hostname)
+// endpoint turns a Redfish URI such as an @odata.id into an absolute URL on this BMC.
+func (c *Client) endpoint(uri string) string {
+	if strings.HasPrefix(uri, "https://") || strings.HasPrefix(uri, "http://") {
+		return uri
+	}
+	return fmt.Sprintf("https://%s%s", c.Config.Hostname, uri)
+}
 
-	// Create a new request
-	log.Printf("Hostname: %s, Username: %s", c.Config.Hostname, c.Config.Username)
+func (c *Client) fetch(uri string, target interface{}) error {
+	return request.FetchAndUnmarshal(c.endpoint(uri), c.Config.Username, c.Config.Password, c.HTTPClientConfig, target)
+}
 
-	body, err := httpclient.DoRequest(url, c.Config.Username, c.Config.Password, c.HTTPClientConfig)
-	if err != nil {
-		return nil, err
-	}
+// Fetch reads any Redfish resource of the XClarity Controller into target.
+func (c *Client) Fetch(uri string, target interface{}) error {
+	return c.fetch(uri, target)
+}
 
-	log.Printf("Raw server info response: %s\n", string(body))
-	var info model.ServerInfo
-	err = json.Unmarshal(body, &info)
-	if err != nil {
-		return nil, err
-	}
+// Messages resolves MessageIds with the bundled registries and those the
+// XClarity Controller hosts, which are fetched once, when first needed.
+func (c *Client) Messages() *registry.Cache {
+	c.registriesOnce.Do(func() { c.registries = registry.NewCache(c) })
+	return c.registries
+}
 
-	return &info, nil
+var (
+	_ client.FullClient            = (*Client)(nil)
+	_ client.CapabilityProber      = (*Client)(nil)
+	_ client.AllowableValuesReader = (*Client)(nil)
+	_ inventory.Source             = (*Client)(nil)
+)
+
+// Capabilities probes the XClarity Controller for the features it exposes.
+func (c *Client) Capabilities() (*client.Capabilities, error) {
+	return client.ProbeCapabilities(c.fetch, systemPath, selPath)
 }
 
-func (c *Client) GetStorageInfo() (*model.StorageInfo, error) {
-	// Example Endpoint for storage information
-	url := fmt.Sprintf("https://%s/redfish/v1/Systems/System.Embedded.1/Storage", c.Config.Hostname)
-	log.Printf("Hostname: %s, Username: %s", c.Config.Hostname, c.Config.Username)
+// AllowableValues reads the reset types and boot targets the XClarity
+// Controller accepts.
+func (c *Client) AllowableValues() (*client.AllowableValues, error) {
+	return client.ReadAllowableValues(c.fetch, systemPath)
+}
 
-	body, err := httpclient.DoRequest(url, c.Config.Username, c.Config.Password, c.HTTPClientConfig)
-	if err != nil {
+// GetServerInfo gets the server information from XClarity
+func (c *Client) GetServerInfo() (*model.ComputerSystem, error) {
+	var info model.ComputerSystem
+	if err := c.fetch(systemPath, &info); err != nil {
 		return nil, err
 	}
+	return &info, nil
+}
 
-	log.Printf("Raw server info response: %s\n", string(body))
-	var info model.StorageInfo
-	err = json.Unmarshal(body, &info)
-	if err != nil {
+// GetStorageInfo retrieves the storage collection of the system.
+func (c *Client) GetStorageInfo() (*model.Collection[model.IDRef], error) {
+	var info model.Collection[model.IDRef]
+	if err := c.fetch(systemPath+"/Storage", &info); err != nil {
 		return nil, err
 	}
-
 	return &info, nil
 }
 
+// GetDrivesInfo retrieves information for all drives of every storage subsystem.
 func (c *Client) GetDrivesInfo() ([]model.Drive, error) {
-	url := fmt.Sprintf("https://%s/redfish/v1/Systems/System.Embedded.1/Storage", c.Config.Hostname)
-
-	storageCollection, err := c.fetchStorageCollection(url)
-	if err != nil {
+	var storageCollection model.Collection[model.IDRef]
+	if err := c.fetch(systemPath+"/Storage", &storageCollection); err != nil {
 		return nil, err
 	}
 
 	var drives []model.Drive
 	for _, member := range storageCollection.Members {
-		storage, err := c.fetchStorage(member.ID)
-		if err != nil {
+		var storage model.Storage
+		if err := c.fetch(member.ODataID, &storage); err != nil {
 			return nil, err
 		}
 
 		for _, driveRef := range storage.Drives {
-			drive, err := c.fetchDrive(driveRef.ID)
-			if err != nil {
+			var drive model.Drive
+			if err := c.fetch(driveRef.ODataID, &drive); err != nil {
 				return nil, err
 			}
-			drives = append(drives, *drive)
+			drives = append(drives, drive)
 		}
 	}
 
 	return drives, nil
 }
 
-func (c *Client) fetchStorageCollection(url string) (*model.StorageCollection, error) {
-	log.Printf("Hostname: %s, Username: %s", c.Config.Hostname, c.Config.Username)
-
-	body, err := httpclient.DoRequest(url, c.Config.Username, c.Config.Password, c.HTTPClientConfig)
-	if err != nil {
-		return nil, err
-	}
-
-	var storageCollection model.StorageCollection
-	err = json.Unmarshal(body, &storageCollection)
-	if err != nil {
-		return nil, err
-	}
-
-	return &storageCollection, nil
-}
-
-func (c *Client) fetchStorage(url string) (*model.Storage, error) {
-	log.Printf("Hostname: %s, Username: %s", c.Config.Hostname, c.Config.Username)
-	redfish_url := fmt.Sprintf("https://%s%s", c.Config.Hostname, url)
-	log.Printf("Redfish API Url: %s", redfish_url)
-
-	body, err := httpclient.DoRequest(redfish_url, c.Config.Username, c.Config.Password, c.HTTPClientConfig)
-	if err != nil {
-		return nil, err
-	}
-
-	log.Printf("Raw server info response: %s\n", string(body))
-	var storage model.Storage
-	err = json.Unmarshal(body, &storage)
-	if err != nil {
-		return nil, err
-	}
-
-	return &storage, nil
-}
-
-func (c *Client) fetchDrive(url string) (*model.Drive, error) {
-	log.Printf("Hostname: %s, Username: %s", c.Config.Hostname, c.Config.Username)
-	redfish_url := fmt.Sprintf("https://%s%s", c.Config.Hostname, url)
-	log.Printf("Redfish API Url: %s", redfish_url)
-
-	body, err := httpclient.DoRequest(redfish_url, c.Config.Username, c.Config.Password, c.HTTPClientConfig)
-	if err != nil {
-		return nil, err
-	}
-	log.Printf("Raw server info response: %s\n", string(body))
-
-	var drive model.Drive
-	err = json.Unmarshal(body, &drive)
-	if err != nil {
-		return nil, err
-	}
-
-	return &drive, nil
-}
-
+// GetStorageControllers retrieves the storage subsystems of the system.
 func (c *Client) GetStorageControllers(config *model.StorageControllerConfig) ([]model.StorageController, error) {
-	url := fmt.Sprintf("https://%s/redfish/v1/Systems/System.Embedded.1/Storage", c.Config.Hostname)
-	logger.Log.Println(url)
-	body, err := httpclient.DoRequest(url, c.Config.Username, c.Config.Password, c.HTTPClientConfig)
-	if err != nil {
-		logger.Log.Error(err.Error())
+	var storageResp model.Collection[model.StorageController]
+	if err := c.fetch(systemPath+"/Storage", &storageResp); err != nil {
 		return nil, err
 	}
-	log.Printf("Raw server info response: %s\n", string(body))
-	var storageResp model.StorageResponse
-	if err := json.Unmarshal(body, &storageResp); err != nil {
-		return nil, err
-	}
-
 	return storageResp.Members, nil
 }
 
-func (c *Client) GetRAIDVolumeInfo(volumeEndpoint string) (*model.RAIDVolume, error) {
-	body, err := httpclient.DoRequest(volumeEndpoint, c.Config.Username, c.Config.Password, c.HTTPClientConfig)
-	if err != nil {
-		return nil, err
-	}
-	log.Printf("Raw server info response: %s\n", string(body))
-	var volume model.RAIDVolume
-	if err := json.Unmarshal(body, &volume); err != nil {
+// GetRAIDVolumeInfo retrieves information for a specific RAID volume.
+func (c *Client) GetRAIDVolumeInfo(volumeEndpoint string) (*model.Volume, error) {
+	var volume model.Volume
+	if err := c.fetch(volumeEndpoint, &volume); err != nil {
 		return nil, err
 	}
-
 	return &volume, nil
 }
 
-func (c *Client) GetStorageControllerInfo(controllerId string) (*model.StorageControllerDetails, error) {
-	url := fmt.Sprintf("https://%s", controllerId)
-	logger.Log.Info("calling redfish:", url)
-	body, err := httpclient.DoRequest(url, c.Config.Username, c.Config.Password, c.HTTPClientConfig)
-	if err != nil {
-		logger.Log.Error(err.Error())
+// GetStorageControllerInfo retrieves detailed information for a specific RAID controller.
+func (c *Client) GetStorageControllerInfo(controllerID string) (*model.Storage, error) {
+	var raidControllerDetails model.Storage
+	if err := c.fetch(controllerID, &raidControllerDetails); err != nil {
 		return nil, err
 	}
-
-	var raidControllerDetails model.StorageControllerDetails
-	if err := json.Unmarshal(body, &raidControllerDetails); err != nil {
-		logger.Log.Error(err.Error())
-		return nil, err
-	}
-
 	return &raidControllerDetails, nil
-
 }
 
 // GetStorageDriveDetails retrieves detailed information for a specific drive.
-func (c *Client) GetStorageDriveDetails(driveUrl string) (*model.Drive, error) {
-	url := fmt.Sprint("http://%%", c.Config.Hostname, driveUrl)
-	body, err := httpclient.DoRequest(url, c.Config.Username, c.Config.Password, c.HTTPClientConfig)
-	if err != nil {
-		logger.Log.Error(err.Error())
-	}
-
+func (c *Client) GetStorageDriveDetails(driveURL string) (*model.Drive, error) {
 	var drive model.Drive
-	if err := json.Unmarshal(body, &drive); err != nil {
-		logger.Log.Error(err.Error())
+	if err := c.fetch(driveURL, &drive); err != nil {
+		return nil, err
 	}
 	return &drive, nil
 }
@@ -226,16 +166,20 @@ func (c *Client) GetPowerState() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return info.PowerState, nil
+	return string(info.PowerState), nil
 }
 
 // SetPowerState sets the power state of the server (On, ForceOff, GracefulShutdown).
 func (c *Client) SetPowerState(state string) error {
-	url := fmt.Sprintf("https://%s/redfish/v1/Systems/System.Embedded.1/Actions/ComputerSystem.Reset", c.Config.Hostname)
+	if allowed, err := c.AllowableValues(); err == nil {
+		if err := client.Validate("ResetType", state, allowed.ResetTypes); err != nil {
+			return err
+		}
+	}
 	payload := map[string]string{
 		"ResetType": state,
 	}
-	return request.Post(url, c.Config.Username, c.Config.Password, c.HTTPClientConfig, payload)
+	return c.Messages().ResolveError(request.Post(c.endpoint(systemPath+"/Actions/ComputerSystem.Reset"), c.Config.Username, c.Config.Password, c.HTTPClientConfig, payload))
 }
 
 // Reboot reboots the server (GracefulRestart).
@@ -244,34 +188,37 @@ func (c *Client) Reboot() error {
 }
 
 // GetBootInfo retrieves the boot information.
-func (c *Client) GetBootInfo() (*model.BootInfo, error) {
-	url := fmt.Sprintf("https://%s/redfish/v1/Systems/System.Embedded.1", c.Config.Hostname)
-	var info model.BootInfo
-	if err := request.FetchAndUnmarshal(url, c.Config.Username, c.Config.Password, c.HTTPClientConfig, &info); err != nil {
+func (c *Client) GetBootInfo() (*model.Boot, error) {
+	var system model.ComputerSystem
+	if err := c.fetch(systemPath, &system); err != nil {
 		return nil, err
 	}
-	return &info, nil
+	return &system.Boot, nil
 }
 
-// SetBootOrder sets the boot order.
+// SetBootOrder sets a one-time boot override (e.g., Pxe, Hdd, Cd).
 func (c *Client) SetBootOrder(device string) error {
-	url := fmt.Sprintf("https://%s/redfish/v1/Systems/System.Embedded.1", c.Config.Hostname)
+	if allowed, err := c.AllowableValues(); err == nil {
+		if err := client.Validate("BootSourceOverrideTarget", device, allowed.BootTargets); err != nil {
+			return err
+		}
+	}
 	payload := map[string]interface{}{
 		"Boot": map[string]string{
 			"BootSourceOverrideTarget": device,
 		},
 	}
-	return request.Post(url, c.Config.Username, c.Config.Password, c.HTTPClientConfig, payload)
+	return c.Messages().ResolveError(request.Patch(c.endpoint(systemPath), c.Config.Username, c.Config.Password, c.HTTPClientConfig, payload))
 }
 
-// GetSystemEventLog retrieves the system event log.
-func (c *Client) GetSystemEventLog() ([]model.EventLogEntry, error) {
-	url := fmt.Sprintf("https://%s/redfish/v1/Systems/System.Embedded.1/LogServices/Standard/Entries", c.Config.Hostname)
-	var log model.EventLog
-	if err := request.FetchAndUnmarshal(url, c.Config.Username, c.Config.Password, c.HTTPClientConfig, &log); err != nil {
-		return nil, err
+// GetSystemEventLog retrieves the platform event log.
+func (c *Client) GetSystemEventLog() ([]model.LogEntry, error) {
+	entries, err := request.FetchMembers[model.LogEntry](c.endpoint(selPath), c.Config.Username, c.Config.Password, c.HTTPClientConfig)
+	if err != nil {
+		return nil, c.Messages().ResolveError(err)
 	}
-	return log.Members, nil
+	c.Messages().ResolveEntries(entries)
+	return entries, nil
 }
 
 func init() {
@@ -0,0 +1,80 @@
+package xclarity
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/angelhvargas/redfishcli/pkg/config"
+	"github.com/angelhvargas/redfishcli/pkg/httpclient"
+	"github.com/angelhvargas/redfishcli/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(transport http.RoundTripper) *Client {
+	client := NewClient(config.XClarityConfig{
+		BMCConnConfig: config.BMCConnConfig{
+			Hostname: "testhost",
+			Username: "user",
+			Password: "pass",
+		},
+	})
+	client.HTTPClientConfig.Transport = transport
+	return client
+}
+
+func respondWith(status int, body string) http.RoundTripper {
+	return httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: status,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+			Request:    req,
+		}, nil
+	})
+}
+
+func TestGetServerInfo(t *testing.T) {
+	t.Run("success case", func(t *testing.T) {
+		client := newTestClient(respondWith(http.StatusOK, `{"Id": "1", "PowerState": "On", "Manufacturer": "Lenovo"}`))
+
+		result, err := client.GetServerInfo()
+
+		require.NoError(t, err)
+		assert.Equal(t, "1", result.ID)
+		assert.Equal(t, model.PowerStateOn, result.PowerState)
+		assert.Equal(t, "Lenovo", result.Manufacturer)
+	})
+
+	t.Run("transport error", func(t *testing.T) {
+		client := newTestClient(httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("request error")
+		}))
+
+		result, err := client.GetServerInfo()
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("authentication error", func(t *testing.T) {
+		client := newTestClient(respondWith(http.StatusUnauthorized, ""))
+
+		_, err := client.GetServerInfo()
+
+		assert.ErrorIs(t, err, httpclient.ErrAuthentication)
+	})
+}
+
+func TestGetStorageControllers(t *testing.T) {
+	client := newTestClient(respondWith(http.StatusOK, `{"Members": [{"@odata.id": "/redfish/v1/Systems/1/Storage/RAID_Slot1", "Name": "RAID"}]}`))
+
+	controllers, err := client.GetStorageControllers(&model.StorageControllerConfig{Type: "RAID"})
+
+	require.NoError(t, err)
+	require.Len(t, controllers, 1)
+	assert.Equal(t, "/redfish/v1/Systems/1/Storage/RAID_Slot1", controllers[0].ODataID)
+}
@@ -0,0 +1,10 @@
+// Package redfishregistry embeds the bundled DMTF Redfish message
+// registries, so MessageIds can be resolved without asking the BMC.
+package redfishregistry
+
+import "embed"
+
+// FS holds every *.json registry file of this directory.
+//
+//go:embed *.json
+var FS embed.FS
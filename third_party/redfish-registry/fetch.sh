@@ -0,0 +1,10 @@
+#!/bin/sh
+# Replace the vendored registries with the complete ones published by DMTF.
+set -eu
+
+dir="$(cd "$(dirname "$0")" && pwd)"
+[ "$#" -gt 0 ] || set -- Base.1.8.1 ResourceEvent.1.0.3
+
+for registry in "$@"; do
+	curl -fsSL -o "$dir/$registry.json" "https://redfish.dmtf.org/registries/$registry.json"
+done
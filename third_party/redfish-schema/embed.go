@@ -0,0 +1,10 @@
+// Package redfishschema embeds the bundled DMTF Redfish JSON schemas, so
+// responses can be validated offline.
+package redfishschema
+
+import "embed"
+
+// FS holds every *.json schema file of this directory.
+//
+//go:embed *.json
+var FS embed.FS
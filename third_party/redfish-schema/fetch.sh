@@ -0,0 +1,13 @@
+#!/bin/sh
+# Replace the vendored schemas with the json-schema folder of a DSP8010 release.
+set -eu
+
+version="${1:-2023.3}"
+dir="$(cd "$(dirname "$0")" && pwd)"
+tmp="$(mktemp -d)"
+trap 'rm -rf "$tmp"' EXIT
+
+curl -fsSL -o "$tmp/bundle.zip" "https://www.dmtf.org/sites/default/files/standards/documents/DSP8010_${version}.zip"
+unzip -q "$tmp/bundle.zip" -d "$tmp"
+find "$dir" -name '*.json' -delete
+cp "$tmp"/DSP8010_"${version}"/json-schema/*.json "$dir"/
@@ -0,0 +1,624 @@
+// Command schemagen generates Go models from DMTF Redfish JSON schemas.
+//
+// It reads a directory laid out like the json-schema folder of the DMTF
+// schema bundle (DSP8010): unversioned files such as Drive.json, versioned
+// files such as Drive.v1_9_0.json, Resource.json and odata-v4.json. For each
+// root schema named on the command line it emits a struct, following $refs
+// to the structs and enums it needs.
+//
+// Every versioned file of a schema is merged into one type, newest version
+// last, so a model decodes payloads from BMCs on any schema version.
+// References to other resources become IDRef links, and string enums become
+// named types with constants.
+//
+//	go run ./tools/schemagen -schemas third_party/redfish-schema -out pkg/model/zz_generated.go ComputerSystem Drive
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	schemaDir := flag.String("schemas", "", "directory holding the DMTF json-schema files")
+	out := flag.String("out", "", "Go file to write")
+	pkg := flag.String("package", "model", "package name of the generated file")
+	flag.Parse()
+	if *schemaDir == "" || *out == "" || flag.NArg() == 0 {
+		log.Fatal("usage: schemagen -schemas <dir> -out <file> [-package <name>] <Schema>...")
+	}
+
+	bundle, err := loadBundle(*schemaDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := generate(bundle, *pkg, flag.Args())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// node is the subset of JSON schema used by the Redfish schemas.
+type node struct {
+	Ref              string            `json:"$ref"`
+	Type             typeList          `json:"type"`
+	Enum             []string          `json:"enum"`
+	EnumDescriptions map[string]string `json:"enumDescriptions"`
+	Description      string            `json:"description"`
+	LongDescription  string            `json:"longDescription"`
+	Properties       map[string]*node  `json:"properties"`
+	Items            *node             `json:"items"`
+	AnyOf            []*node           `json:"anyOf"`
+	Deprecated       string            `json:"deprecated"`
+}
+
+// typeList accepts both "type": "string" and "type": ["string", "null"].
+type typeList []string
+
+func (t *typeList) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = typeList{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*t = many
+	return nil
+}
+
+func (t typeList) nullable() bool {
+	for _, s := range t {
+		if s == "null" {
+			return true
+		}
+	}
+	return false
+}
+
+func (t typeList) base() string {
+	for _, s := range t {
+		if s != "null" {
+			return s
+		}
+	}
+	return ""
+}
+
+type schemaFile struct {
+	Definitions map[string]*node `json:"definitions"`
+}
+
+// bundle indexes schema files by schema name. versions is sorted oldest first.
+type bundle struct {
+	unversioned map[string]*schemaFile
+	versions    map[string][]versioned
+}
+
+type versioned struct {
+	version [3]int
+	file    *schemaFile
+}
+
+var versionedName = regexp.MustCompile(`^(\w+)\.v(\d+)_(\d+)_(\d+)\.json$`)
+
+func loadBundle(dir string) (*bundle, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	b := &bundle{unversioned: map[string]*schemaFile{}, versions: map[string][]versioned{}}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var f schemaFile
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		base := filepath.Base(path)
+		if m := versionedName.FindStringSubmatch(base); m != nil {
+			var v [3]int
+			for i := range v {
+				v[i], _ = strconv.Atoi(m[i+2])
+			}
+			b.versions[m[1]] = append(b.versions[m[1]], versioned{version: v, file: &f})
+			continue
+		}
+		b.unversioned[strings.TrimSuffix(base, ".json")] = &f
+	}
+	for _, list := range b.versions {
+		sort.Slice(list, func(i, j int) bool {
+			a, c := list[i].version, list[j].version
+			for k := range a {
+				if a[k] != c[k] {
+					return a[k] < c[k]
+				}
+			}
+			return false
+		})
+	}
+	return b, nil
+}
+
+// key identifies a definition independently of the schema version.
+type key struct {
+	schema, def string
+}
+
+var refPattern = regexp.MustCompile(`(?:^|/)([\w-]+?)(?:\.v\d+_\d+_\d+)?\.json#/definitions/(\w+)$`)
+
+// resolveRef turns a $ref into a key; local refs resolve against from.
+func resolveRef(ref string, from string) (key, error) {
+	if strings.HasPrefix(ref, "#/definitions/") {
+		return key{from, strings.TrimPrefix(ref, "#/definitions/")}, nil
+	}
+	m := refPattern.FindStringSubmatch(ref)
+	if m == nil {
+		return key{}, fmt.Errorf("unsupported $ref %q", ref)
+	}
+	return key{m[1], m[2]}, nil
+}
+
+// definition returns k merged across every version of its schema. Properties
+// and enum values added in later versions are included, and later versions
+// win for everything else.
+func (b *bundle) definition(k key) (*node, error) {
+	var merged *node
+	for _, v := range b.versions[k.schema] {
+		d, ok := v.file.Definitions[k.def]
+		if !ok {
+			continue
+		}
+		if merged == nil {
+			merged = &node{Properties: map[string]*node{}, EnumDescriptions: map[string]string{}}
+		}
+		props, enum, descs := merged.Properties, merged.Enum, merged.EnumDescriptions
+		*merged = *d
+		for name, p := range d.Properties {
+			props[name] = p
+		}
+		for _, e := range d.Enum {
+			if !contains(enum, e) {
+				enum = append(enum, e)
+			}
+		}
+		for e, desc := range d.EnumDescriptions {
+			descs[e] = desc
+		}
+		merged.Properties, merged.Enum, merged.EnumDescriptions = props, enum, descs
+	}
+	if merged != nil {
+		return merged, nil
+	}
+	if f, ok := b.unversioned[k.schema]; ok {
+		if d, ok := f.Definitions[k.def]; ok {
+			return d, nil
+		}
+	}
+	return nil, fmt.Errorf("definition %s/%s not found", k.schema, k.def)
+}
+
+// isLink reports whether k is a resource entity, which other resources only
+// ever reference by @odata.id.
+func (b *bundle) isLink(k key) bool {
+	if k.schema == "odata-v4" && k.def == "idRef" {
+		return true
+	}
+	f, ok := b.unversioned[k.schema]
+	if !ok {
+		return false
+	}
+	d, ok := f.Definitions[k.def]
+	if !ok {
+		return false
+	}
+	for _, alt := range d.AnyOf {
+		if strings.HasSuffix(alt.Ref, "odata-v4.json#/definitions/idRef") {
+			return true
+		}
+	}
+	return false
+}
+
+type generator struct {
+	b     *bundle
+	roots map[key]bool
+	// names maps every emitted definition to its Go type name.
+	names map[key]string
+	out   bytes.Buffer
+}
+
+func generate(b *bundle, pkg string, roots []string) ([]byte, error) {
+	g := &generator{b: b, roots: map[key]bool{}, names: map[key]string{}}
+	for _, r := range roots {
+		g.roots[rootKey(r)] = true
+	}
+
+	// Collect every definition reachable from the roots first, so names can
+	// be assigned knowing all collisions.
+	seen := map[key]bool{}
+	var order []key
+	var walk func(k key) error
+	walk = func(k key) error {
+		if seen[k] {
+			return nil
+		}
+		seen[k] = true
+		order = append(order, k)
+		d, err := b.definition(k)
+		if err != nil {
+			return err
+		}
+		return g.refs(d, k.schema, walk)
+	}
+	for _, r := range roots {
+		if err := walk(rootKey(r)); err != nil {
+			return nil, err
+		}
+	}
+	g.assignNames(order)
+
+	fmt.Fprintf(&g.out, "// Code generated by schemagen from the DMTF Redfish schemas; DO NOT EDIT.\n\npackage %s\n", pkg)
+	for _, k := range order {
+		if err := g.emit(k); err != nil {
+			return nil, err
+		}
+	}
+	src, err := format.Source(g.out.Bytes())
+	if err != nil {
+		return g.out.Bytes(), fmt.Errorf("formatting generated code: %w", err)
+	}
+	return src, nil
+}
+
+// rootKey parses a root argument: a schema name such as Drive, or
+// Schema/Definition for a definition that is not a resource of its own but
+// should still keep its raw payload, such as Storage/StorageController.
+func rootKey(arg string) key {
+	if schema, def, ok := strings.Cut(arg, "/"); ok {
+		return key{schema, def}
+	}
+	return key{arg, arg}
+}
+
+// refs calls visit for each named definition d needs a Go type for.
+func (g *generator) refs(d *node, schema string, visit func(key) error) error {
+	if d.Ref != "" {
+		k, err := resolveRef(d.Ref, schema)
+		if err != nil {
+			return err
+		}
+		if g.b.isLink(k) || g.builtin(k) != "" {
+			return nil
+		}
+		target, err := g.b.definition(k)
+		if err != nil {
+			return err
+		}
+		if len(target.Properties) == 0 && len(target.Enum) == 0 {
+			return nil
+		}
+		return visit(k)
+	}
+	for _, alt := range d.AnyOf {
+		if err := g.refs(alt, schema, visit); err != nil {
+			return err
+		}
+	}
+	if d.Items != nil {
+		if err := g.refs(d.Items, schema, visit); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedKeys(d.Properties) {
+		if err := g.refs(d.Properties[name], schema, visit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// builtin maps definitions that are plain values to Go types.
+func (g *generator) builtin(k key) string {
+	switch {
+	case k.schema == "odata-v4" && k.def == "count":
+		return "int64"
+	case k.schema == "odata-v4":
+		return "string"
+	case k.schema == "Resource" && (k.def == "Oem" || k.def == "OemObject"):
+		return "map[string]interface{}"
+	}
+	return ""
+}
+
+func (g *generator) assignNames(order []key) {
+	byDef := map[string][]key{}
+	for _, k := range order {
+		byDef[k.def] = append(byDef[k.def], k)
+	}
+	for def, keys := range byDef {
+		for _, k := range keys {
+			name := exported(def)
+			if len(keys) > 1 && k.schema != def {
+				name = exported(k.schema) + name
+			}
+			g.names[k] = name
+		}
+	}
+}
+
+func (g *generator) emit(k key) error {
+	d, err := g.b.definition(k)
+	if err != nil {
+		return err
+	}
+	name := g.names[k]
+	if len(d.Enum) > 0 {
+		g.emitEnum(name, k, d)
+		return nil
+	}
+
+	fmt.Fprintf(&g.out, "\n%s", comment(name, k, d))
+	fmt.Fprintf(&g.out, "type %s struct {\n", name)
+	root := g.roots[k]
+	if root {
+		g.out.WriteString("\tResource `yaml:\",inline\"`\n")
+	}
+	for _, prop := range sortedKeys(d.Properties) {
+		if root && prop == "Oem" {
+			// Kept by the embedded Resource.
+			continue
+		}
+		p := d.Properties[prop]
+		typ, err := g.goType(p, k.schema, false)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", name, prop, err)
+		}
+		if desc := g.fieldDescription(p, k.schema); desc != "" {
+			g.out.WriteString(wrap(desc, "\t// "))
+		}
+		if p.Deprecated != "" {
+			g.out.WriteString(wrap("Deprecated: "+p.Deprecated, "\t// "))
+		}
+		fmt.Fprintf(&g.out, "\t%s %s `json:\"%s,omitempty\"`\n", fieldName(prop), typ, prop)
+	}
+	g.out.WriteString("}\n")
+
+	if root {
+		fmt.Fprintf(&g.out, `
+func (v *%[1]s) UnmarshalJSON(data []byte) error {
+	type plain %[1]s
+	return decode(data, (*plain)(v), &v.Resource)
+}
+`, name)
+	}
+	return nil
+}
+
+func (g *generator) emitEnum(name string, k key, d *node) {
+	fmt.Fprintf(&g.out, "\n%stype %s string\n\nconst (\n", comment(name, k, d), name)
+	for _, v := range d.Enum {
+		if desc := d.EnumDescriptions[v]; desc != "" {
+			g.out.WriteString(wrap(desc, "\t// "))
+		}
+		fmt.Fprintf(&g.out, "\t%s%s %s = %q\n", name, exported(v), name, v)
+	}
+	g.out.WriteString(")\n")
+	fmt.Fprintf(&g.out, `
+// IsKnown reports whether v is defined by the schema versions the model was
+// generated from. Values from newer versions still decode unchanged.
+func (v %s) IsKnown() bool {
+	switch v {
+	case `, name)
+	for i, v := range d.Enum {
+		if i > 0 {
+			g.out.WriteString(", ")
+		}
+		g.out.WriteString(name + exported(v))
+	}
+	g.out.WriteString(":\n\t\treturn true\n\t}\n\treturn false\n}\n")
+}
+
+// goType returns the Go type of a property. Nullable numbers and booleans
+// become pointers so that null and zero stay distinguishable.
+func (g *generator) goType(p *node, schema string, nullable bool) (string, error) {
+	if p.Ref != "" {
+		k, err := resolveRef(p.Ref, schema)
+		if err != nil {
+			return "", err
+		}
+		if g.b.isLink(k) {
+			return "IDRef", nil
+		}
+		if t := g.builtin(k); t != "" {
+			return t, nil
+		}
+		if name, ok := g.names[k]; ok {
+			return name, nil
+		}
+		// A plain value definition such as Resource.Id.
+		d, err := g.b.definition(k)
+		if err != nil {
+			return "", err
+		}
+		return g.goType(d, k.schema, nullable)
+	}
+	if len(p.AnyOf) > 0 {
+		var alt *node
+		for _, a := range p.AnyOf {
+			if a.Ref == "" && a.Type.base() == "" && a.Type.nullable() {
+				nullable = true
+				continue
+			}
+			if alt == nil {
+				alt = a
+			}
+		}
+		if alt == nil {
+			return "interface{}", nil
+		}
+		return g.goType(alt, schema, nullable)
+	}
+
+	nullable = nullable || p.Type.nullable()
+	switch p.Type.base() {
+	case "string":
+		return "string", nil
+	case "integer":
+		return pointer("int64", nullable), nil
+	case "number":
+		return pointer("float64", nullable), nil
+	case "boolean":
+		return pointer("bool", nullable), nil
+	case "array":
+		if p.Items == nil {
+			return "[]interface{}", nil
+		}
+		item, err := g.goType(p.Items, schema, false)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + strings.TrimPrefix(item, "*"), nil
+	case "object":
+		return "map[string]interface{}", nil
+	}
+	return "interface{}", nil
+}
+
+func pointer(t string, nullable bool) string {
+	if nullable {
+		return "*" + t
+	}
+	return t
+}
+
+var nonIdent = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// exported turns a schema name or enum value into an exported identifier.
+func exported(s string) string {
+	parts := nonIdent.Split(s, -1)
+	var b strings.Builder
+	for _, p := range parts {
+		if p == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(p[:1]) + p[1:])
+	}
+	name := b.String()
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "V" + name
+	}
+	return name
+}
+
+var idSuffix = regexp.MustCompile(`Id([A-Z]|$)`)
+
+// fieldName maps a property name to a Go field name: Id becomes ID,
+// @odata.id becomes ODataID, Drives@odata.count becomes DrivesCount and
+// #ComputerSystem.Reset becomes ComputerSystemReset.
+func fieldName(prop string) string {
+	switch {
+	case strings.HasPrefix(prop, "@odata."):
+		return initialisms("OData" + exported(strings.TrimPrefix(prop, "@odata.")))
+	case strings.HasSuffix(prop, "@odata.count"):
+		prop = strings.TrimSuffix(prop, "@odata.count") + "Count"
+	case strings.HasSuffix(prop, "@odata.nextLink"):
+		prop = strings.TrimSuffix(prop, "@odata.nextLink") + "NextLink"
+	}
+	return initialisms(exported(prop))
+}
+
+func initialisms(name string) string {
+	return strings.ReplaceAll(idSuffix.ReplaceAllString(name, "ID$1"), "Uuid", "UUID")
+}
+
+func description(n *node) string {
+	if n.LongDescription != "" && len(n.LongDescription) < 240 {
+		return n.LongDescription
+	}
+	return n.Description
+}
+
+// fieldDescription describes a property, falling back to the description of
+// the definition it references, as Id and Name only carry one there.
+func (g *generator) fieldDescription(p *node, schema string) string {
+	if desc := description(p); desc != "" {
+		return desc
+	}
+	refs := []*node{p}
+	refs = append(refs, p.AnyOf...)
+	for _, r := range refs {
+		if r.Ref == "" {
+			continue
+		}
+		k, err := resolveRef(r.Ref, schema)
+		if err != nil {
+			continue
+		}
+		if d, err := g.b.definition(k); err == nil {
+			return description(d)
+		}
+	}
+	return ""
+}
+
+func comment(name string, k key, d *node) string {
+	what := fmt.Sprintf("the %s %s definition", k.schema, k.def)
+	if k.schema == k.def {
+		what = fmt.Sprintf("the %s schema", k.schema)
+	}
+	return wrap(fmt.Sprintf("%s is %s. %s", name, what, description(d)), "// ")
+}
+
+// wrap formats text as a comment wrapped at about 80 columns.
+func wrap(text, prefix string) string {
+	var b strings.Builder
+	line := prefix
+	for _, word := range strings.Fields(text) {
+		if len(line) > len(prefix) && len(line)+1+len(word) > 80 {
+			b.WriteString(line + "\n")
+			line = prefix
+		}
+		if len(line) > len(prefix) {
+			line += " "
+		}
+		line += word
+	}
+	b.WriteString(line + "\n")
+	return b.String()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const schemaDir = "../../third_party/redfish-schema"
+
+func generateFromBundle(t *testing.T, roots ...string) string {
+	t.Helper()
+	b, err := loadBundle(schemaDir)
+	require.NoError(t, err)
+	src, err := generate(b, "model", roots)
+	require.NoError(t, err)
+	return string(src)
+}
+
+// assertField checks for a struct field, whatever gofmt's alignment.
+func assertField(t *testing.T, src, name, typ, tag string, msg ...interface{}) {
+	t.Helper()
+	field := regexp.MustCompile(`\n\t` + regexp.QuoteMeta(name) + `\s+` + regexp.QuoteMeta(typ) + `\s+` + "`json:\"" + regexp.QuoteMeta(tag) + ",omitempty\"`")
+	assert.Regexp(t, field, src, msg...)
+}
+
+func TestGenerateMergesVersions(t *testing.T) {
+	src := generateFromBundle(t, "ComputerSystem")
+
+	// BootProgress only exists in ComputerSystem.v1_20_0, AssetTag in both.
+	assertField(t, src, "BootProgress", "BootProgress", "BootProgress")
+	assertField(t, src, "AssetTag", "string", "AssetTag")
+	// DPU was added to SystemType in v1_20_0.
+	assert.Contains(t, src, `SystemTypeDPU SystemType = "DPU"`)
+	assert.Contains(t, src, `SystemTypePhysical SystemType = "Physical"`)
+}
+
+func TestGenerateTypes(t *testing.T) {
+	src := generateFromBundle(t, "ComputerSystem", "Manager", "Storage", "Storage/StorageController")
+
+	assertField(t, src, "Storage", "IDRef", "Storage", "links to other resources become IDRef")
+	assertField(t, src, "Drives", "[]IDRef", "Drives")
+	assertField(t, src, "DrivesCount", "int64", "Drives@odata.count")
+	assertField(t, src, "SpeedGbps", "*float64", "SpeedGbps", "nullable numbers become pointers")
+	assertField(t, src, "PowerState", "PowerState", "PowerState")
+	assertField(t, src, "ComputerSystemReset", "ComputerSystemReset", "#ComputerSystem.Reset")
+	assert.Contains(t, src, "type ComputerSystemLinks struct", "colliding definitions are prefixed with their schema")
+	assert.Contains(t, src, "type StorageLinks struct")
+
+	for _, root := range []string{"ComputerSystem", "Storage", "StorageController"} {
+		assert.Contains(t, src, "func (v *"+root+") UnmarshalJSON(data []byte) error {")
+	}
+	assert.NotContains(t, src, "func (v *Status) UnmarshalJSON")
+}
+
+func TestGeneratedModelsAreUpToDate(t *testing.T) {
+	data, err := os.ReadFile("../../pkg/model/generate.go")
+	require.NoError(t, err)
+	var args []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(line, "//go:generate ") {
+			_, roots, _ := strings.Cut(line, "zz_generated.go ")
+			args = strings.Fields(roots)
+		}
+	}
+	require.NotEmpty(t, args)
+
+	want, err := os.ReadFile("../../pkg/model/zz_generated.go")
+	require.NoError(t, err)
+	assert.Equal(t, string(want), generateFromBundle(t, args...), "run go generate ./pkg/model")
+}
+
+func TestFieldName(t *testing.T) {
+	for prop, want := range map[string]string{
+		"Id":                           "ID",
+		"MemberId":                     "MemberID",
+		"UUID":                         "UUID",
+		"@odata.id":                    "ODataID",
+		"Drives@odata.count":           "DrivesCount",
+		"#Drive.SecureErase":           "DriveSecureErase",
+		"Identifiers":                  "Identifiers",
+		"UefiTargetBootSourceOverride": "UefiTargetBootSourceOverride",
+	} {
+		assert.Equal(t, want, fieldName(prop), prop)
+	}
+}
+
+// TestGeneratedModelsUpToDate checks that pkg/model/zz_generated.go is what
+// its go:generate line produces from the vendored schemas, so that a schema
+// listed there without being vendored, or a hand edit, fails the build.
+func TestGeneratedModelsUpToDate(t *testing.T) {
+	directives, err := os.ReadFile("../../pkg/model/generate.go")
+	require.NoError(t, err)
+	line := regexp.MustCompile(`(?m)^//go:generate .* -out zz_generated.go (.*)$`).FindSubmatch(directives)
+	require.NotNil(t, line, "no go:generate line for zz_generated.go")
+	want, err := os.ReadFile("../../pkg/model/zz_generated.go")
+	require.NoError(t, err)
+
+	got := generateFromBundle(t, strings.Fields(string(line[1]))...)
+	assert.True(t, got == string(want), "pkg/model/zz_generated.go is stale; run go generate ./pkg/model")
+}